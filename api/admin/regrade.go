@@ -0,0 +1,79 @@
+package admin
+
+import (
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/grader"
+)
+
+type RegradeRequest struct {
+    core.APIRequestAssignmentContext
+    core.MinRoleAdmin
+
+    // Regrade all submissions (instead of just the most recent one for each user).
+    All bool `json:"all"`
+    // Only regrade submissions from these users (emails, roles, or "*"), defaults to everyone.
+    Users []string `json:"users"`
+    // Only regrade these full submission IDs (all and users are ignored).
+    Submissions []string `json:"submissions"`
+    // Grade, but do not save the new results.
+    DryRun bool `json:"dry-run"`
+}
+
+type RegradeResponse struct {
+    Results []*grader.RegradeResult `json:"results"`
+
+    NumRegraded int `json:"num-regraded"`
+    NumChanged int `json:"num-changed"`
+    NumFailed int `json:"num-failed"`
+}
+
+func HandleRegrade(request *RegradeRequest) (*RegradeResponse, *core.APIError) {
+    response := RegradeResponse{
+        Results: make([]*grader.RegradeResult, 0),
+    };
+
+    options := grader.RegradeOptions{
+        All: request.All,
+        SubmissionIDs: request.Submissions,
+        DryRun: request.DryRun,
+        GradeOptions: grader.GetDefaultGradeOptions(),
+    };
+
+    if (len(request.Users) > 0) {
+        users, err := db.ResolveUsers(request.Course, request.Users);
+        if (err != nil) {
+            return nil, core.NewInternalError("-207", &request.APIRequestCourseUserContext,
+                    "Failed to resolve users.").Err(err).Assignment(request.Assignment.GetID());
+        }
+
+        // None of the requested users exist, so there is nothing to regrade.
+        if ((len(users) == 0) && (len(request.Submissions) == 0)) {
+            return &response, nil;
+        }
+
+        options.Users = users;
+    }
+
+    results, err := grader.Regrade(request.Assignment, options);
+    if (err != nil) {
+        return nil, core.NewBadCourseRequestError("-208", &request.APIRequestCourseUserContext,
+                "Failed to regrade submissions.").Err(err).Assignment(request.Assignment.GetID());
+    }
+
+    response.Results = results;
+
+    for _, result := range results {
+        if (result.Error != "") {
+            response.NumFailed++;
+            continue;
+        }
+
+        response.NumRegraded++;
+        if (result.Changed()) {
+            response.NumChanged++;
+        }
+    }
+
+    return &response, nil;
+}
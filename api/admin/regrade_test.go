@@ -0,0 +1,68 @@
+package admin
+
+import (
+    "testing"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func TestRegrade(test *testing.T) {
+    testCases := []struct{role model.UserRole; fields map[string]any; permError bool; numRegraded int; numChanged int}{
+        {model.RoleAdmin, nil, false, 1, 1},
+        {model.RoleAdmin, map[string]any{"all": true}, false, 3, 2},
+        {model.RoleAdmin, map[string]any{"users": []string{"grader"}}, false, 0, 0},
+        {model.RoleAdmin, map[string]any{"users": []string{"student"}, "dry-run": true}, false, 1, 1},
+        {model.RoleAdmin, map[string]any{"submissions": []string{"course101::hw0::student@test.com::1697406256"}}, false, 1, 0},
+        {model.RoleGrader, nil, true, 0, 0},
+    };
+
+    oldDockerVal := config.DOCKER_DISABLE.Get();
+    config.DOCKER_DISABLE.Set(true);
+    defer config.DOCKER_DISABLE.Set(oldDockerVal);
+
+    defer db.ResetForTesting();
+
+    for i, testCase := range testCases {
+        db.ResetForTesting();
+
+        // Use a grader that gives everyone a zero.
+        assignment := db.MustGetTestAssignment();
+        assignment.Invocation = []string{"sh", "-c", `echo '{"name": "hw0", "questions": [{"name": "Q1", "max_points": 2, "score": 0}]}' > "$0"`, "<outpath>"};
+        err := db.SaveCourse(assignment.GetCourse());
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to save assignment: '%v'.", i, err);
+        }
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/regrade`), testCase.fields, nil, testCase.role);
+        if (!response.Success) {
+            if (testCase.permError) {
+                expectedLocator := "-020";
+                if (response.Locator != expectedLocator) {
+                    test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, expectedLocator, response.Locator);
+                }
+            } else {
+                test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+            }
+
+            continue;
+        }
+
+        if (testCase.permError) {
+            test.Errorf("Case %d: Did not get an expected permissions error.", i);
+            continue;
+        }
+
+        var responseContent RegradeResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if ((responseContent.NumRegraded != testCase.numRegraded) || (responseContent.NumChanged != testCase.numChanged) || (responseContent.NumFailed != 0)) {
+            test.Errorf("Case %d: Unexpected counts. Expected: (%d, %d, 0), Actual: (%d, %d, %d).", i,
+                    testCase.numRegraded, testCase.numChanged, responseContent.NumRegraded, responseContent.NumChanged, responseContent.NumFailed);
+            continue;
+        }
+    }
+}
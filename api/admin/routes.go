@@ -9,6 +9,8 @@ import (
 var routes []*core.Route = []*core.Route{
     core.NewAPIRoute(core.NewEndpoint(`admin/logs/fetch`), HandleFetchLogs),
     core.NewAPIRoute(core.NewEndpoint(`admin/update/course`), HandleUpdateCourse),
+    core.NewAPIRoute(core.NewEndpoint(`admin/regrade`), HandleRegrade),
+    core.NewAPIRoute(core.NewEndpoint(`admin/user/unlock`), HandleUnlockUser),
 };
 
 func GetRoutes() *[]*core.Route {
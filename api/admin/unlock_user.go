@@ -0,0 +1,61 @@
+package admin
+
+import (
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+)
+
+type UnlockUserRequest struct {
+    core.APIRequestCourseUserContext
+    core.MinRoleAdmin
+
+    TargetUser core.TargetUser `json:"target-email"`
+    // Optionally, also unlock an IP address.
+    IP string `json:"ip"`
+}
+
+type UnlockUserResponse struct {
+    FoundUser bool `json:"found-user"`
+    // True if the user had any failed logins.
+    UserUnlocked bool `json:"user-unlocked"`
+    // True if the IP address had any failed logins.
+    IPUnlocked bool `json:"ip-unlocked"`
+}
+
+// Forget all the failed logins for a user (and IP address), ending any lockout.
+func HandleUnlockUser(request *UnlockUserRequest) (*UnlockUserResponse, *core.APIError) {
+    response := UnlockUserResponse{};
+
+    if (request.IP != "") {
+        response.IPUnlocked = core.UnlockIP(request.IP);
+    }
+
+    if (request.TargetUser.Found) {
+        response.FoundUser = true;
+
+        serverUser, err := db.GetServerUser(request.TargetUser.Email);
+        if (err != nil) {
+            return nil, core.NewInternalError("-209", &request.APIRequestCourseUserContext,
+                    "Failed to get server user.").Err(err).Add("target-user", request.TargetUser.Email);
+        }
+
+        credentials := model.GetCredentials(request.TargetUser.User, serverUser);
+        response.UserUnlocked = credentials.ClearFailedLogins();
+
+        if (response.UserUnlocked) {
+            err = db.SaveCredentials(request.Course, request.TargetUser.User, serverUser);
+            if (err != nil) {
+                return nil, core.NewInternalError("-210", &request.APIRequestCourseUserContext,
+                        "Failed to save user.").Err(err).Add("target-user", request.TargetUser.Email);
+            }
+        }
+    }
+
+    log.Info("Unlocked logins.", log.NewAttr("security-event", "login-unlock"), request.Course, log.NewUserAttr(request.UserEmail),
+            log.NewAttr("target-user", request.TargetUser.Email), log.NewAttr("ip", request.IP),
+            log.NewAttr("user-unlocked", response.UserUnlocked), log.NewAttr("ip-unlocked", response.IPUnlocked));
+
+    return &response, nil;
+}
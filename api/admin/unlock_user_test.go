@@ -0,0 +1,88 @@
+package admin
+
+import (
+    "testing"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func TestUnlockUser(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    oldThreshold := config.LOCKOUT_THRESHOLD.Get();
+    defer config.LOCKOUT_THRESHOLD.Set(oldThreshold);
+    config.LOCKOUT_THRESHOLD.Set(2);
+
+    badPassFields := map[string]any{
+        "user-pass": util.Sha256HexFromString("Zstudent"),
+    };
+
+    for i := 0; i < 2; i++ {
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/user/unlock`), badPassFields, nil, model.RoleStudent);
+        if (response.Success) {
+            test.Fatalf("Request with a bad password succeeded.");
+        }
+    }
+
+    // Locked out (even with the correct password).
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/user/unlock`), nil, nil, model.RoleStudent);
+    if (response.Success) {
+        test.Fatalf("Locked out user's request succeeded.");
+    }
+
+    testCases := []struct{role model.UserRole; target string; ip string; permError bool; expected UnlockUserResponse}{
+        {model.RoleGrader, "student@test.com", "", true, UnlockUserResponse{}},
+        {model.RoleAdmin, "ZZZ@test.com", "", false, UnlockUserResponse{}},
+        {model.RoleAdmin, "student@test.com", "", false, UnlockUserResponse{FoundUser: true, UserUnlocked: true}},
+        {model.RoleAdmin, "student@test.com", "", false, UnlockUserResponse{FoundUser: true}},
+        {model.RoleOwner, "student@test.com", "127.0.0.1", false, UnlockUserResponse{FoundUser: true, IPUnlocked: true}},
+    };
+
+    for i, testCase := range testCases {
+        fields := map[string]any{
+            "target-email": testCase.target,
+            "ip": testCase.ip,
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`admin/user/unlock`), fields, nil, testCase.role);
+        if (!response.Success) {
+            if (testCase.permError) {
+                expectedLocator := "-020";
+                if (response.Locator != expectedLocator) {
+                    test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, expectedLocator, response.Locator);
+                }
+            } else {
+                test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+            }
+
+            continue;
+        }
+
+        if (testCase.permError) {
+            test.Errorf("Case %d: Response is a success when it should not be: '%v'.", i, response);
+            continue;
+        }
+
+        var responseContent UnlockUserResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if (testCase.expected != responseContent) {
+            test.Errorf("Case %d: Unexpected result. Expected: '%+v', actual: '%+v'.", i, testCase.expected, responseContent);
+        }
+    }
+
+    // Once unlocked, the user can log in again.
+    user, err := db.GetUser(db.MustGetTestCourse(), "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get user: '%v'.", err);
+    }
+
+    if (user.LoginFailures != nil) {
+        test.Fatalf("Unlocked user still has failed logins: '%+v'.", user.LoginFailures);
+    }
+}
@@ -1,6 +1,8 @@
 package core
 
 import (
+    "time"
+
     "github.com/edulinq/autograder/config"
     "github.com/edulinq/autograder/db"
     "github.com/edulinq/autograder/log"
@@ -8,26 +10,110 @@ import (
 )
 
 // Return a user only in the case that the authentication is successful.
+// Users with a server user are authenticated with the server user's credentials,
+// and then get their role from their course user.
+// Failed logins are recorded, and too many of them will lock out the user (or IP address) for a while.
 // If any error is retuturned, then the request should end and the response sent based on the error.
 // This assumes basic validation has already been done on the request.
 func (this *APIRequestCourseUserContext) Auth() (*model.User, *APIError) {
+    now := time.Now();
+
+    if (!config.NO_AUTH.Get() && isIPLocked(this.RemoteIP, now)) {
+        return nil, NewAuthBadRequestError("-047", this, "IP Locked Out").Add("ip", this.RemoteIP);
+    }
+
     user, err := db.GetUser(this.Course, this.UserEmail);
     if (err != nil) {
         return nil, NewAuthBadRequestError("-012", this, "Cannot Get User").Err(err);
     }
 
     if (user == nil) {
+        if (!config.NO_AUTH.Get()) {
+            this.recordFailedLogin(nil, nil, "-013");
+        }
+
         return nil, NewAuthBadRequestError("-013", this, "Unknown User");
     }
 
+    this.ServerUser, err = db.GetServerUser(this.UserEmail);
+    if (err != nil) {
+        return nil, NewAuthBadRequestError("-046", this, "Cannot Get Server User").Err(err);
+    }
+
     if (config.NO_AUTH.Get()) {
         log.Debug("Authentication Disabled.", this.Course, log.NewUserAttr(this.UserEmail));
         return user, nil;
     }
 
-    if (!user.CheckPassword(this.UserPass)) {
-        return nil, NewAuthBadRequestError("-014", this, "Bad Password");
+    credentials := model.GetCredentials(user, this.ServerUser);
+
+    // A locked out user cannot log in, even with the correct credentials.
+    if (credentials.IsLocked(now)) {
+        return nil, NewAuthBadRequestError("-048", this, "Account Locked Out");
+    }
+
+    apiErr := this.checkCredentials(credentials);
+    if (apiErr != nil) {
+        this.recordFailedLogin(user, credentials, apiErr.Locator);
+        return nil, apiErr;
+    }
+
+    err = clearFailedLogins(this.Course, user, this.ServerUser, credentials);
+    if (err != nil) {
+        return nil, NewInternalError("-049", this, "Failed to clear failed logins.").Err(err);
     }
 
     return user, nil;
 }
+
+// Check the password of a target user (which may not be the user making this request, e.g., for user/auth).
+// This follows the same lockout rules as logging in:
+// a locked out target cannot be checked, and failures are recorded against the target (and this request's IP address).
+func (this *APIRequestCourseUserContext) CheckTargetPassword(user *model.User, serverUser *model.ServerUser, pass string) (bool, *APIError) {
+    credentials := model.GetCredentials(user, serverUser);
+
+    if (credentials.IsLocked(time.Now())) {
+        return false, NewBadRequestError("-050", &this.APIRequest, "Target account is locked out.").
+                Course(this.CourseID).Add("target-user", user.Email);
+    }
+
+    if (!credentials.CheckPassword(pass)) {
+        this.recordFailedLoginForUser(user.Email, user, serverUser, credentials, "-051");
+        return false, nil;
+    }
+
+    err := clearFailedLogins(this.Course, user, serverUser, credentials);
+    if (err != nil) {
+        return false, NewInternalError("-052", this, "Failed to clear failed logins.").Err(err).Add("target-user", user.Email);
+    }
+
+    return true, nil;
+}
+
+func (this *APIRequestCourseUserContext) checkCredentials(credentials *model.Credentials) *APIError {
+    // Tokens take priority over passwords.
+    // Session tokens act just like a password (they have no scope).
+    if ((this.UserToken != "") && model.IsSessionToken(this.UserToken)) {
+        sessionCourseID := model.GetSessionCourseID(this.Course.GetID(), this.ServerUser);
+        if (!credentials.CheckSessionToken(this.UserEmail, sessionCourseID, this.UserToken)) {
+            return NewAuthBadRequestError("-045", this, "Bad Session Token");
+        }
+
+        return nil;
+    }
+
+    if (this.UserToken != "") {
+        this.Token = credentials.CheckToken(this.UserToken);
+        if (this.Token == nil) {
+            return NewAuthBadRequestError("-043", this, "Bad Token");
+        }
+
+        return nil;
+    }
+
+    if (!credentials.CheckPassword(this.UserPass)) {
+        return NewAuthBadRequestError("-014", this, "Bad Password");
+    }
+
+    return nil;
+}
@@ -1,9 +1,15 @@
 package core
 
 import (
+    "fmt"
+    "net/http"
+    "sync"
     "testing"
+    "time"
 
     "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
     "github.com/edulinq/autograder/util"
 )
 
@@ -70,3 +76,247 @@ func TestAuth(test *testing.T) {
         }
     }
 }
+
+func TestAuthToken(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    type readRequest struct {
+        APIRequestCourseUserContext
+        MinRoleOther
+        MinTokenScopeRead
+    }
+
+    type submitRequest struct {
+        APIRequestCourseUserContext
+        MinRoleOther
+        MinTokenScopeSubmit
+    }
+
+    type adminRequest struct {
+        APIRequestCourseUserContext
+        MinRoleOther
+    }
+
+    course := db.MustGetTestCourse();
+    user, err := db.GetUser(course, "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get user: '%v'.", err);
+    }
+
+    tokens := make(map[model.TokenScope]string);
+    for _, scope := range []model.TokenScope{model.TokenScopeRead, model.TokenScopeSubmit, model.TokenScopeAdmin} {
+        token, cleartext, err := model.NewAPIToken("test", scope, "");
+        if (err != nil) {
+            test.Fatalf("Failed to create token: '%v'.", err);
+        }
+
+        user.Tokens = append(user.Tokens, token);
+        tokens[scope] = cleartext;
+    }
+
+    err = db.SaveUser(course, user);
+    if (err != nil) {
+        test.Fatalf("Failed to save user: '%v'.", err);
+    }
+
+    testCases := []struct{token string; bearer bool; request string; locator string}{
+        {tokens[model.TokenScopeRead], false, "read", ""},
+        {tokens[model.TokenScopeRead], false, "submit", "-044"},
+        {tokens[model.TokenScopeRead], false, "admin", "-044"},
+
+        {tokens[model.TokenScopeSubmit], false, "read", ""},
+        {tokens[model.TokenScopeSubmit], false, "submit", ""},
+        {tokens[model.TokenScopeSubmit], false, "admin", "-044"},
+
+        {tokens[model.TokenScopeAdmin], false, "read", ""},
+        {tokens[model.TokenScopeAdmin], false, "submit", ""},
+        {tokens[model.TokenScopeAdmin], false, "admin", ""},
+
+        {tokens[model.TokenScopeAdmin], true, "admin", ""},
+        {tokens[model.TokenScopeRead], true, "admin", "-044"},
+
+        {tokens[model.TokenScopeAdmin] + "Z", false, "read", "-043"},
+        {tokens[model.TokenScopeAdmin] + "Z", true, "read", "-043"},
+        {"", false, "read", "-017"},
+    };
+
+    for i, testCase := range testCases {
+        courseUserContext := APIRequestCourseUserContext{
+            CourseID: "course101",
+            UserEmail: "student@test.com",
+        };
+
+        httpRequest := &http.Request{Header: http.Header{}};
+        if (testCase.bearer) {
+            httpRequest.Header.Set("Authorization", "Bearer " + testCase.token);
+        } else {
+            courseUserContext.UserToken = testCase.token;
+        }
+
+        var request any;
+        switch testCase.request {
+            case "read":
+                request = &readRequest{APIRequestCourseUserContext: courseUserContext};
+            case "submit":
+                request = &submitRequest{APIRequestCourseUserContext: courseUserContext};
+            default:
+                request = &adminRequest{APIRequestCourseUserContext: courseUserContext};
+        }
+
+        apiErr := ValidateAPIRequest(httpRequest, request, "");
+
+        if ((apiErr == nil) && (testCase.locator != "")) {
+            test.Errorf("Case %d: Expecting error '%s', but got no error.", i, testCase.locator);
+        } else if ((apiErr != nil) && (testCase.locator == "")) {
+            test.Errorf("Case %d: Expecting no error, but got '%s': '%v'.", i, apiErr.Locator, apiErr);
+        } else if ((apiErr != nil) && (testCase.locator != "") && (apiErr.Locator != testCase.locator)) {
+            test.Errorf("Case %d: Got a different error than expected. Expected: '%s', actual: '%s' -- '%v'.",
+                    i, testCase.locator, apiErr.Locator, apiErr);
+        }
+    }
+}
+
+func TestAuthLockout(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    type baseAPIRequest struct {
+        APIRequestCourseUserContext
+        MinRoleOther
+    }
+
+    oldThreshold := config.LOCKOUT_THRESHOLD.Get();
+    defer config.LOCKOUT_THRESHOLD.Set(oldThreshold);
+    config.LOCKOUT_THRESHOLD.Set(2);
+
+    oldIPThreshold := config.LOCKOUT_IP_THRESHOLD.Get();
+    defer config.LOCKOUT_IP_THRESHOLD.Set(oldIPThreshold);
+    config.LOCKOUT_IP_THRESHOLD.Set(5);
+
+    const ip = "192.0.2.1";
+    defer UnlockIP(ip);
+
+    testCases := []struct{email string; pass string; locator string}{
+        // A success clears any failures.
+        {"student@test.com", "Zstudent", "-014"},
+        {"student@test.com", "student",  ""},
+        {"student@test.com", "Zstudent", "-014"},
+
+        // The second failure in a row locks the account (even with the right password).
+        {"student@test.com", "Zstudent", "-014"},
+        {"student@test.com", "student",  "-048"},
+
+        // Other accounts are not affected.
+        {"grader@test.com",  "grader",   ""},
+
+        // Unknown users still count against the IP address.
+        {"ZZZ@test.com",     "student",  "-013"},
+
+        // The fifth failure from this IP locks the IP.
+        {"other@test.com",   "Zother",   "-014"},
+        {"grader@test.com",  "grader",   "-047"},
+    };
+
+    for i, testCase := range testCases {
+        request := baseAPIRequest{
+            APIRequestCourseUserContext: APIRequestCourseUserContext{
+                CourseID: "course101",
+                UserEmail: testCase.email,
+                UserPass: util.Sha256HexFromString(testCase.pass),
+            },
+        };
+
+        httpRequest := &http.Request{RemoteAddr: ip + ":1234", Header: http.Header{}};
+        apiErr := ValidateAPIRequest(httpRequest, &request, "");
+
+        if ((apiErr == nil) && (testCase.locator != "")) {
+            test.Fatalf("Case %d: Expecting error '%s', but got no error.", i, testCase.locator);
+        } else if ((apiErr != nil) && (testCase.locator == "")) {
+            test.Fatalf("Case %d: Expecting no error, but got '%s': '%v'.", i, apiErr.Locator, apiErr);
+        } else if ((apiErr != nil) && (testCase.locator != "") && (apiErr.Locator != testCase.locator)) {
+            test.Fatalf("Case %d: Got a different error than expected. Expected: '%s', actual: '%s' -- '%v'.",
+                    i, testCase.locator, apiErr.Locator, apiErr);
+        }
+    }
+
+    // The failures for a user are stored with the user.
+    user, err := db.GetUser(db.MustGetTestCourse(), "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get user: '%v'.", err);
+    }
+
+    if (!user.IsLocked(time.Now())) {
+        test.Fatalf("Locked user is not stored as locked.");
+    }
+
+    if (!UnlockIP(ip)) {
+        test.Fatalf("Locked IP was not unlocked.");
+    }
+
+    if (UnlockIP(ip)) {
+        test.Fatalf("IP was unlocked twice.");
+    }
+}
+
+func TestAuthConcurrentFailures(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    type baseAPIRequest struct {
+        APIRequestCourseUserContext
+        MinRoleOther
+    }
+
+    const numRequests = 20;
+
+    oldThreshold := config.LOCKOUT_THRESHOLD.Get();
+    defer config.LOCKOUT_THRESHOLD.Set(oldThreshold);
+    config.LOCKOUT_THRESHOLD.Set(numRequests * 2);
+
+    var waitGroup sync.WaitGroup;
+    for i := 0; i < numRequests; i++ {
+        ip := fmt.Sprintf("192.0.2.%d", (i + 10));
+        defer UnlockIP(ip);
+
+        waitGroup.Add(1);
+        go func() {
+            defer waitGroup.Done();
+
+            request := baseAPIRequest{
+                APIRequestCourseUserContext: APIRequestCourseUserContext{
+                    CourseID: "course101",
+                    UserEmail: "student@test.com",
+                    UserPass: util.Sha256HexFromString("Zstudent"),
+                },
+            };
+
+            httpRequest := &http.Request{RemoteAddr: ip + ":1234", Header: http.Header{}};
+            apiErr := ValidateAPIRequest(httpRequest, &request, "");
+            if ((apiErr == nil) || (apiErr.Locator != "-014")) {
+                test.Errorf("Expecting error '-014', but got '%v'.", apiErr);
+            }
+        }();
+    }
+
+    waitGroup.Wait();
+
+    user, err := db.GetUser(db.MustGetTestCourse(), "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get user: '%v'.", err);
+    }
+
+    serverUser, err := db.GetServerUser("student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get server user: '%v'.", err);
+    }
+
+    credentials := model.GetCredentials(user, serverUser);
+    if (credentials.LoginFailures == nil) {
+        test.Fatalf("No failed logins were stored.");
+    }
+
+    if (credentials.LoginFailures.Count != numRequests) {
+        test.Fatalf("Failed logins were lost. Expected: %d, actual: %d.", numRequests, credentials.LoginFailures.Count);
+    }
+}
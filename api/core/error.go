@@ -231,6 +231,26 @@ func NewBadPermissionsError(locator string, request *APIRequestCourseUserContext
     return err;
 }
 
+func NewBadTokenScopeError(locator string, request *APIRequestCourseUserContext, minScope model.TokenScope, internalMessage string) *APIError {
+    err := &APIError{
+        RequestID: request.RequestID,
+        Locator: locator,
+        Endpoint: request.Endpoint,
+        Timestamp: request.Timestamp,
+        LogLevel: log.LevelInfo,
+        HTTPStatus: HTTP_PERMISSIONS_ERROR,
+        InternalText: fmt.Sprintf("Insufficient Token Scope: '%s'.", internalMessage),
+        ResponseText: "Your API token does not have the scope required for the requested operation.",
+        CourseID: request.CourseID,
+        UserEmail: request.UserEmail,
+    };
+
+    err.Add("actual-scope", request.Token.Scope);
+    err.Add("min-required-scope", minScope);
+
+    return err;
+}
+
 func NewInternalError(locator string, request *APIRequestCourseUserContext, internalMessage string) *APIError {
     err := &APIError{
         RequestID: request.RequestID,
@@ -0,0 +1,188 @@
+package core
+
+// Failed logins are tracked per user (in the user's credentials) and per IP address (in memory).
+// Either one can lock out logins for a while (see model.LoginFailures).
+
+import (
+    "fmt"
+    "sync"
+    "time"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+)
+
+var (
+    ipFailuresLock sync.Mutex;
+    ipFailures map[string]*model.LoginFailures = make(map[string]*model.LoginFailures);
+
+    // Locks for updating a user's persisted login failures.
+    credentialsLocks sync.Map;
+)
+
+func isIPLocked(ip string, now time.Time) bool {
+    if (ip == "") {
+        return false;
+    }
+
+    ipFailuresLock.Lock();
+    defer ipFailuresLock.Unlock();
+
+    return ipFailures[ip].IsLocked(now);
+}
+
+// Record a failed login from an IP address.
+// Returns true if the address is now locked out.
+func recordIPFailure(ip string, now time.Time) bool {
+    if (ip == "") {
+        return false;
+    }
+
+    ipFailuresLock.Lock();
+    defer ipFailuresLock.Unlock();
+
+    // Forget old failures so the map does not grow forever.
+    for otherIP, failures := range ipFailures {
+        if (failures.IsStale(now)) {
+            delete(ipFailures, otherIP);
+        }
+    }
+
+    failures := ipFailures[ip];
+    if (failures == nil) {
+        failures = &model.LoginFailures{};
+        ipFailures[ip] = failures;
+    }
+
+    return failures.Record(now, config.LOCKOUT_IP_THRESHOLD.Get());
+}
+
+// Forget all failed logins from an IP address.
+// Returns true if there were any.
+func UnlockIP(ip string) bool {
+    ipFailuresLock.Lock();
+    defer ipFailuresLock.Unlock();
+
+    _, exists := ipFailures[ip];
+    delete(ipFailures, ip);
+
+    return exists;
+}
+
+// Record a failed login for this request.
+// The user (and credentials) may be nil if the user is not known.
+func (this *APIRequestCourseUserContext) recordFailedLogin(user *model.User, credentials *model.Credentials, locator string) {
+    this.recordFailedLoginForUser(this.UserEmail, user, this.ServerUser, credentials, locator);
+}
+
+// Record a failed login for some user (which may not be the user making this request, e.g., for user/auth).
+// The failure is also recorded against this request's IP address.
+// The user (and credentials) may be nil if the user is not known.
+func (this *APIRequestCourseUserContext) recordFailedLoginForUser(email string, user *model.User, serverUser *model.ServerUser,
+        credentials *model.Credentials, locator string) {
+    now := time.Now();
+
+    log.Info("Failed login.", log.NewAttr("security-event", "login-failure"), log.NewAttr("locator", locator),
+            this.Course, log.NewUserAttr(email), log.NewAttr("requester", this.UserEmail), log.NewAttr("ip", this.RemoteIP));
+
+    if (recordIPFailure(this.RemoteIP, now)) {
+        log.Warn("IP address locked out after repeated failed logins.", log.NewAttr("security-event", "ip-lockout"),
+                this.Course, log.NewUserAttr(email), log.NewAttr("requester", this.UserEmail), log.NewAttr("ip", this.RemoteIP));
+    }
+
+    if (credentials == nil) {
+        return;
+    }
+
+    err := updateCredentials(this.Course, user, serverUser, credentials, func(fresh *model.Credentials) bool {
+        if (fresh.RecordFailedLogin(now)) {
+            log.Warn("Account locked out after repeated failed logins.", log.NewAttr("security-event", "account-lockout"),
+                    log.NewAttr("failures", fresh.LoginFailures.Count), log.NewAttr("locked-until", fresh.LoginFailures.LockedUntil),
+                    this.Course, log.NewUserAttr(email), log.NewAttr("requester", this.UserEmail), log.NewAttr("ip", this.RemoteIP));
+        }
+
+        return true;
+    });
+    if (err != nil) {
+        log.Error("Failed to save failed login.", err, this.Course, log.NewUserAttr(email));
+    }
+}
+
+// Forget all failed logins for a user after a successful login.
+func clearFailedLogins(course *model.Course, user *model.User, serverUser *model.ServerUser, credentials *model.Credentials) error {
+    if (credentials.LoginFailures == nil) {
+        return nil;
+    }
+
+    return updateCredentials(course, user, serverUser, credentials, func(fresh *model.Credentials) bool {
+        return fresh.ClearFailedLogins();
+    });
+}
+
+// Apply an update to the latest stored credentials for a user, and save them if the update returns true.
+// Concurrent requests for the same user are serialized (and the credentials are re-read under the lock),
+// so no update is lost.
+// The login failures in the passed credentials are refreshed to match what was saved.
+func updateCredentials(course *model.Course, user *model.User, serverUser *model.ServerUser, credentials *model.Credentials,
+        update func(*model.Credentials) bool) error {
+    lock := lockCredentials(course, user, serverUser);
+    defer lock.Unlock();
+
+    var freshUser *model.User = nil;
+    var freshServerUser *model.ServerUser = nil;
+    var err error = nil;
+
+    if (serverUser != nil) {
+        freshServerUser, err = db.GetServerUser(serverUser.Email);
+        if (err != nil) {
+            return fmt.Errorf("Failed to get server user '%s': '%w'.", serverUser.Email, err);
+        }
+
+        if (freshServerUser == nil) {
+            return fmt.Errorf("Server user '%s' no longer exists.", serverUser.Email);
+        }
+    } else {
+        freshUser, err = db.GetUser(course, user.Email);
+        if (err != nil) {
+            return fmt.Errorf("Failed to get user '%s': '%w'.", user.Email, err);
+        }
+
+        if (freshUser == nil) {
+            return fmt.Errorf("User '%s' no longer exists.", user.Email);
+        }
+    }
+
+    freshCredentials := model.GetCredentials(freshUser, freshServerUser);
+    if (!update(freshCredentials)) {
+        credentials.LoginFailures = freshCredentials.LoginFailures;
+        return nil;
+    }
+
+    err = db.SaveCredentials(course, freshUser, freshServerUser);
+    if (err != nil) {
+        return err;
+    }
+
+    credentials.LoginFailures = freshCredentials.LoginFailures;
+    return nil;
+}
+
+// Server users share credentials across courses, so they are locked by email alone.
+func lockCredentials(course *model.Course, user *model.User, serverUser *model.ServerUser) *sync.Mutex {
+    key := "";
+    if (serverUser != nil) {
+        key = fmt.Sprintf("::%s", serverUser.Email);
+    } else {
+        key = fmt.Sprintf("%s::%s", course.GetID(), user.Email);
+    }
+
+    // Get the existing mutex, or store (and fetch) a new one.
+    val, _ := credentialsLocks.LoadOrStore(key, &sync.Mutex{});
+    lock := val.(*sync.Mutex);
+
+    lock.Lock();
+
+    return lock;
+}
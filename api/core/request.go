@@ -2,12 +2,16 @@ package core
 
 import (
     "fmt"
+    "net"
     "net/http"
+    "net/netip"
     "reflect"
+    "strings"
 
     "github.com/edulinq/autograder/common"
     "github.com/edulinq/autograder/config"
     "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/log"
     "github.com/edulinq/autograder/model"
     "github.com/edulinq/autograder/util"
 )
@@ -21,6 +25,8 @@ type APIRequest struct {
     RequestID string `json:"-"`
     Endpoint string `json:"-"`
     Timestamp common.Timestamp `json:"-"`
+    // The IP address the request came from (empty if unknown).
+    RemoteIP string `json:"-"`
 
     // This request is being used as part of a test.
     TestingMode bool `json:"-"`
@@ -33,11 +39,19 @@ type APIRequestCourseUserContext struct {
     CourseID string `json:"course-id"`
     UserEmail string `json:"user-email"`
     UserPass string `json:"user-pass"`
+    // An API token can be used instead of a password.
+    // It may also be sent as a bearer token in the "Authorization" header.
+    UserToken string `json:"user-token"`
 
     // These fields are filled out as the request is parsed,
     // before being sent to the handler.
     Course *model.Course
+    // The user's enrollment in this course (which holds their role).
     User *model.User
+    // The server user that holds this user's credentials (nil if the user does not have one).
+    ServerUser *model.ServerUser
+    // The token used to authenticate (nil if a password was used).
+    Token *model.APIToken
 }
 
 //Context for requests that need an assignment on top of a user/course.
@@ -78,8 +92,8 @@ func (this *APIRequestCourseUserContext) Validate(request any, endpoint string)
         return NewBadRequestError("-016", &this.APIRequest, "No user email specified.");
     }
 
-    if (this.UserPass == "") {
-        return NewBadRequestError("-017", &this.APIRequest, "No user password specified.");
+    if ((this.UserPass == "") && (this.UserToken == "")) {
+        return NewBadRequestError("-017", &this.APIRequest, "No user password or token specified.");
     }
 
     var err error;
@@ -107,6 +121,13 @@ func (this *APIRequestCourseUserContext) Validate(request any, endpoint string)
         return NewBadPermissionsError("-020", this, minRole, "Base API Request");
     }
 
+    if (this.Token != nil) {
+        minScope := getMinTokenScope(request);
+        if (this.Token.Scope < minScope) {
+            return NewBadTokenScopeError("-044", this, minScope, "Base API Request");
+        }
+    }
+
     return nil;
 }
 
@@ -148,7 +169,7 @@ func ValidateAPIRequest(request *http.Request, apiRequest any, endpoint string)
     }
 
     // Ensure the request has an request type embedded, and validate it.
-    foundRequestStruct, apiErr := validateRequestStruct(apiRequest, endpoint);
+    foundRequestStruct, apiErr := validateRequestStruct(apiRequest, endpoint, getBearerToken(request), getRemoteIP(request));
     if (apiErr != nil) {
         return apiErr;
     }
@@ -187,7 +208,8 @@ func CleanupAPIrequest(apiRequest ValidAPIRequest) error {
     return nil;
 }
 
-func validateRequestStruct(request any, endpoint string) (bool, *APIError) {
+// The bearer token (which may be empty) will be used if the request does not contain a token.
+func validateRequestStruct(request any, endpoint string, bearerToken string, remoteIP string) (bool, *APIError) {
     // Check all the fields (including embedded ones) for structures that we recognize as requests.
     foundRequestStruct := false;
 
@@ -205,6 +227,8 @@ func validateRequestStruct(request any, endpoint string) (bool, *APIError) {
             apiRequest := fieldValue.Interface().(APIRequest);
             foundRequestStruct = true;
 
+            apiRequest.RemoteIP = remoteIP;
+
             apiErr := apiRequest.Validate(request, endpoint);
             if (apiErr != nil) {
                 return false, apiErr;
@@ -216,6 +240,12 @@ func validateRequestStruct(request any, endpoint string) (bool, *APIError) {
             courseUserRequest := fieldValue.Interface().(APIRequestCourseUserContext);
             foundRequestStruct = true;
 
+            if (courseUserRequest.UserToken == "") {
+                courseUserRequest.UserToken = bearerToken;
+            }
+
+            courseUserRequest.RemoteIP = remoteIP;
+
             apiErr := courseUserRequest.Validate(request, endpoint);
             if (apiErr != nil) {
                 return false, apiErr;
@@ -227,6 +257,12 @@ func validateRequestStruct(request any, endpoint string) (bool, *APIError) {
             assignmentRequest := fieldValue.Interface().(APIRequestAssignmentContext);
             foundRequestStruct = true;
 
+            if (assignmentRequest.UserToken == "") {
+                assignmentRequest.UserToken = bearerToken;
+            }
+
+            assignmentRequest.RemoteIP = remoteIP;
+
             apiErr := assignmentRequest.Validate(request, endpoint);
             if (apiErr != nil) {
                 return false, apiErr;
@@ -239,6 +275,133 @@ func validateRequestStruct(request any, endpoint string) (bool, *APIError) {
     return foundRequestStruct, nil;
 }
 
+// Take a request (or any object),
+// go through all the fields and look for fields typed as the encoded MinTokenScope* fields.
+// Return the minimum amongst the found scopes,
+// requests without a scope field can only be made with admin tokens.
+func getMinTokenScope(request any) model.TokenScope {
+    reflectValue := reflect.ValueOf(request);
+
+    // Dereference any pointer.
+    if (reflectValue.Kind() == reflect.Pointer) {
+        reflectValue = reflectValue.Elem();
+    }
+
+    scope := model.TokenScope(model.TokenScopeAdmin);
+
+    for i := 0; i < reflectValue.NumField(); i++ {
+        fieldValue := reflectValue.Field(i);
+
+        if (fieldValue.Type() == reflect.TypeOf((*MinTokenScopeRead)(nil)).Elem()) {
+            scope = min(scope, model.TokenScopeRead);
+        } else if (fieldValue.Type() == reflect.TypeOf((*MinTokenScopeSubmit)(nil)).Elem()) {
+            scope = min(scope, model.TokenScopeSubmit);
+        }
+    }
+
+    return scope;
+}
+
+// Get the token from a "Authorization: Bearer <token>" header (or an empty string).
+func getBearerToken(request *http.Request) string {
+    if (request == nil) {
+        return "";
+    }
+
+    scheme, token, found := strings.Cut(strings.TrimSpace(request.Header.Get("Authorization")), " ");
+    if (!found || !strings.EqualFold(scheme, "Bearer")) {
+        return "";
+    }
+
+    return strings.TrimSpace(token);
+}
+
+// Get the IP address (without a port) that a request came from (or an empty string).
+// If the request came through trusted proxies (see config.WEB_TRUSTED_PROXIES),
+// then the X-Forwarded-For header is used (ignoring any addresses added by trusted proxies).
+func getRemoteIP(request *http.Request) string {
+    if (request == nil) {
+        return "";
+    }
+
+    host, _, err := net.SplitHostPort(request.RemoteAddr);
+    if (err != nil) {
+        host = request.RemoteAddr;
+    }
+
+    trustedProxies := getTrustedProxies();
+    if (len(trustedProxies) == 0) {
+        return host;
+    }
+
+    // Walk back from the closest hop, only trusting the header as far as it was written by trusted proxies.
+    forwarded := make([]string, 0);
+    for _, header := range request.Header.Values("X-Forwarded-For") {
+        forwarded = append(forwarded, strings.Split(header, ",")...);
+    }
+
+    ip := host;
+    for i := len(forwarded) - 1; i >= 0; i-- {
+        if (!isTrustedProxy(ip, trustedProxies)) {
+            break;
+        }
+
+        ip = strings.TrimSpace(forwarded[i]);
+    }
+
+    return ip;
+}
+
+// Parse config.WEB_TRUSTED_PROXIES.
+// Bad entries are logged and skipped.
+func getTrustedProxies() []netip.Prefix {
+    proxies := make([]netip.Prefix, 0);
+
+    for _, part := range strings.Split(config.WEB_TRUSTED_PROXIES.Get(), ",") {
+        part = strings.TrimSpace(part);
+        if (part == "") {
+            continue;
+        }
+
+        if (!strings.Contains(part, "/")) {
+            addr, err := netip.ParseAddr(part);
+            if (err != nil) {
+                log.Warn("Skipping bad trusted proxy address.", err, log.NewAttr("address", part));
+                continue;
+            }
+
+            proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()));
+            continue;
+        }
+
+        prefix, err := netip.ParsePrefix(part);
+        if (err != nil) {
+            log.Warn("Skipping bad trusted proxy range.", err, log.NewAttr("range", part));
+            continue;
+        }
+
+        proxies = append(proxies, prefix);
+    }
+
+    return proxies;
+}
+
+func isTrustedProxy(ip string, trustedProxies []netip.Prefix) bool {
+    addr, err := netip.ParseAddr(ip);
+    if (err != nil) {
+        return false;
+    }
+
+    addr = addr.Unmap();
+    for _, prefix := range trustedProxies {
+        if (prefix.Contains(addr)) {
+            return true;
+        }
+    }
+
+    return false;
+}
+
 // Take a request (or any object),
 // go through all the fields and look for fields typed as the encoded MinRole* fields.
 // Return the maximum amongst the found roles.
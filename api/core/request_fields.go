@@ -25,6 +25,12 @@ type MinRoleGrader bool;
 type MinRoleStudent bool;
 type MinRoleOther bool;
 
+// The minimum API token scope required encoded as a type so it can be embedded into a request struct.
+// Requests without one of these can only be made (with a token) using an admin-scoped token.
+// Requests authenticated with a password are not affected.
+type MinTokenScopeRead bool;
+type MinTokenScopeSubmit bool;
+
 // A request having a field of this type indicates that the users for the course should be automatically fetched.
 // The existence of this type in a struct also indicates that the request is at least a APIRequestCourseUserContext.
 type CourseUsers map[string]*model.User;
@@ -2,8 +2,10 @@ package core
 
 import (
     "fmt"
+    "net/http"
     "testing"
 
+    "github.com/edulinq/autograder/config"
     "github.com/edulinq/autograder/model"
     "github.com/edulinq/autograder/util"
 )
@@ -170,3 +172,45 @@ var invalidJSONTestCases []baseAPIRequestTestCase = []baseAPIRequestTestCase{
     baseAPIRequestTestCase{Payload: `{"course-id": "course101" "assignment-id": "hw0"}`},
     baseAPIRequestTestCase{Payload: `{"course-id": "course101", "assignment-id": "hw0}`},
 };
+
+func TestGetRemoteIP(test *testing.T) {
+    oldProxies := config.WEB_TRUSTED_PROXIES.Get();
+    defer config.WEB_TRUSTED_PROXIES.Set(oldProxies);
+
+    testCases := []struct{ proxies string; remoteAddr string; forwarded []string; expected string }{
+        // No trusted proxies, the header is ignored.
+        {"", "192.0.2.1:1234", nil, "192.0.2.1"},
+        {"", "192.0.2.1:1234", []string{"198.51.100.1"}, "192.0.2.1"},
+        {"", "192.0.2.1", nil, "192.0.2.1"},
+
+        // Requests not from a trusted proxy ignore the header.
+        {"10.0.0.1", "192.0.2.1:1234", []string{"198.51.100.1"}, "192.0.2.1"},
+
+        // Requests from a trusted proxy use the header.
+        {"10.0.0.1", "10.0.0.1:1234", []string{"198.51.100.1"}, "198.51.100.1"},
+        {"10.0.0.0/8", "10.0.0.1:1234", []string{"198.51.100.1"}, "198.51.100.1"},
+        {"10.0.0.0/8", "10.0.0.1:1234", []string{"198.51.100.1, 10.0.0.2"}, "198.51.100.1"},
+        {"10.0.0.0/8", "10.0.0.1:1234", []string{"198.51.100.1", "10.0.0.2"}, "198.51.100.1"},
+        {"ZZZ, 10.0.0.1", "10.0.0.1:1234", []string{"198.51.100.1"}, "198.51.100.1"},
+
+        // Clients cannot spoof addresses before the last untrusted hop.
+        {"10.0.0.1", "10.0.0.1:1234", []string{"203.0.113.1, 198.51.100.1"}, "198.51.100.1"},
+
+        // A trusted proxy with no header.
+        {"10.0.0.1", "10.0.0.1:1234", nil, "10.0.0.1"},
+    };
+
+    for i, testCase := range testCases {
+        config.WEB_TRUSTED_PROXIES.Set(testCase.proxies);
+
+        request := &http.Request{RemoteAddr: testCase.remoteAddr, Header: http.Header{}};
+        for _, value := range testCase.forwarded {
+            request.Header.Add("X-Forwarded-For", value);
+        }
+
+        actual := getRemoteIP(request);
+        if (testCase.expected != actual) {
+            test.Errorf("Case %d: Unexpected IP. Expected: '%s', Actual: '%s'.", i, testCase.expected, actual);
+        }
+    }
+}
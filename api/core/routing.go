@@ -122,6 +122,12 @@ func NewAPIRoute(pattern string, apiHandler any) *Route {
     return &Route{"POST", regexp.MustCompile("^" + pattern + "$"), handler};
 }
 
+// Send a standard API response (or error) from a route that is not an API endpoint (see NewRoute()),
+// e.g., a GET endpoint that browsers are sent to.
+func SendAPIResponseFromRoute(response http.ResponseWriter, content any, apiErr *APIError) error {
+    return sendAPIResponse(nil, response, content, apiErr, false);
+}
+
 func handleRedirect(target string, response http.ResponseWriter, request *http.Request) error {
     http.Redirect(response, request, target, 301);
     return nil;
@@ -0,0 +1,179 @@
+package core
+
+// Support for API endpoints that stream their responses as server-sent events.
+// Streaming endpoints are requested the same way as normal API endpoints (a POST with a "content" form value),
+// but respond with a "text/event-stream" body where each event's data is a JSON object.
+// Errors that happen before any events are sent are returned as a normal API response.
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "reflect"
+    "regexp"
+
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/util"
+)
+
+const (
+    STREAM_CONTENT_TYPE = "text/event-stream"
+
+    // The event sent if an error happens after the stream has started.
+    // The data will be an APIResponse.
+    STREAM_EVENT_ERROR = "error"
+)
+
+// A handler for streaming API endpoints.
+// Like APIHandler, the request type is found via reflection.
+type APIStreamHandler func(*any, *EventStream) *APIError;
+
+type EventStream struct {
+    response http.ResponseWriter
+    flusher http.Flusher
+    context context.Context
+    started bool
+}
+
+func NewAPIStreamRoute(pattern string, apiHandler any) *Route {
+    handler := func(response http.ResponseWriter, request *http.Request) (err error) {
+        stream := &EventStream{
+            response: response,
+            context: request.Context(),
+        };
+
+        // Recover from any panic.
+        defer func() {
+            value := recover();
+            if (value == nil) {
+                return;
+            }
+
+            log.Error("Recovered from a panic when handling an API stream endpoint.",
+                    log.NewAttr("value", value), log.NewAttr("endpoint", request.URL.Path));
+            apiErr := NewBareInternalError("-037", request.URL.Path, "Recovered from a panic when handling an API stream endpoint.").
+                    Add("value", value);
+
+            err = stream.sendError(nil, apiErr);
+        }();
+
+        err = handleAPIStreamEndpoint(response, request, apiHandler, stream);
+
+        return err;
+    }
+
+    return &Route{"POST", regexp.MustCompile("^" + pattern + "$"), handler};
+}
+
+// The context for the stream's request, which will be done if the client disconnects.
+func (this *EventStream) Context() context.Context {
+    return this.context;
+}
+
+// Send an event with the given type and data (which will be encoded as JSON).
+func (this *EventStream) Send(eventType string, data any) error {
+    payload, err := util.ToJSON(data);
+    if (err != nil) {
+        return fmt.Errorf("Could not serialize stream event: '%w'.", err);
+    }
+
+    if (!this.started) {
+        this.response.Header().Set("Content-Type", STREAM_CONTENT_TYPE);
+        this.response.Header().Set("Cache-Control", "no-cache");
+        this.response.WriteHeader(HTTP_STATUS_GOOD);
+
+        this.started = true;
+    }
+
+    _, err = fmt.Fprintf(this.response, "event: %s\ndata: %s\n\n", eventType, payload);
+    if (err != nil) {
+        return fmt.Errorf("Could not write stream event: '%w'.", err);
+    }
+
+    if (this.flusher != nil) {
+        this.flusher.Flush();
+    }
+
+    return nil;
+}
+
+// Send an error as a normal API response if the stream has not started, or as an error event if it has.
+func (this *EventStream) sendError(apiRequest ValidAPIRequest, apiErr *APIError) error {
+    if (!this.started) {
+        return sendAPIResponse(apiRequest, this.response, nil, apiErr, false);
+    }
+
+    apiErr.Log();
+
+    return this.Send(STREAM_EVENT_ERROR, apiErr.ToResponse());
+}
+
+func handleAPIStreamEndpoint(response http.ResponseWriter, request *http.Request, apiHandler any, stream *EventStream) error {
+    flusher, ok := response.(http.Flusher);
+    if (ok) {
+        stream.flusher = flusher;
+    }
+
+    // Ensure the handler looks good.
+    validAPIHandler, apiErr := validateAPIStreamHandler(request.URL.Path, apiHandler);
+    if (apiErr != nil) {
+        return stream.sendError(nil, apiErr);
+    }
+
+    // Get the actual request.
+    apiRequest, apiErr := createAPIRequest(request, validAPIHandler);
+    if (apiErr != nil) {
+        return stream.sendError(nil, apiErr);
+    }
+    defer CleanupAPIrequest(apiRequest);
+
+    // Execute the handler.
+    input := []reflect.Value{reflect.ValueOf(apiRequest), reflect.ValueOf(stream)};
+    output := reflect.ValueOf(validAPIHandler).Call(input);
+
+    apiErr = output[0].Interface().(*APIError);
+    if (apiErr != nil) {
+        return stream.sendError(apiRequest, apiErr);
+    }
+
+    return nil;
+}
+
+// Reflexively ensure that the api handler is of the correct type/format (e.g. looks like APIStreamHandler).
+func validateAPIStreamHandler(endpoint string, apiHandler any) (ValidAPIHandler, *APIError) {
+    reflectValue := reflect.ValueOf(apiHandler);
+    reflectType := reflect.TypeOf(apiHandler);
+
+    if (reflectValue.Kind() != reflect.Func) {
+        return nil, NewBareInternalError("-038", endpoint, "API stream handler is not a function.").
+                Add("kind", reflectValue.Kind().String());
+    }
+
+    funcInfo := getFuncInfo(apiHandler);
+
+    if (reflectType.NumIn() != 2) {
+        return nil, NewBareInternalError("-039", endpoint, "API stream handler does not have exactly 2 arguments.").
+                Add("num-in", reflectType.NumIn()).
+                Add("function-info", funcInfo);
+    }
+
+    if (reflectType.In(0).Kind() != reflect.Pointer) {
+        return nil, NewBareInternalError("-040", endpoint, "API stream handler's first argument is not a pointer.").
+                Add("kind", reflectType.In(0).Kind().String()).
+                Add("function-info", funcInfo);
+    }
+
+    if (reflectType.In(1) != reflect.TypeOf((*EventStream)(nil))) {
+        return nil, NewBareInternalError("-041", endpoint, "API stream handler's second argument is not a *EventStream.").
+                Add("type", reflectType.In(1).String()).
+                Add("function-info", funcInfo);
+    }
+
+    if ((reflectType.NumOut() != 1) || (reflectType.Out(0) != reflect.TypeOf((*APIError)(nil)))) {
+        return nil, NewBareInternalError("-042", endpoint, "API stream handler does not return exactly one *APIError.").
+                Add("num-out", reflectType.NumOut()).
+                Add("function-info", funcInfo);
+    }
+
+    return ValidAPIHandler(apiHandler), nil;
+}
@@ -3,6 +3,7 @@ package core
 import (
     "net/http/httptest"
     "os"
+    "strings"
     "testing"
 
     "github.com/edulinq/autograder/common"
@@ -61,24 +62,7 @@ func SendTestAPIRequest(test *testing.T, endpoint string, fields map[string]any)
 // The given role will choose the user (the test course has one user per role).
 func SendTestAPIRequestFull(test *testing.T, endpoint string, fields map[string]any, paths []string, role model.UserRole) *APIResponse {
     url := serverURL + endpoint;
-
-    email := model.GetRoleString(role) + "@test.com";
-    pass := util.Sha256HexFromString(model.GetRoleString(role));
-
-    content := map[string]any{
-        "course-id": "course101",
-        "assignment-id": "hw0",
-        "user-email": email,
-        "user-pass": pass,
-    };
-
-    for key, value := range fields {
-        content[key] = value;
-    }
-
-    form := map[string]string{
-        API_REQUEST_CONTENT_KEY: util.MustToJSON(content),
-    };
+    form := getTestRequestForm(fields, role);
 
     var responseText string;
     var err error;
@@ -101,3 +85,63 @@ func SendTestAPIRequestFull(test *testing.T, endpoint string, fields map[string]
 
     return &response;
 }
+
+// A single event from a streaming endpoint.
+type TestStreamEvent struct {
+    Type string
+    Data string
+}
+
+// Make a request to a streaming endpoint on the test server (see SendTestAPIRequestFull())
+// and return all the events once the stream is closed.
+// If the response is not a stream (e.g. there was an error), then it will be returned as a single event with an empty type.
+func SendTestAPIStreamRequest(test *testing.T, endpoint string, fields map[string]any, role model.UserRole) []TestStreamEvent {
+    url := serverURL + endpoint;
+    form := getTestRequestForm(fields, role);
+
+    responseText, headers, err := common.PostWithHeadersNoCheck(url, form, make(map[string][]string));
+    if (err != nil) {
+        test.Fatalf("API stream POST returned an error: '%v'.", err);
+    }
+
+    if (!strings.Contains(strings.Join(headers["Content-Type"], " "), STREAM_CONTENT_TYPE)) {
+        return []TestStreamEvent{TestStreamEvent{Data: responseText}};
+    }
+
+    events := make([]TestStreamEvent, 0);
+    for _, block := range strings.Split(strings.TrimSpace(responseText), "\n\n") {
+        event := TestStreamEvent{};
+
+        for _, line := range strings.Split(block, "\n") {
+            if (strings.HasPrefix(line, "event: ")) {
+                event.Type = strings.TrimPrefix(line, "event: ");
+            } else if (strings.HasPrefix(line, "data: ")) {
+                event.Data = strings.TrimPrefix(line, "data: ");
+            }
+        }
+
+        events = append(events, event);
+    }
+
+    return events;
+}
+
+func getTestRequestForm(fields map[string]any, role model.UserRole) map[string]string {
+    email := model.GetRoleString(role) + "@test.com";
+    pass := util.Sha256HexFromString(model.GetRoleString(role));
+
+    content := map[string]any{
+        "course-id": "course101",
+        "assignment-id": "hw0",
+        "user-email": email,
+        "user-pass": pass,
+    };
+
+    for key, value := range fields {
+        content[key] = value;
+    }
+
+    return map[string]string{
+        API_REQUEST_CONTENT_KEY: util.MustToJSON(content),
+    };
+}
@@ -15,6 +15,8 @@ type UserInfo struct {
     Name string `json:"name"`
     Role model.UserRole `json:"role"`
     LMSID string `json:"lms-id"`
+    Sections []string `json:"sections,omitempty"`
+    Groups []string `json:"groups,omitempty"`
 }
 
 type UserInfoWithPass struct {
@@ -28,6 +30,8 @@ func NewUserInfo(user *model.User) *UserInfo {
         Name: user.Name,
         Role: user.Role,
         LMSID: user.LMSID,
+        Sections: user.Sections,
+        Groups: user.Groups,
     };
 }
 
@@ -38,10 +42,12 @@ func (this *UserInfoWithPass) ToUsr() (*model.User, error) {
 
     user := model.User{
         Email: this.Email,
-        Pass: this.Pass,
+        Credentials: model.Credentials{Pass: this.Pass},
         Name: this.Name,
         Role: this.Role,
         LMSID: this.LMSID,
+        Sections: this.Sections,
+        Groups: this.Groups,
     };
 
     return &user, nil;
@@ -81,9 +87,26 @@ func UserInfoFromMap(data map[string]any) *UserInfo {
         Name: data["name"].(string),
         Role: model.GetRole(data["role"].(string)),
         LMSID: data["lms-id"].(string),
+        Sections: stringsFromAny(data["sections"]),
+        Groups: stringsFromAny(data["groups"]),
     };
 }
 
+// Returns nil if the value is not a list.
+func stringsFromAny(value any) []string {
+    rawValues, ok := value.([]any);
+    if (!ok) {
+        return nil;
+    }
+
+    values := make([]string, 0, len(rawValues));
+    for _, rawValue := range rawValues {
+        values = append(values, fmt.Sprintf("%v", rawValue));
+    }
+
+    return values;
+}
+
 func CompareUserInfo(a UserInfo, b UserInfo) int {
     return strings.Compare(a.Email, b.Email);
 }
@@ -0,0 +1,66 @@
+package extension
+
+import (
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+)
+
+type GrantRequest struct {
+    core.APIRequestAssignmentContext
+    core.MinRoleAdmin
+
+    TargetUser core.TargetUser `json:"target-email"`
+
+    DueDate common.Timestamp `json:"due-date"`
+    ExtraAttempts int `json:"extra-attempts"`
+    ExtraLateDays int `json:"extra-late-days"`
+    WaiveLatePenalty bool `json:"waive-late-penalty"`
+    Reason string `json:"reason"`
+}
+
+type GrantResponse struct {
+    FoundUser bool `json:"found-user"`
+    Extension *model.Extension `json:"extension"`
+}
+
+// Grant (or replace) a user's extension for an assignment.
+func HandleGrant(request *GrantRequest) (*GrantResponse, *core.APIError) {
+    response := GrantResponse{};
+
+    if (!request.TargetUser.Found) {
+        return &response, nil;
+    }
+
+    response.FoundUser = true;
+
+    extension := &model.Extension{
+        CourseID: request.Course.GetID(),
+        AssignmentID: request.Assignment.GetID(),
+        User: request.TargetUser.Email,
+        DueDate: request.DueDate,
+        ExtraAttempts: request.ExtraAttempts,
+        ExtraLateDays: request.ExtraLateDays,
+        WaiveLatePenalty: request.WaiveLatePenalty,
+        Reason: request.Reason,
+        GrantedBy: request.User.Email,
+        GrantedTime: common.NowTimestamp(),
+    };
+
+    err := extension.Validate();
+    if (err != nil) {
+        return nil, core.NewBadCourseRequestError("-901", &request.APIRequestCourseUserContext, "Invalid extension.").
+                Err(err).Assignment(request.Assignment.GetID()).Add("target-user", request.TargetUser.Email);
+    }
+
+    err = db.SaveExtension(request.Assignment, extension);
+    if (err != nil) {
+        return nil, core.NewInternalError("-902", &request.APIRequestCourseUserContext, "Failed to save extension.").
+                Err(err).Assignment(request.Assignment.GetID()).Add("target-user", request.TargetUser.Email);
+    }
+
+    response.Extension = extension;
+
+    return &response, nil;
+}
@@ -0,0 +1,77 @@
+package extension
+
+import (
+    "testing"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func TestGrant(test *testing.T) {
+    defer db.ResetForTesting();
+
+    testCases := []struct{role model.UserRole; target string; dueDate string; extraAttempts int; expectedLocator string; expectedFound bool}{
+        {model.RoleAdmin, "student@test.com", "2024-01-01T00:00:00Z", 1, "", true},
+        {model.RoleOwner, "student@test.com", "", 2, "", true},
+        {model.RoleAdmin, "ZZZ", "", 1, "", false},
+
+        {model.RoleAdmin, "student@test.com", "tomorrow", 0, "-901", true},
+        {model.RoleAdmin, "student@test.com", "", -1, "-901", true},
+
+        {model.RoleStudent, "student@test.com", "", 1, "-020", true},
+        {model.RoleGrader, "student@test.com", "", 1, "-020", true},
+    };
+
+    for i, testCase := range testCases {
+        db.ResetForTesting();
+
+        fields := map[string]any{
+            "target-email": testCase.target,
+            "due-date": testCase.dueDate,
+            "extra-attempts": testCase.extraAttempts,
+            "reason": "test",
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`extension/grant`), fields, nil, testCase.role);
+        if (!response.Success) {
+            if (testCase.expectedLocator == "") {
+                test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+            } else if (response.Locator != testCase.expectedLocator) {
+                test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.expectedLocator, response.Locator);
+            }
+
+            continue;
+        }
+
+        if (testCase.expectedLocator != "") {
+            test.Errorf("Case %d: Response is a success when it should not be: '%v'.", i, response);
+            continue;
+        }
+
+        var responseContent GrantResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if (responseContent.FoundUser != testCase.expectedFound) {
+            test.Errorf("Case %d: Unexpected found user. Expected: '%v', Actual: '%v'.", i, testCase.expectedFound, responseContent.FoundUser);
+            continue;
+        }
+
+        if (!testCase.expectedFound) {
+            continue;
+        }
+
+        extension, err := db.GetExtension(db.MustGetTestAssignment(), testCase.target);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to get extension: '%v'.", i, err);
+            continue;
+        }
+
+        if ((extension == nil) || (extension.ExtraAttempts != testCase.extraAttempts) ||
+                (string(extension.DueDate) != testCase.dueDate) || (extension.GrantedBy != model.GetRoleString(testCase.role) + "@test.com")) {
+            test.Errorf("Case %d: Unexpected stored extension: '%+v'.", i, extension);
+            continue;
+        }
+    }
+}
@@ -0,0 +1,39 @@
+package extension
+
+import (
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+)
+
+type ListRequest struct {
+    core.APIRequestCourseUserContext
+    core.MinRoleGrader
+    core.MinTokenScopeRead
+
+    // If set, only list extensions for this assignment.
+    TargetAssignment string `json:"target-assignment"`
+}
+
+type ListResponse struct {
+    Extensions []*model.Extension `json:"extensions"`
+}
+
+func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+    extensions, err := db.GetExtensions(request.Course);
+    if (err != nil) {
+        return nil, core.NewInternalError("-904", &request.APIRequestCourseUserContext, "Failed to get extensions.").Err(err);
+    }
+
+    response := ListResponse{
+        Extensions: make([]*model.Extension, 0, len(extensions)),
+    };
+
+    for _, extension := range extensions {
+        if ((request.TargetAssignment == "") || (extension.AssignmentID == request.TargetAssignment)) {
+            response.Extensions = append(response.Extensions, extension);
+        }
+    }
+
+    return &response, nil;
+}
@@ -0,0 +1,60 @@
+package extension
+
+import (
+    "testing"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func TestList(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    assignment := db.MustGetTestAssignment();
+    for _, email := range []string{"student@test.com", "other@test.com"} {
+        err := db.SaveExtension(assignment, &model.Extension{User: email, ExtraAttempts: 1});
+        if (err != nil) {
+            test.Fatalf("Failed to save extension: '%v'.", err);
+        }
+    }
+
+    testCases := []struct{role model.UserRole; targetAssignment string; permError bool; expectedUsers []string}{
+        {model.RoleGrader, "", false, []string{"other@test.com", "student@test.com"}},
+        {model.RoleAdmin, "hw0", false, []string{"other@test.com", "student@test.com"}},
+        {model.RoleAdmin, "zzz", false, []string{}},
+        {model.RoleStudent, "", true, nil},
+    };
+
+    for i, testCase := range testCases {
+        fields := map[string]any{
+            "target-assignment": testCase.targetAssignment,
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`extension/list`), fields, nil, testCase.role);
+        if (!response.Success) {
+            if (!testCase.permError) {
+                test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+            } else if (response.Locator != "-020") {
+                test.Errorf("Case %d: Incorrect error returned. Expected '-020', found '%s'.", i, response.Locator);
+            }
+
+            continue;
+        }
+
+        var responseContent ListResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        users := make([]string, 0, len(responseContent.Extensions));
+        for _, extension := range responseContent.Extensions {
+            users = append(users, extension.User);
+        }
+
+        if (util.MustToJSON(testCase.expectedUsers) != util.MustToJSON(users)) {
+            test.Errorf("Case %d: Unexpected extensions. Expected: '%v', Actual: '%v'.", i, testCase.expectedUsers, users);
+            continue;
+        }
+    }
+}
@@ -0,0 +1,38 @@
+package extension
+
+import (
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/db"
+)
+
+type RevokeRequest struct {
+    core.APIRequestAssignmentContext
+    core.MinRoleAdmin
+
+    TargetUser core.TargetUser `json:"target-email"`
+}
+
+type RevokeResponse struct {
+    FoundUser bool `json:"found-user"`
+    FoundExtension bool `json:"found-extension"`
+}
+
+func HandleRevoke(request *RevokeRequest) (*RevokeResponse, *core.APIError) {
+    response := RevokeResponse{};
+
+    if (!request.TargetUser.Found) {
+        return &response, nil;
+    }
+
+    response.FoundUser = true;
+
+    exists, err := db.RemoveExtension(request.Assignment, request.TargetUser.Email);
+    if (err != nil) {
+        return nil, core.NewInternalError("-903", &request.APIRequestCourseUserContext, "Failed to remove extension.").
+                Err(err).Assignment(request.Assignment.GetID()).Add("target-user", request.TargetUser.Email);
+    }
+
+    response.FoundExtension = exists;
+
+    return &response, nil;
+}
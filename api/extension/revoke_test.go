@@ -0,0 +1,65 @@
+package extension
+
+import (
+    "testing"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func TestRevoke(test *testing.T) {
+    defer db.ResetForTesting();
+
+    testCases := []struct{role model.UserRole; target string; permError bool; expected RevokeResponse}{
+        {model.RoleAdmin, "student@test.com", false, RevokeResponse{true, true}},
+        {model.RoleAdmin, "grader@test.com", false, RevokeResponse{true, false}},
+        {model.RoleAdmin, "ZZZ", false, RevokeResponse{false, false}},
+        {model.RoleGrader, "student@test.com", true, RevokeResponse{}},
+    };
+
+    for i, testCase := range testCases {
+        db.ResetForTesting();
+
+        assignment := db.MustGetTestAssignment();
+        err := db.SaveExtension(assignment, &model.Extension{User: "student@test.com", ExtraAttempts: 1});
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to save extension: '%v'.", i, err);
+        }
+
+        fields := map[string]any{
+            "target-email": testCase.target,
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`extension/revoke`), fields, nil, testCase.role);
+        if (!response.Success) {
+            if (!testCase.permError) {
+                test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+            } else if (response.Locator != "-020") {
+                test.Errorf("Case %d: Incorrect error returned. Expected '-020', found '%s'.", i, response.Locator);
+            }
+
+            continue;
+        }
+
+        var responseContent RevokeResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if (testCase.expected != responseContent) {
+            test.Errorf("Case %d: Unexpected result. Expected: '%+v', actual: '%+v'.", i, testCase.expected, responseContent);
+            continue;
+        }
+
+        extension, err := db.GetExtension(assignment, "student@test.com");
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to get extension: '%v'.", i, err);
+            continue;
+        }
+
+        if (testCase.expected.FoundExtension != (extension == nil)) {
+            test.Errorf("Case %d: Extension was not handled correctly: '%+v'.", i, extension);
+            continue;
+        }
+    }
+}
@@ -0,0 +1,17 @@
+package extension
+
+// All the API endpoints handled by this package.
+
+import (
+    "github.com/edulinq/autograder/api/core"
+)
+
+var routes []*core.Route = []*core.Route{
+    core.NewAPIRoute(core.NewEndpoint(`extension/grant`), HandleGrant),
+    core.NewAPIRoute(core.NewEndpoint(`extension/list`), HandleList),
+    core.NewAPIRoute(core.NewEndpoint(`extension/revoke`), HandleRevoke),
+};
+
+func GetRoutes() *[]*core.Route {
+    return &routes;
+}
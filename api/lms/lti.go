@@ -0,0 +1,133 @@
+package lms
+
+// Launching the autograder from an LMS with LTI 1.3 (see the lms/lti package).
+// Unlike other endpoints, these are not API requests:
+// the LMS sends the user's browser to the login endpoint (GET or POST),
+// which sends them back to the LMS, which posts a signed launch to the launch endpoint.
+// A successful launch starts a session for the user (like an OIDC login).
+
+import (
+    "net/http"
+    "net/url"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/lms/lti"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+type LTILaunchResponse struct {
+    CourseID string `json:"course-id"`
+    Email string `json:"user-email"`
+    // Empty if the launch was not for a specific assignment.
+    AssignmentID string `json:"assignment-id,omitempty"`
+
+    SessionToken string `json:"session-token"`
+    SessionExpiration common.Timestamp `json:"session-expiration"`
+}
+
+// Get the tool's public keys (a JWKS), which LMSs use to verify the tool's requests.
+func HandleLTIKeys(response http.ResponseWriter, request *http.Request) error {
+    keySet, err := lti.GetToolKeySet();
+    if (err != nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareInternalError("-407", request.URL.Path, "Failed to get LTI tool keys.").Err(err));
+    }
+
+    response.Header().Set("Content-Type", "application/json");
+    _, err = response.Write([]byte(util.MustToJSON(keySet)));
+    return err;
+}
+
+// Start a launch (the LMS sends the user here).
+func HandleLTILogin(response http.ResponseWriter, request *http.Request) error {
+    loginRequest := &lti.LoginRequest{
+        Issuer: request.FormValue("iss"),
+        LoginHint: request.FormValue("login_hint"),
+        MessageHint: request.FormValue("lti_message_hint"),
+        TargetLinkURI: request.FormValue("target_link_uri"),
+        ClientID: request.FormValue("client_id"),
+    };
+
+    authURL, err := lti.StartLogin(loginRequest);
+    if (err != nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareBadRequestError("-408", request.URL.Path, "Failed to start LTI launch.").Err(err).
+                Add("issuer", loginRequest.Issuer));
+    }
+
+    http.Redirect(response, request, authURL, http.StatusFound);
+    return nil;
+}
+
+// Finish a launch (the LMS posts the launch here).
+// On success, the user is sent to config.LTI_SUCCESS_URL (with the session and assignment in the URL's fragment)
+// or the session is returned as an LTILaunchResponse.
+func HandleLTILaunch(response http.ResponseWriter, request *http.Request) error {
+    endpoint := request.URL.Path;
+
+    if (request.PostFormValue("error") != "") {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareBadRequestError("-409", endpoint, "The LMS did not launch the user.").
+                Add("error", request.PostFormValue("error")).Add("error-description", request.PostFormValue("error_description")));
+    }
+
+    launch, err := lti.FinishLaunch(request.PostFormValue("state"), request.PostFormValue("id_token"));
+    if (err != nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareBadRequestError("-410", endpoint, "Failed to launch from the LMS.").Err(err));
+    }
+
+    courseID := launch.Course.GetID();
+    email := launch.User.Email;
+
+    serverUser, err := db.GetServerUser(email);
+    if (err != nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareInternalError("-411", endpoint, "Failed to get server user.").Err(err).Course(courseID).User(email));
+    }
+
+    result := LTILaunchResponse{
+        CourseID: courseID,
+        Email: email,
+    };
+
+    if (launch.Assignment != nil) {
+        result.AssignmentID = launch.Assignment.GetID();
+    }
+
+    credentials := model.GetCredentials(launch.User, serverUser);
+    sessionCourseID := model.GetSessionCourseID(courseID, serverUser);
+
+    result.SessionToken, result.SessionExpiration, err = credentials.NewSessionToken(email, sessionCourseID);
+    if (err != nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareInternalError("-412", endpoint, "Failed to create session token.").Err(err).Course(courseID).User(email));
+    }
+
+    log.Info("User launched from the LMS.", launch.Course, log.NewUserAttr(email), log.NewAssignmentAttr(result.AssignmentID));
+
+    successURL := config.LTI_SUCCESS_URL.Get();
+    if (successURL == "") {
+        return core.SendAPIResponseFromRoute(response, result, nil);
+    }
+
+    // Use the fragment so that the session is never sent to a server.
+    fragment := url.Values{};
+    fragment.Set("course-id", result.CourseID);
+    fragment.Set("user-email", result.Email);
+    fragment.Set("session-token", result.SessionToken);
+    fragment.Set("session-expiration", result.SessionExpiration.String());
+
+    if (result.AssignmentID != "") {
+        fragment.Set("assignment-id", result.AssignmentID);
+    }
+
+    // The launch was a POST, so the browser should GET the success URL.
+    http.Redirect(response, request, successURL + "#" + fragment.Encode(), http.StatusSeeOther);
+    return nil;
+}
@@ -0,0 +1,219 @@
+package lms
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "strings"
+    "testing"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/lms/lti"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/oidc"
+    "github.com/edulinq/autograder/util"
+)
+
+func TestLTILaunch(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := lti.StartMockPlatform();
+    defer platform.Close();
+
+    platform.MustRegisterTestCourse(false);
+
+    testCases := []struct{custom map[string]string; badNonce bool; expectedAssignment string; locator string}{
+        {map[string]string{}, false, "", ""},
+        {map[string]string{lti.CUSTOM_ASSIGNMENT_ID: "hw0"}, false, "hw0", ""},
+        {map[string]string{}, true, "", "-410"},
+        {map[string]string{lti.CUSTOM_ASSIGNMENT_ID: "zzz"}, false, "", "-410"},
+    };
+
+    for i, testCase := range testCases {
+        state, nonce := startTestLTILogin(test, platform);
+        if (testCase.badNonce) {
+            nonce = "bad-nonce";
+        }
+
+        claims := platform.GetClaims(nonce, "lms-grader@test.com", lti.TEST_LMS_COURSE_ID, testCase.custom);
+        recorder := sendTestLTILaunch(test, state, platform.SignLaunch(claims));
+
+        var response core.APIResponse;
+        util.MustJSONFromString(recorder.Body.String(), &response);
+
+        if (testCase.locator != "") {
+            if (response.Success) {
+                test.Errorf("Case %d: Response is a success when it should not be: '%v'.", i, response);
+            } else if (response.Locator != testCase.locator) {
+                test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator);
+            }
+
+            continue;
+        }
+
+        if (!response.Success) {
+            test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+            continue;
+        }
+
+        var responseContent LTILaunchResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if ((responseContent.CourseID != "course101") || (responseContent.Email != "grader@test.com") ||
+                (responseContent.AssignmentID != testCase.expectedAssignment)) {
+            test.Errorf("Case %d: Unexpected launch: '%+v'.", i, responseContent);
+            continue;
+        }
+
+        // The session can be used like a password.
+        fields := map[string]any{
+            "user-email": responseContent.Email,
+            "user-pass": "",
+            "user-token": responseContent.SessionToken,
+            "target-email": responseContent.Email,
+        };
+
+        userResponse := core.SendTestAPIRequestFull(test, core.NewEndpoint(`lms/user/get`), fields, nil, model.RoleGrader);
+        if (!userResponse.Success) {
+            test.Errorf("Case %d: Could not use the session: '%v'.", i, userResponse);
+        }
+    }
+}
+
+func TestLTILaunchSuccessURL(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := lti.StartMockPlatform();
+    defer platform.Close();
+
+    platform.MustRegisterTestCourse(false);
+
+    oldSuccessURL := config.LTI_SUCCESS_URL.Get();
+    defer config.LTI_SUCCESS_URL.Set(oldSuccessURL);
+    config.LTI_SUCCESS_URL.Set("http://autograder.test/static/index.html");
+
+    state, nonce := startTestLTILogin(test, platform);
+    custom := map[string]string{lti.CUSTOM_ASSIGNMENT_ID: "hw0"};
+    claims := platform.GetClaims(nonce, "lms-student@test.com", lti.TEST_LMS_COURSE_ID, custom);
+
+    recorder := sendTestLTILaunch(test, state, platform.SignLaunch(claims));
+    if (recorder.Code != http.StatusSeeOther) {
+        test.Fatalf("Unexpected status. Expected: %d, Actual: %d.", http.StatusSeeOther, recorder.Code);
+    }
+
+    target, err := url.Parse(recorder.Header().Get("Location"));
+    if (err != nil) {
+        test.Fatalf("Failed to parse success URL: '%v'.", err);
+    }
+
+    if (!strings.HasPrefix(target.String(), config.LTI_SUCCESS_URL.Get() + "#")) {
+        test.Fatalf("Not sent to the success URL: '%s'.", target.String());
+    }
+
+    fragment, err := url.ParseQuery(target.Fragment);
+    if (err != nil) {
+        test.Fatalf("Failed to parse fragment: '%v'.", err);
+    }
+
+    if ((fragment.Get("user-email") != "student@test.com") || (fragment.Get("assignment-id") != "hw0") ||
+            (fragment.Get("session-token") == "")) {
+        test.Fatalf("Unexpected fragment: '%v'.", fragment);
+    }
+}
+
+func TestLTILoginBadRequest(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := lti.StartMockPlatform();
+    defer platform.Close();
+
+    platform.MustRegisterTestCourse(false);
+
+    testCases := []string{
+        "",
+        "login_hint=some-hint",
+        "iss=" + url.QueryEscape("https://unknown.test") + "&login_hint=some-hint",
+    };
+
+    for i, testCase := range testCases {
+        request := httptest.NewRequest("GET", core.NewEndpoint(`lms/lti/login`) + "?" + testCase, nil);
+        recorder := httptest.NewRecorder();
+
+        err := HandleLTILogin(recorder, request);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to handle login: '%v'.", i, err);
+            continue;
+        }
+
+        var response core.APIResponse;
+        util.MustJSONFromString(recorder.Body.String(), &response);
+
+        if (response.Locator != "-408") {
+            test.Errorf("Case %d: Incorrect error returned. Expected '-408', found '%s'.", i, response.Locator);
+        }
+    }
+}
+
+func TestLTIKeys(test *testing.T) {
+    request := httptest.NewRequest("GET", core.NewEndpoint(`lms/lti/jwks`), nil);
+    recorder := httptest.NewRecorder();
+
+    err := HandleLTIKeys(recorder, request);
+    if (err != nil) {
+        test.Fatalf("Failed to handle keys: '%v'.", err);
+    }
+
+    var keySet oidc.JSONWebKeySet;
+    util.MustJSONFromString(recorder.Body.String(), &keySet);
+
+    if ((len(keySet.Keys) != 1) || (keySet.Keys[0].KeyType != "RSA") || (keySet.Keys[0].KeyID == "")) {
+        test.Fatalf("Unexpected key set: '%s'.", recorder.Body.String());
+    }
+}
+
+// Start a launch (with a POST, like most LMSs) and get the state and nonce that would be sent to the LMS.
+func startTestLTILogin(test *testing.T, platform *lti.MockPlatform) (string, string) {
+    form := url.Values{};
+    form.Set("iss", platform.Server.URL);
+    form.Set("login_hint", "some-hint");
+    form.Set("target_link_uri", "http://autograder.test");
+
+    request := httptest.NewRequest("POST", core.NewEndpoint(`lms/lti/login`), strings.NewReader(form.Encode()));
+    request.Header.Set("Content-Type", "application/x-www-form-urlencoded");
+    recorder := httptest.NewRecorder();
+
+    err := HandleLTILogin(recorder, request);
+    if (err != nil) {
+        test.Fatalf("Failed to handle login: '%v'.", err);
+    }
+
+    if (recorder.Code != http.StatusFound) {
+        test.Fatalf("Login did not redirect: '%s'.", recorder.Body.String());
+    }
+
+    authURL, err := url.Parse(recorder.Header().Get("Location"));
+    if (err != nil) {
+        test.Fatalf("Failed to parse auth URL: '%v'.", err);
+    }
+
+    return authURL.Query().Get("state"), authURL.Query().Get("nonce");
+}
+
+func sendTestLTILaunch(test *testing.T, state string, idToken string) *httptest.ResponseRecorder {
+    form := url.Values{};
+    form.Set("state", state);
+    form.Set("id_token", idToken);
+
+    request := httptest.NewRequest("POST", core.NewEndpoint(`lms/lti/launch`), strings.NewReader(form.Encode()));
+    request.Header.Set("Content-Type", "application/x-www-form-urlencoded");
+    recorder := httptest.NewRecorder();
+
+    err := HandleLTILaunch(recorder, request);
+    if (err != nil) {
+        test.Fatalf("Failed to handle launch: '%v'.", err);
+    }
+
+    return recorder;
+}
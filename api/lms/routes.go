@@ -10,6 +10,10 @@ var routes []*core.Route = []*core.Route{
     core.NewAPIRoute(core.NewEndpoint(`lms/user/get`), HandleUserGet),
     core.NewAPIRoute(core.NewEndpoint(`lms/sync`), HandleSync),
     core.NewAPIRoute(core.NewEndpoint(`lms/upload/scores`), HandleUploadScores),
+    core.NewRoute("GET", core.NewEndpoint(`lms/lti/jwks`), HandleLTIKeys),
+    core.NewRoute("POST", core.NewEndpoint(`lms/lti/launch`), HandleLTILaunch),
+    core.NewRoute("GET", core.NewEndpoint(`lms/lti/login`), HandleLTILogin),
+    core.NewRoute("POST", core.NewEndpoint(`lms/lti/login`), HandleLTILogin),
 };
 
 func GetRoutes() *[]*core.Route {
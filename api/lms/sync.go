@@ -17,6 +17,7 @@ type SyncRequest struct {
 type SyncResponse struct {
     SyncAvailable bool `json:"sync-available"`
     Users *core.SyncUsersInfo `json:"users"`
+    AssignmentPush *model.AssignmentPushResult `json:"assignment-push,omitempty"`
     Assignments *model.AssignmentSyncResult `json:"assignments"`
 }
 
@@ -40,6 +41,7 @@ func HandleSync(request *SyncRequest) (*SyncResponse, *core.APIError) {
 
     response.SyncAvailable = true;
     response.Users = core.NewSyncUsersInfo(result.UserSync);
+    response.AssignmentPush = result.AssignmentPush;
     response.Assignments = result.AssignmentSync;
 
     return &response, nil;
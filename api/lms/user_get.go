@@ -8,6 +8,7 @@ import (
 type UserGetRequest struct {
     core.APIRequestCourseUserContext
     core.MinRoleGrader
+    core.MinTokenScopeRead
 
     TargetUser core.TargetUser `json:"target-email"`
 }
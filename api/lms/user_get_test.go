@@ -11,11 +11,11 @@ import (
 
 func TestUserGet(test *testing.T) {
     testCases := []struct{ role model.UserRole; target string; permError bool; expected *core.UserInfo }{
-        {model.RoleGrader, "other@test.com", false, &core.UserInfo{"other@test.com", "other", model.RoleOther, "lms-other@test.com"}},
-        {model.RoleGrader, "student@test.com", false, &core.UserInfo{"student@test.com", "student", model.RoleStudent, "lms-student@test.com"}},
-        {model.RoleGrader, "grader@test.com", false, &core.UserInfo{"grader@test.com", "grader", model.RoleGrader, "lms-grader@test.com"}},
-        {model.RoleGrader, "admin@test.com", false, &core.UserInfo{"admin@test.com", "admin", model.RoleAdmin, "lms-admin@test.com"}},
-        {model.RoleGrader, "owner@test.com", false, &core.UserInfo{"owner@test.com", "owner", model.RoleOwner, "lms-owner@test.com"}},
+        {model.RoleGrader, "other@test.com", false, &core.UserInfo{"other@test.com", "other", model.RoleOther, "lms-other@test.com", nil, nil}},
+        {model.RoleGrader, "student@test.com", false, &core.UserInfo{"student@test.com", "student", model.RoleStudent, "lms-student@test.com", nil, nil}},
+        {model.RoleGrader, "grader@test.com", false, &core.UserInfo{"grader@test.com", "grader", model.RoleGrader, "lms-grader@test.com", nil, nil}},
+        {model.RoleGrader, "admin@test.com", false, &core.UserInfo{"admin@test.com", "admin", model.RoleAdmin, "lms-admin@test.com", nil, nil}},
+        {model.RoleGrader, "owner@test.com", false, &core.UserInfo{"owner@test.com", "owner", model.RoleOwner, "lms-owner@test.com", nil, nil}},
 
         {model.RoleStudent, "student@test.com", true, nil},
 
@@ -5,6 +5,7 @@ package api
 import (
     "github.com/edulinq/autograder/api/admin"
     "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/api/extension"
     "github.com/edulinq/autograder/api/lms"
     "github.com/edulinq/autograder/api/submission"
     "github.com/edulinq/autograder/api/user"
@@ -27,6 +28,7 @@ func GetRoutes() *[]*core.Route {
     routes = append(routes, *(user.GetRoutes())...);
     routes = append(routes, *(submission.GetRoutes())...);
     routes = append(routes, *(admin.GetRoutes())...);
+    routes = append(routes, *(extension.GetRoutes())...);
 
     return &routes;
 }
@@ -9,9 +9,14 @@ import (
 type FetchScoresRequest struct {
     core.APIRequestAssignmentContext
     core.MinRoleGrader
+    core.MinTokenScopeRead
 
     // Filter results to only users with this role.
     FilterRole model.UserRole `json:"filter-role"`
+
+    // Filter results to only users in this LMS section/group.
+    FilterSection string `json:"filter-section"`
+    FilterGroup string `json:"filter-group"`
 }
 
 type FetchScoresResponse struct {
@@ -25,5 +30,20 @@ func HandleFetchScores(request *FetchScoresRequest) (*FetchScoresResponse, *core
                 Err(err).Assignment(request.Assignment.GetID());
     }
 
+    filter := model.UserFilter{Section: request.FilterSection, Group: request.FilterGroup};
+    if (!filter.IsEmpty()) {
+        users, err := db.GetUsers(request.Course);
+        if (err != nil) {
+            return nil, core.NewInternalError("-613", &request.APIRequestCourseUserContext, "Failed to get users.").
+                    Err(err).Assignment(request.Assignment.GetID());
+        }
+
+        for email := range submissionInfos {
+            if (!filter.Matches(users[email])) {
+                delete(submissionInfos, email);
+            }
+        }
+    }
+
     return &FetchScoresResponse{submissionInfos}, nil;
 }
@@ -5,6 +5,7 @@ import (
     "testing"
 
     "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/db"
     "github.com/edulinq/autograder/model"
     "github.com/edulinq/autograder/util"
 )
@@ -76,3 +77,58 @@ func TestFetchScores(test *testing.T) {
         }
     }
 }
+
+func TestFetchScoresFilter(test *testing.T) {
+    defer db.ResetForTesting();
+
+    course := db.MustGetTestCourse();
+    user, err := db.GetUser(course, "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get user: '%v'.", err);
+    }
+
+    user.Sections = []string{"Lab 01"};
+
+    err = db.SaveUser(course, user);
+    if (err != nil) {
+        test.Fatalf("Failed to save user: '%v'.", err);
+    }
+
+    testCases := []struct{ section string; group string; ids map[string]string; }{
+        {"Lab 01", "", map[string]string{
+            "student@test.com": "course101::hw0::student@test.com::1697406272",
+        }},
+        {"Lab 02", "", map[string]string{}},
+        {"Lab 01", "Team A", map[string]string{}},
+    };
+
+    for i, testCase := range testCases {
+        fields := map[string]any{
+            "filter-section": testCase.section,
+            "filter-group": testCase.group,
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`submission/fetch/scores`), fields, nil, model.RoleGrader);
+        if (!response.Success) {
+            test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+            continue;
+        }
+
+        var responseContent FetchScoresResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        actualIDs := make(map[string]string, len(testCase.ids));
+        for email, info := range responseContent.SubmissionInfos {
+            id := "";
+            if (info != nil) {
+                id = info.ID;
+            }
+
+            actualIDs[email] = id;
+        }
+
+        if (!maps.Equal(testCase.ids, actualIDs)) {
+            test.Errorf("Case %d: Submission IDs do not match. Expected: '%+v', actual: '%+v'.", i, testCase.ids, actualIDs);
+        }
+    }
+}
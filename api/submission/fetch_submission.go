@@ -9,6 +9,7 @@ import (
 type FetchSubmissionRequest struct {
     core.APIRequestAssignmentContext
     core.MinRoleStudent
+    core.MinTokenScopeRead
 
     TargetUser core.TargetUserSelfOrGrader `json:"target-email"`
     TargetSubmission string `json:"target-submission"`
@@ -0,0 +1,71 @@
+package submission
+
+import (
+    "context"
+    "errors"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/grader"
+    "github.com/edulinq/autograder/log"
+)
+
+type ProgressRequest struct {
+    core.APIRequestAssignmentContext
+    core.MinRoleStudent
+    core.MinTokenScopeRead
+
+    JobID string `json:"job-id"`
+}
+
+// Stream the progress of a grading job (see grader.GradingEvent) as server-sent events.
+// Every event type matches a grader.GradingEventType,
+// except for the final "finished" event which has a StatusResponse as its data.
+func HandleProgress(request *ProgressRequest, stream *core.EventStream) *core.APIError {
+    if (request.JobID == "") {
+        return core.NewBadCourseRequestError("-610", &request.APIRequestCourseUserContext, "No job ID provided.").
+                Assignment(request.Assignment.GetID());
+    }
+
+    if (getVisibleJobStatus(&request.APIRequestAssignmentContext, request.JobID) == nil) {
+        return core.NewBadCourseRequestError("-611", &request.APIRequestCourseUserContext, "Unknown job.").
+                Assignment(request.Assignment.GetID()).Add("job-id", request.JobID);
+    }
+
+    start := 0;
+    for {
+        events, finished, err := grader.GetGradingJobEvents(stream.Context(), request.JobID, start);
+        if (errors.Is(err, context.Canceled)) {
+            // The client went away.
+            return nil;
+        }
+
+        if (err != nil) {
+            return core.NewInternalError("-612", &request.APIRequestCourseUserContext, "Failed to get grading progress.").
+                    Err(err).Assignment(request.Assignment.GetID()).Add("job-id", request.JobID);
+        }
+
+        for _, event := range events {
+            // The finished event is sent below (with the full outcome).
+            if (event.Type == grader.GRADING_EVENT_FINISHED) {
+                continue;
+            }
+
+            err = stream.Send(string(event.Type), event);
+            if (err != nil) {
+                log.Debug("Failed to send grading progress, client likely disconnected.", err, request.Assignment, request.User);
+                return nil;
+            }
+        }
+
+        start += len(events);
+
+        if (finished) {
+            break;
+        }
+    }
+
+    response := getStatusResponse(request.Assignment, getVisibleJobStatus(&request.APIRequestAssignmentContext, request.JobID));
+    stream.Send(string(grader.GRADING_EVENT_FINISHED), response);
+
+    return nil;
+}
@@ -0,0 +1,101 @@
+package submission
+
+import (
+    "path/filepath"
+    "testing"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/grader"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func TestProgress(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    oldDockerVal := config.DOCKER_DISABLE.Get();
+    config.DOCKER_DISABLE.Set(true);
+    defer config.DOCKER_DISABLE.Set(oldDockerVal);
+
+    assignment := db.MustGetTestAssignment();
+    assignment.Invocation = []string{"sh", "-c", `echo 'hello'; echo '{"name": "hw0", "questions": []}' > "$0"`, "<outpath>"};
+    err := db.SaveCourse(assignment.GetCourse());
+    if (err != nil) {
+        test.Fatalf("Failed to save assignment: '%v'.", err);
+    }
+
+    submissionPath := filepath.Join(assignment.GetSourceDir(), "test-submissions", "solution");
+
+    jobID, err := grader.QueueGrade(assignment, submissionPath, "student@test.com", "", false);
+    if (err != nil) {
+        test.Fatalf("Failed to queue job: '%v'.", err);
+    }
+
+    fields := map[string]any{
+        "job-id": jobID,
+    };
+
+    events := core.SendTestAPIStreamRequest(test, core.NewEndpoint(`submission/progress`), fields, model.RoleStudent);
+    if (len(events) == 0) {
+        test.Fatalf("Did not get any events.");
+    }
+
+    foundOutput := false;
+    for _, event := range events[0:(len(events) - 1)] {
+        if (event.Type == string(grader.GRADING_EVENT_FINISHED)) {
+            test.Fatalf("Got a finished event before the end of the stream.");
+        }
+
+        if (event.Type != string(grader.GRADING_EVENT_OUTPUT)) {
+            continue;
+        }
+
+        var gradingEvent grader.GradingEvent;
+        util.MustJSONFromString(event.Data, &gradingEvent);
+
+        if (gradingEvent.Line == "hello") {
+            foundOutput = true;
+        }
+    }
+
+    if (!foundOutput) {
+        test.Fatalf("Did not find the grader's output in the events: '%v'.", events);
+    }
+
+    lastEvent := events[len(events) - 1];
+    if (lastEvent.Type != string(grader.GRADING_EVENT_FINISHED)) {
+        test.Fatalf("Last event is not finished: '%v'.", lastEvent);
+    }
+
+    var responseContent StatusResponse;
+    util.MustJSONFromString(lastEvent.Data, &responseContent);
+
+    if ((responseContent.Status != JOB_STATUS_DONE) || (!responseContent.GradingSucess) || (responseContent.GradingInfo == nil)) {
+        test.Fatalf("Unexpected final status: '%v'.", responseContent);
+    }
+}
+
+func TestProgressUnknownJob(test *testing.T) {
+    fields := map[string]any{
+        "job-id": "not-a-job",
+    };
+
+    events := core.SendTestAPIStreamRequest(test, core.NewEndpoint(`submission/progress`), fields, model.RoleStudent);
+    if ((len(events) != 1) || (events[0].Type != "")) {
+        test.Fatalf("Did not get a single non-stream response: '%v'.", events);
+    }
+
+    var response core.APIResponse;
+    util.MustJSONFromString(events[0].Data, &response);
+
+    if (response.Success) {
+        test.Fatalf("Response is a success when it should not be: '%v'.", response);
+    }
+
+    if (response.Locator != "-611") {
+        test.Fatalf("Unexpected locator. Expected: '-611', Actual: '%s'.", response.Locator);
+    }
+}
@@ -0,0 +1,93 @@
+package submission
+
+import (
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/grader"
+    "github.com/edulinq/autograder/model"
+)
+
+const (
+    JOB_STATUS_QUEUED = "queued"
+    JOB_STATUS_RUNNING = "running"
+    JOB_STATUS_DONE = "done"
+    JOB_STATUS_FAILED = "failed"
+    JOB_STATUS_REJECTED = "rejected"
+)
+
+type StatusRequest struct {
+    core.APIRequestAssignmentContext
+    core.MinRoleStudent
+    core.MinTokenScopeRead
+
+    JobID string `json:"job-id"`
+}
+
+type StatusResponse struct {
+    FoundJob bool `json:"found-job"`
+    Status string `json:"status"`
+
+    // The (1-based) position in the grading queue, only set while the job is queued.
+    Position int `json:"position,omitempty"`
+
+    // The grading outcome (only filled once the job is finished).
+    SubmitResponse
+}
+
+func HandleStatus(request *StatusRequest) (*StatusResponse, *core.APIError) {
+    if (request.JobID == "") {
+        return nil, core.NewBadCourseRequestError("-609", &request.APIRequestCourseUserContext, "No job ID provided.").
+                Assignment(request.Assignment.GetID());
+    }
+
+    return getStatusResponse(request.Assignment, getVisibleJobStatus(&request.APIRequestAssignmentContext, request.JobID)), nil;
+}
+
+// Get the status of a job if the requesting user is allowed to see it (or nil).
+// Jobs for other assignments (or other users, for students) are treated as missing.
+func getVisibleJobStatus(request *core.APIRequestAssignmentContext, jobID string) *grader.GradingJobStatus {
+    status := grader.GetGradingJobStatus(jobID);
+    if (status == nil) {
+        return nil;
+    }
+
+    if ((status.CourseID != request.Course.GetID()) || (status.AssignmentID != request.Assignment.GetID())) {
+        return nil;
+    }
+
+    if ((status.User != request.User.Email) && (request.User.Role < model.RoleGrader)) {
+        return nil;
+    }
+
+    return status;
+}
+
+func getStatusResponse(assignment *model.Assignment, status *grader.GradingJobStatus) *StatusResponse {
+    response := StatusResponse{};
+
+    if (status == nil) {
+        return &response;
+    }
+
+    response.FoundJob = true;
+    response.JobID = status.ID;
+    response.Position = status.Position;
+
+    switch status.State {
+        case grader.JOB_STATE_QUEUED:
+            response.Status = JOB_STATUS_QUEUED;
+        case grader.JOB_STATE_RUNNING:
+            response.Status = JOB_STATUS_RUNNING;
+        default:
+            setGradingOutcome(&response.SubmitResponse, assignment, status.Result, status.Reject, status.Error);
+
+            if (response.Rejected) {
+                response.Status = JOB_STATUS_REJECTED;
+            } else if (response.GradingSucess) {
+                response.Status = JOB_STATUS_DONE;
+            } else {
+                response.Status = JOB_STATUS_FAILED;
+            }
+    }
+
+    return &response;
+}
@@ -0,0 +1,143 @@
+package submission
+
+import (
+    "path/filepath"
+    "testing"
+    "time"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/grader"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func TestAsyncSubmitStatus(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    oldDockerVal := config.DOCKER_DISABLE.Get();
+    config.DOCKER_DISABLE.Set(true);
+    defer config.DOCKER_DISABLE.Set(oldDockerVal);
+
+    // Use a grader that does not need anything installed.
+    assignment := db.MustGetTestAssignment();
+    assignment.Invocation = []string{"sh", "-c", `echo '{"name": "hw0", "questions": []}' > "$0"`, "<outpath>"};
+    err := db.SaveCourse(assignment.GetCourse());
+    if (err != nil) {
+        test.Fatalf("Failed to save assignment: '%v'.", err);
+    }
+
+    paths := []string{filepath.Join(assignment.GetSourceDir(), SUBMISSION_RELPATH)};
+    fields := map[string]any{
+        "async": true,
+    };
+
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`submission/submit`), fields, paths, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Response is not a success when it should be: '%v'.", response);
+    }
+
+    var submitContent SubmitResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &submitContent);
+
+    if (submitContent.JobID == "") {
+        test.Fatalf("Async submission did not return a job ID: '%v'.", submitContent);
+    }
+
+    if (submitContent.GradingSucess || submitContent.Rejected) {
+        test.Fatalf("Async submission returned an outcome: '%v'.", submitContent);
+    }
+
+    fields = map[string]any{
+        "job-id": submitContent.JobID,
+    };
+
+    var statusContent StatusResponse;
+    for i := 0; i < 100; i++ {
+        response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`submission/status`), fields, nil, model.RoleStudent);
+        if (!response.Success) {
+            test.Fatalf("Status response is not a success when it should be: '%v'.", response);
+        }
+
+        statusContent = StatusResponse{};
+        util.MustJSONFromString(util.MustToJSON(response.Content), &statusContent);
+
+        if ((statusContent.Status != JOB_STATUS_QUEUED) && (statusContent.Status != JOB_STATUS_RUNNING)) {
+            break;
+        }
+
+        time.Sleep(100 * time.Millisecond);
+    }
+
+    if (!statusContent.FoundJob) {
+        test.Fatalf("Job was not found: '%v'.", statusContent);
+    }
+
+    if ((statusContent.Status != JOB_STATUS_DONE) || (!statusContent.GradingSucess) || (statusContent.GradingInfo == nil)) {
+        test.Fatalf("Job did not finish successfully: '%v'.", statusContent);
+    }
+
+    // Graders can see other users' jobs.
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`submission/status`), fields, nil, model.RoleGrader);
+    util.MustJSONFromString(util.MustToJSON(response.Content), &statusContent);
+    if (!statusContent.FoundJob) {
+        test.Fatalf("Grader could not find a student's job: '%v'.", statusContent);
+    }
+
+    // Jobs are not visible from other assignments.
+    fields["course-id"] = "course101-with-zero-limit";
+    statusContent = StatusResponse{};
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`submission/status`), fields, nil, model.RoleGrader);
+    util.MustJSONFromString(util.MustToJSON(response.Content), &statusContent);
+    if (statusContent.FoundJob) {
+        test.Fatalf("Found a job from another course: '%v'.", statusContent);
+    }
+}
+
+func TestStatusOtherUser(test *testing.T) {
+    assignment := db.MustGetTestAssignment();
+    submissionPath := filepath.Join(assignment.GetSourceDir(), "test-submissions", "solution");
+
+    jobID, err := grader.QueueGrade(assignment, submissionPath, "someone-else@test.com", "", false);
+    if (err != nil) {
+        test.Fatalf("Failed to queue job: '%v'.", err);
+    }
+
+    grader.WaitForGradingJob(jobID);
+
+    fields := map[string]any{
+        "job-id": jobID,
+    };
+
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`submission/status`), fields, nil, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Response is not a success when it should be: '%v'.", response);
+    }
+
+    var responseContent StatusResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+    if (responseContent.FoundJob) {
+        test.Fatalf("Student found another user's job: '%v'.", responseContent);
+    }
+}
+
+func TestStatusUnknownJob(test *testing.T) {
+    fields := map[string]any{
+        "job-id": "not-a-job",
+    };
+
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`submission/status`), fields, nil, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Response is not a success when it should be: '%v'.", response);
+    }
+
+    var responseContent StatusResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+    if (responseContent.FoundJob) {
+        test.Fatalf("Found an unknown job: '%v'.", responseContent);
+    }
+}
@@ -1,18 +1,24 @@
 package submission
 
 import (
+    "fmt"
+
     "github.com/edulinq/autograder/api/core"
     "github.com/edulinq/autograder/grader"
-    "github.com/edulinq/autograder/log"
     "github.com/edulinq/autograder/model"
 )
 
 type SubmitRequest struct {
     core.APIRequestAssignmentContext
     core.MinRoleStudent
+    core.MinTokenScopeSubmit
     Files core.POSTFiles
 
     Message string `json:"message"`
+
+    // Return as soon as the submission is queued (instead of waiting for grading to finish).
+    // Use submission/status with the returned job ID to get the result.
+    Async bool `json:"async"`
 }
 
 type SubmitResponse struct {
@@ -20,37 +26,59 @@ type SubmitResponse struct {
     Message string `json:"message"`
 
     GradingSucess bool `json:"grading-success"`
+    TimedOut bool `json:"timed-out"`
+    OutOfMemory bool `json:"out-of-memory"`
     GradingInfo *model.GradingInfo `json:"result"`
+
+    // Only set for async submissions.
+    JobID string `json:"job-id,omitempty"`
 }
 
 func HandleSubmit(request *SubmitRequest) (*SubmitResponse, *core.APIError) {
     response := SubmitResponse{};
 
-    result, reject, err := grader.GradeDefault(request.Assignment, request.Files.TempDir, request.User.Email, request.Message);
-    if (err != nil) {
-        stdout := "";
-        stderr := "";
+    if (request.Async) {
+        jobID, err := grader.QueueGrade(request.Assignment, request.Files.TempDir, request.User.Email, request.Message, true);
+        if (err != nil) {
+            return nil, core.NewInternalError("-608", &request.APIRequestCourseUserContext, "Failed to queue submission for grading.").
+                    Err(err).Assignment(request.Assignment.GetID());
+        }
 
-        if ((result != nil) && (result.HasTextOutput())) {
-            stdout = result.Stdout;
-            stderr = result.Stderr;
+        response.JobID = jobID;
+        return &response, nil;
+    }
+
+    result, reject, err := grader.GradeQueued(request.Assignment, request.Files.TempDir, request.User.Email, request.Message);
+    setGradingOutcome(&response, request.Assignment, result, reject, err);
+
+    return &response, nil;
+}
+
+// Fill in a response with the outcome of grading a submission.
+func setGradingOutcome(response *SubmitResponse, assignment *model.Assignment,
+        result *model.GradingResult, reject grader.RejectReason, err error) {
+    if (err != nil) {
+        if ((result != nil) && (result.TimedOut)) {
+            response.TimedOut = true;
+            response.Message = fmt.Sprintf("Grading took longer than the allowed %s and was stopped. Check your submission for infinite loops or very slow code.",
+                    assignment.GetImageInfo().GetTimeout());
         }
 
-        log.Info("Submission grading failed.", err, request.Assignment, log.NewAttr("stdout", stdout), log.NewAttr("stderr", stderr), request.User);
+        if ((result != nil) && (result.OutOfMemory)) {
+            response.OutOfMemory = true;
+            response.Message = fmt.Sprintf("Grading used more than the allowed %d MB of memory and was stopped.",
+                    assignment.GetImageInfo().ResourceLimits.GetEffectiveLimits().MemoryMB);
+        }
 
-        return &response, nil;
+        return;
     }
 
     if (reject != nil) {
-        log.Debug("Submission rejected.", request.Assignment, log.NewAttr("reason", reject.String()), log.NewAttr("request", request), request.User);
-
         response.Rejected = true;
         response.Message = reject.String();
-        return &response, nil;
+        return;
     }
 
     response.GradingSucess = true;
     response.GradingInfo = result.Info;
-
-    return &response, nil;
 }
@@ -28,18 +28,18 @@ func TestUserAdd(test *testing.T) {
             model.RoleAdmin, false,
             false, false, true, false,
             []*core.UserInfoWithPass{
-                &core.UserInfoWithPass{core.UserInfo{"add@test.com", "add", model.RoleAdmin, "add@test.com"}, ""},
-                &core.UserInfoWithPass{core.UserInfo{"student@test.com", "new name", model.RoleStudent, ""}, ""},
+                &core.UserInfoWithPass{core.UserInfo{"add@test.com", "add", model.RoleAdmin, "add@test.com", nil, nil}, ""},
+                &core.UserInfoWithPass{core.UserInfo{"student@test.com", "new name", model.RoleStudent, "", nil, nil}, ""},
             },
             AddResponse{
                 SyncUsersInfo: core.SyncUsersInfo{
                     Add: []*core.UserInfo{
-                        &core.UserInfo{"add@test.com", "add", model.RoleAdmin, "lms-add@test.com"},
+                        &core.UserInfo{"add@test.com", "add", model.RoleAdmin, "lms-add@test.com", nil, nil},
                     },
                     Mod: []*core.UserInfo{},
                     Del: []*core.UserInfo{},
                     Skip: []*core.UserInfo{
-                        &core.UserInfo{"student@test.com", "student", model.RoleStudent, "lms-student@test.com"},
+                        &core.UserInfo{"student@test.com", "student", model.RoleStudent, "lms-student@test.com", nil, nil},
                     },
                     Unchanged: []*core.UserInfo{},
                 },
@@ -53,9 +53,9 @@ func TestUserAdd(test *testing.T) {
             model.RoleAdmin, false,
             false, false, true, false,
             []*core.UserInfoWithPass{
-                &core.UserInfoWithPass{core.UserInfo{"", "", model.RoleStudent, ""}, ""},
-                &core.UserInfoWithPass{core.UserInfo{"owner@test.com", "new name", model.RoleOwner, ""}, ""},
-                &core.UserInfoWithPass{core.UserInfo{"owner@test.com", "", model.RoleAdmin, ""}, ""},
+                &core.UserInfoWithPass{core.UserInfo{"", "", model.RoleStudent, "", nil, nil}, ""},
+                &core.UserInfoWithPass{core.UserInfo{"owner@test.com", "new name", model.RoleOwner, "", nil, nil}, ""},
+                &core.UserInfoWithPass{core.UserInfo{"owner@test.com", "", model.RoleAdmin, "", nil, nil}, ""},
             },
             AddResponse{
                 SyncUsersInfo: core.SyncUsersInfo{
@@ -87,18 +87,18 @@ func TestUserAdd(test *testing.T) {
             model.RoleAdmin, false,
             false, false, true, true,
             []*core.UserInfoWithPass{
-                &core.UserInfoWithPass{core.UserInfo{"add@test.com", "add", model.RoleAdmin, ""}, ""},
-                &core.UserInfoWithPass{core.UserInfo{"student@test.com", "new name", model.RoleStudent, ""}, ""},
+                &core.UserInfoWithPass{core.UserInfo{"add@test.com", "add", model.RoleAdmin, "", nil, nil}, ""},
+                &core.UserInfoWithPass{core.UserInfo{"student@test.com", "new name", model.RoleStudent, "", nil, nil}, ""},
             },
             AddResponse{
                 SyncUsersInfo: core.SyncUsersInfo{
                     Add: []*core.UserInfo{
-                        &core.UserInfo{"add@test.com", "add", model.RoleAdmin, ""},
+                        &core.UserInfo{"add@test.com", "add", model.RoleAdmin, "", nil, nil},
                     },
                     Mod: []*core.UserInfo{},
                     Del: []*core.UserInfo{},
                     Skip: []*core.UserInfo{
-                        &core.UserInfo{"student@test.com", "student", model.RoleStudent, ""},
+                        &core.UserInfo{"student@test.com", "student", model.RoleStudent, "", nil, nil},
                     },
                     Unchanged: []*core.UserInfo{},
                 },
@@ -2,11 +2,15 @@ package user
 
 import (
     "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
 )
 
 type AuthRequest struct {
     core.APIRequestCourseUserContext
     core.MinRoleOther
+    core.MinTokenScopeRead
 
     TargetUser core.TargetUser `json:"target-email"`
     TargetPass core.NonEmptyString `json:"target-pass"`
@@ -15,8 +19,14 @@ type AuthRequest struct {
 type AuthResponse struct {
     FoundUser bool `json:"found-user"`
     AuthSuccess bool `json:"auth-success"`
+
+    // On a successful auth, a session token for the target user that can be used instead of their password.
+    // Sessions for server users are good for every course.
+    SessionToken string `json:"session-token,omitempty"`
+    SessionExpiration common.Timestamp `json:"session-expiration,omitempty"`
 }
 
+// Check a user's password and start a session for them if it is correct.
 func HandleAuth(request *AuthRequest) (*AuthResponse, *core.APIError) {
     response := AuthResponse{};
 
@@ -25,7 +35,32 @@ func HandleAuth(request *AuthRequest) (*AuthResponse, *core.APIError) {
     }
 
     response.FoundUser = true;
-    response.AuthSuccess = request.TargetUser.User.CheckPassword(string(request.TargetPass));
+
+    serverUser, err := db.GetServerUser(request.TargetUser.Email);
+    if (err != nil) {
+        return nil, core.NewInternalError("-823", &request.APIRequestCourseUserContext,
+                "Failed to get server user.").Err(err).Add("target-user", request.TargetUser.Email);
+    }
+
+    // Checking the password (and recording failures) follows the same lockout rules as logging in.
+    authSuccess, apiErr := request.CheckTargetPassword(request.TargetUser.User, serverUser, string(request.TargetPass));
+    if (apiErr != nil) {
+        return nil, apiErr;
+    }
+
+    response.AuthSuccess = authSuccess;
+    if (!response.AuthSuccess) {
+        return &response, nil;
+    }
+
+    credentials := model.GetCredentials(request.TargetUser.User, serverUser);
+
+    sessionCourseID := model.GetSessionCourseID(request.Course.GetID(), serverUser);
+    response.SessionToken, response.SessionExpiration, err = credentials.NewSessionToken(request.TargetUser.Email, sessionCourseID);
+    if (err != nil) {
+        return nil, core.NewInternalError("-812", &request.APIRequestCourseUserContext,
+                "Failed to create session token.").Err(err).Add("target-user", request.TargetUser.Email);
+    }
 
     return &response, nil;
 }
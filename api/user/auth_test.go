@@ -1,34 +1,41 @@
 package user
 
 import (
+    "net/http"
     "testing"
+    "time"
 
     "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
     "github.com/edulinq/autograder/model"
     "github.com/edulinq/autograder/util"
 )
 
 func TestUserAuth(test *testing.T) {
+    // Failed auths are recorded against the target users.
+    defer db.ResetForTesting();
+
     testCases := []struct{ role model.UserRole; email string; pass string; expected AuthResponse }{
-        {model.RoleGrader, "other@test.com",   "other",   AuthResponse{true, true}},
-        {model.RoleGrader, "student@test.com", "student", AuthResponse{true, true}},
-        {model.RoleGrader, "grader@test.com",  "grader",  AuthResponse{true, true}},
-        {model.RoleGrader, "admin@test.com",   "admin",   AuthResponse{true, true}},
-        {model.RoleGrader, "owner@test.com",   "owner",   AuthResponse{true, true}},
-
-        {model.RoleGrader, "other@test.com",   "ZZZ", AuthResponse{true, false}},
-        {model.RoleGrader, "student@test.com", "ZZZ", AuthResponse{true, false}},
-        {model.RoleGrader, "grader@test.com",  "ZZZ", AuthResponse{true, false}},
-        {model.RoleGrader, "admin@test.com",   "ZZZ", AuthResponse{true, false}},
-        {model.RoleGrader, "owner@test.com",   "ZZZ", AuthResponse{true, false}},
-
-        {model.RoleOther,   "student@test.com", "student", AuthResponse{true, true}},
-        {model.RoleStudent, "student@test.com", "student", AuthResponse{true, true}},
-        {model.RoleGrader,  "student@test.com", "student", AuthResponse{true, true}},
-        {model.RoleAdmin,   "student@test.com", "student", AuthResponse{true, true}},
-        {model.RoleOwner,   "student@test.com", "student", AuthResponse{true, true}},
-
-        {model.RoleGrader, "ZZZ", "ZZZ", AuthResponse{false, false}},
+        {model.RoleGrader, "other@test.com",   "other",   AuthResponse{FoundUser: true, AuthSuccess: true}},
+        {model.RoleGrader, "student@test.com", "student", AuthResponse{FoundUser: true, AuthSuccess: true}},
+        {model.RoleGrader, "grader@test.com",  "grader",  AuthResponse{FoundUser: true, AuthSuccess: true}},
+        {model.RoleGrader, "admin@test.com",   "admin",   AuthResponse{FoundUser: true, AuthSuccess: true}},
+        {model.RoleGrader, "owner@test.com",   "owner",   AuthResponse{FoundUser: true, AuthSuccess: true}},
+
+        {model.RoleGrader, "other@test.com",   "ZZZ", AuthResponse{FoundUser: true, AuthSuccess: false}},
+        {model.RoleGrader, "student@test.com", "ZZZ", AuthResponse{FoundUser: true, AuthSuccess: false}},
+        {model.RoleGrader, "grader@test.com",  "ZZZ", AuthResponse{FoundUser: true, AuthSuccess: false}},
+        {model.RoleGrader, "admin@test.com",   "ZZZ", AuthResponse{FoundUser: true, AuthSuccess: false}},
+        {model.RoleGrader, "owner@test.com",   "ZZZ", AuthResponse{FoundUser: true, AuthSuccess: false}},
+
+        {model.RoleOther,   "student@test.com", "student", AuthResponse{FoundUser: true, AuthSuccess: true}},
+        {model.RoleStudent, "student@test.com", "student", AuthResponse{FoundUser: true, AuthSuccess: true}},
+        {model.RoleGrader,  "student@test.com", "student", AuthResponse{FoundUser: true, AuthSuccess: true}},
+        {model.RoleAdmin,   "student@test.com", "student", AuthResponse{FoundUser: true, AuthSuccess: true}},
+        {model.RoleOwner,   "student@test.com", "student", AuthResponse{FoundUser: true, AuthSuccess: true}},
+
+        {model.RoleGrader, "ZZZ", "ZZZ", AuthResponse{FoundUser: false, AuthSuccess: false}},
     };
 
     for i, testCase := range testCases {
@@ -46,9 +53,235 @@ func TestUserAuth(test *testing.T) {
         var responseContent AuthResponse;
         util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
 
+        if (responseContent.AuthSuccess != (responseContent.SessionToken != "")) {
+            test.Errorf("Case %d: A session token should be given out iff auth is successful: '%+v'.", i, responseContent);
+            continue;
+        }
+
+        responseContent.SessionToken = "";
+        responseContent.SessionExpiration = "";
+
         if (testCase.expected != responseContent) {
             test.Errorf("Case %d: Unexpected result. Expected: '%+v', actual: '%+v'.", i, testCase.expected, responseContent);
             continue;
         }
     }
 }
+
+func TestUserAuthSession(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    startSession := func() string {
+        fields := map[string]any{
+            "target-email": "student@test.com",
+            "target-pass": util.Sha256HexFromString("student"),
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/auth`), fields, nil, model.RoleStudent);
+        if (!response.Success) {
+            test.Fatalf("Failed to auth: '%v'.", response);
+        }
+
+        var responseContent AuthResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if (responseContent.SessionToken == "") {
+            test.Fatalf("Did not get a session token.");
+        }
+
+        return responseContent.SessionToken;
+    };
+
+    // Returns true if the session could be used.
+    useSession := func(token string) bool {
+        fields := map[string]any{
+            "user-pass": "",
+            "user-token": token,
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/list`), fields, nil, model.RoleStudent);
+        if (!response.Success && (response.HTTPStatus != http.StatusUnauthorized)) {
+            test.Fatalf("Unexpected failure when using a session: '%v'.", response);
+        }
+
+        return response.Success;
+    };
+
+    token := startSession();
+    if (!useSession(token)) {
+        test.Fatalf("Could not use a new session.");
+    }
+
+    if (useSession(token + "A")) {
+        test.Fatalf("Could use a tampered session.");
+    }
+
+    // Logging out ends the session.
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/logout`), nil, nil, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Failed to log out: '%v'.", response);
+    }
+
+    if (useSession(token)) {
+        test.Fatalf("Could use a session after logging out.");
+    }
+
+    // Changing the password ends the session.
+    token = startSession();
+
+    fields := map[string]any{
+        "target-email": "student@test.com",
+        "new-pass": util.Sha256HexFromString("student"),
+    };
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/change/pass`), fields, nil, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Failed to change password: '%v'.", response);
+    }
+
+    if (useSession(token)) {
+        test.Fatalf("Could use a session after changing the password.");
+    }
+
+    if (!useSession(startSession())) {
+        test.Fatalf("Could not use a session started after changing the password.");
+    }
+}
+
+func TestUserAuthServerUser(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    serverUser, err := db.GetServerUser("student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get server user: '%v'.", err);
+    }
+
+    if (serverUser != nil) {
+        test.Fatalf("Server user exists before migration.");
+    }
+
+    _, err = db.MigrateToServerUsers(false);
+    if (err != nil) {
+        test.Fatalf("Failed to migrate: '%v'.", err);
+    }
+
+    serverUser, err = db.GetServerUser("student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get server user: '%v'.", err);
+    }
+
+    err = serverUser.SetPassword(util.Sha256HexFromString("server-pass"));
+    if (err != nil) {
+        test.Fatalf("Failed to set password: '%v'.", err);
+    }
+
+    err = db.SaveServerUser(serverUser);
+    if (err != nil) {
+        test.Fatalf("Failed to save server user: '%v'.", err);
+    }
+
+    // The server user's password is used instead of the course user's password.
+    testCases := []struct{ pass string; expected bool }{
+        {"student", false},
+        {"server-pass", true},
+    };
+
+    var sessionToken string;
+
+    for i, testCase := range testCases {
+        fields := map[string]any{
+            "target-email": "student@test.com",
+            "target-pass": util.Sha256HexFromString(testCase.pass),
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/auth`), fields, nil, model.RoleGrader);
+        if (!response.Success) {
+            test.Fatalf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+        }
+
+        var responseContent AuthResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if (responseContent.AuthSuccess != testCase.expected) {
+            test.Fatalf("Case %d: Unexpected auth result. Expected: '%v', Actual: '%v'.", i, testCase.expected, responseContent.AuthSuccess);
+        }
+
+        if (responseContent.AuthSuccess) {
+            sessionToken = responseContent.SessionToken;
+        }
+    }
+
+    // A server user's session (started in one course) can be used in any course they are enrolled in.
+    for _, courseID := range []string{"course101", "course-languages"} {
+        fields := map[string]any{
+            "course-id": courseID,
+            "user-email": "student@test.com",
+            "user-pass": "",
+            "user-token": sessionToken,
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/list`), fields, nil, model.RoleStudent);
+        if (!response.Success) {
+            test.Fatalf("Could not use a server user's session in course '%s': '%v'.", courseID, response);
+        }
+    }
+}
+
+func TestUserAuthLockout(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    oldThreshold := config.LOCKOUT_THRESHOLD.Get();
+    defer config.LOCKOUT_THRESHOLD.Set(oldThreshold);
+    config.LOCKOUT_THRESHOLD.Set(2);
+
+    testCases := []struct{ pass string; locator string; expected bool }{
+        // A success clears any failures.
+        {"ZZZ", "", false},
+        {"student", "", true},
+        {"ZZZ", "", false},
+
+        // The second failure in a row locks the target account (even with the right password).
+        {"ZZZ", "", false},
+        {"student", "-050", false},
+    };
+
+    for i, testCase := range testCases {
+        fields := map[string]any{
+            "target-email": "student@test.com",
+            "target-pass": util.Sha256HexFromString(testCase.pass),
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/auth`), fields, nil, model.RoleGrader);
+        if (!response.Success) {
+            if (response.Locator != testCase.locator) {
+                test.Fatalf("Case %d: Unexpected error. Expected: '%s', Actual: '%s' -- '%v'.", i, testCase.locator, response.Locator, response);
+            }
+
+            continue;
+        }
+
+        if (testCase.locator != "") {
+            test.Fatalf("Case %d: Expected error '%s', but got a success.", i, testCase.locator);
+        }
+
+        var responseContent AuthResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if (responseContent.AuthSuccess != testCase.expected) {
+            test.Fatalf("Case %d: Unexpected auth result. Expected: '%v', Actual: '%v'.", i, testCase.expected, responseContent.AuthSuccess);
+        }
+    }
+
+    // The lockout is stored with the target user.
+    user, err := db.GetUser(db.MustGetTestCourse(), "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get user: '%v'.", err);
+    }
+
+    if (!user.IsLocked(time.Now())) {
+        test.Fatalf("Target user is not stored as locked.");
+    }
+}
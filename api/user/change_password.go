@@ -32,13 +32,27 @@ func HandleChangePassword(request *ChangePasswordRequest) (*ChangePasswordRespon
                 "Cannot modify a user with a higher role.").Add("target-user", request.TargetUser.User.Email);
     }
 
-    var err error;
+    serverUser, err := db.GetServerUser(request.TargetUser.Email);
+    if (err != nil) {
+        return nil, core.NewInternalError("-824", &request.APIRequestCourseUserContext,
+                "Failed to get server user.").Err(err).Add("target-user", request.TargetUser.Email);
+    }
+
+    // A server user's password is used in every course, so it cannot be changed by the staff of just one course.
+    if ((serverUser != nil) && (request.TargetUser.Email != request.User.Email)) {
+        return nil, core.NewBadCourseRequestError("-825", &request.APIRequestCourseUserContext,
+                "Cannot change the password of another server user, they can request a password reset.").
+                Add("target-user", request.TargetUser.Email);
+    }
+
+    credentials := model.GetCredentials(request.TargetUser.User, serverUser);
+
     var pass string;
 
     if (request.NewPass == "") {
-        pass, err = request.TargetUser.User.SetRandomPassword();
+        pass, err = credentials.SetRandomPassword();
     } else {
-        err = request.TargetUser.User.SetPassword(request.NewPass);
+        err = credentials.SetPassword(request.NewPass);
     }
 
     if (err != nil) {
@@ -46,7 +60,7 @@ func HandleChangePassword(request *ChangePasswordRequest) (*ChangePasswordRespon
                 "Failed to set password.").Err(err).Add("target-user", request.TargetUser.Email);
     }
 
-    err = db.SaveUser(request.Course, request.TargetUser.User);
+    err = db.SaveCredentials(request.Course, request.TargetUser.User, serverUser);
     if (err != nil) {
         return nil, core.NewInternalError("-807", &request.APIRequestCourseUserContext,
                 "Failed to save user.").Err(err).Add("target-user", request.TargetUser.Email);
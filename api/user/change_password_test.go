@@ -104,3 +104,53 @@ func TestChangePassword(test *testing.T) {
         }
     }
 }
+
+func TestChangePasswordServerUser(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    _, err := db.MigrateToServerUsers(false);
+    if (err != nil) {
+        test.Fatalf("Failed to migrate: '%v'.", err);
+    }
+
+    // Course staff cannot change another server user's password.
+    fields := map[string]any{
+        "target-email": "student@test.com",
+        "new-pass": util.Sha256HexFromString("new-pass"),
+    };
+
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/change/pass`), fields, nil, model.RoleAdmin);
+    if (response.Success) {
+        test.Fatalf("Response is a success when it should not be: '%v'.", response);
+    }
+
+    if (response.Locator != "-825") {
+        test.Fatalf("Unexpected locator. Expected: '-825', Actual: '%s'.", response.Locator);
+    }
+
+    // A server user can change their own password.
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/change/pass`), fields, nil, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Response is not a success when it should be: '%v'.", response);
+    }
+
+    serverUser, err := db.GetServerUser("student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get server user: '%v'.", err);
+    }
+
+    if (!serverUser.CheckPassword(util.Sha256HexFromString("new-pass"))) {
+        test.Fatalf("Server user's password was not changed.");
+    }
+
+    // The course user is untouched.
+    courseUser, err := db.GetUser(db.MustGetTestCourse(), "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get course user: '%v'.", err);
+    }
+
+    if (!courseUser.CheckPassword(util.Sha256HexFromString("student"))) {
+        test.Fatalf("Course user's password was changed.");
+    }
+}
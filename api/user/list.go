@@ -4,12 +4,18 @@ import (
     "slices"
 
     "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/model"
 )
 
 type ListRequest struct {
     core.APIRequestCourseUserContext
     core.MinRoleGrader
+    core.MinTokenScopeRead
     Users core.CourseUsers `json:"-"`
+
+    // Filter results to only users in this LMS section/group.
+    FilterSection string `json:"filter-section"`
+    FilterGroup string `json:"filter-group"`
 }
 
 type ListResponse struct {
@@ -17,9 +23,10 @@ type ListResponse struct {
 }
 
 func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+    filter := model.UserFilter{Section: request.FilterSection, Group: request.FilterGroup};
     users := make([]*core.UserInfo, 0, len(request.Users));
 
-    for _, user := range request.Users {
+    for _, user := range filter.FilterUsers(request.Users) {
         users = append(users, core.NewUserInfo(user));
     }
 
@@ -1,20 +1,22 @@
 package user
 
 import (
+    "reflect"
     "slices"
     "testing"
 
     "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/db"
     "github.com/edulinq/autograder/model"
 )
 
 func TestUserList(test *testing.T) {
     expectedUsers := []core.UserInfo{
-        core.UserInfo{"other@test.com", "other", model.RoleOther, ""},
-        core.UserInfo{"student@test.com", "student", model.RoleStudent, ""},
-        core.UserInfo{"grader@test.com", "grader", model.RoleGrader, ""},
-        core.UserInfo{"admin@test.com", "admin", model.RoleAdmin, ""},
-        core.UserInfo{"owner@test.com", "owner", model.RoleOwner, ""},
+        core.UserInfo{"other@test.com", "other", model.RoleOther, "", nil, nil},
+        core.UserInfo{"student@test.com", "student", model.RoleStudent, "", nil, nil},
+        core.UserInfo{"grader@test.com", "grader", model.RoleGrader, "", nil, nil},
+        core.UserInfo{"admin@test.com", "admin", model.RoleAdmin, "", nil, nil},
+        core.UserInfo{"owner@test.com", "owner", model.RoleOwner, "", nil, nil},
     };
 
     response := core.SendTestAPIRequest(test, core.NewEndpoint(`user/list`), nil);
@@ -38,7 +40,59 @@ func TestUserList(test *testing.T) {
     slices.SortFunc(expectedUsers, core.CompareUserInfo);
     slices.SortFunc(actualUsers, core.CompareUserInfo);
 
-    if (!slices.Equal(expectedUsers, actualUsers)) {
+    if (!reflect.DeepEqual(expectedUsers, actualUsers)) {
         test.Fatalf("Users not as expected. Expected: '%+v', actual: '%+v'.", expectedUsers, actualUsers);
     }
 }
+
+func TestUserListFilter(test *testing.T) {
+    defer db.ResetForTesting();
+
+    course := db.MustGetTestCourse();
+    user, err := db.GetUser(course, "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get user: '%v'.", err);
+    }
+
+    user.Sections = []string{"Lab 01"};
+    user.Groups = []string{"Team A"};
+
+    err = db.SaveUser(course, user);
+    if (err != nil) {
+        test.Fatalf("Failed to save user: '%v'.", err);
+    }
+
+    testCases := []struct{ section string; group string; expected []string }{
+        {"", "", []string{"admin@test.com", "grader@test.com", "other@test.com", "owner@test.com", "student@test.com"}},
+        {"Lab 01", "", []string{"student@test.com"}},
+        {"lab 01", "team a", []string{"student@test.com"}},
+        {"", "Team A", []string{"student@test.com"}},
+        {"Lab 02", "", []string{}},
+        {"Lab 01", "Team B", []string{}},
+    };
+
+    for i, testCase := range testCases {
+        fields := map[string]any{
+            "filter-section": testCase.section,
+            "filter-group": testCase.group,
+        };
+
+        response := core.SendTestAPIRequest(test, core.NewEndpoint(`user/list`), fields);
+        if (!response.Success) {
+            test.Errorf("Case %d: Response is not a success: '%v'.", i, response);
+            continue;
+        }
+
+        rawUsers := response.Content.(map[string]any)["users"].([]any);
+        actual := make([]string, 0, len(rawUsers));
+        for _, rawUser := range rawUsers {
+            actual = append(actual, core.UserInfoFromMap(rawUser.(map[string]any)).Email);
+        }
+
+        slices.Sort(actual);
+
+        if (!slices.Equal(testCase.expected, actual)) {
+            test.Errorf("Case %d: Users not as expected. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual);
+        }
+    }
+}
@@ -0,0 +1,28 @@
+package user
+
+import (
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+)
+
+type LogoutRequest struct {
+    core.APIRequestCourseUserContext
+    core.MinRoleOther
+}
+
+type LogoutResponse struct {
+}
+
+// End all of the context user's sessions (on every client, and in every course for server users).
+// API tokens are not affected.
+func HandleLogout(request *LogoutRequest) (*LogoutResponse, *core.APIError) {
+    model.GetCredentials(request.User, request.ServerUser).EndSessions();
+
+    err := db.SaveCredentials(request.Course, request.User, request.ServerUser);
+    if (err != nil) {
+        return nil, core.NewInternalError("-813", &request.APIRequestCourseUserContext, "Failed to save user.").Err(err);
+    }
+
+    return &LogoutResponse{}, nil;
+}
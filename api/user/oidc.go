@@ -0,0 +1,168 @@
+package user
+
+// Logging in through an OpenID Connect provider (see the oidc package).
+// Unlike other endpoints, these are GET requests that a user's browser is sent to.
+// The login endpoint sends the user to the provider, which then sends them back to the callback endpoint.
+// The callback maps the provider's (verified) email to a course user and starts a session for them.
+
+import (
+    "crypto/subtle"
+    "net/http"
+    "net/url"
+    "strings"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/oidc"
+)
+
+// Ties a callback to the browser that started the login.
+const OIDC_STATE_COOKIE = "autograder-oidc-state";
+
+type OIDCLoginResponse struct {
+    CourseID string `json:"course-id"`
+    Email string `json:"user-email"`
+
+    SessionToken string `json:"session-token"`
+    SessionExpiration common.Timestamp `json:"session-expiration"`
+}
+
+// Start a login, the "course-id" query parameter must be set.
+func HandleOIDCLogin(response http.ResponseWriter, request *http.Request) error {
+    endpoint := request.URL.Path;
+
+    if (!oidc.IsEnabled()) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareBadRequestError("-827", endpoint, "OIDC logins are not enabled on this server."));
+    }
+
+    courseID := request.URL.Query().Get("course-id");
+    if (courseID == "") {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareBadRequestError("-828", endpoint, "No course ID specified."));
+    }
+
+    course, err := db.GetCourse(courseID);
+    if (err != nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareInternalError("-829", endpoint, "Unable to get course.").Err(err).Course(courseID));
+    }
+
+    if (course == nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareBadRequestError("-830", endpoint, "Could not find course.").Course(courseID));
+    }
+
+    authURL, state, err := oidc.StartLogin(course.GetID());
+    if (err != nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareInternalError("-831", endpoint, "Failed to start OIDC login.").Err(err).Course(courseID));
+    }
+
+    http.SetCookie(response, &http.Cookie{
+        Name: OIDC_STATE_COOKIE,
+        Value: state,
+        Path: "/",
+        MaxAge: int(oidc.LOGIN_TIMEOUT.Seconds()),
+        HttpOnly: true,
+        Secure: strings.HasPrefix(config.OIDC_REDIRECT_URL.Get(), "https://"),
+        SameSite: http.SameSiteLaxMode,
+    });
+
+    http.Redirect(response, request, authURL, http.StatusFound);
+    return nil;
+}
+
+// Finish a login (the provider sends the user here).
+// On success, the user is sent to config.OIDC_SUCCESS_URL (with the session in the URL's fragment)
+// or the session is returned as an OIDCLoginResponse.
+func HandleOIDCCallback(response http.ResponseWriter, request *http.Request) error {
+    endpoint := request.URL.Path;
+    query := request.URL.Query();
+
+    if (query.Get("error") != "") {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareBadRequestError("-832", endpoint, "The OIDC provider did not log in the user.").
+                Add("error", query.Get("error")).Add("error-description", query.Get("error_description")));
+    }
+
+    state := query.Get("state");
+    cookie, err := request.Cookie(OIDC_STATE_COOKIE);
+    if ((err != nil) || (state == "") || (subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1)) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareBadRequestError("-833", endpoint, "Login state does not match, try logging in again."));
+    }
+
+    // The state cookie is no longer needed.
+    http.SetCookie(response, &http.Cookie{Name: OIDC_STATE_COOKIE, Value: "", Path: "/", MaxAge: -1});
+
+    login, err := oidc.FinishLogin(state, query.Get("code"));
+    if (err != nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareBadRequestError("-834", endpoint, "Failed to log in through the OIDC provider.").Err(err));
+    }
+
+    course, err := db.GetCourse(login.CourseID);
+    if (err != nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareInternalError("-835", endpoint, "Unable to get course.").Err(err).Course(login.CourseID));
+    }
+
+    if (course == nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareBadRequestError("-836", endpoint, "Could not find course.").Course(login.CourseID));
+    }
+
+    user, err := db.GetUser(course, login.Email);
+    if (err != nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareInternalError("-837", endpoint, "Failed to get user.").Err(err).Course(login.CourseID).User(login.Email));
+    }
+
+    if (user == nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareBadRequestError("-838", endpoint, "There is no user in this course with your email.").
+                Course(login.CourseID).User(login.Email));
+    }
+
+    serverUser, err := db.GetServerUser(login.Email);
+    if (err != nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareInternalError("-839", endpoint, "Failed to get server user.").Err(err).Course(login.CourseID).User(login.Email));
+    }
+
+    result := OIDCLoginResponse{
+        CourseID: course.GetID(),
+        Email: user.Email,
+    };
+
+    credentials := model.GetCredentials(user, serverUser);
+    sessionCourseID := model.GetSessionCourseID(course.GetID(), serverUser);
+
+    result.SessionToken, result.SessionExpiration, err = credentials.NewSessionToken(user.Email, sessionCourseID);
+    if (err != nil) {
+        return core.SendAPIResponseFromRoute(response, nil,
+                core.NewBareInternalError("-840", endpoint, "Failed to create session token.").Err(err).Course(login.CourseID).User(login.Email));
+    }
+
+    log.Info("User logged in through OIDC.", course, log.NewUserAttr(user.Email));
+
+    successURL := config.OIDC_SUCCESS_URL.Get();
+    if (successURL == "") {
+        return core.SendAPIResponseFromRoute(response, result, nil);
+    }
+
+    // Use the fragment so that the session is never sent to a server.
+    fragment := url.Values{};
+    fragment.Set("course-id", result.CourseID);
+    fragment.Set("user-email", result.Email);
+    fragment.Set("session-token", result.SessionToken);
+    fragment.Set("session-expiration", result.SessionExpiration.String());
+
+    http.Redirect(response, request, successURL + "#" + fragment.Encode(), http.StatusFound);
+    return nil;
+}
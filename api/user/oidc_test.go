@@ -0,0 +1,243 @@
+package user
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "strings"
+    "testing"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/oidc"
+    "github.com/edulinq/autograder/util"
+)
+
+const testOIDCBaseURL = "http://autograder.test.com";
+
+func TestUserOIDCLogin(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    provider := oidc.StartMockProvider("student@test.com");
+    defer provider.Close();
+
+    oldRedirectURL := config.OIDC_REDIRECT_URL.Get();
+    defer config.OIDC_REDIRECT_URL.Set(oldRedirectURL);
+    config.OIDC_REDIRECT_URL.Set(testOIDCBaseURL + core.NewEndpoint(`user/oidc/callback`));
+
+    testCases := []struct{email string; useCookie bool; reuseState bool; locator string}{
+        {"student@test.com", true, false, ""},
+        {"grader@test.com", true, false, ""},
+        {"student@test.com", false, false, "-833"},
+        {"student@test.com", true, true, "-834"},
+        {"ZZZ@test.com", true, false, "-838"},
+    };
+
+    for i, testCase := range testCases {
+        provider.Email = testCase.email;
+
+        callbackQuery, cookie := startTestOIDCLogin(test, "course101");
+
+        if (!testCase.useCookie) {
+            cookie = nil;
+        }
+
+        if (testCase.reuseState) {
+            sendTestOIDCCallback(test, callbackQuery, cookie);
+        }
+
+        response := sendTestOIDCCallback(test, callbackQuery, cookie);
+
+        if (testCase.locator != "") {
+            if (response.Success) {
+                test.Errorf("Case %d: Response is a success when it should not be: '%v'.", i, response);
+            } else if (response.Locator != testCase.locator) {
+                test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator);
+            }
+
+            continue;
+        }
+
+        if (!response.Success) {
+            test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response);
+            continue;
+        }
+
+        var responseContent OIDCLoginResponse;
+        util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent);
+
+        if ((responseContent.CourseID != "course101") || (responseContent.Email != testCase.email)) {
+            test.Errorf("Case %d: Unexpected login: '%+v'.", i, responseContent);
+            continue;
+        }
+
+        // The session can be used like a password.
+        fields := map[string]any{
+            "user-email": testCase.email,
+            "user-pass": "",
+            "user-token": responseContent.SessionToken,
+        };
+
+        tokenResponse := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/list`), fields, nil, model.RoleStudent);
+        if (!tokenResponse.Success) {
+            test.Errorf("Case %d: Could not use the session: '%v'.", i, tokenResponse);
+        }
+    }
+}
+
+func TestUserOIDCLoginSuccessURL(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    provider := oidc.StartMockProvider("student@test.com");
+    defer provider.Close();
+
+    oldRedirectURL := config.OIDC_REDIRECT_URL.Get();
+    defer config.OIDC_REDIRECT_URL.Set(oldRedirectURL);
+    config.OIDC_REDIRECT_URL.Set(testOIDCBaseURL + core.NewEndpoint(`user/oidc/callback`));
+
+    oldSuccessURL := config.OIDC_SUCCESS_URL.Get();
+    defer config.OIDC_SUCCESS_URL.Set(oldSuccessURL);
+    config.OIDC_SUCCESS_URL.Set(testOIDCBaseURL + "/static/index.html");
+
+    callbackQuery, cookie := startTestOIDCLogin(test, "course101");
+
+    request := httptest.NewRequest("GET", core.NewEndpoint(`user/oidc/callback`) + "?" + callbackQuery, nil);
+    request.AddCookie(cookie);
+
+    recorder := httptest.NewRecorder();
+    err := HandleOIDCCallback(recorder, request);
+    if (err != nil) {
+        test.Fatalf("Failed to handle callback: '%v'.", err);
+    }
+
+    if (recorder.Code != http.StatusFound) {
+        test.Fatalf("Unexpected status. Expected: %d, Actual: %d.", http.StatusFound, recorder.Code);
+    }
+
+    target, err := url.Parse(recorder.Header().Get("Location"));
+    if (err != nil) {
+        test.Fatalf("Failed to parse success URL: '%v'.", err);
+    }
+
+    if (!strings.HasPrefix(target.String(), config.OIDC_SUCCESS_URL.Get() + "#")) {
+        test.Fatalf("Not sent to the success URL: '%s'.", target.String());
+    }
+
+    fragment, err := url.ParseQuery(target.Fragment);
+    if (err != nil) {
+        test.Fatalf("Failed to parse fragment: '%v'.", err);
+    }
+
+    if ((fragment.Get("user-email") != "student@test.com") || (fragment.Get("session-token") == "")) {
+        test.Fatalf("Unexpected fragment: '%v'.", fragment);
+    }
+}
+
+func TestUserOIDCLoginBadRequest(test *testing.T) {
+    testCases := []struct{enabled bool; courseID string; locator string}{
+        {false, "course101", "-827"},
+        {true, "", "-828"},
+        {true, "ZZZ", "-830"},
+    };
+
+    for i, testCase := range testCases {
+        var provider *oidc.MockProvider;
+        if (testCase.enabled) {
+            provider = oidc.StartMockProvider("student@test.com");
+        }
+
+        request := httptest.NewRequest("GET", core.NewEndpoint(`user/oidc/login`) + "?course-id=" + testCase.courseID, nil);
+        recorder := httptest.NewRecorder();
+
+        err := HandleOIDCLogin(recorder, request);
+
+        if (provider != nil) {
+            provider.Close();
+        }
+
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to handle login: '%v'.", i, err);
+            continue;
+        }
+
+        var response core.APIResponse;
+        util.MustJSONFromString(recorder.Body.String(), &response);
+
+        if (response.Locator != testCase.locator) {
+            test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator);
+        }
+    }
+}
+
+// Start a login and follow it through the provider.
+// Returns the query that the provider sent back (to the callback) and the state cookie.
+func startTestOIDCLogin(test *testing.T, courseID string) (string, *http.Cookie) {
+    request := httptest.NewRequest("GET", core.NewEndpoint(`user/oidc/login`) + "?course-id=" + courseID, nil);
+    recorder := httptest.NewRecorder();
+
+    err := HandleOIDCLogin(recorder, request);
+    if (err != nil) {
+        test.Fatalf("Failed to handle login: '%v'.", err);
+    }
+
+    if (recorder.Code != http.StatusFound) {
+        test.Fatalf("Login did not redirect: '%s'.", recorder.Body.String());
+    }
+
+    var cookie *http.Cookie;
+    for _, resultCookie := range recorder.Result().Cookies() {
+        if (resultCookie.Name == OIDC_STATE_COOKIE) {
+            cookie = resultCookie;
+        }
+    }
+
+    if (cookie == nil) {
+        test.Fatalf("Login did not set a state cookie.");
+    }
+
+    // Visit the provider, which will immediately send us back.
+    client := http.Client{
+        CheckRedirect: func(request *http.Request, via []*http.Request) error {
+            return http.ErrUseLastResponse;
+        },
+    };
+
+    response, err := client.Get(recorder.Header().Get("Location"));
+    if (err != nil) {
+        test.Fatalf("Failed to visit provider: '%v'.", err);
+    }
+    defer response.Body.Close();
+
+    callbackURL, err := url.Parse(response.Header.Get("Location"));
+    if (err != nil) {
+        test.Fatalf("Provider did not redirect to a valid URL: '%v'.", err);
+    }
+
+    if (callbackURL.Path != core.NewEndpoint(`user/oidc/callback`)) {
+        test.Fatalf("Provider did not redirect to the callback: '%s'.", callbackURL.String());
+    }
+
+    return callbackURL.RawQuery, cookie;
+}
+
+func sendTestOIDCCallback(test *testing.T, query string, cookie *http.Cookie) *core.APIResponse {
+    request := httptest.NewRequest("GET", core.NewEndpoint(`user/oidc/callback`) + "?" + query, nil);
+    if (cookie != nil) {
+        request.AddCookie(cookie);
+    }
+
+    recorder := httptest.NewRecorder();
+    err := HandleOIDCCallback(recorder, request);
+    if (err != nil) {
+        test.Fatalf("Failed to handle callback: '%v'.", err);
+    }
+
+    var response core.APIResponse;
+    util.MustJSONFromString(recorder.Body.String(), &response);
+
+    return &response;
+}
@@ -0,0 +1,141 @@
+package user
+
+// Password resets are requested by users who cannot log in,
+// so these endpoints are not authenticated.
+
+import (
+    "fmt"
+    "time"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+)
+
+type PasswordResetRequest struct {
+    core.APIRequest
+
+    CourseID string `json:"course-id"`
+    UserEmail core.NonEmptyString `json:"user-email"`
+}
+
+// The response is the same whether or not an email was sent,
+// so that this endpoint cannot be used to check for users.
+type PasswordResetResponse struct {
+}
+
+type PasswordResetConfirmRequest struct {
+    core.APIRequest
+
+    CourseID string `json:"course-id"`
+    UserEmail core.NonEmptyString `json:"user-email"`
+    Token core.NonEmptyString `json:"token"`
+    NewPass core.NonEmptyString `json:"new-pass"`
+}
+
+type PasswordResetConfirmResponse struct {
+}
+
+// Email the user a one-time token that can be used to set a new password.
+func HandlePasswordResetRequest(request *PasswordResetRequest) (*PasswordResetResponse, *core.APIError) {
+    response := PasswordResetResponse{};
+
+    course, user, serverUser, apiErr := getPasswordResetUser(&request.APIRequest, request.CourseID, string(request.UserEmail));
+    if (apiErr != nil) {
+        return nil, apiErr;
+    }
+
+    if (user == nil) {
+        log.Debug("Password reset requested for unknown user.", course, log.NewUserAttr(string(request.UserEmail)));
+        return &response, nil;
+    }
+
+    credentials := model.GetCredentials(user, serverUser);
+    now := time.Now();
+
+    if (!credentials.CanRequestPasswordReset(now)) {
+        log.Info("Password reset requested too soon after the last request.", course, user);
+        return &response, nil;
+    }
+
+    token, err := credentials.StartPasswordReset(now);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-817", request.Endpoint, "Failed to start password reset.").Err(err);
+    }
+
+    err = db.SaveCredentials(course, user, serverUser);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-818", request.Endpoint, "Failed to save user.").Err(err);
+    }
+
+    err = model.SendPasswordResetEmail(course, user, token);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-819", request.Endpoint, "Failed to send password reset email.").Err(err);
+    }
+
+    return &response, nil;
+}
+
+// Use a token from HandlePasswordResetRequest() to set a new password.
+func HandlePasswordResetConfirm(request *PasswordResetConfirmRequest) (*PasswordResetConfirmResponse, *core.APIError) {
+    course, user, serverUser, apiErr := getPasswordResetUser(&request.APIRequest, request.CourseID, string(request.UserEmail));
+    if (apiErr != nil) {
+        return nil, apiErr;
+    }
+
+    // Do not reveal whether the user or the token is the problem.
+    badTokenErr := core.NewBadRequestError("-820", &request.APIRequest, "Invalid or expired password reset token.").
+            Course(request.CourseID);
+
+    if (user == nil) {
+        return nil, badTokenErr;
+    }
+
+    credentials := model.GetCredentials(user, serverUser);
+
+    changed, err := credentials.ConfirmPasswordReset(string(request.Token), string(request.NewPass), time.Now());
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-821", request.Endpoint, "Failed to set password.").Err(err);
+    }
+
+    if (!changed) {
+        return nil, badTokenErr;
+    }
+
+    err = db.SaveCredentials(course, user, serverUser);
+    if (err != nil) {
+        return nil, core.NewBareInternalError("-822", request.Endpoint, "Failed to save user.").Err(err);
+    }
+
+    return &PasswordResetConfirmResponse{}, nil;
+}
+
+// Get the course, user (which may be nil), and server user (which may be nil) for a password reset.
+func getPasswordResetUser(request *core.APIRequest, courseID string, email string) (*model.Course, *model.User, *model.ServerUser, *core.APIError) {
+    course, err := db.GetCourse(courseID);
+    if (err != nil) {
+        return nil, nil, nil, core.NewBareInternalError("-814", request.Endpoint, "Unable to get course.").Err(err);
+    }
+
+    if (course == nil) {
+        return nil, nil, nil, core.NewBadRequestError("-815", request, fmt.Sprintf("Could not find course: '%s'.", courseID)).
+                Course(courseID);
+    }
+
+    user, err := db.GetUser(course, email);
+    if (err != nil) {
+        return nil, nil, nil, core.NewBareInternalError("-816", request.Endpoint, "Unable to get user.").Err(err);
+    }
+
+    if (user == nil) {
+        return course, nil, nil, nil;
+    }
+
+    serverUser, err := db.GetServerUser(email);
+    if (err != nil) {
+        return nil, nil, nil, core.NewBareInternalError("-826", request.Endpoint, "Unable to get server user.").Err(err);
+    }
+
+    return course, user, serverUser, nil;
+}
@@ -0,0 +1,109 @@
+package user
+
+import (
+    "regexp"
+    "testing"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/email"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+var passwordResetTokenRegex = regexp.MustCompile(`token is '([0-9a-f]+)'`);
+
+func TestUserPasswordReset(test *testing.T) {
+    defer db.ResetForTesting();
+    defer email.ClearTestMessages();
+
+    db.ResetForTesting();
+    email.ClearTestMessages();
+
+    requestReset := func(address string) {
+        fields := map[string]any{
+            "user-email": address,
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/password/reset/request`), fields, nil, model.RoleOther);
+        if (!response.Success) {
+            test.Fatalf("Failed to request a password reset for '%s': '%v'.", address, response);
+        }
+    };
+
+    // Returns the locator on failure.
+    confirmReset := func(token string, pass string) string {
+        fields := map[string]any{
+            "user-email": "student@test.com",
+            "token": token,
+            "new-pass": util.Sha256HexFromString(pass),
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/password/reset/confirm`), fields, nil, model.RoleOther);
+        return response.Locator;
+    };
+
+    // Unknown users look successful, but get no email.
+    requestReset("ZZZ@test.com");
+    if (len(email.GetTestMessages()) != 0) {
+        test.Fatalf("Email sent for an unknown user: '%s'.", util.MustToJSONIndent(email.GetTestMessages()));
+    }
+
+    requestReset("student@test.com");
+
+    // A quick second request is rate limited.
+    requestReset("student@test.com");
+
+    messages := email.GetTestMessages();
+    if (len(messages) != 1) {
+        test.Fatalf("Unexpected number of emails. Expected: 1, Actual: %d.", len(messages));
+    }
+
+    if ((len(messages[0].To) != 1) || (messages[0].To[0] != "student@test.com")) {
+        test.Fatalf("Email sent to the wrong address: '%v'.", messages[0].To);
+    }
+
+    match := passwordResetTokenRegex.FindStringSubmatch(messages[0].Body);
+    if (match == nil) {
+        test.Fatalf("Could not find token in email body: '%s'.", messages[0].Body);
+    }
+
+    token := match[1];
+
+    locator := confirmReset(token + "0", "new-pass");
+    if (locator != "-820") {
+        test.Fatalf("Unexpected result for a bad token. Expected: '-820', Actual: '%s'.", locator);
+    }
+
+    locator = confirmReset(token, "new-pass");
+    if (locator != "") {
+        test.Fatalf("Failed to confirm password reset: '%s'.", locator);
+    }
+
+    user, err := db.GetUser(db.MustGetTestCourse(), "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get user: '%v'.", err);
+    }
+
+    if (!user.CheckPassword(util.Sha256HexFromString("new-pass"))) {
+        test.Fatalf("Password was not changed.");
+    }
+
+    // Tokens can only be used once.
+    locator = confirmReset(token, "other-pass");
+    if (locator != "-820") {
+        test.Fatalf("Unexpected result for a used token. Expected: '-820', Actual: '%s'.", locator);
+    }
+}
+
+func TestUserPasswordResetUnknownCourse(test *testing.T) {
+    fields := map[string]any{
+        "course-id": "ZZZ",
+        "user-email": "student@test.com",
+    };
+
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/password/reset/request`), fields, nil, model.RoleOther);
+    if (response.Locator != "-815") {
+        test.Fatalf("Unexpected result for an unknown course. Expected: '-815', Actual: '%v'.", response);
+    }
+}
@@ -12,7 +12,15 @@ var routes []*core.Route = []*core.Route{
     core.NewAPIRoute(core.NewEndpoint(`user/change/pass`), HandleChangePassword),
     core.NewAPIRoute(core.NewEndpoint(`user/get`), HandleUserGet),
     core.NewAPIRoute(core.NewEndpoint(`user/list`), HandleList),
+    core.NewAPIRoute(core.NewEndpoint(`user/logout`), HandleLogout),
+    core.NewRoute("GET", core.NewEndpoint(`user/oidc/callback`), HandleOIDCCallback),
+    core.NewRoute("GET", core.NewEndpoint(`user/oidc/login`), HandleOIDCLogin),
+    core.NewAPIRoute(core.NewEndpoint(`user/password/reset/confirm`), HandlePasswordResetConfirm),
+    core.NewAPIRoute(core.NewEndpoint(`user/password/reset/request`), HandlePasswordResetRequest),
     core.NewAPIRoute(core.NewEndpoint(`user/remove`), HandleRemove),
+    core.NewAPIRoute(core.NewEndpoint(`user/token/create`), HandleTokenCreate),
+    core.NewAPIRoute(core.NewEndpoint(`user/token/list`), HandleTokenList),
+    core.NewAPIRoute(core.NewEndpoint(`user/token/revoke`), HandleTokenRevoke),
 };
 
 func GetRoutes() *[]*core.Route {
@@ -0,0 +1,47 @@
+package user
+
+import (
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+)
+
+type TokenCreateRequest struct {
+    core.APIRequestCourseUserContext
+    core.MinRoleOther
+
+    Name string `json:"name"`
+    Scope model.TokenScope `json:"scope"`
+    // Leave empty for a token that never expires.
+    ExpirationTime common.Timestamp `json:"expiration-time"`
+}
+
+type TokenCreateResponse struct {
+    TokenInfo *TokenInfo `json:"token-info"`
+    // The token's cleartext, this is the only time it will be available.
+    Token string `json:"token"`
+}
+
+// Create an API token for the context user.
+func HandleTokenCreate(request *TokenCreateRequest) (*TokenCreateResponse, *core.APIError) {
+    token, cleartext, err := model.NewAPIToken(request.Name, request.Scope, request.ExpirationTime);
+    if (err != nil) {
+        return nil, core.NewBadCourseRequestError("-809", &request.APIRequestCourseUserContext, "Invalid token request.").Err(err);
+    }
+
+    credentials := model.GetCredentials(request.User, request.ServerUser);
+    credentials.Tokens = append(credentials.Tokens, token);
+
+    err = db.SaveCredentials(request.Course, request.User, request.ServerUser);
+    if (err != nil) {
+        return nil, core.NewInternalError("-810", &request.APIRequestCourseUserContext, "Failed to save user.").Err(err);
+    }
+
+    response := TokenCreateResponse{
+        TokenInfo: NewTokenInfo(token),
+        Token: cleartext,
+    };
+
+    return &response, nil;
+}
@@ -0,0 +1,52 @@
+package user
+
+import (
+    "time"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/model"
+)
+
+// How to represent API tokens in responses (without their hash).
+type TokenInfo struct {
+    ID string `json:"id"`
+    Name string `json:"name"`
+    Scope model.TokenScope `json:"scope"`
+    CreationTime common.Timestamp `json:"creation-time"`
+    ExpirationTime common.Timestamp `json:"expiration-time"`
+    Expired bool `json:"expired"`
+}
+
+type TokenListRequest struct {
+    core.APIRequestCourseUserContext
+    core.MinRoleOther
+    core.MinTokenScopeRead
+}
+
+type TokenListResponse struct {
+    Tokens []*TokenInfo `json:"tokens"`
+}
+
+// List the context user's API tokens.
+func HandleTokenList(request *TokenListRequest) (*TokenListResponse, *core.APIError) {
+    credentials := model.GetCredentials(request.User, request.ServerUser);
+
+    tokens := make([]*TokenInfo, 0, len(credentials.Tokens));
+    for _, token := range credentials.Tokens {
+        tokens = append(tokens, NewTokenInfo(token));
+    }
+
+    return &TokenListResponse{tokens}, nil;
+}
+
+func NewTokenInfo(token *model.APIToken) *TokenInfo {
+    return &TokenInfo{
+        ID: token.ID,
+        Name: token.Name,
+        Scope: token.Scope,
+        CreationTime: token.CreationTime,
+        ExpirationTime: token.ExpirationTime,
+        Expired: token.IsExpired(time.Now()),
+    };
+}
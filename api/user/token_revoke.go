@@ -0,0 +1,38 @@
+package user
+
+import (
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+)
+
+type TokenRevokeRequest struct {
+    core.APIRequestCourseUserContext
+    core.MinRoleOther
+
+    TokenID string `json:"token-id"`
+}
+
+type TokenRevokeResponse struct {
+    FoundToken bool `json:"found-token"`
+}
+
+// Revoke one of the context user's API tokens.
+func HandleTokenRevoke(request *TokenRevokeRequest) (*TokenRevokeResponse, *core.APIError) {
+    response := TokenRevokeResponse{};
+
+    credentials := model.GetCredentials(request.User, request.ServerUser);
+
+    response.FoundToken = credentials.RemoveToken(request.TokenID);
+    if (!response.FoundToken) {
+        return &response, nil;
+    }
+
+    err := db.SaveCredentials(request.Course, request.User, request.ServerUser);
+    if (err != nil) {
+        return nil, core.NewInternalError("-811", &request.APIRequestCourseUserContext, "Failed to save user.").
+                Err(err).Add("token-id", request.TokenID);
+    }
+
+    return &response, nil;
+}
@@ -0,0 +1,131 @@
+package user
+
+import (
+    "net/http"
+    "testing"
+
+    "github.com/edulinq/autograder/api/core"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func TestUserTokenCreateBadRequest(test *testing.T) {
+    defer db.ResetForTesting();
+
+    testCases := []struct{ scope string; expiration string; locator string }{
+        {"unknown", "", "-809"},
+        {"read", "ZZZ", "-809"},
+        {"ZZZ", "", "-005"},
+    };
+
+    for i, testCase := range testCases {
+        fields := map[string]any{
+            "name": "test",
+            "scope": testCase.scope,
+            "expiration-time": testCase.expiration,
+        };
+
+        response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/create`), fields, nil, model.RoleStudent);
+        if (response.Success) {
+            test.Errorf("Case %d: Response is a success when it should not be: '%v'.", i, response);
+            continue;
+        }
+
+        if (response.Locator != testCase.locator) {
+            test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator);
+        }
+    }
+}
+
+// Create a token, use it, and then revoke it.
+func TestUserTokenLifecycle(test *testing.T) {
+    defer db.ResetForTesting();
+    db.ResetForTesting();
+
+    fields := map[string]any{
+        "name": "test",
+        "scope": "read",
+    };
+
+    response := core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/create`), fields, nil, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Failed to create token: '%v'.", response);
+    }
+
+    var createResponse TokenCreateResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &createResponse);
+
+    if (createResponse.Token == "") {
+        test.Fatalf("Did not get a token back.");
+    }
+
+    if ((createResponse.TokenInfo.Name != "test") || (createResponse.TokenInfo.Scope != model.TokenScopeRead)) {
+        test.Fatalf("Unexpected token info: '%+v'.", createResponse.TokenInfo);
+    }
+
+    tokenFields := map[string]any{
+        "user-pass": "",
+        "user-token": createResponse.Token,
+    };
+
+    // A read token can list tokens.
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/list`), tokenFields, nil, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Failed to list tokens using a token: '%v'.", response);
+    }
+
+    var listResponse TokenListResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &listResponse);
+
+    if ((len(listResponse.Tokens) != 1) || (*listResponse.Tokens[0] != *createResponse.TokenInfo)) {
+        test.Fatalf("Unexpected token list. Expected: '%+v', Actual: '%s'.", createResponse.TokenInfo, util.MustToJSON(listResponse.Tokens));
+    }
+
+    // A read token cannot create more tokens.
+    createFields := map[string]any{
+        "user-pass": "",
+        "user-token": createResponse.Token,
+        "name": "test",
+        "scope": "read",
+    };
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/create`), createFields, nil, model.RoleStudent);
+    if (response.Success || (response.Locator != "-044")) {
+        test.Fatalf("Read token was able to create a token (or got the wrong error): '%v'.", response);
+    }
+
+    revokeFields := map[string]any{
+        "token-id": createResponse.TokenInfo.ID,
+    };
+
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/revoke`), revokeFields, nil, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Failed to revoke token: '%v'.", response);
+    }
+
+    var revokeResponse TokenRevokeResponse;
+    util.MustJSONFromString(util.MustToJSON(response.Content), &revokeResponse);
+
+    if (!revokeResponse.FoundToken) {
+        test.Fatalf("Did not find token to revoke.");
+    }
+
+    // A revoked token no longer works.
+    // Auth errors do not expose their locator.
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/list`), tokenFields, nil, model.RoleStudent);
+    if (response.Success || (response.HTTPStatus != http.StatusUnauthorized)) {
+        test.Fatalf("Revoked token still works (or got the wrong error): '%v'.", response);
+    }
+
+    // Revoking again finds nothing.
+    response = core.SendTestAPIRequestFull(test, core.NewEndpoint(`user/token/revoke`), revokeFields, nil, model.RoleStudent);
+    if (!response.Success) {
+        test.Fatalf("Failed to revoke token a second time: '%v'.", response);
+    }
+
+    util.MustJSONFromString(util.MustToJSON(response.Content), &revokeResponse);
+    if (revokeResponse.FoundToken) {
+        test.Fatalf("Found a token that was already revoked.");
+    }
+}
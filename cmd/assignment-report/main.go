@@ -8,6 +8,7 @@ import (
     "github.com/edulinq/autograder/config"
     "github.com/edulinq/autograder/db"
     "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
     "github.com/edulinq/autograder/report"
     "github.com/edulinq/autograder/util"
 )
@@ -17,6 +18,8 @@ var args struct {
     Course string `help:"ID of the course." arg:""`
     Assignment string `help:"ID of the assignment." arg:""`
     HTML bool `help:"Output report as html." default:"false"`
+    Section string `help:"Only include students in this LMS section."`
+    Group string `help:"Only include students in this LMS group."`
 }
 
 func main() {
@@ -34,7 +37,12 @@ func main() {
 
     assignment := db.MustGetAssignment(args.Course, args.Assignment);
 
-    report, err := report.GetAssignmentScoringReport(assignment);
+    filter := &model.UserFilter{
+        Section: args.Section,
+        Group: args.Group,
+    };
+
+    report, err := report.GetFilteredAssignmentScoringReport(assignment, filter);
     if (err != nil) {
         log.Fatal("Failed to get scoring report.", assignment, err);
     }
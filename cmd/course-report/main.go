@@ -8,6 +8,7 @@ import (
     "github.com/edulinq/autograder/config"
     "github.com/edulinq/autograder/db"
     "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
     "github.com/edulinq/autograder/email"
     "github.com/edulinq/autograder/report"
     "github.com/edulinq/autograder/util"
@@ -18,6 +19,8 @@ var args struct {
     Course string `help:"ID of the course." arg:""`
     Email []string `help:"Email addresses to send the report to (as HTML)." short:"e"`
     HTML bool `help:"Output report as html." default:"false"`
+    Section string `help:"Only include students in this LMS section."`
+    Group string `help:"Only include students in this LMS group."`
 }
 
 func main() {
@@ -35,7 +38,12 @@ func main() {
 
     course := db.MustGetCourse(args.Course);
 
-    report, err := report.GetCourseScoringReport(course);
+    filter := &model.UserFilter{
+        Section: args.Section,
+        Group: args.Group,
+    };
+
+    report, err := report.GetFilteredCourseScoringReport(course, filter);
     if (err != nil) {
         log.Fatal("Failed to get scoring report.", course, err);
     }
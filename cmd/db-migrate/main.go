@@ -0,0 +1,96 @@
+package main
+
+import (
+    "fmt"
+    "reflect"
+
+    "github.com/alecthomas/kong"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/util"
+)
+
+var args struct {
+    config.ConfigArgs
+
+    Source map[string]string `help:"Config options for the source database (on top of the standard config), e.g. '--source dirs.base=/old/dir'." required:""`
+    Target map[string]string `help:"Config options for the target database (on top of the standard config), e.g. '--target db.type=sqlite'." required:""`
+
+    SkipVerify bool `help:"Do not verify the target database after migrating." default:"false"`
+    VerifyOnly bool `help:"Do not migrate, only check that the target database contains everything in the source database." default:"false"`
+}
+
+func main() {
+    kong.Parse(&args,
+        kong.Description("Copy all courses, users, submissions, task completions, and logs from one database into another." +
+                " The target database will keep any data it already has."),
+    );
+
+    err := config.HandleConfigArgs(args.ConfigArgs);
+    if (err != nil) {
+        log.Fatal("Could not load config options.", err);
+    }
+
+    if (reflect.DeepEqual(args.Source, args.Target)) {
+        log.Fatal("The source and target databases have the same config options.");
+    }
+
+    source := mustOpenBackend("source", args.Source);
+    defer source.Close();
+
+    target := mustOpenBackend("target", args.Target);
+    defer target.Close();
+
+    if (!args.VerifyOnly) {
+        stats, err := db.Migrate(source, target);
+        if (err != nil) {
+            log.Fatal("Failed to migrate database.", err);
+        }
+
+        fmt.Printf("Migrated: %s\n", util.MustToJSON(stats));
+    }
+
+    if (args.SkipVerify) {
+        return;
+    }
+
+    stats, err := db.VerifyMigration(source, target);
+    if (err != nil) {
+        log.Fatal("Failed to verify database migration.", err);
+    }
+
+    fmt.Printf("Verified: %s\n", util.MustToJSON(stats));
+}
+
+// Open a backend with the given options temporarily applied to the config.
+func mustOpenBackend(name string, options map[string]string) db.Backend {
+    type oldValue struct {
+        value any
+        exists bool
+    }
+
+    oldValues := make(map[string]oldValue, len(options));
+    for key, value := range options {
+        oldValues[key] = oldValue{config.GetDefault(key, nil), config.Has(key)};
+        config.Set(key, value);
+    }
+
+    defer func() {
+        for key, old := range oldValues {
+            if (old.exists) {
+                config.Set(key, old.value);
+            } else {
+                config.Unset(key);
+            }
+        }
+    }();
+
+    backend, err := db.OpenBackend();
+    if (err != nil) {
+        log.Fatal("Failed to open database.", err, log.NewAttr("database", name));
+    }
+
+    return backend;
+}
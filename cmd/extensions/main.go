@@ -0,0 +1,138 @@
+package main
+
+import (
+    "fmt"
+
+    "github.com/alecthomas/kong"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+type GrantExtension struct {
+    Assignment string `help:"ID of the assignment." arg:"" required:""`
+    Email string `help:"Email of the user." arg:"" required:""`
+    DueDate string `help:"New due date for the user (RFC 3339, e.g. '2024-01-01T23:59:59-08:00')."`
+    ExtraAttempts int `help:"Number of additional submission attempts." default:"0"`
+    ExtraLateDays int `help:"Number of days a submission can be late without a penalty." default:"0"`
+    WaiveLatePenalty bool `help:"Do not apply any late penalty to this user." default:"false"`
+    Reason string `help:"Reason for the extension."`
+}
+
+func (this *GrantExtension) Run(course *model.Course) error {
+    assignment := course.Assignments[this.Assignment];
+    if (assignment == nil) {
+        return fmt.Errorf("Unknown assignment: '%s'.", this.Assignment);
+    }
+
+    user, err := db.GetUser(course, this.Email);
+    if (err != nil) {
+        return fmt.Errorf("Failed to get user: '%w'.", err);
+    }
+
+    if (user == nil) {
+        return fmt.Errorf("User '%s' does not exist.", this.Email);
+    }
+
+    extension := &model.Extension{
+        User: this.Email,
+        DueDate: common.Timestamp(this.DueDate),
+        ExtraAttempts: this.ExtraAttempts,
+        ExtraLateDays: this.ExtraLateDays,
+        WaiveLatePenalty: this.WaiveLatePenalty,
+        Reason: this.Reason,
+    };
+
+    err = db.SaveExtension(assignment, extension);
+    if (err != nil) {
+        return fmt.Errorf("Failed to save extension: '%w'.", err);
+    }
+
+    fmt.Println(util.MustToJSONIndent(extension));
+
+    return nil;
+}
+
+type ListExtensions struct {
+    Assignment string `help:"Only list extensions for this assignment."`
+}
+
+func (this *ListExtensions) Run(course *model.Course) error {
+    extensions, err := db.GetExtensions(course);
+    if (err != nil) {
+        return fmt.Errorf("Failed to get extensions: '%w'.", err);
+    }
+
+    fmt.Println("Assignment\tUser\tDue Date\tExtra Attempts\tExtra Late Days\tWaive Late Penalty\tReason");
+
+    for _, extension := range extensions {
+        if ((this.Assignment != "") && (extension.AssignmentID != this.Assignment)) {
+            continue;
+        }
+
+        fmt.Printf("%s\t%s\t%s\t%d\t%d\t%v\t%s\n",
+                extension.AssignmentID, extension.User, extension.DueDate,
+                extension.ExtraAttempts, extension.ExtraLateDays, extension.WaiveLatePenalty, extension.Reason);
+    }
+
+    return nil;
+}
+
+type RevokeExtension struct {
+    Assignment string `help:"ID of the assignment." arg:"" required:""`
+    Email string `help:"Email of the user." arg:"" required:""`
+}
+
+func (this *RevokeExtension) Run(course *model.Course) error {
+    assignment := course.Assignments[this.Assignment];
+    if (assignment == nil) {
+        return fmt.Errorf("Unknown assignment: '%s'.", this.Assignment);
+    }
+
+    exists, err := db.RemoveExtension(assignment, this.Email);
+    if (err != nil) {
+        return fmt.Errorf("Failed to remove extension: '%w'.", err);
+    }
+
+    if (!exists) {
+        return fmt.Errorf("User '%s' does not have an extension for assignment '%s'.", this.Email, this.Assignment);
+    }
+
+    fmt.Printf("Extension for user '%s' on assignment '%s' revoked.\n", this.Email, this.Assignment);
+
+    return nil;
+}
+
+var cli struct {
+    config.ConfigArgs
+    Course string `help:"ID of the course."`
+
+    Grant GrantExtension `cmd:"" help:"Grant (or replace) a user's extension for an assignment."`
+    Ls ListExtensions `cmd:"" help:"List extensions."`
+    Rm RevokeExtension `cmd:"" help:"Revoke a user's extension for an assignment."`
+}
+
+func main() {
+    context := kong.Parse(&cli,
+        kong.Description("Manage per-user assignment extensions."),
+    );
+
+    err := config.HandleConfigArgs(cli.ConfigArgs);
+    if (err != nil) {
+        log.Fatal("Could not load config options.", err);
+    }
+
+    db.MustOpen();
+    defer db.MustClose();
+
+    course := db.MustGetCourse(cli.Course);
+
+    err = context.Run(course);
+    if (err != nil) {
+        log.Fatal("Failed to run command.", err, course);
+    }
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+    "fmt"
+
+    "github.com/alecthomas/kong"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/lms/lmssync"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/util"
+)
+
+var args struct {
+    config.ConfigArgs
+    Course string `help:"ID of the course." arg:""`
+    DryRun bool `help:"Do not actually do the operation, just state what you would do." default:"false"`
+}
+
+func main() {
+    kong.Parse(&args,
+        kong.Description("Create/update a course's LMS assignments from the course's assignments (linking new LMS assignments to their local assignment)."),
+    );
+
+    err := config.HandleConfigArgs(args.ConfigArgs);
+    if (err != nil) {
+        log.Fatal("Could not load config options.", err);
+    }
+
+    db.MustOpen();
+    defer db.MustClose();
+
+    course := db.MustGetCourse(args.Course);
+
+    if (!course.HasLMSAdapter()) {
+        log.Fatal("Course has no LMS.", course);
+    }
+
+    result, err := lmssync.PushAssignments(course, args.DryRun);
+
+    // On a partial failure, still show what was done.
+    if (result != nil) {
+        fmt.Println(util.MustToJSONIndent(result));
+    }
+
+    if (err != nil) {
+        log.Fatal("Failed to push assignments to LMS.", err, course);
+    }
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+    "fmt"
+
+    "github.com/alecthomas/kong"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/grader"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/util"
+)
+
+var args struct {
+    config.ConfigArgs
+    Course string `help:"ID of the course." arg:""`
+    Assignment string `help:"ID of the assignment." arg:""`
+    All bool `help:"Regrade all submissions (instead of just the most recent one for each user)." default:"false"`
+    User []string `help:"Only regrade submissions from this user (email, role, or '*'). Can be specified multiple times."`
+    Submission []string `help:"Only regrade this (full) submission ID. Can be specified multiple times. Overrides --all and --user."`
+    DryRun bool `help:"Grade, but do not save the new results." default:"false"`
+    ChangedOnly bool `help:"Only output submissions whose score changed (or failed to regrade)." default:"false"`
+    JSON bool `help:"Output the results as JSON." default:"false"`
+}
+
+func main() {
+    kong.Parse(&args,
+        kong.Description("Regrade stored submissions for an assignment and report how each score changed."),
+    );
+
+    err := config.HandleConfigArgs(args.ConfigArgs);
+    if (err != nil) {
+        log.Fatal("Could not load config options.", err);
+    }
+
+    db.MustOpen();
+    defer db.MustClose();
+
+    assignment := db.MustGetAssignment(args.Course, args.Assignment);
+
+    options := grader.RegradeOptions{
+        All: args.All,
+        SubmissionIDs: args.Submission,
+        DryRun: args.DryRun,
+        GradeOptions: grader.GetDefaultGradeOptions(),
+    };
+
+    if (len(args.User) > 0) {
+        options.Users, err = db.ResolveUsers(assignment.GetCourse(), args.User);
+        if (err != nil) {
+            log.Fatal("Failed to resolve users.", assignment, err);
+        }
+
+        if ((len(options.Users) == 0) && (len(args.Submission) == 0)) {
+            log.Fatal("No matching users found.", assignment, log.NewAttr("users", args.User));
+        }
+    }
+
+    results, err := grader.Regrade(assignment, options);
+    if (err != nil) {
+        log.Fatal("Failed to regrade.", assignment, err);
+    }
+
+    numChanged := 0;
+    numFailed := 0;
+    outputResults := make([]*grader.RegradeResult, 0, len(results));
+
+    for _, result := range results {
+        if (result.Error != "") {
+            numFailed++;
+        } else if (result.Changed()) {
+            numChanged++;
+        } else if (args.ChangedOnly) {
+            continue;
+        }
+
+        outputResults = append(outputResults, result);
+    }
+
+    if (args.JSON) {
+        fmt.Println(util.MustToJSONIndent(outputResults));
+        return;
+    }
+
+    fmt.Println("user\tsubmission\tmax-points\told-score\tnew-score\tchange\terror");
+    for _, result := range outputResults {
+        fmt.Printf("%s\t%s\t%s\t%s\t%s\t%s\t%s\n", result.User, result.SubmissionID,
+                util.FloatToStr(result.MaxPoints), util.FloatToStr(result.OldScore), util.FloatToStr(result.NewScore),
+                util.FloatToStr(result.ScoreChange()), result.Error);
+    }
+
+    fmt.Printf("\nRegraded %d submission(s), %d changed, %d failed.", len(results) - numFailed, numChanged, numFailed);
+    if (args.DryRun) {
+        fmt.Print(" (Dry run, nothing was saved.)");
+    }
+
+    fmt.Println();
+}
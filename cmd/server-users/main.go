@@ -0,0 +1,151 @@
+package main
+
+import (
+    "fmt"
+    "slices"
+
+    "github.com/alecthomas/kong"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/util"
+)
+
+type MigrateUsers struct {
+    DryRun bool `help:"Do not actually create any server users, just state what would be done." default:"false"`
+}
+
+func (this *MigrateUsers) Run() error {
+    result, err := db.MigrateToServerUsers(this.DryRun);
+    if (err != nil) {
+        return err;
+    }
+
+    fmt.Println(util.MustToJSONIndent(result));
+
+    return nil;
+}
+
+type GetServerUser struct {
+    Email string `help:"Email for the user." arg:"" required:""`
+}
+
+func (this *GetServerUser) Run() error {
+    user, err := db.GetServerUser(this.Email);
+    if (err != nil) {
+        return fmt.Errorf("Failed to get server user: '%w'.", err);
+    }
+
+    if (user == nil) {
+        fmt.Printf("No server user found with email '%s'.\n", this.Email);
+        return nil;
+    }
+
+    enrollments, err := db.GetEnrollments(this.Email);
+    if (err != nil) {
+        return fmt.Errorf("Failed to get enrollments: '%w'.", err);
+    }
+
+    fmt.Printf("Email: '%s', Name: '%s'.\n", user.Email, user.Name);
+
+    courseIDs := make([]string, 0, len(enrollments));
+    for courseID, _ := range enrollments {
+        courseIDs = append(courseIDs, courseID);
+    }
+    slices.Sort(courseIDs);
+
+    for _, courseID := range courseIDs {
+        fmt.Printf("    Course: '%s', Role: '%s'.\n", courseID, enrollments[courseID].Role);
+    }
+
+    return nil;
+}
+
+type ListServerUsers struct {
+}
+
+func (this *ListServerUsers) Run() error {
+    users, err := db.GetServerUsers();
+    if (err != nil) {
+        return fmt.Errorf("Failed to load server users: '%w'.", err);
+    }
+
+    emails := make([]string, 0, len(users));
+    for email, _ := range users {
+        emails = append(emails, email);
+    }
+    slices.Sort(emails);
+
+    for _, email := range emails {
+        fmt.Println(email);
+    }
+
+    return nil;
+}
+
+type ChangePassword struct {
+    Email string `help:"Email for the user." arg:"" required:""`
+    Pass string `help:"Password for the user. Defaults to a random string (will be output)." short:"p"`
+}
+
+func (this *ChangePassword) Run() error {
+    user, err := db.GetServerUser(this.Email);
+    if (err != nil) {
+        return fmt.Errorf("Failed to get server user: '%w'.", err);
+    }
+
+    if (user == nil) {
+        return fmt.Errorf("Server user '%s' does not exist.", this.Email);
+    }
+
+    pass := this.Pass;
+    if (pass == "") {
+        pass, err = user.SetRandomPassword();
+    } else {
+        err = user.SetPassword(util.Sha256HexFromString(pass));
+    }
+
+    if (err != nil) {
+        return fmt.Errorf("Failed to set password: '%w'.", err);
+    }
+
+    err = db.SaveServerUser(user);
+    if (err != nil) {
+        return fmt.Errorf("Failed to save server user: '%w'.", err);
+    }
+
+    if (this.Pass == "") {
+        fmt.Printf("Generated password: '%s'.\n", pass);
+    }
+
+    return nil;
+}
+
+var cli struct {
+    config.ConfigArgs
+
+    Migrate MigrateUsers `cmd:"" help:"Create server users for all course users that do not have one (using their existing course credentials)."`
+    Get GetServerUser `cmd:"" help:"Get a server user and the courses they are enrolled in."`
+    Ls ListServerUsers `cmd:"" help:"List server users."`
+    Pass ChangePassword `cmd:"" help:"Change a server user's password (for all courses)."`
+}
+
+func main() {
+    context := kong.Parse(&cli,
+        kong.Description("Manage server users (user accounts shared by all courses)."),
+    );
+
+    err := config.HandleConfigArgs(cli.ConfigArgs);
+    if (err != nil) {
+        log.Fatal("Could not load config options.", err);
+    }
+
+    db.MustOpen();
+    defer db.MustClose();
+
+    err = context.Run();
+    if (err != nil) {
+        log.Fatal("Failed to run command.", err);
+    }
+}
@@ -8,6 +8,7 @@ import (
     "github.com/edulinq/autograder/api"
     "github.com/edulinq/autograder/config"
     "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/grader"
     "github.com/edulinq/autograder/log"
     "github.com/edulinq/autograder/model"
     "github.com/edulinq/autograder/procedures"
@@ -53,6 +54,9 @@ func main() {
         }(course);
     }
 
+    // Start grading (including any submissions that were queued before the last shutdown).
+    grader.StartGradingQueue();
+
     // Cleanup any temp dirs.
     defer util.RemoveRecordedTempDirs();
 
@@ -127,9 +127,14 @@ func (this *AuthUser) Run(course *model.Course) error {
         return fmt.Errorf("User '%s' does not exist, cannot auth.", this.Email);
     }
 
+    serverUser, err := db.GetServerUser(this.Email);
+    if (err != nil) {
+        return fmt.Errorf("Failed to get server user: '%w'.", err);
+    }
+
     passHash := util.Sha256Hex([]byte(this.Pass));
 
-    if (user.CheckPassword(passHash)) {
+    if (model.GetCredentials(user, serverUser).CheckPassword(passHash)) {
         fmt.Println("Authentication Successful");
     } else {
         fmt.Println("Authentication Failed, Bad Password");
@@ -29,6 +29,19 @@ type SavedHTTPRequest struct {
     ResponseBody string
 }
 
+// The error returned when a request gets a non-OK status (and the result is checked).
+type HTTPStatusError struct {
+    URL string
+    Method string
+    Code int
+    Headers map[string][]string
+    Body string
+}
+
+func (this *HTTPStatusError) Error() string {
+    return fmt.Sprintf("Got a non-OK status code '%d' from %s on URL '%s'.", this.Code, this.Method, this.URL);
+}
+
 // Get a binary response.
 func RawGet(uri string) ([]byte, error) {
     response, err := http.Get(uri);
@@ -202,7 +215,13 @@ func doRequest(uri string, request *http.Request, verb string, checkResult bool)
         log.Error("Got a non-OK status.",
                 log.NewAttr("code", response.StatusCode), log.NewAttr("body", body),
                 log.NewAttr("headers", response.Header), log.NewAttr("url", uri));
-        return "", nil, fmt.Errorf("Got a non-OK status code '%d' from %s on URL '%s'.", response.StatusCode, verb, uri);
+        return "", nil, &HTTPStatusError{
+            URL: uri,
+            Method: verb,
+            Code: response.StatusCode,
+            Headers: response.Header,
+            Body: body,
+        };
     }
 
     return body, response.Header, nil;
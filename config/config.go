@@ -188,6 +188,10 @@ func Set(key string, value any) {
     configValues[key] = value;
 }
 
+func Unset(key string) {
+    delete(configValues, key);
+}
+
 func GetDefault(key string, defaultValue any) any {
     value, exists := configValues[key];
     if (exists) {
@@ -15,6 +15,8 @@ const (
     CONFIG_DIRNAME = "config"
     COURSE_IMPORT_DIRNAME = "course_import"
     DATABASE_DIRNAME = "database"
+    GRADEBOOKS_DIRNAME = "gradebooks"
+    GRADING_QUEUE_DIRNAME = "grading_queue"
     LOGS_DIRNAME = "logs"
     SOURCES_DIRNAME = "sources"
 )
@@ -65,6 +67,14 @@ func GetDatabaseDir() string {
     return filepath.Join(GetWorkDir(), DATABASE_DIRNAME);
 }
 
+func GetGradebooksDir() string {
+    return filepath.Join(GetWorkDir(), GRADEBOOKS_DIRNAME);
+}
+
+func GetGradingQueueDir() string {
+    return filepath.Join(GetWorkDir(), GRADING_QUEUE_DIRNAME);
+}
+
 func GetLogsDir() string {
     return filepath.Join(GetWorkDir(), LOGS_DIRNAME);
 }
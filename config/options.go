@@ -31,6 +31,27 @@ var (
 
     // Docker
     DOCKER_DISABLE = MustNewBoolOption("docker.disable", false, "Disable the use of docker (usually for testing).");
+    DOCKER_MAX_MEMORY_MB = MustNewIntOption("docker.limits.memory", 2 * 1024,
+            "The maximum memory (in MB) a grading container can use. Assignments may set lower limits. Zero means no limit.");
+    DOCKER_MAX_CPUS = MustNewFloatOption("docker.limits.cpus", 2.0,
+            "The maximum number of CPUs a grading container can use. Assignments may set lower limits. Zero means no limit.");
+    DOCKER_MAX_PIDS = MustNewIntOption("docker.limits.pids", 512,
+            "The maximum number of processes/threads a grading container can have. Assignments may set lower limits. Zero means no limit.");
+    DOCKER_MAX_DISK_MB = MustNewIntOption("docker.limits.disk", 512,
+            "The maximum size (in MB) of the scratch space (a tmpfs over /tmp) in a grading container." +
+            " Only assignments that set a disk limit get a scratch space, and it only limits /tmp. Zero means no maximum.");
+
+    // Grading
+    GRADING_TIMEOUT_DEFAULT_SECS = MustNewIntOption("grader.timeout.default", 5 * 60,
+            "The default maximum number of seconds (wall-clock) a single grading run can take" +
+            " for assignments (and courses) that do not set their own timeout." +
+            " Zero means no limit.");
+    GRADING_WORKERS = MustNewIntOption("grader.workers", 4,
+            "The maximum number of submissions that will be graded at the same time." +
+            " Other submissions will wait in the grading queue.");
+    GRADING_QUEUE_FAIR = MustNewBoolOption("grader.queue.fair", false,
+            "Take turns between users when pulling from the grading queue (instead of first-in-first-out)," +
+            " so that one user submitting many times does not delay everyone else.");
 
     // Tasks
     NO_TASKS = MustNewBoolOption("tasks.disable", false, "Disable all scheduled tasks.");
@@ -42,9 +63,64 @@ var (
     // Server
     WEB_PORT = MustNewIntOption("web.port", 8080, "The port for the web interface to serve on.");
     WEB_MAX_FILE_SIZE_KB = MustNewIntOption("web.maxsizekb", 2 * 1024, "The maximum allowed file size (in KB) submitted via POST request. The default is 2048 KB (2 MB).");
+    WEB_TRUSTED_PROXIES = MustNewStringOption("web.proxies.trusted", "",
+            "A comma-separated list of IP addresses or CIDR ranges (e.g., '127.0.0.1,10.0.0.0/8') of trusted reverse proxies." +
+            " For requests from a trusted proxy, the client's IP address is taken from the X-Forwarded-For header." +
+            " Must be set when running behind a reverse proxy, otherwise all clients share the proxy's IP address (see api.lockout.ip.threshold).");
+
+    // Sessions
+    SESSION_KEY = MustNewStringOption("api.session.key", "",
+            "Hex-encoded key used to sign session tokens (should be put in a secrets file)." +
+            " If empty, a key will be generated and stored in the config dir.");
+    SESSION_DURATION_SECS = MustNewIntOption("api.session.duration", 24 * 60 * 60,
+            "The number of seconds that a session token (from user/auth) is valid for.");
+
+    // Login Lockout
+    LOCKOUT_THRESHOLD = MustNewIntOption("api.lockout.threshold", 5,
+            "The number of failed logins for a user before their account is temporarily locked. Zero disables account lockouts.");
+    LOCKOUT_IP_THRESHOLD = MustNewIntOption("api.lockout.ip.threshold", 50,
+            "The number of failed logins from a single IP address before that address is temporarily locked out." +
+            " Zero disables IP lockouts." +
+            " When running behind a reverse proxy, web.proxies.trusted must be set (or IP lockouts disabled).");
+    LOCKOUT_DURATION_SECS = MustNewIntOption("api.lockout.duration", 60,
+            "The number of seconds of the first lockout. Each additional failed login doubles the lockout (up to the max duration).");
+    LOCKOUT_MAX_DURATION_SECS = MustNewIntOption("api.lockout.maxduration", 60 * 60,
+            "The maximum number of seconds of a lockout." +
+            " Failed logins are also forgotten after this many seconds without another failure.");
+
+    // Password Reset
+    PASSWORD_RESET_DURATION_SECS = MustNewIntOption("password.reset.duration", 60 * 60,
+            "The number of seconds that an emailed password reset token is valid for.");
+    PASSWORD_RESET_MIN_INTERVAL_SECS = MustNewIntOption("password.reset.mininterval", 5 * 60,
+            "The minimum number of seconds between password reset emails sent to the same user.");
+    PASSWORD_RESET_URL = MustNewStringOption("password.reset.url", "",
+            "A URL (e.g., a page in a web UI) to link to in password reset emails." +
+            " The course, email, and token will be added as query parameters." +
+            " If empty, only the token will be sent.");
+
+    // OpenID Connect (SSO)
+    OIDC_ISSUER = MustNewStringOption("oidc.issuer", "",
+            "The issuer URL of an OpenID Connect provider to allow logins through. If empty, OIDC logins are disabled.");
+    OIDC_CLIENT_ID = MustNewStringOption("oidc.client.id", "", "The client ID this server is registered with at the OIDC provider.");
+    OIDC_CLIENT_SECRET = MustNewStringOption("oidc.client.secret", "",
+            "The client secret this server is registered with at the OIDC provider (should be put in a secrets file).");
+    OIDC_REDIRECT_URL = MustNewStringOption("oidc.redirect.url", "",
+            "The full public URL of this server's OIDC callback endpoint (as registered with the OIDC provider).");
+    OIDC_SUCCESS_URL = MustNewStringOption("oidc.success.url", "",
+            "A URL (e.g., a page in a web UI) to send users to after a successful OIDC login." +
+            " The course, email, and session token will be added to the URL's fragment." +
+            " If empty, the session will be returned as a normal API response.");
+
+    // LTI
+    LTI_LAUNCH_URL = MustNewStringOption("lti.launch.url", "",
+            "The full public URL of this server's LTI launch endpoint (as registered with LMSs).");
+    LTI_SUCCESS_URL = MustNewStringOption("lti.success.url", "",
+            "A URL (e.g., a page in a web UI) to send users to after a successful LTI launch." +
+            " The course, email, session token, and assignment (if any) will be added to the URL's fragment." +
+            " If empty, the session will be returned as a normal API response.");
 
     // Database
-    DB_TYPE = MustNewStringOption("db.type", "disk", "The type of database to use.");
+    DB_TYPE = MustNewStringOption("db.type", "disk", "The type of database to use. One of: disk, sqlite, postgres.");
     DB_PG_URI = MustNewStringOption("db.pg.uri", "", "Connection string to connect to a Postgres Databse. Empty if not using Postgres.");
 
     STALELOCK_DURATION_SECS = MustNewIntOption("lockmanager.staleduration", 2 * 60 * 60, "Number of seconds a lock can be unused before getting removed.");
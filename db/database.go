@@ -13,6 +13,8 @@ import (
 
     "github.com/edulinq/autograder/config"
     "github.com/edulinq/autograder/db/disk"
+    "github.com/edulinq/autograder/db/pg"
+    "github.com/edulinq/autograder/db/sqlite"
     "github.com/edulinq/autograder/log"
     "github.com/edulinq/autograder/model"
 )
@@ -74,6 +76,20 @@ type Backend interface {
     // Do nothing and return nil if the user does not exist.
     RemoveUser(course *model.Course, email string) error;
 
+    // Get all the server users.
+    GetServerUsers() (map[string]*model.ServerUser, error);
+
+    // Get a specific server user.
+    // Returns nil if no matching user exists.
+    GetServerUser(email string) (*model.ServerUser, error);
+
+    // Upsert the given server users.
+    SaveServerUsers(users map[string]*model.ServerUser) error;
+
+    // Remove a server user.
+    // Do nothing and return nil if the user does not exist.
+    RemoveServerUser(email string) error;
+
     // Remove a submission.
     // Return a bool indicating whether the submission exists or not and an error if there is one.
     RemoveSubmission(assignment *model.Assignment, email string, submissionID string) (bool, error);
@@ -123,6 +139,23 @@ type Backend interface {
     // A nil map should only be returned on error.
     GetRecentSubmissionContents(assignment *model.Assignment, filterRole model.UserRole) (map[string]*model.GradingResult, error);
 
+    // Get every submission (for all assignments and users, even removed users) in a course.
+    GetCourseSubmissions(course *model.Course) ([]*model.GradingResult, error);
+
+    // Upsert an extension.
+    SaveExtension(extension *model.Extension) error;
+
+    // Get a user's extension for an assignment.
+    // Returns nil if no matching extension exists.
+    GetExtension(assignment *model.Assignment, email string) (*model.Extension, error);
+
+    // Get all the extensions for a course.
+    GetExtensions(course *model.Course) ([]*model.Extension, error);
+
+    // Remove an extension.
+    // Do nothing and return nil if the extension does not exist.
+    RemoveExtension(assignment *model.Assignment, email string) error;
+
     // Record that a task has been completed.
     // The DB is only required to keep the most recently completed task with the given course/ID.
     LogTaskCompletion(courseID string, taskID string, instance time.Time) error;
@@ -131,6 +164,9 @@ type Backend interface {
     // Will return a zero time (time.Time{}).
     GetLastTaskCompletion(courseID string, taskID string) (time.Time, error);
 
+    // Get the last completion time of every task that has been completed for this course.
+    GetTaskCompletions(courseID string) (map[string]time.Time, error);
+
     // DB backends will also be used as logging storage backends.
     log.StorageBackend
 
@@ -147,23 +183,49 @@ func Open() error {
         return nil;
     }
 
+    var err error;
+    backend, err = OpenBackend();
+    if (err != nil) {
+        return err;
+    }
+
+    log.SetStorageBackend(backend);
+
+    return nil;
+}
+
+// Open a new backend (with its tables ensured) from the current config,
+// without making it the active database or a logging backend.
+// Most callers want Open() instead,
+// this is for tools that need to work with more than one database at a time.
+// The caller is responsible for closing the returned backend.
+func OpenBackend() (Backend, error) {
+    var newBackend Backend;
     var err error;
     dbType := config.DB_TYPE.Get();
 
     switch dbType {
         case DB_TYPE_DISK:
-            backend, err = disk.Open();
+            newBackend, err = disk.Open();
+        case DB_TYPE_SQLITE:
+            newBackend, err = sqlite.Open();
+        case DB_TYPE_POSTGRES:
+            newBackend, err = pg.Open();
         default:
             err = fmt.Errorf("Unknown database type: '%s'.", dbType);
     }
 
     if (err != nil) {
-        return fmt.Errorf("Failed to open database: %w.", err);
+        return nil, fmt.Errorf("Failed to open database: %w.", err);
     }
 
-    log.SetStorageBackend(backend);
+    err = newBackend.EnsureTables();
+    if (err != nil) {
+        newBackend.Close();
+        return nil, err;
+    }
 
-    return backend.EnsureTables();
+    return newBackend, nil;
 }
 
 func Close() error {
@@ -2,6 +2,7 @@ package db
 
 import (
     "fmt"
+    "os"
     "reflect"
     "testing"
 
@@ -12,8 +13,14 @@ import (
 // Backends to put through the standard tests.
 var testBackends []string = []string{
     DB_TYPE_DISK,
+    DB_TYPE_SQLITE,
 };
 
+// Postgres needs an external server,
+// so it is only tested when a connection URI is provided through this variable.
+// Note that the tests will clear the database.
+const PG_TEST_URI_ENV = "AUTOGRADER__TEST__PG__URI";
+
 // Methods attatched to this struct will be called for each backend in testBackends.
 type DBTests struct {
 }
@@ -36,7 +43,18 @@ func TestDatabases(test *testing.T) {
     // Quiet the logs.
     log.SetLevelFatal();
 
-    for _, dbType := range testBackends {
+    backends := testBackends;
+
+    pgURI := os.Getenv(PG_TEST_URI_ENV);
+    if (pgURI != "") {
+        oldURI := config.DB_PG_URI.Get();
+        defer config.DB_PG_URI.Set(oldURI);
+
+        config.DB_PG_URI.Set(pgURI);
+        backends = append(backends, DB_TYPE_POSTGRES);
+    }
+
+    for _, dbType := range backends {
         config.DB_TYPE.Set(dbType);
 
         PrepForTestingMain();
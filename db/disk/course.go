@@ -10,6 +10,8 @@ import (
 )
 
 const DISK_DB_COURSES_DIR = "courses";
+// Scratch space for writes that are moved into place once they are complete.
+const DISK_DB_TEMP_DIR = "tmp";
 
 func (this *backend) ClearCourse(course *model.Course) error {
     this.lock.Lock();
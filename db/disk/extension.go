@@ -0,0 +1,126 @@
+package disk
+
+import (
+    "fmt"
+    "path/filepath"
+    "slices"
+
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+const DISK_DB_EXTENSIONS_FILENAME = "extensions.json";
+
+// Extensions are stored as: {<assignment id>: {<email>: extension, ...}, ...}.
+type extensionMap map[string]map[string]*model.Extension;
+
+func (this *backend) SaveExtension(extension *model.Extension) error {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    extensions, err := this.getExtensionsLock(extension.CourseID);
+    if (err != nil) {
+        return err;
+    }
+
+    if (extensions[extension.AssignmentID] == nil) {
+        extensions[extension.AssignmentID] = make(map[string]*model.Extension);
+    }
+
+    extensions[extension.AssignmentID][extension.User] = extension;
+
+    return this.writeExtensionsLock(extension.CourseID, extensions);
+}
+
+func (this *backend) GetExtension(assignment *model.Assignment, email string) (*model.Extension, error) {
+    this.lock.RLock();
+    defer this.lock.RUnlock();
+
+    extensions, err := this.getExtensionsLock(assignment.GetCourse().GetID());
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return extensions[assignment.GetID()][email], nil;
+}
+
+func (this *backend) GetExtensions(course *model.Course) ([]*model.Extension, error) {
+    this.lock.RLock();
+    defer this.lock.RUnlock();
+
+    extensions, err := this.getExtensionsLock(course.GetID());
+    if (err != nil) {
+        return nil, err;
+    }
+
+    results := make([]*model.Extension, 0);
+    for _, assignmentExtensions := range extensions {
+        for _, extension := range assignmentExtensions {
+            results = append(results, extension);
+        }
+    }
+
+    slices.SortFunc(results, model.CompareExtensions);
+
+    return results, nil;
+}
+
+func (this *backend) RemoveExtension(assignment *model.Assignment, email string) error {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    courseID := assignment.GetCourse().GetID();
+
+    extensions, err := this.getExtensionsLock(courseID);
+    if (err != nil) {
+        return err;
+    }
+
+    _, ok := extensions[assignment.GetID()][email];
+    if (!ok) {
+        return nil;
+    }
+
+    delete(extensions[assignment.GetID()], email);
+    if (len(extensions[assignment.GetID()]) == 0) {
+        delete(extensions, assignment.GetID());
+    }
+
+    return this.writeExtensionsLock(courseID, extensions);
+}
+
+func (this *backend) getExtensionsPathFromID(courseID string) string {
+    return filepath.Join(this.getCourseDirFromID(courseID), DISK_DB_EXTENSIONS_FILENAME);
+}
+
+func (this *backend) getExtensionsLock(courseID string) (extensionMap, error) {
+    path := this.getExtensionsPathFromID(courseID);
+
+    extensions := make(extensionMap);
+    if (!util.PathExists(path)) {
+        return extensions, nil;
+    }
+
+    err := util.JSONFromFile(path, &extensions);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read extensions '%s': '%w'.", path, err);
+    }
+
+    return extensions, nil;
+}
+
+func (this *backend) writeExtensionsLock(courseID string, extensions extensionMap) error {
+    path := this.getExtensionsPathFromID(courseID);
+
+    err := util.MkDir(filepath.Dir(path));
+    if (err != nil) {
+        return fmt.Errorf("Failed to create directory for extensions '%s': '%w'.", path, err);
+    }
+
+    err = util.ToJSONFileIndent(extensions, path);
+    if (err != nil) {
+        return fmt.Errorf("Failed to write extensions '%s': '%w'.", path, err);
+    }
+
+    return nil;
+}
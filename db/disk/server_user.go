@@ -0,0 +1,93 @@
+package disk
+
+import (
+    "fmt"
+    "path/filepath"
+
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+const DISK_DB_SERVER_USERS_FILENAME = "server_users.json";
+
+func (this *backend) GetServerUsers() (map[string]*model.ServerUser, error) {
+    this.lock.RLock();
+    defer this.lock.RUnlock();
+
+    return this.getServerUsersLock();
+}
+
+func (this *backend) GetServerUser(email string) (*model.ServerUser, error) {
+    users, err := this.GetServerUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get server users when searching for '%s': '%w'.", email, err);
+    }
+
+    return users[email], nil;
+}
+
+func (this *backend) SaveServerUsers(newUsers map[string]*model.ServerUser) error {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    users, err := this.getServerUsersLock();
+    if (err != nil) {
+        return fmt.Errorf("Failed to get server users to merge before saving: '%w'.", err);
+    }
+
+    for key, value := range newUsers {
+        users[key] = value;
+    }
+
+    err = util.ToJSONFileIndent(users, this.getServerUsersPath());
+    if (err != nil) {
+        return fmt.Errorf("Unable to save server users file: '%w'.", err);
+    }
+
+    return nil;
+}
+
+func (this *backend) RemoveServerUser(email string) error {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    users, err := this.getServerUsersLock();
+    if (err != nil) {
+        return fmt.Errorf("Failed to get server users when removing for '%s': '%w'.", email, err);
+    }
+
+    _, ok := users[email];
+    if (!ok) {
+        return nil;
+    }
+
+    delete(users, email);
+
+    err = util.ToJSONFileIndent(users, this.getServerUsersPath());
+    if (err != nil) {
+        return fmt.Errorf("Unable to save server users file: '%w'.", err);
+    }
+
+    return nil;
+}
+
+// The caller should already hold the lock.
+func (this *backend) getServerUsersLock() (map[string]*model.ServerUser, error) {
+    users := make(map[string]*model.ServerUser);
+
+    path := this.getServerUsersPath();
+    if (!util.PathExists(path)) {
+        return users, nil;
+    }
+
+    err := util.JSONFromFile(path, &users);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return users, nil;
+}
+
+func (this *backend) getServerUsersPath() string {
+    return filepath.Join(this.baseDir, DISK_DB_SERVER_USERS_FILENAME);
+}
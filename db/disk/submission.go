@@ -6,7 +6,6 @@ import (
     "path/filepath"
     "time"
 
-    "github.com/edulinq/autograder/common"
     "github.com/edulinq/autograder/model"
     "github.com/edulinq/autograder/util"
 )
@@ -18,37 +17,62 @@ func (this *backend) saveSubmissionsLock(course *model.Course, submissions []*mo
     }
 
     for _, submission := range submissions {
-        baseDir := this.getSubmissionDirFromResult(submission.Info);
-        err := util.MkDir(baseDir);
-        if (err != nil) {
-            return fmt.Errorf("Failed to make submission dir '%s': '%w'.", baseDir, err);
-        }
+        submissionDir := this.getSubmissionDirFromResult(submission.Info);
 
-        resultPath := filepath.Join(baseDir, model.SUBMISSION_RESULT_FILENAME);
-        err = util.ToJSONFileIndent(submission.Info, resultPath);
+        err := this.writeSubmission(submission, submissionDir);
         if (err != nil) {
-            return fmt.Errorf("Failed to write submission result '%s': '%w'.", resultPath, err);
+            return err;
         }
+    }
 
-        err = util.GzipBytesToDirectory(filepath.Join(baseDir, common.GRADING_INPUT_DIRNAME), submission.InputFilesGZip);
-        if (err != nil) {
-            return fmt.Errorf("Failed to write submission input files: '%w'.", err);
-        }
+    return nil;
+}
 
-        err = util.GzipBytesToDirectory(filepath.Join(baseDir, common.GRADING_OUTPUT_DIRNAME), submission.OutputFilesGZip);
+// Write a submission to a temp dir and then move it into place,
+// replacing any old version of the submission (e.g. when regrading) without leaving stale files behind.
+// If the write fails, then the old version is left intact.
+func (this *backend) writeSubmission(submission *model.GradingResult, submissionDir string) error {
+    // The temp dir is inside the database, so it can be renamed into place.
+    tempBaseDir := filepath.Join(this.baseDir, DISK_DB_TEMP_DIR);
+    err := util.MkDir(tempBaseDir);
+    if (err != nil) {
+        return fmt.Errorf("Failed to make temp dir '%s': '%w'.", tempBaseDir, err);
+    }
+
+    tempDir, err := os.MkdirTemp(tempBaseDir, "submission-");
+    if (err != nil) {
+        return fmt.Errorf("Failed to make temp submission dir: '%w'.", err);
+    }
+    defer util.RemoveDirent(tempDir);
+
+    newDir := filepath.Join(tempDir, "new");
+    oldDir := filepath.Join(tempDir, "old");
+
+    err = model.WriteGradingResult(submission, newDir);
+    if (err != nil) {
+        return err;
+    }
+
+    hasOld := util.PathExists(submissionDir);
+    if (hasOld) {
+        err = os.Rename(submissionDir, oldDir);
         if (err != nil) {
-            return fmt.Errorf("Failed to write submission input files: '%w'.", err);
+            return fmt.Errorf("Failed to move old submission dir '%s': '%w'.", submissionDir, err);
         }
-
-        err = util.WriteFile(submission.Stdout, filepath.Join(baseDir, common.SUBMISSION_STDOUT_FILENAME));
+    } else {
+        err = util.MkDir(filepath.Dir(submissionDir));
         if (err != nil) {
-            return fmt.Errorf("Failed to write submission stdout file: '%w'.", err);
+            return fmt.Errorf("Failed to make user submission dir '%s': '%w'.", filepath.Dir(submissionDir), err);
         }
+    }
 
-        err = util.WriteFile(submission.Stderr, filepath.Join(baseDir, common.SUBMISSION_STDERR_FILENAME));
-        if (err != nil) {
-            return fmt.Errorf("Failed to write submission stderr file: '%w'.", err);
+    err = os.Rename(newDir, submissionDir);
+    if (err != nil) {
+        if (hasOld) {
+            os.Rename(oldDir, submissionDir);
         }
+
+        return fmt.Errorf("Failed to move new submission into '%s': '%w'.", submissionDir, err);
     }
 
     return nil;
@@ -348,3 +372,31 @@ func (this *backend) GetSubmissionAttempts(assignment *model.Assignment, email s
 
     return submissions, nil;
 }
+
+func (this *backend) GetCourseSubmissions(course *model.Course) ([]*model.GradingResult, error) {
+    this.lock.RLock();
+    defer this.lock.RUnlock();
+
+    submissions := make([]*model.GradingResult, 0);
+
+    submissionsDir := filepath.Join(this.getCourseDir(course), model.SUBMISSIONS_DIRNAME);
+    if (!util.PathExists(submissionsDir)) {
+        return submissions, nil;
+    }
+
+    resultPaths, err := util.FindFiles(model.SUBMISSION_RESULT_FILENAME, submissionsDir);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to search for submission results in '%s': '%w'.", submissionsDir, err);
+    }
+
+    for _, resultPath := range resultPaths {
+        submission, err := model.LoadGradingResult(resultPath);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        submissions = append(submissions, submission);
+    }
+
+    return submissions, nil;
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+    "fmt"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/model"
+)
+
+// Save (upsert) an extension for the given assignment.
+// The extension's course/assignment IDs will be set from the assignment,
+// and its granted time will be set if it is empty.
+func SaveExtension(assignment *model.Assignment, extension *model.Extension) error {
+    if (backend == nil) {
+        return fmt.Errorf("Database has not been opened.");
+    }
+
+    extension.CourseID = assignment.GetCourse().GetID();
+    extension.AssignmentID = assignment.GetID();
+
+    if (extension.GrantedTime.IsZero()) {
+        extension.GrantedTime = common.NowTimestamp();
+    }
+
+    err := extension.Validate();
+    if (err != nil) {
+        return fmt.Errorf("Invalid extension: '%w'.", err);
+    }
+
+    return backend.SaveExtension(extension);
+}
+
+// Get a user's extension for an assignment.
+// Returns nil if the user does not have an extension.
+func GetExtension(assignment *model.Assignment, email string) (*model.Extension, error) {
+    if (backend == nil) {
+        return nil, fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.GetExtension(assignment, email);
+}
+
+// Get all the extensions in a course, ordered by assignment and user.
+func GetExtensions(course *model.Course) ([]*model.Extension, error) {
+    if (backend == nil) {
+        return nil, fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.GetExtensions(course);
+}
+
+// Get all the extensions for an assignment, keyed by user.
+func GetAssignmentExtensions(assignment *model.Assignment) (map[string]*model.Extension, error) {
+    extensions, err := GetExtensions(assignment.GetCourse());
+    if (err != nil) {
+        return nil, err;
+    }
+
+    results := make(map[string]*model.Extension);
+    for _, extension := range extensions {
+        if (extension.AssignmentID == assignment.GetID()) {
+            results[extension.User] = extension;
+        }
+    }
+
+    return results, nil;
+}
+
+// Remove a user's extension for an assignment.
+// Returns a boolean indicating if the extension existed (and was removed).
+func RemoveExtension(assignment *model.Assignment, email string) (bool, error) {
+    if (backend == nil) {
+        return false, fmt.Errorf("Database has not been opened.");
+    }
+
+    extension, err := GetExtension(assignment, email);
+    if (err != nil) {
+        return false, err;
+    }
+
+    if (extension == nil) {
+        return false, nil;
+    }
+
+    return true, backend.RemoveExtension(assignment, email);
+}
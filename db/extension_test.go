@@ -0,0 +1,82 @@
+package db
+
+import (
+    "reflect"
+    "testing"
+
+    "github.com/edulinq/autograder/model"
+)
+
+func (this *DBTests) DBTestExtensions(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    assignment := MustGetTestAssignment();
+
+    extension, err := GetExtension(assignment, "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get missing extension: '%v'.", err);
+    }
+
+    if (extension != nil) {
+        test.Fatalf("Found an extension that should not exist: '%+v'.", extension);
+    }
+
+    expected := []*model.Extension{
+        &model.Extension{User: "student@test.com", DueDate: "2024-01-01T00:00:00Z", ExtraAttempts: 2, Reason: "a"},
+        &model.Extension{User: "other@test.com", WaiveLatePenalty: true},
+    };
+
+    for i, extension := range expected {
+        err = SaveExtension(assignment, extension);
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to save extension: '%v'.", i, err);
+        }
+    }
+
+    // Update an existing extension.
+    expected[0].ExtraLateDays = 1;
+    err = SaveExtension(assignment, expected[0]);
+    if (err != nil) {
+        test.Fatalf("Failed to update extension: '%v'.", err);
+    }
+
+    extension, err = GetExtension(assignment, "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get extension: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(expected[0], extension)) {
+        test.Fatalf("Unexpected extension. Expected: '%+v', Actual: '%+v'.", expected[0], extension);
+    }
+
+    extensions, err := GetExtensions(assignment.GetCourse());
+    if (err != nil) {
+        test.Fatalf("Failed to get extensions: '%v'.", err);
+    }
+
+    // Extensions are sorted by user.
+    expectedList := []*model.Extension{expected[1], expected[0]};
+    if (!reflect.DeepEqual(expectedList, extensions)) {
+        test.Fatalf("Unexpected extensions. Expected: '%+v', Actual: '%+v'.", expectedList, extensions);
+    }
+
+    removed, err := RemoveExtension(assignment, "student@test.com");
+    if ((err != nil) || (!removed)) {
+        test.Fatalf("Failed to remove extension (%v): '%v'.", removed, err);
+    }
+
+    removed, err = RemoveExtension(assignment, "student@test.com");
+    if ((err != nil) || (removed)) {
+        test.Fatalf("Removed a missing extension (%v): '%v'.", removed, err);
+    }
+
+    extensionMap, err := GetAssignmentExtensions(assignment);
+    if (err != nil) {
+        test.Fatalf("Failed to get assignment extensions: '%v'.", err);
+    }
+
+    if ((len(extensionMap) != 1) || (!reflect.DeepEqual(expected[1], extensionMap["other@test.com"]))) {
+        test.Fatalf("Unexpected assignment extensions: '%+v'.", extensionMap);
+    }
+}
@@ -0,0 +1,397 @@
+package db
+
+// Moving data between two (possibly different types of) database backends.
+// Unlike dumping courses, migrations also carry over task completions and log records.
+
+import (
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+// Counts of the items that were migrated (or verified).
+type MigrationStats struct {
+    Courses int `json:"courses"`
+    Assignments int `json:"assignments"`
+    Users int `json:"users"`
+    ServerUsers int `json:"server-users"`
+    Submissions int `json:"submissions"`
+    TaskCompletions int `json:"task-completions"`
+    Extensions int `json:"extensions"`
+    LogRecords int `json:"log-records"`
+}
+
+// Copy all data from the source backend into the target backend.
+// Existing data in the target is kept (matching courses/users/submissions are overwritten),
+// so this can also be used to merge several databases into one.
+// Log records are always appended, so migrating the same source twice will duplicate its logs.
+func Migrate(source Backend, target Backend) (*MigrationStats, error) {
+    stats := &MigrationStats{};
+
+    courses, err := source.GetCourses();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get source courses: '%w'.", err);
+    }
+
+    for _, course := range courses {
+        err = migrateCourse(source, target, course, stats);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to migrate course '%s': '%w'.", course.GetID(), err);
+        }
+    }
+
+    serverUsers, err := source.GetServerUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get source server users: '%w'.", err);
+    }
+
+    err = target.SaveServerUsers(serverUsers);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to save server users: '%w'.", err);
+    }
+
+    stats.ServerUsers = len(serverUsers);
+
+    records, err := getAllLogRecords(source);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get source log records: '%w'.", err);
+    }
+
+    for _, record := range records {
+        err = target.LogDirect(record);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to write log record: '%w'.", err);
+        }
+    }
+
+    stats.LogRecords = len(records);
+
+    return stats, nil;
+}
+
+func migrateCourse(source Backend, target Backend, course *model.Course, stats *MigrationStats) error {
+    err := target.SaveCourse(course);
+    if (err != nil) {
+        return fmt.Errorf("Failed to save course: '%w'.", err);
+    }
+
+    users, err := source.GetUsers(course);
+    if (err != nil) {
+        return fmt.Errorf("Failed to get source users: '%w'.", err);
+    }
+
+    err = target.SaveUsers(course, users);
+    if (err != nil) {
+        return fmt.Errorf("Failed to save users: '%w'.", err);
+    }
+
+    submissions, err := source.GetCourseSubmissions(course);
+    if (err != nil) {
+        return fmt.Errorf("Failed to get source submissions: '%w'.", err);
+    }
+
+    err = target.SaveSubmissions(course, submissions);
+    if (err != nil) {
+        return fmt.Errorf("Failed to save submissions: '%w'.", err);
+    }
+
+    tasks, err := source.GetTaskCompletions(course.GetID());
+    if (err != nil) {
+        return fmt.Errorf("Failed to get source task completions: '%w'.", err);
+    }
+
+    for taskID, instance := range tasks {
+        err = target.LogTaskCompletion(course.GetID(), taskID, instance);
+        if (err != nil) {
+            return fmt.Errorf("Failed to save completion of task '%s': '%w'.", taskID, err);
+        }
+    }
+
+    extensions, err := source.GetExtensions(course);
+    if (err != nil) {
+        return fmt.Errorf("Failed to get source extensions: '%w'.", err);
+    }
+
+    for _, extension := range extensions {
+        err = target.SaveExtension(extension);
+        if (err != nil) {
+            return fmt.Errorf("Failed to save extension for user '%s' on assignment '%s': '%w'.", extension.User, extension.AssignmentID, err);
+        }
+    }
+
+    stats.Courses++;
+    stats.Assignments += len(course.Assignments);
+    stats.Users += len(users);
+    stats.Submissions += len(submissions);
+    stats.TaskCompletions += len(tasks);
+    stats.Extensions += len(extensions);
+
+    return nil;
+}
+
+// Check that every item in the source backend exists (and is identical) in the target backend.
+// The target may contain additional data (e.g. when merging).
+// Items are compared using counts and hashes of their JSON representation.
+// All found differences will be returned in a single error.
+func VerifyMigration(source Backend, target Backend) (*MigrationStats, error) {
+    stats := &MigrationStats{};
+    errs := make([]error, 0);
+
+    courses, err := source.GetCourses();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get source courses: '%w'.", err);
+    }
+
+    for _, course := range courses {
+        courseErrs, err := verifyCourse(source, target, course, stats);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to verify course '%s': '%w'.", course.GetID(), err);
+        }
+
+        errs = append(errs, courseErrs...);
+    }
+
+    serverUserErrs, err := verifyServerUsers(source, target, stats);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    errs = append(errs, serverUserErrs...);
+
+    logErrs, err := verifyLogRecords(source, target, stats);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    errs = append(errs, logErrs...);
+
+    if (len(errs) > 0) {
+        return stats, fmt.Errorf("Found %d difference(s) between the source and target databases: '%w'.", len(errs), errors.Join(errs...));
+    }
+
+    return stats, nil;
+}
+
+// Returns (differences, error), where the error is only for failures to read data.
+func verifyCourse(source Backend, target Backend, sourceCourse *model.Course, stats *MigrationStats) ([]error, error) {
+    courseID := sourceCourse.GetID();
+    errs := make([]error, 0);
+
+    targetCourse, err := target.GetCourse(courseID);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get target course: '%w'.", err);
+    }
+
+    if (targetCourse == nil) {
+        return append(errs, fmt.Errorf("Course '%s' is missing.", courseID)), nil;
+    }
+
+    err = compareHashes(sourceCourse, targetCourse, fmt.Sprintf("course '%s'", courseID));
+    if (err != nil) {
+        errs = append(errs, err);
+    }
+
+    stats.Courses++;
+
+    for assignmentID, sourceAssignment := range sourceCourse.Assignments {
+        err = compareHashes(sourceAssignment, targetCourse.Assignments[assignmentID], fmt.Sprintf("assignment '%s'", sourceAssignment.FullID()));
+        if (err != nil) {
+            errs = append(errs, err);
+        }
+
+        stats.Assignments++;
+    }
+
+    sourceUsers, err := source.GetUsers(sourceCourse);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get source users: '%w'.", err);
+    }
+
+    targetUsers, err := target.GetUsers(targetCourse);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get target users: '%w'.", err);
+    }
+
+    for email, sourceUser := range sourceUsers {
+        err = compareHashes(sourceUser, targetUsers[email], fmt.Sprintf("user '%s' in course '%s'", email, courseID));
+        if (err != nil) {
+            errs = append(errs, err);
+        }
+
+        stats.Users++;
+    }
+
+    sourceSubmissions, err := source.GetCourseSubmissions(sourceCourse);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get source submissions: '%w'.", err);
+    }
+
+    targetSubmissionList, err := target.GetCourseSubmissions(targetCourse);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get target submissions: '%w'.", err);
+    }
+
+    targetSubmissions := make(map[string]*model.GradingResult, len(targetSubmissionList));
+    for _, submission := range targetSubmissionList {
+        targetSubmissions[submission.Info.ID] = submission;
+    }
+
+    for _, sourceSubmission := range sourceSubmissions {
+        id := sourceSubmission.Info.ID;
+        err = compareHashes(sourceSubmission, targetSubmissions[id], fmt.Sprintf("submission '%s'", id));
+        if (err != nil) {
+            errs = append(errs, err);
+        }
+
+        stats.Submissions++;
+    }
+
+    sourceTasks, err := source.GetTaskCompletions(courseID);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get source task completions: '%w'.", err);
+    }
+
+    targetTasks, err := target.GetTaskCompletions(courseID);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get target task completions: '%w'.", err);
+    }
+
+    for taskID, sourceInstance := range sourceTasks {
+        targetInstance, ok := targetTasks[taskID];
+        if (!ok) {
+            errs = append(errs, fmt.Errorf("Completion of task '%s' in course '%s' is missing.", taskID, courseID));
+        } else if (!sourceInstance.Equal(targetInstance)) {
+            errs = append(errs, fmt.Errorf("Completion of task '%s' in course '%s' does not match. Source: '%s', Target: '%s'.",
+                    taskID, courseID, sourceInstance, targetInstance));
+        }
+
+        stats.TaskCompletions++;
+    }
+
+    sourceExtensions, err := source.GetExtensions(sourceCourse);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get source extensions: '%w'.", err);
+    }
+
+    targetExtensionList, err := target.GetExtensions(targetCourse);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get target extensions: '%w'.", err);
+    }
+
+    targetExtensions := make(map[string]*model.Extension, len(targetExtensionList));
+    for _, extension := range targetExtensionList {
+        targetExtensions[extension.AssignmentID + "::" + extension.User] = extension;
+    }
+
+    for _, sourceExtension := range sourceExtensions {
+        key := sourceExtension.AssignmentID + "::" + sourceExtension.User;
+        err = compareHashes(sourceExtension, targetExtensions[key],
+                fmt.Sprintf("extension for user '%s' on assignment '%s' in course '%s'", sourceExtension.User, sourceExtension.AssignmentID, courseID));
+        if (err != nil) {
+            errs = append(errs, err);
+        }
+
+        stats.Extensions++;
+    }
+
+    return errs, nil;
+}
+
+func verifyServerUsers(source Backend, target Backend, stats *MigrationStats) ([]error, error) {
+    sourceUsers, err := source.GetServerUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get source server users: '%w'.", err);
+    }
+
+    targetUsers, err := target.GetServerUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get target server users: '%w'.", err);
+    }
+
+    errs := make([]error, 0);
+    for email, sourceUser := range sourceUsers {
+        err = compareHashes(sourceUser, targetUsers[email], fmt.Sprintf("server user '%s'", email));
+        if (err != nil) {
+            errs = append(errs, err);
+        }
+
+        stats.ServerUsers++;
+    }
+
+    return errs, nil;
+}
+
+// Every source record must have a distinct matching record in the target.
+func verifyLogRecords(source Backend, target Backend, stats *MigrationStats) ([]error, error) {
+    sourceRecords, err := getAllLogRecords(source);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get source log records: '%w'.", err);
+    }
+
+    targetRecords, err := getAllLogRecords(target);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get target log records: '%w'.", err);
+    }
+
+    targetCounts := make(map[string]int, len(targetRecords));
+    for _, record := range targetRecords {
+        hash, err := util.Sha256HashFromJSONObject(record);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to hash target log record: '%w'.", err);
+        }
+
+        targetCounts[hash]++;
+    }
+
+    missingCount := 0;
+    for _, record := range sourceRecords {
+        hash, err := util.Sha256HashFromJSONObject(record);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to hash source log record: '%w'.", err);
+        }
+
+        if (targetCounts[hash] == 0) {
+            missingCount++;
+        } else {
+            targetCounts[hash]--;
+        }
+    }
+
+    stats.LogRecords = len(sourceRecords);
+
+    if (missingCount > 0) {
+        return []error{fmt.Errorf("%d of %d log records are missing.", missingCount, len(sourceRecords))}, nil;
+    }
+
+    return nil, nil;
+}
+
+func compareHashes(source any, target any, name string) error {
+    if (util.IsNil(target)) {
+        return fmt.Errorf("The %s is missing.", name);
+    }
+
+    sourceHash, err := util.Sha256HashFromJSONObject(source);
+    if (err != nil) {
+        return fmt.Errorf("Failed to hash source %s: '%w'.", name, err);
+    }
+
+    targetHash, err := util.Sha256HashFromJSONObject(target);
+    if (err != nil) {
+        return fmt.Errorf("Failed to hash target %s: '%w'.", name, err);
+    }
+
+    if (sourceHash != targetHash) {
+        return fmt.Errorf("The %s does not match. Source hash: '%s', Target hash: '%s'.", name, sourceHash, targetHash);
+    }
+
+    return nil;
+}
+
+func getAllLogRecords(backend Backend) ([]*log.Record, error) {
+    return backend.GetLogRecords(log.LevelTrace, time.Time{}, "", "", "");
+}
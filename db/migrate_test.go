@@ -0,0 +1,107 @@
+package db
+
+import (
+    "testing"
+    "time"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func (this *DBTests) DBTestMigrate(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    course := MustGetTestCourse();
+    instance := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC);
+
+    err := LogTaskCompletion(course.GetID(), "task", instance);
+    if (err != nil) {
+        test.Fatalf("Failed to log task completion: '%v'.", err);
+    }
+
+    err = SaveExtension(MustGetTestAssignment(), &model.Extension{User: "student@test.com", ExtraAttempts: 1});
+    if (err != nil) {
+        test.Fatalf("Failed to save extension: '%v'.", err);
+    }
+
+    err = backend.LogDirect(&log.Record{Level: log.LevelInfo, Message: "migrate", UnixMicro: instance.UnixMicro(),
+            Course: course.GetID(), Attributes: map[string]any{"key": "value"}});
+    if (err != nil) {
+        test.Fatalf("Failed to write log record: '%v'.", err);
+    }
+
+    sourceSubmissions, err := backend.GetCourseSubmissions(course);
+    if (err != nil) {
+        test.Fatalf("Failed to get source submissions: '%v'.", err);
+    }
+
+    if (len(sourceSubmissions) == 0) {
+        test.Fatalf("Test course has no submissions.");
+    }
+
+    target := mustOpenMigrationTarget(test);
+    defer target.Close();
+
+    migrateStats, err := Migrate(backend, target);
+    if (err != nil) {
+        test.Fatalf("Failed to migrate: '%v'.", err);
+    }
+
+    verifyStats, err := VerifyMigration(backend, target);
+    if (err != nil) {
+        test.Fatalf("Failed to verify migration: '%v'.", err);
+    }
+
+    if (*migrateStats != *verifyStats) {
+        test.Fatalf("Migrated and verified counts do not match. Migrated: '%s', Verified: '%s'.",
+                util.MustToJSON(migrateStats), util.MustToJSON(verifyStats));
+    }
+
+    if ((migrateStats.TaskCompletions != 1) || (migrateStats.Extensions != 1) || (migrateStats.LogRecords == 0) || (migrateStats.Submissions < len(sourceSubmissions))) {
+        test.Fatalf("Unexpected migration counts: '%s'.", util.MustToJSON(migrateStats));
+    }
+
+    // Break the target and ensure verification notices.
+    info := sourceSubmissions[0].Info;
+    _, err = target.RemoveSubmission(course.Assignments[info.AssignmentID], info.User, info.ShortID);
+    if (err != nil) {
+        test.Fatalf("Failed to remove target submission: '%v'.", err);
+    }
+
+    _, err = VerifyMigration(backend, target);
+    if (err == nil) {
+        test.Fatalf("Verification did not find a removed submission.");
+    }
+}
+
+// Open a backend with a different type (or location) than the current one.
+func mustOpenMigrationTarget(test *testing.T) Backend {
+    oldType := config.DB_TYPE.Get();
+    defer config.DB_TYPE.Set(oldType);
+
+    oldBaseDir := config.BASE_DIR.Get();
+    defer config.BASE_DIR.Set(oldBaseDir);
+
+    if (oldType == DB_TYPE_DISK) {
+        config.DB_TYPE.Set(DB_TYPE_SQLITE);
+    } else {
+        config.DB_TYPE.Set(DB_TYPE_DISK);
+    }
+
+    baseDir, err := util.MkDirTemp("autograder-test-migrate-");
+    if (err != nil) {
+        test.Fatalf("Failed to make temp dir: '%v'.", err);
+    }
+
+    config.BASE_DIR.Set(baseDir);
+
+    target, err := OpenBackend();
+    if (err != nil) {
+        test.Fatalf("Failed to open target backend: '%v'.", err);
+    }
+
+    return target;
+}
@@ -0,0 +1,30 @@
+package pg
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func (this *backend) SaveAssignment(assignment *model.Assignment) error {
+    return saveAssignment(this.pool, assignment);
+}
+
+func saveAssignment(tx queryer, assignment *model.Assignment) error {
+    data, err := util.ToJSON(assignment);
+    if (err != nil) {
+        return fmt.Errorf("Failed to serialize assignment '%s': '%w'.", assignment.FullID(), err);
+    }
+
+    _, err = tx.Exec(context.Background(),
+            "INSERT INTO assignments (course_id, id, data) VALUES ($1, $2, $3)" +
+            " ON CONFLICT (course_id, id) DO UPDATE SET data = excluded.data",
+            assignment.GetCourse().GetID(), assignment.GetID(), data);
+    if (err != nil) {
+        return fmt.Errorf("Failed to save assignment '%s': '%w'.", assignment.FullID(), err);
+    }
+
+    return nil;
+}
@@ -1,14 +1,113 @@
+// A database backend that stores everything in a Postgres database.
+// Unlike the disk and SQLite backends, the database can be shared by several autograder instances.
 package pg
 
 import (
     "context"
     "fmt"
 
+    "github.com/jackc/pgx/v5"
+    "github.com/jackc/pgx/v5/pgconn"
     "github.com/jackc/pgx/v5/pgxpool"
 
     "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/log"
 )
 
+// An arbitrary key used to make sure only one instance is creating the schema at a time.
+const SCHEMA_LOCK_ID = 8675309;
+
+// Table names, in the order they should be cleared.
+var tableNames []string = []string{
+    "logs",
+    "extensions",
+    "tasks",
+    "submission_ids",
+    "submissions",
+    "users",
+    "server_users",
+    "assignments",
+    "courses",
+};
+
+// Note that "user" is reserved in Postgres, so user columns are named "user_email".
+var schema []string = []string{
+    `CREATE TABLE IF NOT EXISTS courses (
+        id TEXT PRIMARY KEY,
+        data TEXT NOT NULL
+    )`,
+    `CREATE TABLE IF NOT EXISTS assignments (
+        course_id TEXT NOT NULL,
+        id TEXT NOT NULL,
+        data TEXT NOT NULL,
+        PRIMARY KEY (course_id, id)
+    )`,
+    `CREATE TABLE IF NOT EXISTS users (
+        course_id TEXT NOT NULL,
+        email TEXT NOT NULL,
+        role INTEGER NOT NULL,
+        data TEXT NOT NULL,
+        PRIMARY KEY (course_id, email)
+    )`,
+    `CREATE TABLE IF NOT EXISTS server_users (
+        email TEXT PRIMARY KEY,
+        data TEXT NOT NULL
+    )`,
+    `CREATE TABLE IF NOT EXISTS submissions (
+        course_id TEXT NOT NULL,
+        assignment_id TEXT NOT NULL,
+        user_email TEXT NOT NULL,
+        short_id TEXT NOT NULL,
+        info TEXT NOT NULL,
+        input_files TEXT NOT NULL,
+        output_files TEXT NOT NULL,
+        stdout TEXT NOT NULL,
+        stderr TEXT NOT NULL,
+        PRIMARY KEY (course_id, assignment_id, user_email, short_id)
+    )`,
+    // Submission IDs that have been handed out (but may not have been saved yet).
+    `CREATE TABLE IF NOT EXISTS submission_ids (
+        course_id TEXT NOT NULL,
+        assignment_id TEXT NOT NULL,
+        user_email TEXT NOT NULL,
+        short_id TEXT NOT NULL,
+        PRIMARY KEY (course_id, assignment_id, user_email, short_id)
+    )`,
+    `CREATE TABLE IF NOT EXISTS tasks (
+        course_id TEXT NOT NULL,
+        task_id TEXT NOT NULL,
+        instance TEXT NOT NULL,
+        PRIMARY KEY (course_id, task_id)
+    )`,
+    `CREATE TABLE IF NOT EXISTS extensions (
+        course_id TEXT NOT NULL,
+        assignment_id TEXT NOT NULL,
+        user_email TEXT NOT NULL,
+        data TEXT NOT NULL,
+        PRIMARY KEY (course_id, assignment_id, user_email)
+    )`,
+    `CREATE TABLE IF NOT EXISTS logs (
+        id BIGSERIAL PRIMARY KEY,
+        level INTEGER NOT NULL,
+        message TEXT NOT NULL,
+        unix_micro BIGINT NOT NULL,
+        error TEXT NOT NULL,
+        course_id TEXT NOT NULL,
+        assignment_id TEXT NOT NULL,
+        user_email TEXT NOT NULL,
+        attributes TEXT
+    )`,
+    `CREATE INDEX IF NOT EXISTS logs_time ON logs (unix_micro)`,
+    `CREATE INDEX IF NOT EXISTS logs_context ON logs (course_id, assignment_id, user_email)`,
+};
+
+// Operations shared by *pgxpool.Pool and pgx.Tx.
+type queryer interface {
+    Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error);
+    Query(ctx context.Context, query string, args ...any) (pgx.Rows, error);
+    QueryRow(ctx context.Context, query string, args ...any) pgx.Row;
+}
+
 type backend struct {
     pool *pgxpool.Pool
 }
@@ -20,14 +119,76 @@ func Open() (*backend, error) {
     }
 
     pool, err := pgxpool.New(context.Background(), uri);
-	if (err != nil) {
-        return nil, fmt.Errorf("Failed to open connection pool to Postgres database at '%s': %w.", uri, err);
-	}
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to open connection pool to Postgres database: '%w'.", err);
+    }
+
+    err = pool.Ping(context.Background());
+    if (err != nil) {
+        pool.Close();
+        return nil, fmt.Errorf("Failed to connect to Postgres database: '%w'.", err);
+    }
+
+    log.Debug("Opened Postgres database.", log.NewAttr("database", pool.Config().ConnConfig.Database));
 
     return &backend{pool}, nil;
 }
 
 func (this *backend) Close() error {
-    this.pool.Close()
+    this.pool.Close();
+    return nil;
+}
+
+func (this *backend) EnsureTables() error {
+    return this.withTransaction(func(tx pgx.Tx) error {
+        // Several instances may be starting against the same database.
+        _, err := tx.Exec(context.Background(), "SELECT pg_advisory_xact_lock($1)", SCHEMA_LOCK_ID);
+        if (err != nil) {
+            return fmt.Errorf("Failed to lock Postgres schema: '%w'.", err);
+        }
+
+        for _, statement := range schema {
+            _, err = tx.Exec(context.Background(), statement);
+            if (err != nil) {
+                return fmt.Errorf("Failed to create Postgres schema: '%w'.", err);
+            }
+        }
+
+        return nil;
+    });
+}
+
+func (this *backend) Clear() error {
+    return this.withTransaction(func(tx pgx.Tx) error {
+        for _, tableName := range tableNames {
+            _, err := tx.Exec(context.Background(), "DELETE FROM " + tableName);
+            if (err != nil) {
+                return fmt.Errorf("Failed to clear table '%s': '%w'.", tableName, err);
+            }
+        }
+
+        return nil;
+    });
+}
+
+// Run the given function inside a transaction,
+// committing if it returns nil and rolling back otherwise.
+func (this *backend) withTransaction(operation func(tx pgx.Tx) error) error {
+    tx, err := this.pool.Begin(context.Background());
+    if (err != nil) {
+        return fmt.Errorf("Failed to begin transaction: '%w'.", err);
+    }
+
+    err = operation(tx);
+    if (err != nil) {
+        tx.Rollback(context.Background());
+        return err;
+    }
+
+    err = tx.Commit(context.Background());
+    if (err != nil) {
+        return fmt.Errorf("Failed to commit transaction: '%w'.", err);
+    }
+
     return nil;
 }
@@ -0,0 +1,88 @@
+package pg
+
+import (
+    "context"
+    "errors"
+    "fmt"
+
+    "github.com/jackc/pgx/v5"
+
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func (this *backend) SaveExtension(extension *model.Extension) error {
+    data, err := util.ToJSON(extension);
+    if (err != nil) {
+        return fmt.Errorf("Failed to serialize extension for user '%s': '%w'.", extension.User, err);
+    }
+
+    _, err = this.pool.Exec(context.Background(),
+            "INSERT INTO extensions (course_id, assignment_id, user_email, data) VALUES ($1, $2, $3, $4)" +
+            " ON CONFLICT (course_id, assignment_id, user_email) DO UPDATE SET data = excluded.data",
+            extension.CourseID, extension.AssignmentID, extension.User, data);
+    if (err != nil) {
+        return fmt.Errorf("Failed to save extension for user '%s': '%w'.", extension.User, err);
+    }
+
+    return nil;
+}
+
+func (this *backend) GetExtension(assignment *model.Assignment, email string) (*model.Extension, error) {
+    var data string;
+    err := this.pool.QueryRow(context.Background(),
+            "SELECT data FROM extensions WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3",
+            assignment.GetCourse().GetID(), assignment.GetID(), email).Scan(&data);
+    if (errors.Is(err, pgx.ErrNoRows)) {
+        return nil, nil;
+    }
+
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch extension for user '%s': '%w'.", email, err);
+    }
+
+    var extension model.Extension;
+    err = util.JSONFromString(data, &extension);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to deserialize extension for user '%s': '%w'.", email, err);
+    }
+
+    return &extension, nil;
+}
+
+func (this *backend) GetExtensions(course *model.Course) ([]*model.Extension, error) {
+    rows, err := this.pool.Query(context.Background(),
+            "SELECT data FROM extensions WHERE course_id = $1 ORDER BY assignment_id, user_email", course.GetID());
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch extensions for course '%s': '%w'.", course.GetID(), err);
+    }
+
+    datas, err := scanStrings(rows);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read extensions for course '%s': '%w'.", course.GetID(), err);
+    }
+
+    extensions := make([]*model.Extension, 0, len(datas));
+    for _, data := range datas {
+        var extension model.Extension;
+        err = util.JSONFromString(data, &extension);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to deserialize extension for course '%s': '%w'.", course.GetID(), err);
+        }
+
+        extensions = append(extensions, &extension);
+    }
+
+    return extensions, nil;
+}
+
+func (this *backend) RemoveExtension(assignment *model.Assignment, email string) error {
+    _, err := this.pool.Exec(context.Background(),
+            "DELETE FROM extensions WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3",
+            assignment.GetCourse().GetID(), assignment.GetID(), email);
+    if (err != nil) {
+        return fmt.Errorf("Failed to remove extension for user '%s': '%w'.", email, err);
+    }
+
+    return nil;
+}
@@ -0,0 +1,98 @@
+package pg
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/util"
+)
+
+func (this *backend) LogDirect(record *log.Record) error {
+    var attributes *string = nil;
+    if (len(record.Attributes) > 0) {
+        data, err := util.ToJSON(record.Attributes);
+        if (err != nil) {
+            return fmt.Errorf("Failed to convert log attributes to JSON: '%w'.", err);
+        }
+
+        attributes = &data;
+    }
+
+    _, err := this.pool.Exec(context.Background(),
+            "INSERT INTO logs (level, message, unix_micro, error, course_id, assignment_id, user_email, attributes)" +
+            " VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+            int(record.Level), record.Message, record.UnixMicro, record.Error,
+            record.Course, record.Assignment, record.User, attributes);
+    if (err != nil) {
+        return fmt.Errorf("Failed to write log record: '%w'.", err);
+    }
+
+    return nil;
+}
+
+func (this *backend) GetLogRecords(level log.LogLevel, after time.Time, courseID string, assignmentID string, userID string) ([]*log.Record, error) {
+    query := "SELECT level, message, unix_micro, error, course_id, assignment_id, user_email, attributes FROM logs WHERE level >= $1";
+    args := []any{int(level)};
+
+    addFilter := func(column string, operator string, value any) {
+        args = append(args, value);
+        query += fmt.Sprintf(" AND %s %s $%d", column, operator, len(args));
+    }
+
+    if (!after.IsZero()) {
+        addFilter("unix_micro", ">", after.UnixMicro());
+    }
+
+    if (courseID != "") {
+        addFilter("course_id", "=", courseID);
+    }
+
+    if (assignmentID != "") {
+        addFilter("assignment_id", "=", assignmentID);
+    }
+
+    if (userID != "") {
+        addFilter("user_email", "=", userID);
+    }
+
+    query += " ORDER BY id";
+
+    rows, err := this.pool.Query(context.Background(), query, args...);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch log records: '%w'.", err);
+    }
+    defer rows.Close();
+
+    records := make([]*log.Record, 0);
+    for rows.Next() {
+        var record log.Record;
+        var level int;
+        var attributes *string;
+
+        err = rows.Scan(&level, &record.Message, &record.UnixMicro, &record.Error,
+                &record.Course, &record.Assignment, &record.User, &attributes);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read log record: '%w'.", err);
+        }
+
+        record.Level = log.LogLevel(level);
+
+        if (attributes != nil) {
+            err = util.JSONFromString(*attributes, &record.Attributes);
+            if (err != nil) {
+                return nil, fmt.Errorf("Failed to convert log attributes from JSON: '%w'.", err);
+            }
+        }
+
+        records = append(records, &record);
+    }
+
+    err = rows.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read log records: '%w'.", err);
+    }
+
+    return records, nil;
+}
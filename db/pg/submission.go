@@ -0,0 +1,378 @@
+package pg
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "github.com/jackc/pgx/v5"
+
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+const SUBMISSION_CONTENT_COLUMNS = "info, input_files, output_files, stdout, stderr";
+
+// Select the most recent submission for each user of an assignment.
+// Short submission IDs are ordered the same way the disk backend orders its submission dirs
+// (COLLATE "C" gives plain byte ordering regardless of the database's locale).
+// Takes the course and assignment IDs as the first two parameters.
+const RECENT_SUBMISSIONS_WHERE = `
+    course_id = $1 AND assignment_id = $2
+    AND short_id = (
+        SELECT MAX(other.short_id COLLATE "C")
+        FROM submissions other
+        WHERE
+            other.course_id = submissions.course_id
+            AND other.assignment_id = submissions.assignment_id
+            AND other.user_email = submissions.user_email
+    )`;
+
+func (this *backend) SaveSubmissions(course *model.Course, submissions []*model.GradingResult) error {
+    return this.withTransaction(func(tx pgx.Tx) error {
+        return saveSubmissions(tx, submissions);
+    });
+}
+
+func saveSubmissions(tx queryer, submissions []*model.GradingResult) error {
+    for _, submission := range submissions {
+        info := submission.Info;
+
+        infoData, err := util.ToJSON(info);
+        if (err != nil) {
+            return fmt.Errorf("Failed to serialize submission result '%s': '%w'.", info.ID, err);
+        }
+
+        inputData, err := util.ToJSON(submission.InputFilesGZip);
+        if (err != nil) {
+            return fmt.Errorf("Failed to serialize submission input files '%s': '%w'.", info.ID, err);
+        }
+
+        outputData, err := util.ToJSON(submission.OutputFilesGZip);
+        if (err != nil) {
+            return fmt.Errorf("Failed to serialize submission output files '%s': '%w'.", info.ID, err);
+        }
+
+        _, err = tx.Exec(context.Background(),
+                "INSERT INTO submissions" +
+                " (course_id, assignment_id, user_email, short_id, info, input_files, output_files, stdout, stderr)" +
+                " VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)" +
+                " ON CONFLICT (course_id, assignment_id, user_email, short_id) DO UPDATE SET" +
+                " info = excluded.info, input_files = excluded.input_files, output_files = excluded.output_files," +
+                " stdout = excluded.stdout, stderr = excluded.stderr",
+                info.CourseID, info.AssignmentID, info.User, info.ShortID,
+                infoData, inputData, outputData, submission.Stdout, submission.Stderr);
+        if (err != nil) {
+            return fmt.Errorf("Failed to save submission '%s': '%w'.", info.ID, err);
+        }
+    }
+
+    return nil;
+}
+
+// Reserve the next free submission ID.
+// Several instances may share the database, so an ID is claimed by inserting it
+// (a conflict means that another request already claimed it).
+func (this *backend) GetNextSubmissionID(assignment *model.Assignment, email string) (string, error) {
+    submissionID := time.Now().Unix();
+
+    for ; ; {
+        shortID := fmt.Sprintf("%d", submissionID);
+
+        tag, err := this.pool.Exec(context.Background(),
+                "INSERT INTO submission_ids (course_id, assignment_id, user_email, short_id)" +
+                " SELECT $1, $2, $3, $4" +
+                " WHERE NOT EXISTS (SELECT 1 FROM submissions WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3 AND short_id = $4)" +
+                " ON CONFLICT DO NOTHING",
+                assignment.GetCourse().GetID(), assignment.GetID(), email, shortID);
+        if (err != nil) {
+            return "", fmt.Errorf("Failed to reserve submission id '%s': '%w'.", shortID, err);
+        }
+
+        if (tag.RowsAffected() == 1) {
+            return shortID, nil;
+        }
+
+        // This ID has been used.
+        submissionID++;
+    }
+}
+
+func (this *backend) GetSubmissionResult(assignment *model.Assignment, email string, shortSubmissionID string) (*model.GradingInfo, error) {
+    result, err := this.GetSubmissionContents(assignment, email, shortSubmissionID);
+    if ((err != nil) || (result == nil)) {
+        return nil, err;
+    }
+
+    return result.Info, nil;
+}
+
+func (this *backend) GetSubmissionHistory(assignment *model.Assignment, email string) ([]*model.SubmissionHistoryItem, error) {
+    rows, err := this.pool.Query(context.Background(),
+            "SELECT info FROM submissions WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3 ORDER BY short_id COLLATE \"C\"",
+            assignment.GetCourse().GetID(), assignment.GetID(), email);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch submission history for '%s': '%w'.", email, err);
+    }
+
+    datas, err := scanStrings(rows);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read submission history for '%s': '%w'.", email, err);
+    }
+
+    history := make([]*model.SubmissionHistoryItem, 0, len(datas));
+    for _, data := range datas {
+        var gradingInfo model.GradingInfo;
+        err = util.JSONFromString(data, &gradingInfo);
+        if (err != nil) {
+            return nil, fmt.Errorf("Unable to deserialize grading info for '%s': '%w'.", email, err);
+        }
+
+        history = append(history, gradingInfo.ToHistoryItem());
+    }
+
+    return history, nil;
+}
+
+func (this *backend) GetRecentSubmissions(assignment *model.Assignment, filterRole model.UserRole) (map[string]*model.GradingInfo, error) {
+    users, err := getUsers(this.pool, assignment.GetCourse().GetID(), filterRole);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    rows, err := this.pool.Query(context.Background(), "SELECT user_email, info FROM submissions WHERE " + RECENT_SUBMISSIONS_WHERE,
+            assignment.GetCourse().GetID(), assignment.GetID());
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch recent submissions: '%w'.", err);
+    }
+    defer rows.Close();
+
+    gradingInfos := make(map[string]*model.GradingInfo, len(users));
+    for email := range users {
+        gradingInfos[email] = nil;
+    }
+
+    for rows.Next() {
+        var email string;
+        var data string;
+
+        err = rows.Scan(&email, &data);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read recent submission: '%w'.", err);
+        }
+
+        _, ok := users[email];
+        if (!ok) {
+            continue;
+        }
+
+        var gradingInfo model.GradingInfo;
+        err = util.JSONFromString(data, &gradingInfo);
+        if (err != nil) {
+            return nil, fmt.Errorf("Unable to deserialize grading info for '%s': '%w'.", email, err);
+        }
+
+        gradingInfos[email] = &gradingInfo;
+    }
+
+    err = rows.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read recent submissions: '%w'.", err);
+    }
+
+    return gradingInfos, nil;
+}
+
+func (this *backend) GetScoringInfos(assignment *model.Assignment, filterRole model.UserRole) (map[string]*model.ScoringInfo, error) {
+    scoringInfos := make(map[string]*model.ScoringInfo);
+
+    submissionResults, err := this.GetRecentSubmissions(assignment, filterRole);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    for email, submissionResult := range submissionResults {
+        if (submissionResult == nil) {
+            scoringInfos[email] = nil;
+        } else {
+            scoringInfos[email] = submissionResult.ToScoringInfo();
+        }
+    }
+
+    return scoringInfos, nil;
+}
+
+func (this *backend) GetRecentSubmissionSurvey(assignment *model.Assignment, filterRole model.UserRole) (map[string]*model.SubmissionHistoryItem, error) {
+    results := make(map[string]*model.SubmissionHistoryItem);
+
+    submissionResults, err := this.GetRecentSubmissions(assignment, filterRole);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    for email, submissionResult := range submissionResults {
+        if (submissionResult == nil) {
+            results[email] = nil;
+        } else {
+            results[email] = submissionResult.ToHistoryItem();
+        }
+    }
+
+    return results, nil;
+}
+
+func (this *backend) GetSubmissionContents(assignment *model.Assignment, email string, shortSubmissionID string) (*model.GradingResult, error) {
+    query := "SELECT " + SUBMISSION_CONTENT_COLUMNS + " FROM submissions WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3";
+    args := []any{assignment.GetCourse().GetID(), assignment.GetID(), email};
+
+    if (shortSubmissionID == "") {
+        query += " ORDER BY short_id COLLATE \"C\" DESC LIMIT 1";
+    } else {
+        query += " AND short_id = $4";
+        args = append(args, shortSubmissionID);
+    }
+
+    rows, err := this.pool.Query(context.Background(), query, args...);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch submission for '%s': '%w'.", email, err);
+    }
+
+    results, err := scanGradingResults(rows);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    if (len(results) == 0) {
+        return nil, nil;
+    }
+
+    return results[0], nil;
+}
+
+func (this *backend) GetRecentSubmissionContents(assignment *model.Assignment, filterRole model.UserRole) (map[string]*model.GradingResult, error) {
+    users, err := getUsers(this.pool, assignment.GetCourse().GetID(), filterRole);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    rows, err := this.pool.Query(context.Background(), "SELECT " + SUBMISSION_CONTENT_COLUMNS + " FROM submissions WHERE " + RECENT_SUBMISSIONS_WHERE,
+            assignment.GetCourse().GetID(), assignment.GetID());
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch recent submissions: '%w'.", err);
+    }
+
+    submissions, err := scanGradingResults(rows);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    results := make(map[string]*model.GradingResult, len(users));
+    for email := range users {
+        results[email] = nil;
+    }
+
+    for _, submission := range submissions {
+        _, ok := users[submission.Info.User];
+        if (ok) {
+            results[submission.Info.User] = submission;
+        }
+    }
+
+    return results, nil;
+}
+
+func (this *backend) RemoveSubmission(assignment *model.Assignment, email string, shortSubmissionID string) (bool, error) {
+    query := "DELETE FROM submissions WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3";
+    args := []any{assignment.GetCourse().GetID(), assignment.GetID(), email};
+
+    if (shortSubmissionID == "") {
+        query += " AND short_id = (SELECT MAX(short_id COLLATE \"C\") FROM submissions WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3)";
+    } else {
+        query += " AND short_id = $4";
+        args = append(args, shortSubmissionID);
+    }
+
+    result, err := this.pool.Exec(context.Background(), query, args...);
+    if (err != nil) {
+        return false, fmt.Errorf("Failed to remove submission '%s': '%w'.", shortSubmissionID, err);
+    }
+
+    return (result.RowsAffected() > 0), nil;
+}
+
+func (this *backend) GetSubmissionAttempts(assignment *model.Assignment, email string) ([]*model.GradingResult, error) {
+    rows, err := this.pool.Query(context.Background(),
+            "SELECT " + SUBMISSION_CONTENT_COLUMNS + " FROM submissions" +
+            " WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3 ORDER BY short_id COLLATE \"C\"",
+            assignment.GetCourse().GetID(), assignment.GetID(), email);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch submission attempts for '%s': '%w'.", email, err);
+    }
+
+    return scanGradingResults(rows);
+}
+
+func (this *backend) GetCourseSubmissions(course *model.Course) ([]*model.GradingResult, error) {
+    courseID := course.GetID();
+
+    rows, err := this.pool.Query(context.Background(),
+            "SELECT " + SUBMISSION_CONTENT_COLUMNS + " FROM submissions" +
+            " WHERE course_id = $1 ORDER BY assignment_id, user_email, short_id COLLATE \"C\"",
+            courseID);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch submissions for course '%s': '%w'.", courseID, err);
+    }
+
+    return scanGradingResults(rows);
+}
+
+// Read all the rows (which must have the SUBMISSION_CONTENT_COLUMNS columns) and close the result.
+func scanGradingResults(rows pgx.Rows) ([]*model.GradingResult, error) {
+    defer rows.Close();
+
+    results := make([]*model.GradingResult, 0);
+    for rows.Next() {
+        var infoData string;
+        var inputData string;
+        var outputData string;
+        var result model.GradingResult;
+
+        err := rows.Scan(&infoData, &inputData, &outputData, &result.Stdout, &result.Stderr);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read submission: '%w'.", err);
+        }
+
+        err = util.JSONFromString(infoData, &result.Info);
+        if (err != nil) {
+            return nil, fmt.Errorf("Unable to deserialize grading info: '%w'.", err);
+        }
+
+        err = util.JSONFromString(inputData, &result.InputFilesGZip);
+        if (err != nil) {
+            return nil, fmt.Errorf("Unable to deserialize submission input files: '%w'.", err);
+        }
+
+        err = util.JSONFromString(outputData, &result.OutputFilesGZip);
+        if (err != nil) {
+            return nil, fmt.Errorf("Unable to deserialize submission output files: '%w'.", err);
+        }
+
+        // Match the disk backend, which always has (possibly empty) file dirs.
+        if (result.InputFilesGZip == nil) {
+            result.InputFilesGZip = make(map[string][]byte);
+        }
+
+        if (result.OutputFilesGZip == nil) {
+            result.OutputFilesGZip = make(map[string][]byte);
+        }
+
+        results = append(results, &result);
+    }
+
+    err := rows.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read submissions: '%w'.", err);
+    }
+
+    return results, nil;
+}
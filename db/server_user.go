@@ -0,0 +1,202 @@
+package db
+
+import (
+    "fmt"
+    "slices"
+
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+)
+
+// The result of creating server users from existing course users.
+type ServerUserMigrationResult struct {
+    // Emails of the server users that were created.
+    Added []string `json:"added"`
+    // Emails of users that already had a server user.
+    Existing []string `json:"existing"`
+    // Users that had different credentials in several courses,
+    // mapped to the IDs of the courses with a password (in order).
+    // These server users are created without any credentials (so they will need to reset their password).
+    // (Passwords are salted, so there is no way to tell if they are actually different.)
+    Conflicts map[string][]string `json:"conflicts"`
+}
+
+func GetServerUsers() (map[string]*model.ServerUser, error) {
+    if (backend == nil) {
+        return nil, fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.GetServerUsers();
+}
+
+// Returns nil if the user does not exist.
+func GetServerUser(email string) (*model.ServerUser, error) {
+    if (backend == nil) {
+        return nil, fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.GetServerUser(email);
+}
+
+// Insert the given server users (overriding any conflicting users).
+func SaveServerUsers(users map[string]*model.ServerUser) error {
+    if (backend == nil) {
+        return fmt.Errorf("Database has not been opened.");
+    }
+
+    return backend.SaveServerUsers(users);
+}
+
+// Convenience function for SaveServerUsers() with a single user.
+func SaveServerUser(user *model.ServerUser) error {
+    users := map[string]*model.ServerUser{user.Email: user};
+    return SaveServerUsers(users);
+}
+
+// Remove a server user (course users are not affected).
+// Returns a boolean indicating if the user existed.
+func RemoveServerUser(email string) (bool, error) {
+    if (backend == nil) {
+        return false, fmt.Errorf("Database has not been opened.");
+    }
+
+    user, err := GetServerUser(email);
+    if (err != nil) {
+        return false, err;
+    }
+
+    if (user == nil) {
+        return false, nil;
+    }
+
+    return true, backend.RemoveServerUser(email);
+}
+
+// Get all the courses that a user is enrolled in,
+// as a map of course ID to the course user (which holds the user's role).
+func GetEnrollments(email string) (map[string]*model.User, error) {
+    courses, err := GetCourses();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get courses: '%w'.", err);
+    }
+
+    enrollments := make(map[string]*model.User);
+    for courseID, course := range courses {
+        user, err := GetUser(course, email);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to get user '%s' in course '%s': '%w'.", email, courseID, err);
+        }
+
+        if (user != nil) {
+            enrollments[courseID] = user;
+        }
+    }
+
+    return enrollments, nil;
+}
+
+// Save any changes made to the credentials of a course user (see model.GetCredentials()).
+// The server user may be nil.
+func SaveCredentials(course *model.Course, user *model.User, serverUser *model.ServerUser) error {
+    if (serverUser != nil) {
+        return SaveServerUser(serverUser);
+    }
+
+    return SaveUser(course, user);
+}
+
+// Create a server user for every course user that does not already have one.
+// The user's credentials are taken from the course with a password.
+// When a user has different credentials in several courses, none of them are used
+// (since any one of them would then unlock every course), and the user will need to reset their password.
+func MigrateToServerUsers(dryRun bool) (*ServerUserMigrationResult, error) {
+    if (backend == nil) {
+        return nil, fmt.Errorf("Database has not been opened.");
+    }
+
+    serverUsers, err := GetServerUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get server users: '%w'.", err);
+    }
+
+    courses, err := GetCourses();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get courses: '%w'.", err);
+    }
+
+    courseIDs := make([]string, 0, len(courses));
+    for courseID, _ := range courses {
+        courseIDs = append(courseIDs, courseID);
+    }
+
+    slices.Sort(courseIDs);
+
+    result := &ServerUserMigrationResult{
+        Added: make([]string, 0),
+        Existing: make([]string, 0),
+        Conflicts: make(map[string][]string),
+    };
+
+    newUsers := make(map[string]*model.ServerUser);
+    // The courses (in order) that each user has a password in.
+    passCourses := make(map[string][]string);
+
+    for _, courseID := range courseIDs {
+        users, err := GetUsers(courses[courseID]);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to get users for course '%s': '%w'.", courseID, err);
+        }
+
+        for email, user := range users {
+            if (serverUsers[email] != nil) {
+                if (!slices.Contains(result.Existing, email)) {
+                    result.Existing = append(result.Existing, email);
+                }
+
+                continue;
+            }
+
+            newUser := newUsers[email];
+            if ((newUser == nil) || ((newUser.Pass == "") && (user.Pass != ""))) {
+                newUsers[email] = model.NewServerUserFromCourseUser(user);
+                newUser = newUsers[email];
+            }
+
+            if (user.Pass == "") {
+                continue;
+            }
+
+            passCourses[email] = append(passCourses[email], courseID);
+
+            if ((user.Pass != newUser.Pass) || (user.Salt != newUser.Salt)) {
+                result.Conflicts[email] = passCourses[email];
+            }
+        }
+    }
+
+    for email, _ := range result.Conflicts {
+        result.Conflicts[email] = passCourses[email];
+        newUsers[email].Credentials = model.Credentials{};
+    }
+
+    for email, _ := range newUsers {
+        result.Added = append(result.Added, email);
+    }
+
+    slices.Sort(result.Added);
+    slices.Sort(result.Existing);
+
+    if (dryRun) {
+        return result, nil;
+    }
+
+    err = SaveServerUsers(newUsers);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to save server users: '%w'.", err);
+    }
+
+    log.Info("Created server users from course users.",
+            log.NewAttr("added", len(result.Added)), log.NewAttr("conflicts", len(result.Conflicts)));
+
+    return result, nil;
+}
@@ -0,0 +1,255 @@
+package db
+
+import (
+    "reflect"
+    "slices"
+    "testing"
+
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+var testServerUserEmails []string = []string{
+    "admin@test.com",
+    "grader@test.com",
+    "no-lms-id@test.com",
+    "other@test.com",
+    "owner@test.com",
+    "student@test.com",
+};
+
+func (this *DBTests) DBTestServerUsers(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    user, err := GetServerUser("student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get missing server user: '%v'.", err);
+    }
+
+    if (user != nil) {
+        test.Fatalf("Found a server user that should not exist: '%+v'.", user);
+    }
+
+    expected := &model.ServerUser{Email: "student@test.com", Name: "student"};
+    err = expected.SetPassword(util.Sha256HexFromString("server-pass"));
+    if (err != nil) {
+        test.Fatalf("Failed to set password: '%v'.", err);
+    }
+
+    err = SaveServerUser(expected);
+    if (err != nil) {
+        test.Fatalf("Failed to save server user: '%v'.", err);
+    }
+
+    user, err = GetServerUser("student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get server user: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(expected, user)) {
+        test.Fatalf("Unexpected server user. Expected: '%+v', Actual: '%+v'.", expected, user);
+    }
+
+    users, err := GetServerUsers();
+    if (err != nil) {
+        test.Fatalf("Failed to get server users: '%v'.", err);
+    }
+
+    if (len(users) != 1) {
+        test.Fatalf("Unexpected number of server users. Expected: 1, Actual: %d.", len(users));
+    }
+
+    // Course users are not affected by server users.
+    courseUser, err := GetUser(MustGetTestCourse(), "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get course user: '%v'.", err);
+    }
+
+    if (!courseUser.CheckPassword(util.Sha256HexFromString("student"))) {
+        test.Fatalf("Course user's password was changed.");
+    }
+
+    for i, expected := range []bool{true, false} {
+        removed, err := RemoveServerUser("student@test.com");
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to remove server user: '%v'.", i, err);
+        }
+
+        if (removed != expected) {
+            test.Fatalf("Case %d: Unexpected removal result. Expected: '%v', Actual: '%v'.", i, expected, removed);
+        }
+    }
+}
+
+func (this *DBTests) DBTestMigrateToServerUsers(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    // Give a user a different password in one course.
+    course := MustGetTestCourse();
+    courseUser, err := GetUser(course, "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get course user: '%v'.", err);
+    }
+
+    err = courseUser.SetPassword(util.Sha256HexFromString("other-pass"));
+    if (err != nil) {
+        test.Fatalf("Failed to set password: '%v'.", err);
+    }
+
+    err = SaveUser(course, courseUser);
+    if (err != nil) {
+        test.Fatalf("Failed to save course user: '%v'.", err);
+    }
+
+    // Neither course's credentials will be used.
+    expected := &ServerUserMigrationResult{
+        Added: testServerUserEmails,
+        Existing: []string{},
+        Conflicts: map[string][]string{
+            "student@test.com": []string{"course-languages", "course-with-lms", "course-without-source", "course101", "course101-with-zero-limit"},
+        },
+    };
+
+    result, err := MigrateToServerUsers(true);
+    if (err != nil) {
+        test.Fatalf("Failed to do a dry run: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(expected, result)) {
+        test.Fatalf("Unexpected dry run result. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(result));
+    }
+
+    users, err := GetServerUsers();
+    if (err != nil) {
+        test.Fatalf("Failed to get server users: '%v'.", err);
+    }
+
+    if (len(users) != 0) {
+        test.Fatalf("Dry run created server users: '%s'.", util.MustToJSONIndent(users));
+    }
+
+    result, err = MigrateToServerUsers(false);
+    if (err != nil) {
+        test.Fatalf("Failed to migrate: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(expected, result)) {
+        test.Fatalf("Unexpected result. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(result));
+    }
+
+    user, err := GetServerUser("student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get server user: '%v'.", err);
+    }
+
+    for _, pass := range []string{"student", "other-pass", ""} {
+        if (user.CheckPassword(util.Sha256HexFromString(pass))) {
+            test.Fatalf("Server user with conflicting passwords has a usable password ('%s').", pass);
+        }
+    }
+
+    if ((user.Pass != "") || (user.Salt != "") || (len(user.Tokens) != 0)) {
+        test.Fatalf("Server user with conflicting passwords has credentials: '%s'.", util.MustToJSONIndent(user.Credentials));
+    }
+
+    // Users without a conflict keep their password.
+    user, err = GetServerUser("grader@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get server user: '%v'.", err);
+    }
+
+    if (!user.CheckPassword(util.Sha256HexFromString("grader"))) {
+        test.Fatalf("Server user does not have the expected password.");
+    }
+
+    // Running again does nothing.
+    expected = &ServerUserMigrationResult{
+        Added: []string{},
+        Existing: testServerUserEmails,
+        Conflicts: map[string][]string{},
+    };
+
+    result, err = MigrateToServerUsers(false);
+    if (err != nil) {
+        test.Fatalf("Failed to migrate a second time: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(expected, result)) {
+        test.Fatalf("Unexpected second result. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(result));
+    }
+}
+
+func (this *DBTests) DBTestGetEnrollments(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    enrollments, err := GetEnrollments("no-lms-id@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get enrollments: '%v'.", err);
+    }
+
+    courseIDs := make([]string, 0, len(enrollments));
+    for courseID, user := range enrollments {
+        courseIDs = append(courseIDs, courseID);
+
+        if (user.Email != "no-lms-id@test.com") {
+            test.Fatalf("Enrollment for course '%s' has the wrong user: '%s'.", courseID, user.Email);
+        }
+    }
+
+    expected := []string{"course-languages", "course-with-lms", "course-without-source"};
+    slices.Sort(courseIDs);
+
+    if (!reflect.DeepEqual(expected, courseIDs)) {
+        test.Fatalf("Unexpected enrollments. Expected: '%v', Actual: '%v'.", expected, courseIDs);
+    }
+}
+
+// Syncing a user that has a server user should not touch their credentials.
+func (this *DBTests) DBTestSyncUsersServerUser(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    _, err := MigrateToServerUsers(false);
+    if (err != nil) {
+        test.Fatalf("Failed to migrate: '%v'.", err);
+    }
+
+    course := MustGetTestCourse();
+
+    newUser := model.NewUser("student@test.com", "new name", model.RoleStudent);
+    newUser.Pass = util.Sha256HexFromString("new-pass");
+
+    result, err := SyncUser(course, newUser, true, false, false);
+    if (err != nil) {
+        test.Fatalf("Failed to sync user: '%v'.", err);
+    }
+
+    if (len(result.ClearTextPasswords) != 0) {
+        test.Fatalf("Passwords were generated: '%v'.", result.ClearTextPasswords);
+    }
+
+    courseUser, err := GetUser(course, "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get course user: '%v'.", err);
+    }
+
+    if (courseUser.Name != "new name") {
+        test.Fatalf("Course user was not updated.");
+    }
+
+    if (!courseUser.CheckPassword(util.Sha256HexFromString("student"))) {
+        test.Fatalf("Course user's password was changed.");
+    }
+
+    serverUser, err := GetServerUser("student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get server user: '%v'.", err);
+    }
+
+    if (!serverUser.CheckPassword(util.Sha256HexFromString("student"))) {
+        test.Fatalf("Server user's password was changed.");
+    }
+}
@@ -0,0 +1,268 @@
+package sqlite
+
+import (
+    "database/sql"
+    "fmt"
+    "path/filepath"
+
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+const DUMP_ASSIGNMENTS_DIRNAME = "assignments";
+const DUMP_TASKS_FILENAME = "tasks.json";
+const DUMP_EXTENSIONS_FILENAME = "extensions.json";
+
+// Tables that are keyed by course (and should be cleared with a course).
+var courseTableNames []string = []string{
+    "extensions",
+    "tasks",
+    "submissions",
+    "users",
+    "assignments",
+};
+
+func (this *backend) ClearCourse(course *model.Course) error {
+    return this.withTransaction(func(tx *sql.Tx) error {
+        for _, tableName := range courseTableNames {
+            _, err := tx.Exec("DELETE FROM " + tableName + " WHERE course_id = ?", course.GetID());
+            if (err != nil) {
+                return fmt.Errorf("Failed to clear table '%s' for course '%s': '%w'.", tableName, course.GetID(), err);
+            }
+        }
+
+        _, err := tx.Exec("DELETE FROM courses WHERE id = ?", course.GetID());
+        if (err != nil) {
+            return fmt.Errorf("Failed to remove course '%s': '%w'.", course.GetID(), err);
+        }
+
+        return nil;
+    });
+}
+
+func (this *backend) LoadCourse(path string) (*model.Course, error) {
+    course, users, submissions, err := model.FullLoadCourseFromPath(path);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    log.Debug("Loaded SQLite course.",
+            log.NewAttr("database", "sqlite"), log.NewAttr("path", path),
+            log.NewAttr("id", course.GetID()), log.NewAttr("num-assignments", len(course.Assignments)));
+
+    err = this.withTransaction(func(tx *sql.Tx) error {
+        err := saveCourse(tx, course);
+        if (err != nil) {
+            return err;
+        }
+
+        err = saveUsers(tx, course, users);
+        if (err != nil) {
+            return err;
+        }
+
+        return saveSubmissions(tx, submissions);
+    });
+
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return course, nil;
+}
+
+func (this *backend) SaveCourse(course *model.Course) error {
+    return this.withTransaction(func(tx *sql.Tx) error {
+        return saveCourse(tx, course);
+    });
+}
+
+func saveCourse(tx queryer, course *model.Course) error {
+    data, err := util.ToJSON(course);
+    if (err != nil) {
+        return fmt.Errorf("Failed to serialize course '%s': '%w'.", course.GetID(), err);
+    }
+
+    _, err = tx.Exec(
+            "INSERT INTO courses (id, data) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET data = excluded.data",
+            course.GetID(), data);
+    if (err != nil) {
+        return fmt.Errorf("Failed to save course '%s': '%w'.", course.GetID(), err);
+    }
+
+    for _, assignment := range course.Assignments {
+        err = saveAssignment(tx, assignment);
+        if (err != nil) {
+            return err;
+        }
+    }
+
+    return nil;
+}
+
+// Write out the course in the standard directory layout (the same one the disk backend uses).
+func (this *backend) DumpCourse(course *model.Course, targetDir string) error {
+    err := util.ToJSONFileIndent(course, filepath.Join(targetDir, model.COURSE_CONFIG_FILENAME));
+    if (err != nil) {
+        return fmt.Errorf("Failed to dump course config: '%w'.", err);
+    }
+
+    for _, assignment := range course.Assignments {
+        path := filepath.Join(targetDir, DUMP_ASSIGNMENTS_DIRNAME, assignment.GetID(), model.ASSIGNMENT_CONFIG_FILENAME);
+
+        err = util.MkDir(filepath.Dir(path));
+        if (err != nil) {
+            return fmt.Errorf("Failed to make assignment dump dir '%s': '%w'.", filepath.Dir(path), err);
+        }
+
+        err = util.ToJSONFileIndent(assignment, path);
+        if (err != nil) {
+            return fmt.Errorf("Failed to dump assignment '%s': '%w'.", assignment.FullID(), err);
+        }
+    }
+
+    users, err := this.GetUsers(course);
+    if (err != nil) {
+        return err;
+    }
+
+    err = util.ToJSONFileIndent(users, filepath.Join(targetDir, model.USERS_FILENAME));
+    if (err != nil) {
+        return fmt.Errorf("Failed to dump users: '%w'.", err);
+    }
+
+    submissions, err := this.GetCourseSubmissions(course);
+    if (err != nil) {
+        return err;
+    }
+
+    for _, submission := range submissions {
+        info := submission.Info;
+        dir := filepath.Join(targetDir, model.SUBMISSIONS_DIRNAME, info.AssignmentID, info.User, info.ShortID);
+
+        err = model.WriteGradingResult(submission, dir);
+        if (err != nil) {
+            return fmt.Errorf("Failed to dump submission '%s': '%w'.", info.ID, err);
+        }
+    }
+
+    tasks, err := this.GetTaskCompletions(course.GetID());
+    if (err != nil) {
+        return err;
+    }
+
+    if (len(tasks) > 0) {
+        err = util.ToJSONFileIndent(tasks, filepath.Join(targetDir, DUMP_TASKS_FILENAME));
+        if (err != nil) {
+            return fmt.Errorf("Failed to dump task log: '%w'.", err);
+        }
+    }
+
+    extensions, err := this.GetExtensions(course);
+    if (err != nil) {
+        return err;
+    }
+
+    if (len(extensions) > 0) {
+        // Use the same {<assignment id>: {<email>: extension}} layout as the disk backend.
+        extensionMap := make(map[string]map[string]*model.Extension);
+        for _, extension := range extensions {
+            if (extensionMap[extension.AssignmentID] == nil) {
+                extensionMap[extension.AssignmentID] = make(map[string]*model.Extension);
+            }
+
+            extensionMap[extension.AssignmentID][extension.User] = extension;
+        }
+
+        err = util.ToJSONFileIndent(extensionMap, filepath.Join(targetDir, DUMP_EXTENSIONS_FILENAME));
+        if (err != nil) {
+            return fmt.Errorf("Failed to dump extensions: '%w'.", err);
+        }
+    }
+
+    return nil;
+}
+
+func (this *backend) GetCourse(courseID string) (*model.Course, error) {
+    var data string;
+    err := this.db.QueryRow("SELECT data FROM courses WHERE id = ?", courseID).Scan(&data);
+    if (err == sql.ErrNoRows) {
+        return nil, nil;
+    }
+
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch course '%s': '%w'.", courseID, err);
+    }
+
+    return this.loadCourse(data);
+}
+
+func (this *backend) GetCourses() (map[string]*model.Course, error) {
+    rows, err := this.db.Query("SELECT data FROM courses ORDER BY id");
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch courses: '%w'.", err);
+    }
+
+    datas, err := scanStrings(rows);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read courses: '%w'.", err);
+    }
+
+    courses := make(map[string]*model.Course, len(datas));
+    for _, data := range datas {
+        course, err := this.loadCourse(data);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        courses[course.GetID()] = course;
+    }
+
+    return courses, nil;
+}
+
+// Build a full course (including assignments) from a course's stored JSON.
+func (this *backend) loadCourse(data string) (*model.Course, error) {
+    course, err := model.ReadCourseConfigFromJSON(data);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    rows, err := this.db.Query("SELECT data FROM assignments WHERE course_id = ? ORDER BY id", course.GetID());
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch assignments for course '%s': '%w'.", course.GetID(), err);
+    }
+
+    assignmentDatas, err := scanStrings(rows);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read assignments for course '%s': '%w'.", course.GetID(), err);
+    }
+
+    for _, assignmentData := range assignmentDatas {
+        _, err = model.ReadAssignmentConfigFromJSON(course, assignmentData);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to load assignment for course '%s': '%w'.", course.GetID(), err);
+        }
+    }
+
+    return course, nil;
+}
+
+// Read all the rows (which must have a single string column) and close the result.
+func scanStrings(rows *sql.Rows) ([]string, error) {
+    defer rows.Close();
+
+    values := make([]string, 0);
+    for rows.Next() {
+        var value string;
+        err := rows.Scan(&value);
+        if (err != nil) {
+            return nil, err;
+        }
+
+        values = append(values, value);
+    }
+
+    return values, rows.Err();
+}
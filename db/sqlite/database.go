@@ -0,0 +1,187 @@
+// A database backend that stores everything in a single SQLite file.
+// No external server is required, but unlike the disk backend,
+// data is indexed and can be queried without walking directories.
+package sqlite
+
+import (
+    "database/sql"
+    "fmt"
+    "path/filepath"
+
+    _ "github.com/mattn/go-sqlite3"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/util"
+)
+
+const (
+    DB_FILENAME = "sqlite.db";
+    DRIVER_NAME = "sqlite3";
+
+    // WAL lets readers proceed while a writer is active,
+    // and immediate transactions avoid deadlocks when upgrading to a write lock.
+    DSN_OPTIONS = "_journal_mode=WAL&_busy_timeout=10000&_txlock=immediate";
+)
+
+// Table names, in the order they should be cleared.
+var tableNames []string = []string{
+    "logs",
+    "extensions",
+    "tasks",
+    "submissions",
+    "users",
+    "server_users",
+    "assignments",
+    "courses",
+};
+
+var schema []string = []string{
+    `CREATE TABLE IF NOT EXISTS courses (
+        id TEXT PRIMARY KEY,
+        data TEXT NOT NULL
+    )`,
+    `CREATE TABLE IF NOT EXISTS assignments (
+        course_id TEXT NOT NULL,
+        id TEXT NOT NULL,
+        data TEXT NOT NULL,
+        PRIMARY KEY (course_id, id)
+    )`,
+    `CREATE TABLE IF NOT EXISTS users (
+        course_id TEXT NOT NULL,
+        email TEXT NOT NULL,
+        role INTEGER NOT NULL,
+        data TEXT NOT NULL,
+        PRIMARY KEY (course_id, email)
+    )`,
+    `CREATE TABLE IF NOT EXISTS server_users (
+        email TEXT PRIMARY KEY,
+        data TEXT NOT NULL
+    )`,
+    `CREATE TABLE IF NOT EXISTS submissions (
+        course_id TEXT NOT NULL,
+        assignment_id TEXT NOT NULL,
+        user TEXT NOT NULL,
+        short_id TEXT NOT NULL,
+        info TEXT NOT NULL,
+        input_files TEXT NOT NULL,
+        output_files TEXT NOT NULL,
+        stdout TEXT NOT NULL,
+        stderr TEXT NOT NULL,
+        PRIMARY KEY (course_id, assignment_id, user, short_id)
+    )`,
+    `CREATE TABLE IF NOT EXISTS tasks (
+        course_id TEXT NOT NULL,
+        task_id TEXT NOT NULL,
+        instance TEXT NOT NULL,
+        PRIMARY KEY (course_id, task_id)
+    )`,
+    `CREATE TABLE IF NOT EXISTS extensions (
+        course_id TEXT NOT NULL,
+        assignment_id TEXT NOT NULL,
+        user TEXT NOT NULL,
+        data TEXT NOT NULL,
+        PRIMARY KEY (course_id, assignment_id, user)
+    )`,
+    `CREATE TABLE IF NOT EXISTS logs (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        level INTEGER NOT NULL,
+        message TEXT NOT NULL,
+        unix_micro INTEGER NOT NULL,
+        error TEXT NOT NULL,
+        course_id TEXT NOT NULL,
+        assignment_id TEXT NOT NULL,
+        user TEXT NOT NULL,
+        attributes TEXT
+    )`,
+    `CREATE INDEX IF NOT EXISTS logs_time ON logs (unix_micro)`,
+    `CREATE INDEX IF NOT EXISTS logs_context ON logs (course_id, assignment_id, user)`,
+};
+
+// Operations shared by *sql.DB and *sql.Tx.
+type queryer interface {
+    Exec(query string, args ...any) (sql.Result, error);
+    Query(query string, args ...any) (*sql.Rows, error);
+    QueryRow(query string, args ...any) *sql.Row;
+}
+
+type backend struct {
+    path string
+    db *sql.DB
+}
+
+func Open() (*backend, error) {
+    baseDir := util.ShouldAbs(config.GetDatabaseDir());
+
+    err := util.MkDir(baseDir);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to make db dir '%s': '%w'.", baseDir, err);
+    }
+
+    path := filepath.Join(baseDir, DB_FILENAME);
+
+    db, err := sql.Open(DRIVER_NAME, fmt.Sprintf("file:%s?%s", path, DSN_OPTIONS));
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to open SQLite database '%s': '%w'.", path, err);
+    }
+
+    err = db.Ping();
+    if (err != nil) {
+        db.Close();
+        return nil, fmt.Errorf("Failed to connect to SQLite database '%s': '%w'.", path, err);
+    }
+
+    log.Debug("Opened SQLite database.", log.NewAttr("path", path));
+
+    return &backend{path: path, db: db}, nil;
+}
+
+func (this *backend) Close() error {
+    return this.db.Close();
+}
+
+func (this *backend) EnsureTables() error {
+    for _, statement := range schema {
+        _, err := this.db.Exec(statement);
+        if (err != nil) {
+            return fmt.Errorf("Failed to create SQLite schema: '%w'.", err);
+        }
+    }
+
+    return nil;
+}
+
+func (this *backend) Clear() error {
+    return this.withTransaction(func(tx *sql.Tx) error {
+        for _, tableName := range tableNames {
+            _, err := tx.Exec("DELETE FROM " + tableName);
+            if (err != nil) {
+                return fmt.Errorf("Failed to clear table '%s': '%w'.", tableName, err);
+            }
+        }
+
+        return nil;
+    });
+}
+
+// Run the given function inside a transaction,
+// committing if it returns nil and rolling back otherwise.
+func (this *backend) withTransaction(operation func(tx *sql.Tx) error) error {
+    tx, err := this.db.Begin();
+    if (err != nil) {
+        return fmt.Errorf("Failed to begin transaction: '%w'.", err);
+    }
+
+    err = operation(tx);
+    if (err != nil) {
+        tx.Rollback();
+        return err;
+    }
+
+    err = tx.Commit();
+    if (err != nil) {
+        return fmt.Errorf("Failed to commit transaction: '%w'.", err);
+    }
+
+    return nil;
+}
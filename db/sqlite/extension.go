@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+    "database/sql"
+    "fmt"
+
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func (this *backend) SaveExtension(extension *model.Extension) error {
+    data, err := util.ToJSON(extension);
+    if (err != nil) {
+        return fmt.Errorf("Failed to serialize extension for user '%s': '%w'.", extension.User, err);
+    }
+
+    _, err = this.db.Exec(
+            "INSERT INTO extensions (course_id, assignment_id, user, data) VALUES (?, ?, ?, ?)" +
+            " ON CONFLICT (course_id, assignment_id, user) DO UPDATE SET data = excluded.data",
+            extension.CourseID, extension.AssignmentID, extension.User, data);
+    if (err != nil) {
+        return fmt.Errorf("Failed to save extension for user '%s': '%w'.", extension.User, err);
+    }
+
+    return nil;
+}
+
+func (this *backend) GetExtension(assignment *model.Assignment, email string) (*model.Extension, error) {
+    var data string;
+    err := this.db.QueryRow("SELECT data FROM extensions WHERE course_id = ? AND assignment_id = ? AND user = ?",
+            assignment.GetCourse().GetID(), assignment.GetID(), email).Scan(&data);
+    if (err == sql.ErrNoRows) {
+        return nil, nil;
+    }
+
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch extension for user '%s': '%w'.", email, err);
+    }
+
+    var extension model.Extension;
+    err = util.JSONFromString(data, &extension);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to deserialize extension for user '%s': '%w'.", email, err);
+    }
+
+    return &extension, nil;
+}
+
+func (this *backend) GetExtensions(course *model.Course) ([]*model.Extension, error) {
+    rows, err := this.db.Query("SELECT data FROM extensions WHERE course_id = ? ORDER BY assignment_id, user", course.GetID());
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch extensions for course '%s': '%w'.", course.GetID(), err);
+    }
+    defer rows.Close();
+
+    extensions := make([]*model.Extension, 0);
+    for rows.Next() {
+        var data string;
+        err = rows.Scan(&data);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read extension for course '%s': '%w'.", course.GetID(), err);
+        }
+
+        var extension model.Extension;
+        err = util.JSONFromString(data, &extension);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to deserialize extension for course '%s': '%w'.", course.GetID(), err);
+        }
+
+        extensions = append(extensions, &extension);
+    }
+
+    err = rows.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read extensions for course '%s': '%w'.", course.GetID(), err);
+    }
+
+    return extensions, nil;
+}
+
+func (this *backend) RemoveExtension(assignment *model.Assignment, email string) error {
+    _, err := this.db.Exec("DELETE FROM extensions WHERE course_id = ? AND assignment_id = ? AND user = ?",
+            assignment.GetCourse().GetID(), assignment.GetID(), email);
+    if (err != nil) {
+        return fmt.Errorf("Failed to remove extension for user '%s': '%w'.", email, err);
+    }
+
+    return nil;
+}
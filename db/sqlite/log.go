@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+    "database/sql"
+    "fmt"
+    "time"
+
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/util"
+)
+
+func (this *backend) LogDirect(record *log.Record) error {
+    var attributes sql.NullString;
+    if (len(record.Attributes) > 0) {
+        data, err := util.ToJSON(record.Attributes);
+        if (err != nil) {
+            return fmt.Errorf("Failed to convert log attributes to JSON: '%w'.", err);
+        }
+
+        attributes = sql.NullString{String: data, Valid: true};
+    }
+
+    _, err := this.db.Exec(
+            "INSERT INTO logs (level, message, unix_micro, error, course_id, assignment_id, user, attributes)" +
+            " VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+            int(record.Level), record.Message, record.UnixMicro, record.Error,
+            record.Course, record.Assignment, record.User, attributes);
+    if (err != nil) {
+        return fmt.Errorf("Failed to write log record: '%w'.", err);
+    }
+
+    return nil;
+}
+
+func (this *backend) GetLogRecords(level log.LogLevel, after time.Time, courseID string, assignmentID string, userID string) ([]*log.Record, error) {
+    query := "SELECT level, message, unix_micro, error, course_id, assignment_id, user, attributes FROM logs WHERE level >= ?";
+    args := []any{int(level)};
+
+    if (!after.IsZero()) {
+        query += " AND unix_micro > ?";
+        args = append(args, after.UnixMicro());
+    }
+
+    if (courseID != "") {
+        query += " AND course_id = ?";
+        args = append(args, courseID);
+    }
+
+    if (assignmentID != "") {
+        query += " AND assignment_id = ?";
+        args = append(args, assignmentID);
+    }
+
+    if (userID != "") {
+        query += " AND user = ?";
+        args = append(args, userID);
+    }
+
+    query += " ORDER BY id";
+
+    rows, err := this.db.Query(query, args...);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch log records: '%w'.", err);
+    }
+    defer rows.Close();
+
+    records := make([]*log.Record, 0);
+    for rows.Next() {
+        var record log.Record;
+        var attributes sql.NullString;
+
+        err = rows.Scan(&record.Level, &record.Message, &record.UnixMicro, &record.Error,
+                &record.Course, &record.Assignment, &record.User, &attributes);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read log record: '%w'.", err);
+        }
+
+        if (attributes.Valid) {
+            err = util.JSONFromString(attributes.String, &record.Attributes);
+            if (err != nil) {
+                return nil, fmt.Errorf("Failed to convert log attributes from JSON: '%w'.", err);
+            }
+        }
+
+        records = append(records, &record);
+    }
+
+    err = rows.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read log records: '%w'.", err);
+    }
+
+    return records, nil;
+}
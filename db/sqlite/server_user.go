@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+    "database/sql"
+    "fmt"
+
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func (this *backend) GetServerUsers() (map[string]*model.ServerUser, error) {
+    rows, err := this.db.Query("SELECT email, data FROM server_users");
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch server users: '%w'.", err);
+    }
+
+    defer rows.Close();
+
+    users := make(map[string]*model.ServerUser);
+    for rows.Next() {
+        var email string;
+        var data string;
+
+        err = rows.Scan(&email, &data);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read server user: '%w'.", err);
+        }
+
+        var user model.ServerUser;
+        err = util.JSONFromString(data, &user);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to deserialize server user '%s': '%w'.", email, err);
+        }
+
+        users[email] = &user;
+    }
+
+    err = rows.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read server users: '%w'.", err);
+    }
+
+    return users, nil;
+}
+
+func (this *backend) GetServerUser(email string) (*model.ServerUser, error) {
+    var data string;
+    err := this.db.QueryRow("SELECT data FROM server_users WHERE email = ?", email).Scan(&data);
+    if (err == sql.ErrNoRows) {
+        return nil, nil;
+    }
+
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch server user '%s': '%w'.", email, err);
+    }
+
+    var user model.ServerUser;
+    err = util.JSONFromString(data, &user);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to deserialize server user '%s': '%w'.", email, err);
+    }
+
+    return &user, nil;
+}
+
+func (this *backend) SaveServerUsers(users map[string]*model.ServerUser) error {
+    return this.withTransaction(func(tx *sql.Tx) error {
+        for email, user := range users {
+            data, err := util.ToJSON(user);
+            if (err != nil) {
+                return fmt.Errorf("Failed to serialize server user '%s': '%w'.", email, err);
+            }
+
+            _, err = tx.Exec(
+                    "INSERT INTO server_users (email, data) VALUES (?, ?)" +
+                    " ON CONFLICT (email) DO UPDATE SET data = excluded.data",
+                    email, data);
+            if (err != nil) {
+                return fmt.Errorf("Failed to save server user '%s': '%w'.", email, err);
+            }
+        }
+
+        return nil;
+    });
+}
+
+func (this *backend) RemoveServerUser(email string) error {
+    _, err := this.db.Exec("DELETE FROM server_users WHERE email = ?", email);
+    if (err != nil) {
+        return fmt.Errorf("Failed to remove server user '%s': '%w'.", email, err);
+    }
+
+    return nil;
+}
@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+    "database/sql"
+    "fmt"
+    "time"
+)
+
+func (this *backend) LogTaskCompletion(courseID string, taskID string, instance time.Time) error {
+    _, err := this.db.Exec(
+            "INSERT INTO tasks (course_id, task_id, instance) VALUES (?, ?, ?)" +
+            " ON CONFLICT (course_id, task_id) DO UPDATE SET instance = excluded.instance",
+            courseID, taskID, instance.Format(time.RFC3339Nano));
+    if (err != nil) {
+        return fmt.Errorf("Failed to log completion of task '%s' for course '%s': '%w'.", taskID, courseID, err);
+    }
+
+    return nil;
+}
+
+func (this *backend) GetLastTaskCompletion(courseID string, taskID string) (time.Time, error) {
+    var text string;
+    err := this.db.QueryRow("SELECT instance FROM tasks WHERE course_id = ? AND task_id = ?", courseID, taskID).Scan(&text);
+    if (err == sql.ErrNoRows) {
+        return time.Time{}, nil;
+    }
+
+    if (err != nil) {
+        return time.Time{}, fmt.Errorf("Failed to fetch completion of task '%s' for course '%s': '%w'.", taskID, courseID, err);
+    }
+
+    instance, err := time.Parse(time.RFC3339Nano, text);
+    if (err != nil) {
+        return time.Time{}, fmt.Errorf("Failed to parse completion time of task '%s' for course '%s': '%w'.", taskID, courseID, err);
+    }
+
+    return instance, nil;
+}
+
+func (this *backend) GetTaskCompletions(courseID string) (map[string]time.Time, error) {
+    rows, err := this.db.Query("SELECT task_id, instance FROM tasks WHERE course_id = ?", courseID);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch task log for course '%s': '%w'.", courseID, err);
+    }
+    defer rows.Close();
+
+    log := make(map[string]time.Time);
+    for rows.Next() {
+        var taskID string;
+        var text string;
+
+        err = rows.Scan(&taskID, &text);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read task log for course '%s': '%w'.", courseID, err);
+        }
+
+        log[taskID], err = time.Parse(time.RFC3339Nano, text);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to parse completion time of task '%s' for course '%s': '%w'.", taskID, courseID, err);
+        }
+    }
+
+    return log, rows.Err();
+}
@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+    "database/sql"
+    "fmt"
+
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func (this *backend) GetUsers(course *model.Course) (map[string]*model.User, error) {
+    return getUsers(this.db, course.GetID(), model.RoleUnknown);
+}
+
+// Get all the users in a course that match the given role (model.RoleUnknown matches all roles).
+func getUsers(tx queryer, courseID string, filterRole model.UserRole) (map[string]*model.User, error) {
+    query := "SELECT email, data FROM users WHERE course_id = ?";
+    args := []any{courseID};
+
+    if (filterRole != model.RoleUnknown) {
+        query += " AND role = ?";
+        args = append(args, int(filterRole));
+    }
+
+    rows, err := tx.Query(query, args...);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch users for course '%s': '%w'.", courseID, err);
+    }
+
+    defer rows.Close();
+
+    users := make(map[string]*model.User);
+    for rows.Next() {
+        var email string;
+        var data string;
+
+        err = rows.Scan(&email, &data);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read user for course '%s': '%w'.", courseID, err);
+        }
+
+        var user model.User;
+        err = util.JSONFromString(data, &user);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to deserialize user '%s' for course '%s': '%w'.", email, courseID, err);
+        }
+
+        users[email] = &user;
+    }
+
+    err = rows.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read users for course '%s': '%w'.", courseID, err);
+    }
+
+    return users, nil;
+}
+
+func (this *backend) GetUser(course *model.Course, email string) (*model.User, error) {
+    var data string;
+    err := this.db.QueryRow("SELECT data FROM users WHERE course_id = ? AND email = ?", course.GetID(), email).Scan(&data);
+    if (err == sql.ErrNoRows) {
+        return nil, nil;
+    }
+
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch user '%s': '%w'.", email, err);
+    }
+
+    var user model.User;
+    err = util.JSONFromString(data, &user);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to deserialize user '%s': '%w'.", email, err);
+    }
+
+    return &user, nil;
+}
+
+func (this *backend) SaveUsers(course *model.Course, users map[string]*model.User) error {
+    return this.withTransaction(func(tx *sql.Tx) error {
+        return saveUsers(tx, course, users);
+    });
+}
+
+func saveUsers(tx queryer, course *model.Course, users map[string]*model.User) error {
+    for email, user := range users {
+        data, err := util.ToJSON(user);
+        if (err != nil) {
+            return fmt.Errorf("Failed to serialize user '%s': '%w'.", email, err);
+        }
+
+        _, err = tx.Exec(
+                "INSERT INTO users (course_id, email, role, data) VALUES (?, ?, ?, ?)" +
+                " ON CONFLICT (course_id, email) DO UPDATE SET role = excluded.role, data = excluded.data",
+                course.GetID(), email, int(user.Role), data);
+        if (err != nil) {
+            return fmt.Errorf("Failed to save user '%s': '%w'.", email, err);
+        }
+    }
+
+    return nil;
+}
+
+func (this *backend) RemoveUser(course *model.Course, email string) error {
+    _, err := this.db.Exec("DELETE FROM users WHERE course_id = ? AND email = ?", course.GetID(), email);
+    if (err != nil) {
+        return fmt.Errorf("Failed to remove user '%s': '%w'.", email, err);
+    }
+
+    return nil;
+}
@@ -2,8 +2,11 @@ package db
 
 import (
     "reflect"
+    "sync"
     "testing"
 
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/model"
     "github.com/edulinq/autograder/util"
 )
 
@@ -63,6 +66,45 @@ func (this *DBTests) DBTestRemoveSubmission(test *testing.T) {
     }
 }
 
+// Postgres may be shared by several instances, so it must hand out an ID only once (even before it is saved).
+func (this *DBTests) DBTestGetNextSubmissionIDConcurrent(test *testing.T) {
+    if (config.DB_TYPE.Get() != DB_TYPE_POSTGRES) {
+        test.Skip("Only Postgres reserves submission IDs.");
+    }
+
+    defer ResetForTesting();
+
+    assignment := MustGetTestAssignment();
+    count := 10;
+
+    ids := make([]string, count);
+    errs := make([]error, count);
+
+    var waitGroup sync.WaitGroup;
+    for i := 0; i < count; i++ {
+        waitGroup.Add(1);
+        go func(index int) {
+            defer waitGroup.Done();
+            ids[index], errs[index] = GetNextSubmissionID(assignment, "student@test.com");
+        }(i);
+    }
+
+    waitGroup.Wait();
+
+    seen := make(map[string]bool, count);
+    for i := 0; i < count; i++ {
+        if (errs[i] != nil) {
+            test.Fatalf("Failed to get submission ID %d: '%v'.", i, errs[i]);
+        }
+
+        if (seen[ids[i]]) {
+            test.Fatalf("Submission ID '%s' was handed out more than once.", ids[i]);
+        }
+
+        seen[ids[i]] = true;
+    }
+}
+
 // Tests GetSubmissionAttempts as follows:
 // A) Fetch all attempts from a user who has submissions and check that the result is not empty.
 // B) Fetch attempts from a user who has no submissions and check that the result is empty.
@@ -136,3 +178,49 @@ func (this *DBTests) DBTestFetchAttempts(test *testing.T) {
         test.Fatalf("Unexpected result length. Expected: '%d', Actual: '%d'.", 0, len(graderAttempts));
     }
 }
+
+func (this *DBTests) DBTestGetRecentSubmissions(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    assignment := MustGetTestAssignment();
+
+    testCases := []struct{role model.UserRole; expected map[string]string}{
+        {model.RoleUnknown, map[string]string{
+            "owner@test.com": "",
+            "admin@test.com": "",
+            "grader@test.com": "",
+            "student@test.com": "1697406272",
+            "other@test.com": "",
+        }},
+        {model.RoleStudent, map[string]string{
+            "student@test.com": "1697406272",
+        }},
+        {model.RoleGrader, map[string]string{
+            "grader@test.com": "",
+        }},
+    };
+
+    for i, testCase := range testCases {
+        results, err := GetRecentSubmissions(assignment, testCase.role);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to get recent submissions: '%v'.", i, err);
+            continue;
+        }
+
+        actual := make(map[string]string, len(results));
+        for email, result := range results {
+            if (result == nil) {
+                actual[email] = "";
+            } else {
+                actual[email] = result.ShortID;
+            }
+        }
+
+        if (!reflect.DeepEqual(testCase.expected, actual)) {
+            test.Errorf("Case %d: Unexpected recent submissions. Expected: '%s', Actual: '%s'.", i,
+                    util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(actual));
+            continue;
+        }
+    }
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+    "testing"
+    "time"
+)
+
+func (this *DBTests) DBTestTaskCompletion(test *testing.T) {
+    defer ResetForTesting();
+    ResetForTesting();
+
+    courseID := MustGetTestCourse().GetID();
+
+    instance, err := GetLastTaskCompletion(courseID, "task");
+    if (err != nil) {
+        test.Fatalf("Failed to get missing task completion: '%v'.", err);
+    }
+
+    if (!instance.IsZero()) {
+        test.Fatalf("Missing task has a non-zero completion: '%v'.", instance);
+    }
+
+    first := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC);
+    second := first.Add(time.Hour);
+
+    for i, expected := range []time.Time{first, second} {
+        err = LogTaskCompletion(courseID, "task", expected);
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to log task completion: '%v'.", i, err);
+        }
+
+        instance, err = GetLastTaskCompletion(courseID, "task");
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to get task completion: '%v'.", i, err);
+        }
+
+        if (!expected.Equal(instance)) {
+            test.Fatalf("Case %d: Unexpected task completion. Expected: '%v', Actual: '%v'.", i, expected, instance);
+        }
+    }
+}
@@ -117,9 +117,10 @@ func SyncUser(course *model.Course, user *model.User,
 // The db takes ownership of the passed-in users (they may be modified).
 // If |merge| is true, then existing users will be updated with non-empty fields.
 // Otherwise existing users will be ignored.
-// Any non-ignored user WILL have their password changed.
+// Any non-ignored user WILL have their password changed (unless they have a server user).
 // Passwords should either be left empty (and they will be randomly generated),
 // or set to the hash of the desired password.
+// Users with a server user get their credentials from it, so their passwords are left alone here.
 func SyncUsers(course *model.Course, newUsers map[string]*model.User,
         merge bool, dryRun bool, sendEmails bool) (*model.UserSyncResult, error) {
     if (backend == nil) {
@@ -131,6 +132,11 @@ func SyncUsers(course *model.Course, newUsers map[string]*model.User,
         return nil, fmt.Errorf("Failed to fetch local users: '%w'.", err);
     }
 
+    serverUsers, err := GetServerUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch server users: '%w'.", err);
+    }
+
     // Summary of results.
     syncResult := model.NewUserSyncResult();
 
@@ -146,7 +152,9 @@ func SyncUsers(course *model.Course, newUsers map[string]*model.User,
             continue;
         }
 
-        if (newUser.Pass == "") {
+        if (serverUsers[newUser.Email] != nil) {
+            newUser.Credentials = model.Credentials{};
+        } else if (newUser.Pass == "") {
             clearTextPass, err := newUser.SetRandomPassword();
             if (err != nil) {
                 return nil, err;
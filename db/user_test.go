@@ -320,7 +320,7 @@ func getSynNewUsersTestUsers() (
         },
         "add-pass@test.com": &model.User{
             Email: "add-pass@test.com",
-            Pass: util.Sha256HexFromString("add-pass"),
+            Credentials: model.Credentials{Pass: util.Sha256HexFromString("add-pass")},
             Name: "add pass",
             Role: model.RoleStudent,
             LMSID: "lms-add-pass@test.com",
@@ -333,7 +333,7 @@ func getSynNewUsersTestUsers() (
         },
         "student@test.com": &model.User{
             Email: "student@test.com",
-            Pass: util.Sha256HexFromString("mod-pass"),
+            Credentials: model.Credentials{Pass: util.Sha256HexFromString("mod-pass")},
         },
         // No change, should be marked as mod (because of password).
         "grader@test.com": &model.User{
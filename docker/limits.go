@@ -0,0 +1,130 @@
+package docker
+
+import (
+    "fmt"
+
+    "github.com/docker/docker/api/types/container"
+
+    "github.com/edulinq/autograder/config"
+)
+
+const (
+    BYTES_PER_MB = 1024 * 1024
+    NANO_CPUS_PER_CPU = 1000 * 1000 * 1000
+
+    // Where the (size-limited) scratch space is mounted inside grading containers.
+    TMPFS_PATH = "/tmp"
+)
+
+// Limits on the resources a grading container may use.
+// A zero value means that the server maximum (from config) is used (except for DiskMB).
+type ResourceLimits struct {
+    MemoryMB int `json:"memory-mb,omitempty"`
+    CPUs float64 `json:"cpus,omitempty"`
+    PIDs int `json:"pids,omitempty"`
+    // If set, a size-limited scratch space (tmpfs) is mounted over TMPFS_PATH.
+    // This only limits TMPFS_PATH (hiding anything the image has there), not writes anywhere else,
+    // and the scratch space counts against the container's memory.
+    // Zero means no scratch space is mounted.
+    DiskMB int `json:"disk-mb,omitempty"`
+}
+
+// Ensure that no limit is negative or over the server maximum.
+func (this *ResourceLimits) Validate() error {
+    if (this == nil) {
+        return nil;
+    }
+
+    err := validateLimit("memory (MB)", float64(this.MemoryMB), float64(config.DOCKER_MAX_MEMORY_MB.Get()));
+    if (err != nil) {
+        return err;
+    }
+
+    err = validateLimit("CPUs", this.CPUs, config.DOCKER_MAX_CPUS.Get());
+    if (err != nil) {
+        return err;
+    }
+
+    err = validateLimit("PIDs", float64(this.PIDs), float64(config.DOCKER_MAX_PIDS.Get()));
+    if (err != nil) {
+        return err;
+    }
+
+    err = validateLimit("disk (MB)", float64(this.DiskMB), float64(config.DOCKER_MAX_DISK_MB.Get()));
+    if (err != nil) {
+        return err;
+    }
+
+    return nil;
+}
+
+// Get the limits that will actually be used, where unset values are replaced by the server maximums
+// and values that are over the server maximums (e.g. if the server config changed) are lowered to them.
+// An unset disk limit is left unset (see DiskMB).
+// Can be called on a nil receiver.
+func (this *ResourceLimits) GetEffectiveLimits() *ResourceLimits {
+    limits := ResourceLimits{};
+    if (this != nil) {
+        limits = *this;
+    }
+
+    limits.MemoryMB = int(effectiveLimit(float64(limits.MemoryMB), float64(config.DOCKER_MAX_MEMORY_MB.Get())));
+    limits.CPUs = effectiveLimit(limits.CPUs, config.DOCKER_MAX_CPUS.Get());
+    limits.PIDs = int(effectiveLimit(float64(limits.PIDs), float64(config.DOCKER_MAX_PIDS.Get())));
+    if (limits.DiskMB > 0) {
+        limits.DiskMB = int(effectiveLimit(float64(limits.DiskMB), float64(config.DOCKER_MAX_DISK_MB.Get())));
+    }
+
+    return &limits;
+}
+
+// Apply the effective limits to a container's host config.
+func (this *ResourceLimits) apply(hostConfig *container.HostConfig) {
+    limits := this.GetEffectiveLimits();
+
+    if (limits.MemoryMB > 0) {
+        hostConfig.Resources.Memory = int64(limits.MemoryMB) * BYTES_PER_MB;
+        // Do not allow any swap.
+        hostConfig.Resources.MemorySwap = hostConfig.Resources.Memory;
+    }
+
+    if (limits.CPUs > 0) {
+        hostConfig.Resources.NanoCPUs = int64(limits.CPUs * NANO_CPUS_PER_CPU);
+    }
+
+    if (limits.PIDs > 0) {
+        pids := int64(limits.PIDs);
+        hostConfig.Resources.PidsLimit = &pids;
+    }
+
+    if (limits.DiskMB > 0) {
+        hostConfig.Tmpfs = map[string]string{
+            TMPFS_PATH: fmt.Sprintf("rw,size=%dm", limits.DiskMB),
+        };
+    }
+}
+
+// A non-positive max means no limit.
+func validateLimit(name string, value float64, max float64) error {
+    if (value < 0) {
+        return fmt.Errorf("Resource limit for %s cannot be negative: %v.", name, value);
+    }
+
+    if ((max > 0) && (value > max)) {
+        return fmt.Errorf("Resource limit for %s (%v) cannot be more than the server maximum (%v).", name, value, max);
+    }
+
+    return nil;
+}
+
+func effectiveLimit(value float64, max float64) float64 {
+    if (max <= 0) {
+        return value;
+    }
+
+    if ((value <= 0) || (value > max)) {
+        return max;
+    }
+
+    return value;
+}
@@ -0,0 +1,129 @@
+package docker
+
+import (
+    "reflect"
+    "testing"
+
+    "github.com/docker/docker/api/types/container"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/util"
+)
+
+func TestResourceLimitsValidate(test *testing.T) {
+    oldMemory := config.DOCKER_MAX_MEMORY_MB.Get();
+    defer config.DOCKER_MAX_MEMORY_MB.Set(oldMemory);
+
+    config.DOCKER_MAX_MEMORY_MB.Set(1024);
+
+    testCases := []struct {limits *ResourceLimits; valid bool}{
+        {nil, true},
+        {&ResourceLimits{}, true},
+        {&ResourceLimits{MemoryMB: 1024, CPUs: 0.5, PIDs: 10, DiskMB: 10}, true},
+        {&ResourceLimits{MemoryMB: 1025}, false},
+        {&ResourceLimits{MemoryMB: -1}, false},
+        {&ResourceLimits{CPUs: -0.5}, false},
+        {&ResourceLimits{PIDs: config.DOCKER_MAX_PIDS.Get() + 1}, false},
+        {&ResourceLimits{DiskMB: config.DOCKER_MAX_DISK_MB.Get() + 1}, false},
+    };
+
+    for i, testCase := range testCases {
+        err := testCase.limits.Validate();
+        if (testCase.valid && (err != nil)) {
+            test.Errorf("Case %d: Valid limits failed validation: '%v'.", i, err);
+        } else if (!testCase.valid && (err == nil)) {
+            test.Errorf("Case %d: Invalid limits passed validation: '%s'.", i, util.MustToJSON(testCase.limits));
+        }
+    }
+}
+
+func TestResourceLimitsEffective(test *testing.T) {
+    oldMemory := config.DOCKER_MAX_MEMORY_MB.Get();
+    defer config.DOCKER_MAX_MEMORY_MB.Set(oldMemory);
+
+    oldCPUs := config.DOCKER_MAX_CPUS.Get();
+    defer config.DOCKER_MAX_CPUS.Set(oldCPUs);
+
+    oldPIDs := config.DOCKER_MAX_PIDS.Get();
+    defer config.DOCKER_MAX_PIDS.Set(oldPIDs);
+
+    oldDisk := config.DOCKER_MAX_DISK_MB.Get();
+    defer config.DOCKER_MAX_DISK_MB.Set(oldDisk);
+
+    config.DOCKER_MAX_MEMORY_MB.Set(1024);
+    config.DOCKER_MAX_CPUS.Set(2.0);
+    config.DOCKER_MAX_PIDS.Set(0);
+    config.DOCKER_MAX_DISK_MB.Set(100);
+
+    testCases := []struct {limits *ResourceLimits; expected ResourceLimits}{
+        // An unset disk limit is not replaced by the maximum.
+        {nil, ResourceLimits{MemoryMB: 1024, CPUs: 2.0, PIDs: 0, DiskMB: 0}},
+        {&ResourceLimits{}, ResourceLimits{MemoryMB: 1024, CPUs: 2.0, PIDs: 0, DiskMB: 0}},
+        {&ResourceLimits{MemoryMB: 10, CPUs: 0.5, PIDs: 20, DiskMB: 5}, ResourceLimits{MemoryMB: 10, CPUs: 0.5, PIDs: 20, DiskMB: 5}},
+        // Over the maximum (e.g. the server config was lowered after the assignment was loaded).
+        {&ResourceLimits{MemoryMB: 4096, CPUs: 4.0, DiskMB: 500}, ResourceLimits{MemoryMB: 1024, CPUs: 2.0, PIDs: 0, DiskMB: 100}},
+    };
+
+    for i, testCase := range testCases {
+        actual := testCase.limits.GetEffectiveLimits();
+        if (*actual != testCase.expected) {
+            test.Errorf("Case %d: Unexpected effective limits. Expected: '%s', Actual: '%s'.",
+                    i, util.MustToJSON(testCase.expected), util.MustToJSON(actual));
+        }
+    }
+}
+
+func TestResourceLimitsApply(test *testing.T) {
+    oldMemory := config.DOCKER_MAX_MEMORY_MB.Get();
+    defer config.DOCKER_MAX_MEMORY_MB.Set(oldMemory);
+
+    oldCPUs := config.DOCKER_MAX_CPUS.Get();
+    defer config.DOCKER_MAX_CPUS.Set(oldCPUs);
+
+    oldPIDs := config.DOCKER_MAX_PIDS.Get();
+    defer config.DOCKER_MAX_PIDS.Set(oldPIDs);
+
+    oldDisk := config.DOCKER_MAX_DISK_MB.Get();
+    defer config.DOCKER_MAX_DISK_MB.Set(oldDisk);
+
+    // Without any server maximums, a container without limits has the same host config as before limits existed.
+    config.DOCKER_MAX_MEMORY_MB.Set(0);
+    config.DOCKER_MAX_CPUS.Set(0.0);
+    config.DOCKER_MAX_PIDS.Set(0);
+    config.DOCKER_MAX_DISK_MB.Set(100);
+
+    for i, limits := range []*ResourceLimits{nil, &ResourceLimits{}} {
+        hostConfig := &container.HostConfig{};
+        limits.apply(hostConfig);
+
+        if (!reflect.DeepEqual(&container.HostConfig{}, hostConfig)) {
+            test.Errorf("Case %d: Host config was changed without limits: '%s'.", i, util.MustToJSON(hostConfig));
+        }
+    }
+
+    // Server maximums apply to memory/CPUs/PIDs, but never add a scratch space.
+    config.DOCKER_MAX_MEMORY_MB.Set(1024);
+    config.DOCKER_MAX_CPUS.Set(2.0);
+    config.DOCKER_MAX_PIDS.Set(10);
+
+    hostConfig := &container.HostConfig{};
+    (&ResourceLimits{}).apply(hostConfig);
+
+    if (hostConfig.Tmpfs != nil) {
+        test.Fatalf("Scratch space was mounted without a disk limit: '%v'.", hostConfig.Tmpfs);
+    }
+
+    if ((hostConfig.Resources.Memory != (1024 * BYTES_PER_MB)) || (hostConfig.Resources.NanoCPUs != (2 * NANO_CPUS_PER_CPU)) ||
+            (hostConfig.Resources.PidsLimit == nil) || (*hostConfig.Resources.PidsLimit != 10)) {
+        test.Fatalf("Server maximums were not applied: '%s'.", util.MustToJSON(hostConfig.Resources));
+    }
+
+    // An explicit disk limit mounts a scratch space.
+    hostConfig = &container.HostConfig{};
+    (&ResourceLimits{DiskMB: 5}).apply(hostConfig);
+
+    expectedTmpfs := map[string]string{TMPFS_PATH: "rw,size=5m"};
+    if (!reflect.DeepEqual(expectedTmpfs, hostConfig.Tmpfs)) {
+        test.Fatalf("Unexpected scratch space. Expected: '%v', Actual: '%v'.", expectedTmpfs, hostConfig.Tmpfs);
+    }
+}
@@ -2,8 +2,10 @@ package docker
 
 import (
     "fmt"
+    "time"
 
     "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/config"
 )
 
 const (
@@ -24,6 +26,14 @@ type ImageInfo struct {
 
     PostSubmissionFileOperations []common.FileOperation `json:"post-submission-files-ops,omitempty"`
 
+    // The maximum number of seconds (wall-clock) a grading run may take.
+    // Zero means that the default (from the course or config) is used.
+    TimeoutSecs int `json:"timeout-secs,omitempty"`
+
+    // Limits on the grading container.
+    // Nil means that the server maximums are used.
+    ResourceLimits *ResourceLimits `json:"resource-limits,omitempty"`
+
     // Fields that are not part of the JSON and are set after deserialization.
 
     Name string `json:"-"`
@@ -44,6 +54,21 @@ func (this *ImageInfo) GetGradingConfig() *GradingConfig {
     };
 }
 
+// Get the maximum (wall-clock) duration of a grading run, falling back to the config default.
+// A zero duration means that there is no limit.
+func (this *ImageInfo) GetTimeout() time.Duration {
+    timeoutSecs := this.TimeoutSecs;
+    if (timeoutSecs == 0) {
+        timeoutSecs = config.GRADING_TIMEOUT_DEFAULT_SECS.Get();
+    }
+
+    if (timeoutSecs <= 0) {
+        return 0;
+    }
+
+    return time.Duration(timeoutSecs) * time.Second;
+}
+
 func (this *ImageInfo) Validate() error {
     if (this.Name == "") {
         return fmt.Errorf("Missing name.");
@@ -65,6 +90,15 @@ func (this *ImageInfo) Validate() error {
         this.Image = DEFAULT_IMAGE;
     }
 
+    if (this.TimeoutSecs < 0) {
+        return fmt.Errorf("Timeout cannot be negative: %d.", this.TimeoutSecs);
+    }
+
+    err := this.ResourceLimits.Validate();
+    if (err != nil) {
+        return fmt.Errorf("Failed to validate resource limits: '%w'.", err);
+    }
+
     if (this.PreStaticDockerCommands == nil) {
         this.PreStaticDockerCommands = make([]string, 0);
     }
@@ -92,7 +126,7 @@ func (this *ImageInfo) Validate() error {
         this.PreStaticFileOperations = make([]common.FileOperation, 0);
     }
 
-    err := common.ValidateFileOperations(this.PreStaticFileOperations);
+    err = common.ValidateFileOperations(this.PreStaticFileOperations);
     if (err != nil) {
         return fmt.Errorf("Failed to validate pre-static file operations: '%w'.", err);
     }
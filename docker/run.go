@@ -1,9 +1,11 @@
 package docker
 
 import (
+    "context"
+    "errors"
     "fmt"
     "regexp"
-    "strings"
+    "time"
 
     "github.com/docker/docker/api/types"
     "github.com/docker/docker/api/types/container"
@@ -14,7 +16,36 @@ import (
     "github.com/edulinq/autograder/util"
 )
 
-func RunContainer(logId log.Loggable, imageName string, inputDir string, outputDir string, gradingID string) (string, string, error) {
+// Returned (wrapped) when a container is killed for running longer than its timeout.
+var ErrContainerTimeout = errors.New("Container timed out.");
+
+// Returned (wrapped) when a container is killed for going over its memory limit.
+var ErrContainerOutOfMemory = errors.New("Container ran out of memory.");
+
+const (
+    STREAM_STDOUT = "stdout"
+    STREAM_STDERR = "stderr"
+)
+
+// Optional callbacks for watching a container while it runs.
+// Any nil callback is skipped.
+type RunListener struct {
+    // Called once the container has been started.
+    Started func()
+
+    // Called with each line of output as the container produces it
+    // (the stream is STREAM_STDOUT or STREAM_STDERR).
+    Output func(stream string, line string)
+}
+
+// Run a grading container and return its (stdout, stderr, error).
+// If the container runs longer than the timeout (when it is not zero), it will be killed.
+// The container will run under the effective version of the given limits (see ResourceLimits.GetEffectiveLimits()).
+// On a timeout or out-of-memory kill, the output produced up to that point will be returned
+// along with an error wrapping ErrContainerTimeout or ErrContainerOutOfMemory.
+// The listener may be nil.
+func RunContainer(logId log.Loggable, imageName string, inputDir string, outputDir string, gradingID string,
+        timeout time.Duration, limits *ResourceLimits, listener *RunListener) (string, string, error) {
     ctx, docker, err := getDockerClient();
     if (err != nil) {
         return "", "", err;
@@ -26,6 +57,25 @@ func RunContainer(logId log.Loggable, imageName string, inputDir string, outputD
 
     name := cleanContainerName(fmt.Sprintf("%s-%s", gradingID, util.UUID()));
 
+    hostConfig := &container.HostConfig{
+        Mounts: []mount.Mount{
+            mount.Mount{
+                Type: "bind",
+                Source: inputDir,
+                Target: "/autograder/input",
+                ReadOnly: true,
+            },
+            mount.Mount{
+                Type: "bind",
+                Source: outputDir,
+                Target: "/autograder/output",
+                ReadOnly: false,
+            },
+        },
+    };
+
+    limits.apply(hostConfig);
+
     containerInstance, err := docker.ContainerCreate(
         ctx,
         &container.Config{
@@ -33,23 +83,7 @@ func RunContainer(logId log.Loggable, imageName string, inputDir string, outputD
             Tty: false,
             NetworkDisabled: true,
         },
-        &container.HostConfig{
-            AutoRemove: true,
-            Mounts: []mount.Mount{
-                mount.Mount{
-                    Type: "bind",
-                    Source: inputDir,
-                    Target: "/autograder/input",
-                    ReadOnly: true,
-                },
-                mount.Mount{
-                    Type: "bind",
-                    Source: outputDir,
-                    Target: "/autograder/output",
-                    ReadOnly: false,
-                },
-            },
-        },
+        hostConfig,
         nil,
         nil,
         name)
@@ -58,11 +92,22 @@ func RunContainer(logId log.Loggable, imageName string, inputDir string, outputD
         return "", "", fmt.Errorf("Failed to create container '%s': '%w'.", name, err);
     }
 
+    // The container is not auto-removed, since it needs to be inspected after it finishes.
+    defer func() {
+        err := docker.ContainerRemove(ctx, containerInstance.ID, types.ContainerRemoveOptions{Force: true});
+        if (err != nil) {
+            log.Warn("Failed to remove container.", err, logId,
+                    log.NewAttr("container-name", name), log.NewAttr("container-id", containerInstance.ID));
+        }
+    }();
+
     err = docker.ContainerStart(ctx, containerInstance.ID, types.ContainerStartOptions{});
     if (err != nil) {
         return "", "", fmt.Errorf("Failed to start container '%s' (%s): '%w'.", name, containerInstance.ID, err);
     }
 
+    listener.started();
+
     // Get the output reader before the container dies.
     out, err := docker.ContainerLogs(ctx, containerInstance.ID, types.ContainerLogsOptions{
         ShowStdout: true,
@@ -70,37 +115,80 @@ func RunContainer(logId log.Loggable, imageName string, inputDir string, outputD
         Follow: true,
     })
 
+    outBuffer := util.NewLineWriter(listener.outputFunc(STREAM_STDOUT));
+    errBuffer := util.NewLineWriter(listener.outputFunc(STREAM_STDERR));
+
+    // Read the output while the container runs (the stream ends when the container stops).
+    outputDone := make(chan struct{});
+
     if (err != nil) {
         log.Warn("Failed to get output from container (but run did not throw an error).",
                 err, logId,
                 log.NewAttr("container-name", name), log.NewAttr("container-id", containerInstance.ID));
         out = nil;
+        close(outputDone);
+    } else {
+        defer out.Close()
+
+        go func() {
+            defer close(outputDone);
+
+            stdcopy.StdCopy(outBuffer, errBuffer, out);
+            outBuffer.Flush();
+            errBuffer.Flush();
+        }();
     }
-    defer out.Close()
 
-    statusChan, errorChan := docker.ContainerWait(ctx, containerInstance.ID, container.WaitConditionNotRunning);
+    waitCtx := ctx;
+    if (timeout > 0) {
+        var cancel context.CancelFunc;
+        waitCtx, cancel = context.WithTimeout(ctx, timeout);
+        defer cancel();
+    }
+
+    var runErr error = nil;
+
+    statusChan, errorChan := docker.ContainerWait(waitCtx, containerInstance.ID, container.WaitConditionNotRunning);
     select {
         case err := <-errorChan:
-            if (err != nil) {
+            if (errors.Is(err, context.DeadlineExceeded) || errors.Is(waitCtx.Err(), context.DeadlineExceeded)) {
+                runErr = fmt.Errorf("Container '%s' (%s) ran longer than %s: '%w'.", name, containerInstance.ID, timeout, ErrContainerTimeout);
+            } else if (err != nil) {
                 return "", "", fmt.Errorf("Got an error when running container '%s' (%s): '%w'.", name, containerInstance.ID, err);
             }
         case <-statusChan:
             // Waiting is complete.
     }
 
-    stdout := "";
-    stderr := "";
-
-    // Read the output after the container is done.
-    if (out != nil) {
-        outBuffer := new(strings.Builder);
-        errBuffer := new(strings.Builder);
+    if (runErr != nil) {
+        log.Warn("Grading container timed out, killing it.",
+                logId,
+                log.NewAttr("container-name", name), log.NewAttr("container-id", containerInstance.ID),
+                log.NewAttr("timeout", timeout.String()));
+
+        // Killing the container also closes the output stream.
+        err = docker.ContainerKill(ctx, containerInstance.ID, "KILL");
+        if (err != nil) {
+            return "", "", errors.Join(runErr,
+                    fmt.Errorf("Failed to kill timed out container '%s' (%s): '%w'.", name, containerInstance.ID, err));
+        }
+    } else {
+        info, err := docker.ContainerInspect(ctx, containerInstance.ID);
+        if (err != nil) {
+            log.Warn("Failed to inspect finished container.", err, logId,
+                    log.NewAttr("container-name", name), log.NewAttr("container-id", containerInstance.ID));
+        } else if ((info.State != nil) && (info.State.OOMKilled)) {
+            runErr = fmt.Errorf("Container '%s' (%s) was killed for using more than %d MB of memory: '%w'.",
+                    name, containerInstance.ID, limits.GetEffectiveLimits().MemoryMB, ErrContainerOutOfMemory);
+        }
+    }
 
-        stdcopy.StdCopy(outBuffer, errBuffer, out);
+    <-outputDone;
 
-        stdout = outBuffer.String();
-        stderr = errBuffer.String();
+    stdout := outBuffer.String();
+    stderr := errBuffer.String();
 
+    if (out != nil) {
         log.Debug("Container output.",
                 logId,
                 log.NewAttr("container-name", name),
@@ -109,7 +197,24 @@ func RunContainer(logId log.Loggable, imageName string, inputDir string, outputD
                 log.NewAttr("stderr", stderr));
     }
 
-    return stdout, stderr, nil;
+    return stdout, stderr, runErr;
+}
+
+func (this *RunListener) started() {
+    if ((this != nil) && (this.Started != nil)) {
+        this.Started();
+    }
+}
+
+// Get a function to pass each line of a stream to the listener (or nil if there is nothing listening).
+func (this *RunListener) outputFunc(stream string) func(string) {
+    if ((this == nil) || (this.Output == nil)) {
+        return nil;
+    }
+
+    return func(line string) {
+        this.Output(stream, line);
+    };
 }
 
 func cleanContainerName(text string) string {
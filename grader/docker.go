@@ -1,6 +1,7 @@
 package grader
 
 import (
+    "errors"
     "fmt"
     "os"
     "path/filepath"
@@ -36,7 +37,18 @@ func runDockerGrader(assignment *model.Assignment, submissionPath string, option
         return nil, nil, "", "", fmt.Errorf("Failed to copy over submission/input contents: '%w'.", err);
     }
 
-    stdout, stderr, err := docker.RunContainer(assignment, assignment.ImageName(), inputDir, outputDir, fullSubmissionID);
+    imageInfo := assignment.GetImageInfo();
+
+    stdout, stderr, err := docker.RunContainer(assignment, assignment.ImageName(), inputDir, outputDir, fullSubmissionID,
+            imageInfo.GetTimeout(), imageInfo.ResourceLimits, options.runListener());
+    if (errors.Is(err, docker.ErrContainerTimeout)) {
+        return nil, nil, stdout, stderr, fmt.Errorf("Failed to grade assignment '%s': '%w'.", assignment.FullID(), errors.Join(ErrGradingTimeout, err));
+    }
+
+    if (errors.Is(err, docker.ErrContainerOutOfMemory)) {
+        return nil, nil, stdout, stderr, fmt.Errorf("Failed to grade assignment '%s': '%w'.", assignment.FullID(), errors.Join(ErrGradingOutOfMemory, err));
+    }
+
     if (err != nil) {
         return nil, nil, stdout, stderr, err;
     }
@@ -1,6 +1,7 @@
 package grader
 
 import (
+    "errors"
     "fmt"
     "sync"
 
@@ -8,15 +9,33 @@ import (
     "github.com/edulinq/autograder/config"
     "github.com/edulinq/autograder/db"
     "github.com/edulinq/autograder/docker"
+    "github.com/edulinq/autograder/lms/lti"
     "github.com/edulinq/autograder/model"
     "github.com/edulinq/autograder/util"
 )
 
 var submissionLocks sync.Map;
 
+// Wrapped by the errors returned when a grader runs longer than its assignment's timeout.
+var ErrGradingTimeout = errors.New("Grading timed out.");
+
+// Wrapped by the errors returned when a grader is killed for going over its assignment's memory limit.
+var ErrGradingOutOfMemory = errors.New("Grading ran out of memory.");
+
 type GradeOptions struct {
     NoDocker bool
     LeaveTempDir bool
+
+    // If set, will be called with each step of grading (see GradingEvent).
+    Progress ProgressFunc
+
+    // When the submission was made (e.g., when it was added to the grading queue).
+    // This is the time used for late policies and submission restrictions (e.g., close dates).
+    // If empty, the time that grading is started is used.
+    SubmissionTime common.Timestamp
+
+    // Set for each run of a grader, so questions are only reported once.
+    reportedQuestions *reportedQuestions
 }
 
 func GetDefaultGradeOptions() GradeOptions {
@@ -35,8 +54,17 @@ func GradeDefault(assignment *model.Assignment, submissionPath string, user stri
 // Grade with custom options.
 func Grade(assignment *model.Assignment, submissionPath string, user string, message string, checkRejection bool, options GradeOptions) (
         *model.GradingResult, RejectReason, error) {
+    if (options.SubmissionTime.IsZero()) {
+        options.SubmissionTime = common.NowTimestamp();
+    }
+
     if (checkRejection) {
-        reject, err := checkForRejection(assignment, submissionPath, user, message);
+        submissionTime, err := options.SubmissionTime.Time();
+        if (err != nil) {
+            return nil, nil, fmt.Errorf("Bad submission time '%s': '%w'.", options.SubmissionTime, err);
+        }
+
+        reject, err := checkForRejection(assignment, submissionPath, user, message, submissionTime);
         if (err != nil) {
             return nil, nil, fmt.Errorf("Failed to check for rejection: '%w'.", err);
         }
@@ -46,6 +74,61 @@ func Grade(assignment *model.Assignment, submissionPath string, user string, mes
         }
     }
 
+    gradingResult, err := gradeLocked(assignment, submissionPath, user, message, options);
+    if (err != nil) {
+        return gradingResult, nil, err;
+    }
+
+    // Scores are posted outside of the submission lock (and in the background),
+    // so a slow LMS does not hold up grading.
+    // A score that was not saved is not posted.
+    if (!config.NO_STORE.Get()) {
+        queueLTIScore(assignment, user, gradingResult.Info);
+    }
+
+    return gradingResult, nil, nil;
+}
+
+// Grade (and save) a submission while holding the user's submission lock.
+func gradeLocked(assignment *model.Assignment, submissionPath string, user string, message string, options GradeOptions) (
+        *model.GradingResult, error) {
+    lock := lockSubmissions(assignment, user);
+    defer lock.Unlock();
+
+    options.report(NewGradingEvent(GRADING_EVENT_IMAGE_BUILD));
+
+    submissionID, inputFileContents, err := prepForGrading(assignment, submissionPath, user);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to prep for grading: '%w'.", err);
+    }
+
+    gradingResult, err := runGrader(assignment, submissionPath, user, message, submissionID, inputFileContents, options);
+
+    // Failed runs that still have an info (timeouts and running out of memory) are saved,
+    // so they show up in the user's history.
+    if ((gradingResult != nil) && (gradingResult.Info != nil) && !config.NO_STORE.Get()) {
+        saveErr := db.SaveSubmission(assignment, gradingResult);
+        if (saveErr != nil) {
+            return gradingResult, errors.Join(err, fmt.Errorf("Failed to save grading result: '%w'.", saveErr));
+        }
+    }
+
+    return gradingResult, err;
+}
+
+// Queue a score to be posted back to the LMS (if the course was launched with LTI and posts scores).
+// Failures are logged, but do not fail grading.
+func queueLTIScore(assignment *model.Assignment, user string, gradingInfo *model.GradingInfo) {
+    if (gradingInfo == nil) {
+        return;
+    }
+
+    lti.QueueScore(assignment, user, gradingInfo.Score, gradingInfo.MaxPoints);
+}
+
+// Lock grading for a user's submissions to an assignment.
+// The caller must unlock the returned lock.
+func lockSubmissions(assignment *model.Assignment, user string) *sync.Mutex {
     gradingKey := fmt.Sprintf("%s::%s::%s", assignment.GetCourse().GetID(), assignment.GetID(), user);
 
     // Get the existing mutex, or store (and fetch) a new one.
@@ -53,13 +136,16 @@ func Grade(assignment *model.Assignment, submissionPath string, user string, mes
     lock := val.(*sync.Mutex)
 
     lock.Lock();
-    defer lock.Unlock()
 
-    submissionID, inputFileContents, err := prepForGrading(assignment, submissionPath, user);
-    if (err != nil) {
-        return nil, nil, fmt.Errorf("Failed to prep for grading: '%w'.", err);
-    }
+    return lock;
+}
 
+// Run the grader on a submission (which should already be prepped) and fill in the result.
+// The result is not saved.
+// On an error, any output from the grader will still be in the returned result.
+// If the grader ran out of time or memory, the result will also have an info (with no score) that can be saved.
+func runGrader(assignment *model.Assignment, submissionPath string, user string, message string,
+        submissionID string, inputFileContents map[string][]byte, options GradeOptions) (*model.GradingResult, error) {
     var gradingResult model.GradingResult;
     gradingResult.InputFilesGZip = inputFileContents;
 
@@ -69,9 +155,12 @@ func Grade(assignment *model.Assignment, submissionPath string, user string, mes
     var outputFileContents map[string][]byte;
     var stdout string;
     var stderr string;
+    var err error;
 
     startTimestamp := common.NowTimestamp();
 
+    options.reportedQuestions = &reportedQuestions{names: make(map[string]bool)};
+
     if (options.NoDocker) {
         gradingInfo, outputFileContents, stdout, stderr, err = runNoDockerGrader(assignment, submissionPath, options, fullSubmissionID);
     } else {
@@ -83,9 +172,24 @@ func Grade(assignment *model.Assignment, submissionPath string, user string, mes
     // Copy over stdout and stderr even if an error occured.
     gradingResult.Stdout = stdout;
     gradingResult.Stderr = stderr;
+    gradingResult.TimedOut = errors.Is(err, ErrGradingTimeout);
+    gradingResult.OutOfMemory = errors.Is(err, ErrGradingOutOfMemory);
 
-    if (err != nil) {
-        return &gradingResult, nil, err;
+    unfinished := (gradingResult.TimedOut || gradingResult.OutOfMemory);
+
+    if ((err != nil) && !unfinished) {
+        return &gradingResult, err;
+    }
+
+    // A grader that did not finish still used up this submission, so it gets an (unscored) info.
+    if (unfinished) {
+        gradingInfo = &model.GradingInfo{
+            Name: assignment.GetName(),
+            MaxPoints: assignment.MaxPoints,
+            TimedOut: gradingResult.TimedOut,
+            OutOfMemory: gradingResult.OutOfMemory,
+            Questions: make([]*model.GradedQuestion, 0),
+        };
     }
 
     // Set all the autograder fields in the grading info.
@@ -100,23 +204,25 @@ func Grade(assignment *model.Assignment, submissionPath string, user string, mes
         gradingInfo.GradingStartTime = startTimestamp;
     }
 
+    gradingInfo.SubmissionTime = options.SubmissionTime;
+    if (gradingInfo.SubmissionTime.IsZero()) {
+        gradingInfo.SubmissionTime = startTimestamp;
+    }
+
     if (gradingInfo.GradingEndTime.IsZero()) {
         gradingInfo.GradingEndTime = endTimestamp;
     }
 
     gradingInfo.ComputePoints();
 
+    for _, question := range gradingInfo.Questions {
+        options.reportQuestion(question);
+    }
+
     gradingResult.Info = gradingInfo;
     gradingResult.OutputFilesGZip = outputFileContents;
 
-    if (!config.NO_STORE.Get()) {
-        err = db.SaveSubmission(assignment, &gradingResult);
-        if (err != nil) {
-            return &gradingResult, nil, fmt.Errorf("Failed to save grading result: '%w'.", err);
-        }
-    }
-
-    return &gradingResult, nil, nil;
+    return &gradingResult, err;
 }
 
 func prepForGrading(assignment *model.Assignment, submissionPath string, user string) (string, map[string][]byte, error) {
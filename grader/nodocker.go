@@ -1,14 +1,17 @@
 package grader
 
 import (
-    "bytes"
+    "context"
+    "errors"
     "fmt"
     "os"
     "os/exec"
     "path/filepath"
     "strings"
+    "time"
 
     "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/docker"
     "github.com/edulinq/autograder/log"
     "github.com/edulinq/autograder/model"
     "github.com/edulinq/autograder/util"
@@ -18,6 +21,11 @@ const PYTHON_AUTOGRADER_INVOCATION = "python3 -m autograder.cli.grading.grade-di
 const PYTHON_GRADER_FILENAME = "grader.py"
 const PYTHON_DOCKER_IMAGE_BASENAME = "autograder.python";
 
+// How long to wait for a timed out grader's output to close after it has been killed
+// (any processes it started may still be holding on to it).
+const NODOCKER_KILL_WAIT = 1 * time.Second;
+
+// Note that only the timeout is enforced here, resource limits require docker.
 func runNoDockerGrader(assignment *model.Assignment, submissionPath string, options GradeOptions, fullSubmissionID string) (
         *model.GradingInfo, map[string][]byte, string, string, error) {
     imageInfo := assignment.GetImageInfo();
@@ -36,11 +44,6 @@ func runNoDockerGrader(assignment *model.Assignment, submissionPath string, opti
         log.Info("Leaving behind temp grading dir.", log.NewAttr("path", tempDir));
     }
 
-    cmd, err := getAssignmentInvocation(assignment, tempDir, inputDir, outputDir, workDir);
-    if (err != nil) {
-        return nil, nil, "", "", err;
-    }
-
     // Copy over the static files (and do any file ops).
     err = common.CopyFileSpecs(imageInfo.BaseDir, workDir, tempDir,
             imageInfo.StaticFiles, false, imageInfo.PreStaticFileOperations, imageInfo.PostStaticFileOperations);
@@ -55,7 +58,25 @@ func runNoDockerGrader(assignment *model.Assignment, submissionPath string, opti
         return nil, nil, "", "", fmt.Errorf("Failed to copy submission ssignment files: '%w'.", err);
     }
 
-    stdout, stderr, err := runCMD(cmd);
+    ctx := context.Background();
+    timeout := imageInfo.GetTimeout();
+    if (timeout > 0) {
+        var cancel context.CancelFunc;
+        ctx, cancel = context.WithTimeout(ctx, timeout);
+        defer cancel();
+    }
+
+    cmd, err := getAssignmentInvocation(ctx, assignment, tempDir, inputDir, outputDir, workDir);
+    if (err != nil) {
+        return nil, nil, "", "", err;
+    }
+
+    stdout, stderr, err := runCMD(cmd, options.runListener());
+    if (errors.Is(ctx.Err(), context.DeadlineExceeded)) {
+        return nil, nil, stdout, stderr,
+                fmt.Errorf("Non-docker grader for assignment '%s' ran longer than %s: '%w'.", assignment.FullID(), timeout, ErrGradingTimeout);
+    }
+
     if (err != nil) {
         return nil, nil, stdout, stderr,
                 fmt.Errorf("Failed to run non-docker grader for assignment '%s': '%w'.", assignment.FullID(), err);
@@ -80,14 +101,38 @@ func runNoDockerGrader(assignment *model.Assignment, submissionPath string, opti
     return &gradingInfo, fileContents, stdout, stderr, nil;
 }
 
-func runCMD(cmd *exec.Cmd) (string, string, error) {
-    var outBuffer bytes.Buffer;
-    var errBuffer bytes.Buffer;
+// Run a command, passing along its progress to the listener (which may be nil).
+func runCMD(cmd *exec.Cmd, listener *docker.RunListener) (string, string, error) {
+    var onStdout func(string) = nil;
+    var onStderr func(string) = nil;
+
+    if ((listener != nil) && (listener.Output != nil)) {
+        onStdout = func(line string) {
+            listener.Output(docker.STREAM_STDOUT, line);
+        };
+
+        onStderr = func(line string) {
+            listener.Output(docker.STREAM_STDERR, line);
+        };
+    }
 
-    cmd.Stdout = &outBuffer;
-    cmd.Stderr = &errBuffer;
+    outBuffer := util.NewLineWriter(onStdout);
+    errBuffer := util.NewLineWriter(onStderr);
+
+    cmd.Stdout = outBuffer;
+    cmd.Stderr = errBuffer;
+
+    err := cmd.Start();
+    if (err == nil) {
+        if ((listener != nil) && (listener.Started != nil)) {
+            listener.Started();
+        }
+
+        err = cmd.Wait();
+    }
 
-    err := cmd.Run();
+    outBuffer.Flush();
+    errBuffer.Flush();
 
     stdout := outBuffer.String();
     stderr := errBuffer.String();
@@ -96,7 +141,8 @@ func runCMD(cmd *exec.Cmd) (string, string, error) {
 }
 
 // Get a command to invoke the non-docker grader.
-func getAssignmentInvocation(assignment *model.Assignment,
+// The command will be killed when the context is done.
+func getAssignmentInvocation(ctx context.Context, assignment *model.Assignment,
         baseDir string, inputDir string, outputDir string, workDir string) (*exec.Cmd, error) {
     imageInfo := assignment.GetImageInfo();
     if (imageInfo == nil) {
@@ -137,8 +183,9 @@ func getAssignmentInvocation(assignment *model.Assignment,
         cleanCommand = append(cleanCommand, value);
     }
 
-    cmd := exec.Command(cleanCommand[0], cleanCommand[1:]...);
+    cmd := exec.CommandContext(ctx, cleanCommand[0], cleanCommand[1:]...);
     cmd.Dir = workDir;
+    cmd.WaitDelay = NODOCKER_KILL_WAIT;
 
     return cmd, nil;
 }
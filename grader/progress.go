@@ -0,0 +1,138 @@
+package grader
+
+import (
+    "strings"
+    "sync"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/docker"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+// Graders may report each question as soon as it is graded by writing a line to stdout
+// that starts with this prefix and is followed by the question as JSON (in the same format as the grader's result file).
+// Any questions that were not reported this way will be reported once the grader is done.
+// Either way, the result file is what is saved.
+const GRADER_QUESTION_LINE_PREFIX = "autograder-question: ";
+
+type GradingEventType string;
+
+const (
+    // The job is waiting in the grading queue (sent again whenever its position changes).
+    GRADING_EVENT_QUEUED GradingEventType = "queued"
+    // The job was pulled from the queue.
+    GRADING_EVENT_RUNNING GradingEventType = "running"
+    // The assignment's image is being built (or checked to see if it is already built).
+    GRADING_EVENT_IMAGE_BUILD GradingEventType = "image-build"
+    // The grader (container or local process) has started.
+    GRADING_EVENT_GRADER_STARTED GradingEventType = "grader-started"
+    // A line of output from the grader.
+    GRADING_EVENT_OUTPUT GradingEventType = "output"
+    // The result for a single question.
+    // Sent as the grader reports it (see GRADER_QUESTION_LINE_PREFIX), or once the grader is done.
+    GRADING_EVENT_QUESTION GradingEventType = "question"
+    // Grading is done (successfully or not), this is always the final event.
+    GRADING_EVENT_FINISHED GradingEventType = "finished"
+)
+
+// A step in grading a submission.
+type GradingEvent struct {
+    Type GradingEventType `json:"type"`
+    Time common.Timestamp `json:"time"`
+
+    // For GRADING_EVENT_QUEUED.
+    Position int `json:"position,omitempty"`
+
+    // For GRADING_EVENT_OUTPUT.
+    Stream string `json:"stream,omitempty"`
+    Line string `json:"line,omitempty"`
+
+    // For GRADING_EVENT_QUESTION.
+    Question *model.GradedQuestion `json:"question,omitempty"`
+}
+
+// Called with each event while grading.
+type ProgressFunc func(event *GradingEvent);
+
+// The names of the questions that have already been reported for a single run of a grader.
+type reportedQuestions struct {
+    lock sync.Mutex
+    names map[string]bool
+}
+
+func NewGradingEvent(eventType GradingEventType) *GradingEvent {
+    return &GradingEvent{
+        Type: eventType,
+        Time: common.NowTimestamp(),
+    };
+}
+
+func (this GradeOptions) report(event *GradingEvent) {
+    if (this.Progress != nil) {
+        this.Progress(event);
+    }
+}
+
+// Get a listener that will report the grader starting and its output.
+func (this GradeOptions) runListener() *docker.RunListener {
+    if (this.Progress == nil) {
+        return nil;
+    }
+
+    return &docker.RunListener{
+        Started: func() {
+            this.report(NewGradingEvent(GRADING_EVENT_GRADER_STARTED));
+        },
+        Output: func(stream string, line string) {
+            event := NewGradingEvent(GRADING_EVENT_OUTPUT);
+            event.Stream = stream;
+            event.Line = line;
+            this.report(event);
+
+            if (stream == docker.STREAM_STDOUT) {
+                this.reportQuestionLine(line);
+            }
+        },
+    };
+}
+
+// Report a question if this line of grader output holds one.
+func (this GradeOptions) reportQuestionLine(line string) {
+    text, ok := strings.CutPrefix(line, GRADER_QUESTION_LINE_PREFIX);
+    if (!ok) {
+        return;
+    }
+
+    var question model.GradedQuestion;
+    err := util.JSONFromString(text, &question);
+    if (err != nil) {
+        log.Warn("Failed to parse a question from grader output.", err, log.NewAttr("line", line));
+        return;
+    }
+
+    this.reportQuestion(&question);
+}
+
+// Report a question, unless it has already been reported (by name) for this run.
+func (this GradeOptions) reportQuestion(question *model.GradedQuestion) {
+    if (this.Progress == nil) {
+        return;
+    }
+
+    if (this.reportedQuestions != nil) {
+        this.reportedQuestions.lock.Lock();
+        reported := this.reportedQuestions.names[question.Name];
+        this.reportedQuestions.names[question.Name] = true;
+        this.reportedQuestions.lock.Unlock();
+
+        if (reported) {
+            return;
+        }
+    }
+
+    event := NewGradingEvent(GRADING_EVENT_QUESTION);
+    event.Question = question;
+    this.report(event);
+}
@@ -0,0 +1,240 @@
+package grader
+
+import (
+    "context"
+    "path/filepath"
+    "slices"
+    "sync"
+    "testing"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/docker"
+)
+
+const TEST_PROGRESS_GRADER = `echo 'line 1'; echo 'line 2' >&2; printf 'line 3';` +
+        ` echo '{"name": "hw0", "questions": [{"name": "Q1", "max_points": 1, "score": 1}]}' > "$0"`;
+
+func TestGradeProgress(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    oldDockerVal := config.DOCKER_DISABLE.Get();
+    config.DOCKER_DISABLE.Set(true);
+    defer config.DOCKER_DISABLE.Set(oldDockerVal);
+
+    assignment := db.MustGetTestAssignment();
+    assignment.Invocation = []string{"sh", "-c", TEST_PROGRESS_GRADER, "<outpath>"};
+
+    submissionPath := filepath.Join(config.GetCourseImportDir(), "_tests", "COURSE101", "HW0", "test-submissions", "solution");
+
+    var lock sync.Mutex;
+    events := make([]*GradingEvent, 0);
+
+    options := GradeOptions{
+        NoDocker: true,
+        Progress: func(event *GradingEvent) {
+            lock.Lock();
+            defer lock.Unlock();
+
+            events = append(events, event);
+        },
+    };
+
+    _, reject, err := Grade(assignment, submissionPath, "progress_" + BASE_TEST_USER, TEST_MESSAGE, false, options);
+    if (err != nil) {
+        test.Fatalf("Failed to grade: '%v'.", err);
+    }
+
+    if (reject != nil) {
+        test.Fatalf("Submission was rejected: '%s'.", reject.String());
+    }
+
+    types := make([]GradingEventType, 0, len(events));
+    stdout := make([]string, 0);
+    stderr := make([]string, 0);
+
+    for _, event := range events {
+        types = append(types, event.Type);
+
+        if (event.Stream == docker.STREAM_STDOUT) {
+            stdout = append(stdout, event.Line);
+        } else if (event.Stream == docker.STREAM_STDERR) {
+            stderr = append(stderr, event.Line);
+        }
+    }
+
+    expectedTypes := []GradingEventType{
+        GRADING_EVENT_IMAGE_BUILD,
+        GRADING_EVENT_GRADER_STARTED,
+        GRADING_EVENT_OUTPUT,
+        GRADING_EVENT_OUTPUT,
+        GRADING_EVENT_OUTPUT,
+        GRADING_EVENT_QUESTION,
+    };
+
+    // Lines from stdout and stderr may be interleaved in any order, so they are checked separately.
+    if (!slices.Equal(expectedTypes, types)) {
+        test.Fatalf("Unexpected event types. Expected: '%v', Actual: '%v'.", expectedTypes, types);
+    }
+
+    if (!slices.Equal([]string{"line 1", "line 3"}, stdout)) {
+        test.Fatalf("Unexpected stdout lines: '%v'.", stdout);
+    }
+
+    if (!slices.Equal([]string{"line 2"}, stderr)) {
+        test.Fatalf("Unexpected stderr lines: '%v'.", stderr);
+    }
+
+    question := events[len(events) - 1].Question;
+    if ((question == nil) || (question.Name != "Q1") || (question.Score != 1)) {
+        test.Fatalf("Unexpected question: '%v'.", question);
+    }
+}
+
+const TEST_STREAMED_QUESTION_GRADER = `echo 'autograder-question: {"name": "Q1", "max_points": 1, "score": 1}'; echo 'done';` +
+        ` echo '{"name": "hw0", "questions": [{"name": "Q1", "max_points": 1, "score": 1}, {"name": "Q2", "max_points": 1, "score": 0}]}' > "$0"`;
+
+func TestGradeProgressStreamedQuestions(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    oldDockerVal := config.DOCKER_DISABLE.Get();
+    config.DOCKER_DISABLE.Set(true);
+    defer config.DOCKER_DISABLE.Set(oldDockerVal);
+
+    assignment := db.MustGetTestAssignment();
+    assignment.Invocation = []string{"sh", "-c", TEST_STREAMED_QUESTION_GRADER, "<outpath>"};
+
+    submissionPath := filepath.Join(config.GetCourseImportDir(), "_tests", "COURSE101", "HW0", "test-submissions", "solution");
+
+    var lock sync.Mutex;
+    events := make([]*GradingEvent, 0);
+
+    options := GradeOptions{
+        NoDocker: true,
+        Progress: func(event *GradingEvent) {
+            lock.Lock();
+            defer lock.Unlock();
+
+            events = append(events, event);
+        },
+    };
+
+    _, _, err := Grade(assignment, submissionPath, "progress_" + BASE_TEST_USER, TEST_MESSAGE, false, options);
+    if (err != nil) {
+        test.Fatalf("Failed to grade: '%v'.", err);
+    }
+
+    types := make([]GradingEventType, 0, len(events));
+    questions := make([]string, 0);
+
+    for _, event := range events {
+        types = append(types, event.Type);
+
+        if (event.Type == GRADING_EVENT_QUESTION) {
+            questions = append(questions, event.Question.Name);
+        }
+    }
+
+    // Q1 is reported when the grader prints it (and not again at the end), Q2 is reported at the end.
+    expectedTypes := []GradingEventType{
+        GRADING_EVENT_IMAGE_BUILD,
+        GRADING_EVENT_GRADER_STARTED,
+        GRADING_EVENT_OUTPUT,
+        GRADING_EVENT_QUESTION,
+        GRADING_EVENT_OUTPUT,
+        GRADING_EVENT_QUESTION,
+    };
+
+    if (!slices.Equal(expectedTypes, types)) {
+        test.Fatalf("Unexpected event types. Expected: '%v', Actual: '%v'.", expectedTypes, types);
+    }
+
+    if (!slices.Equal([]string{"Q1", "Q2"}, questions)) {
+        test.Fatalf("Unexpected questions: '%v'.", questions);
+    }
+}
+
+func TestGradingJobEvents(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    oldDockerVal := config.DOCKER_DISABLE.Get();
+    config.DOCKER_DISABLE.Set(true);
+    defer config.DOCKER_DISABLE.Set(oldDockerVal);
+
+    assignment := db.MustGetTestAssignment();
+    assignment.Invocation = []string{"sh", "-c", TEST_PROGRESS_GRADER, "<outpath>"};
+
+    err := db.SaveCourse(assignment.GetCourse());
+    if (err != nil) {
+        test.Fatalf("Failed to save assignment: '%v'.", err);
+    }
+
+    submissionPath := filepath.Join(config.GetCourseImportDir(), "_tests", "COURSE101", "HW0", "test-submissions", "solution");
+
+    jobID, err := QueueGrade(assignment, submissionPath, "progress_" + BASE_TEST_USER, TEST_MESSAGE, false);
+    if (err != nil) {
+        test.Fatalf("Failed to queue submission: '%v'.", err);
+    }
+
+    events := make([]*GradingEvent, 0);
+    for {
+        newEvents, finished, err := GetGradingJobEvents(context.Background(), jobID, len(events));
+        if (err != nil) {
+            test.Fatalf("Failed to get events: '%v'.", err);
+        }
+
+        events = append(events, newEvents...);
+
+        if (finished) {
+            break;
+        }
+    }
+
+    if (events[0].Type != GRADING_EVENT_QUEUED) {
+        test.Fatalf("First event is not queued: '%v'.", events[0]);
+    }
+
+    if (events[len(events) - 1].Type != GRADING_EVENT_FINISHED) {
+        test.Fatalf("Last event is not finished: '%v'.", events[len(events) - 1]);
+    }
+
+    for _, eventType := range []GradingEventType{GRADING_EVENT_RUNNING, GRADING_EVENT_GRADER_STARTED, GRADING_EVENT_OUTPUT, GRADING_EVENT_QUESTION} {
+        found := slices.ContainsFunc(events, func(event *GradingEvent) bool {
+            return (event.Type == eventType);
+        });
+
+        if (!found) {
+            test.Errorf("Did not find an event of type '%s'.", eventType);
+        }
+    }
+
+    // Asking again once finished should return right away.
+    newEvents, finished, err := GetGradingJobEvents(context.Background(), jobID, len(events));
+    if ((err != nil) || (!finished) || (len(newEvents) != 0)) {
+        test.Fatalf("Unexpected result after finishing. Events: '%v', Finished: %v, Error: '%v'.", newEvents, finished, err);
+    }
+
+    // Waiting on an unfinished job should stop when the context is done.
+    ctx, cancel := context.WithCancel(context.Background());
+    cancel();
+
+    waitJob := &GradingJob{ID: "wait-test", User: BASE_TEST_USER};
+    queue.lock.Lock();
+    waitJob.eventsAdded = make(chan struct{});
+    queue.jobs[waitJob.ID] = waitJob;
+    queue.lock.Unlock();
+
+    defer func() {
+        queue.lock.Lock();
+        delete(queue.jobs, waitJob.ID);
+        queue.lock.Unlock();
+    }();
+
+    _, _, err = GetGradingJobEvents(ctx, waitJob.ID, 0);
+    if (err == nil) {
+        test.Fatalf("Did not get an error from a canceled context.");
+    }
+}
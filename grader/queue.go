@@ -0,0 +1,541 @@
+package grader
+
+// A queue that limits how many submissions are graded at the same time.
+// Queued jobs (including a copy of their submission files) are kept on disk
+// until they are finished, so they will be picked back up if the server restarts.
+
+import (
+    "cmp"
+    "context"
+    "fmt"
+    "os"
+    "path/filepath"
+    "slices"
+    "sync"
+    "time"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+const (
+    QUEUE_JOB_FILENAME = "job.json"
+    QUEUE_SUBMISSION_DIRNAME = "submission"
+
+    // How long finished jobs are remembered (so their results can be fetched).
+    FINISHED_JOB_RETENTION = 1 * time.Hour
+
+    // The most output lines that will be kept as events for a single job.
+    // Any more output will still be in the grading result, but not sent as events.
+    MAX_JOB_OUTPUT_EVENTS = 5000
+)
+
+type JobState string;
+
+const (
+    JOB_STATE_QUEUED JobState = "queued"
+    JOB_STATE_RUNNING JobState = "running"
+    JOB_STATE_DONE JobState = "done"
+)
+
+// A request to grade a submission.
+// Only the exported fields are persisted.
+type GradingJob struct {
+    ID string `json:"id"`
+    CourseID string `json:"course-id"`
+    AssignmentID string `json:"assignment-id"`
+    User string `json:"user"`
+    Message string `json:"message"`
+    CheckRejection bool `json:"check-rejection"`
+    QueueTime common.Timestamp `json:"queue-time"`
+
+    // The order jobs were queued in.
+    Sequence int64 `json:"sequence"`
+
+    // Set if this job regrades a stored submission (see Regrade()) instead of grading a new one.
+    RegradeShortID string `json:"regrade-short-id,omitempty"`
+    // Regrade, but do not save the new result.
+    RegradeDryRun bool `json:"regrade-dry-run,omitempty"`
+
+    // Options to grade with instead of the defaults.
+    // Not persisted, so a recovered job will use the defaults.
+    options *GradeOptions
+
+    state JobState
+    result *model.GradingResult
+    reject RejectReason
+    err error
+    finishTime time.Time
+    done chan struct{}
+    // The last position reported in a GRADING_EVENT_QUEUED event.
+    lastPosition int
+
+    eventLock sync.Mutex
+    events []*GradingEvent
+    numOutputEvents int
+    finished bool
+    // Closed (and replaced) whenever an event is added.
+    eventsAdded chan struct{}
+}
+
+// A snapshot of a job's progress.
+type GradingJobStatus struct {
+    ID string `json:"id"`
+    CourseID string `json:"course-id"`
+    AssignmentID string `json:"assignment-id"`
+    User string `json:"user"`
+    State JobState `json:"state"`
+
+    // The (1-based) position in the queue, only set while the job is queued.
+    Position int `json:"position,omitempty"`
+
+    // Only set once the job is done.
+    Result *model.GradingResult `json:"-"`
+    Reject RejectReason `json:"-"`
+    Error error `json:"-"`
+}
+
+type gradingQueue struct {
+    lock sync.Mutex
+    // Signaled when a job is added.
+    added *sync.Cond
+
+    // Jobs waiting to be graded (in the order they were added).
+    pending []*GradingJob
+    // How many jobs are currently being graded for each user.
+    running map[string]int
+    // All known (pending, running, and recently finished) jobs.
+    jobs map[string]*GradingJob
+
+    nextSequence int64
+}
+
+var queue *gradingQueue = newGradingQueue();
+var queueStartOnce sync.Once;
+
+func newGradingQueue() *gradingQueue {
+    newQueue := &gradingQueue{
+        pending: make([]*GradingJob, 0),
+        running: make(map[string]int),
+        jobs: make(map[string]*GradingJob),
+    };
+
+    newQueue.added = sync.NewCond(&newQueue.lock);
+
+    return newQueue;
+}
+
+// Start the grading workers and pick up any jobs that were left over from a previous run.
+// Calling this more than once has no effect.
+// The queue will be started automatically when a job is queued,
+// but servers should start it early so old jobs do not need to wait for a new one.
+func StartGradingQueue() {
+    queueStartOnce.Do(func() {
+        err := queue.recover(config.GetGradingQueueDir());
+        if (err != nil) {
+            log.Error("Failed to recover grading queue.", err);
+        }
+
+        numWorkers := max(1, config.GRADING_WORKERS.Get());
+        for i := 0; i < numWorkers; i++ {
+            go queue.work();
+        }
+
+        log.Debug("Started grading queue.", log.NewAttr("workers", numWorkers), log.NewAttr("recovered-jobs", len(queue.pending)));
+    });
+}
+
+// Add a submission to the grading queue and return the job's ID.
+// The submission files are copied, so the caller may remove them once this returns.
+func QueueGrade(assignment *model.Assignment, submissionPath string, user string, message string, checkRejection bool) (string, error) {
+    StartGradingQueue();
+
+    job := &GradingJob{
+        ID: util.UUID(),
+        CourseID: assignment.GetCourse().GetID(),
+        AssignmentID: assignment.GetID(),
+        User: user,
+        Message: message,
+        CheckRejection: checkRejection,
+        QueueTime: common.NowTimestamp(),
+    };
+
+    return job.ID, queue.add(job, submissionPath, config.GetGradingQueueDir());
+}
+
+// Queue a submission (with the default options) and wait for it to be graded.
+// Returns the same values as Grade().
+func GradeQueued(assignment *model.Assignment, submissionPath string, user string, message string) (
+        *model.GradingResult, RejectReason, error) {
+    jobID, err := QueueGrade(assignment, submissionPath, user, message, true);
+    if (err != nil) {
+        return nil, nil, fmt.Errorf("Failed to queue submission for grading: '%w'.", err);
+    }
+
+    return WaitForGradingJob(jobID);
+}
+
+// Wait for a job to finish and return its results (the same values as Grade()).
+func WaitForGradingJob(jobID string) (*model.GradingResult, RejectReason, error) {
+    queue.lock.Lock();
+    job, ok := queue.jobs[jobID];
+    queue.lock.Unlock();
+
+    if (!ok) {
+        return nil, nil, fmt.Errorf("Unknown grading job: '%s'.", jobID);
+    }
+
+    <-job.done;
+
+    return job.result, job.reject, job.err;
+}
+
+// Get a job's events, starting at the given index.
+// If there are no events past start, then this will wait until there are (or until the context is done).
+// Also returns if the job has finished (in which case there will be no events after the returned ones).
+func GetGradingJobEvents(ctx context.Context, jobID string, start int) ([]*GradingEvent, bool, error) {
+    queue.lock.Lock();
+    job, ok := queue.jobs[jobID];
+    queue.lock.Unlock();
+
+    if (!ok) {
+        return nil, false, fmt.Errorf("Unknown grading job: '%s'.", jobID);
+    }
+
+    for {
+        job.eventLock.Lock();
+        if ((start < len(job.events)) || (job.finished)) {
+            events := slices.Clone(job.events[min(start, len(job.events)):]);
+            finished := job.finished;
+            job.eventLock.Unlock();
+
+            return events, finished, nil;
+        }
+
+        eventsAdded := job.eventsAdded;
+        job.eventLock.Unlock();
+
+        select {
+            case <-eventsAdded:
+                continue;
+            case <-ctx.Done():
+                return nil, false, ctx.Err();
+        }
+    }
+}
+
+// Get the status of a job, or nil if the job is not known
+// (finished jobs are only remembered for FINISHED_JOB_RETENTION).
+func GetGradingJobStatus(jobID string) *GradingJobStatus {
+    queue.lock.Lock();
+    defer queue.lock.Unlock();
+
+    job, ok := queue.jobs[jobID];
+    if (!ok) {
+        return nil;
+    }
+
+    status := &GradingJobStatus{
+        ID: job.ID,
+        CourseID: job.CourseID,
+        AssignmentID: job.AssignmentID,
+        User: job.User,
+        State: job.state,
+    };
+
+    switch job.state {
+        case JOB_STATE_QUEUED:
+            status.Position = slices.Index(queue.ordered(), job) + 1;
+        case JOB_STATE_DONE:
+            status.Result = job.result;
+            status.Reject = job.reject;
+            status.Error = job.err;
+    }
+
+    return status;
+}
+
+func (this *gradingQueue) add(job *GradingJob, submissionPath string, queueDir string) error {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    job.Sequence = this.nextSequence;
+    this.nextSequence++;
+
+    jobDir := filepath.Join(queueDir, job.ID);
+
+    err := util.CopyDirent(submissionPath, filepath.Join(jobDir, QUEUE_SUBMISSION_DIRNAME), true);
+    if (err != nil) {
+        util.RemoveDirent(jobDir);
+        return fmt.Errorf("Failed to copy submission into the grading queue: '%w'.", err);
+    }
+
+    err = util.ToJSONFile(job, filepath.Join(jobDir, QUEUE_JOB_FILENAME));
+    if (err != nil) {
+        util.RemoveDirent(jobDir);
+        return fmt.Errorf("Failed to write grading job: '%w'.", err);
+    }
+
+    this.push(job);
+
+    return nil;
+}
+
+// Add a job that is already on disk.
+// The caller must hold the lock.
+func (this *gradingQueue) push(job *GradingJob) {
+    job.state = JOB_STATE_QUEUED;
+    job.done = make(chan struct{});
+
+    job.eventsAdded = make(chan struct{});
+
+    this.pending = append(this.pending, job);
+    this.jobs[job.ID] = job;
+
+    this.updatePositions();
+    this.added.Signal();
+}
+
+// Let every pending job whose position changed know about it.
+// The caller must hold the lock.
+func (this *gradingQueue) updatePositions() {
+    for i, job := range this.ordered() {
+        if (job.lastPosition == (i + 1)) {
+            continue;
+        }
+
+        job.lastPosition = i + 1;
+
+        event := NewGradingEvent(GRADING_EVENT_QUEUED);
+        event.Position = job.lastPosition;
+        job.addEvent(event);
+    }
+}
+
+// Load any jobs left in the queue dir.
+// Jobs that were running when the server stopped will be run again.
+func (this *gradingQueue) recover(queueDir string) error {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    if (!util.PathExists(queueDir)) {
+        return nil;
+    }
+
+    dirents, err := os.ReadDir(queueDir);
+    if (err != nil) {
+        return fmt.Errorf("Failed to read grading queue dir '%s': '%w'.", queueDir, err);
+    }
+
+    jobs := make([]*GradingJob, 0, len(dirents));
+    for _, dirent := range dirents {
+        jobDir := filepath.Join(queueDir, dirent.Name());
+
+        var job GradingJob;
+        err = util.JSONFromFile(filepath.Join(jobDir, QUEUE_JOB_FILENAME), &job);
+        if (err != nil) {
+            log.Warn("Removing unreadable grading job.", err, log.NewAttr("path", jobDir));
+            util.RemoveDirent(jobDir);
+            continue;
+        }
+
+        jobs = append(jobs, &job);
+    }
+
+    slices.SortFunc(jobs, func(a *GradingJob, b *GradingJob) int {
+        return cmp.Compare(a.Sequence, b.Sequence);
+    });
+
+    for _, job := range jobs {
+        this.push(job);
+        this.nextSequence = max(this.nextSequence, job.Sequence + 1);
+    }
+
+    return nil;
+}
+
+// Get the pending jobs in the order they will be run.
+// The caller must hold the lock.
+func (this *gradingQueue) ordered() []*GradingJob {
+    if (!config.GRADING_QUEUE_FAIR.Get()) {
+        return this.pending;
+    }
+
+    // Rank each job by how many of its user's jobs would run before it,
+    // so users take turns (and users with running jobs go later).
+    ranks := make(map[*GradingJob]int, len(this.pending));
+    userCounts := make(map[string]int);
+    for _, job := range this.pending {
+        ranks[job] = this.running[job.User] + userCounts[job.User];
+        userCounts[job.User]++;
+    }
+
+    ordered := slices.Clone(this.pending);
+    slices.SortStableFunc(ordered, func(a *GradingJob, b *GradingJob) int {
+        return ranks[a] - ranks[b];
+    });
+
+    return ordered;
+}
+
+// Block until there is a job to run, and mark it as running.
+func (this *gradingQueue) next() *GradingJob {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    for (len(this.pending) == 0) {
+        this.added.Wait();
+    }
+
+    job := this.ordered()[0];
+
+    this.pending = slices.DeleteFunc(this.pending, func(other *GradingJob) bool {
+        return (other == job);
+    });
+
+    job.state = JOB_STATE_RUNNING;
+    this.running[job.User]++;
+
+    job.addEvent(NewGradingEvent(GRADING_EVENT_RUNNING));
+    this.updatePositions();
+
+    return job;
+}
+
+func (this *gradingQueue) finish(job *GradingJob, result *model.GradingResult, reject RejectReason, err error) {
+    // Remove the job from disk before marking it done, so it will not be re-run.
+    removeErr := util.RemoveDirent(filepath.Join(config.GetGradingQueueDir(), job.ID));
+    if (removeErr != nil) {
+        log.Warn("Failed to remove finished grading job.", removeErr, log.NewAttr("job", job.ID));
+    }
+
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    job.state = JOB_STATE_DONE;
+    job.result = result;
+    job.reject = reject;
+    job.err = err;
+    job.finishTime = time.Now();
+
+    this.running[job.User]--;
+    if (this.running[job.User] <= 0) {
+        delete(this.running, job.User);
+    }
+
+    job.addEvent(NewGradingEvent(GRADING_EVENT_FINISHED));
+    close(job.done);
+
+    // Forget old jobs.
+    for id, other := range this.jobs {
+        if ((other.state == JOB_STATE_DONE) && (time.Since(other.finishTime) > FINISHED_JOB_RETENTION)) {
+            delete(this.jobs, id);
+        }
+    }
+}
+
+// Run jobs forever.
+func (this *gradingQueue) work() {
+    for {
+        job := this.next();
+        result, reject, err := job.gradeSafely(job.grade);
+        job.log(result, reject, err);
+        this.finish(job, result, reject, err);
+    }
+}
+
+// Run a grading function for this job, turning any panic into an error.
+// A panicking job still fails (and is removed from disk) like any other failed job,
+// instead of taking down the server (and panicking again when the queue is recovered).
+func (this *GradingJob) gradeSafely(gradeFunc func() (*model.GradingResult, RejectReason, error)) (
+        result *model.GradingResult, reject RejectReason, err error) {
+    defer func() {
+        value := recover();
+        if (value == nil) {
+            return;
+        }
+
+        log.Error("Recovered from a panic when grading.", log.NewAttr("value", value),
+                log.NewCourseAttr(this.CourseID), log.NewAssignmentAttr(this.AssignmentID), log.NewUserAttr(this.User), log.NewAttr("job", this.ID));
+
+        result = nil;
+        reject = nil;
+        err = fmt.Errorf("Grading job '%s' panicked: '%v'.", this.ID, value);
+    }();
+
+    return gradeFunc();
+}
+
+func (this *GradingJob) grade() (*model.GradingResult, RejectReason, error) {
+    assignment, err := db.GetAssignment(this.CourseID, this.AssignmentID);
+    if (err != nil) {
+        return nil, nil, fmt.Errorf("Failed to get assignment for grading job '%s': '%w'.", this.ID, err);
+    }
+
+    if (assignment == nil) {
+        return nil, nil, fmt.Errorf("Unknown assignment for grading job '%s': '%s'.", this.ID, this.AssignmentID);
+    }
+
+    submissionPath := filepath.Join(config.GetGradingQueueDir(), this.ID, QUEUE_SUBMISSION_DIRNAME);
+
+    options := GetDefaultGradeOptions();
+    if (this.options != nil) {
+        options = *this.options;
+    }
+
+    options.Progress = this.addEvent;
+
+    if (this.RegradeShortID != "") {
+        return this.regrade(assignment, submissionPath, options);
+    }
+
+    // Time spent waiting in the queue does not count against the submission.
+    options.SubmissionTime = this.QueueTime;
+
+    return Grade(assignment, submissionPath, this.User, this.Message, this.CheckRejection, options);
+}
+
+func (this *GradingJob) addEvent(event *GradingEvent) {
+    this.eventLock.Lock();
+    defer this.eventLock.Unlock();
+
+    if (this.finished) {
+        return;
+    }
+
+    if (event.Type == GRADING_EVENT_OUTPUT) {
+        if (this.numOutputEvents >= MAX_JOB_OUTPUT_EVENTS) {
+            return;
+        }
+
+        this.numOutputEvents++;
+    }
+
+    this.events = append(this.events, event);
+    this.finished = (event.Type == GRADING_EVENT_FINISHED);
+
+    close(this.eventsAdded);
+    this.eventsAdded = make(chan struct{});
+}
+
+func (this *GradingJob) log(result *model.GradingResult, reject RejectReason, err error) {
+    attrs := []any{log.NewCourseAttr(this.CourseID), log.NewAssignmentAttr(this.AssignmentID), log.NewUserAttr(this.User), log.NewAttr("job", this.ID)};
+
+    if (err != nil) {
+        stdout := "";
+        stderr := "";
+
+        if ((result != nil) && (result.HasTextOutput())) {
+            stdout = result.Stdout;
+            stderr = result.Stderr;
+        }
+
+        log.Info("Submission grading failed.", append(attrs, err, log.NewAttr("stdout", stdout), log.NewAttr("stderr", stderr))...);
+    } else if (reject != nil) {
+        log.Debug("Submission rejected.", append(attrs, log.NewAttr("reason", reject.String()))...);
+    }
+}
@@ -0,0 +1,227 @@
+package grader
+
+import (
+    "path/filepath"
+    "slices"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+func TestGradingQueueOrder(test *testing.T) {
+    oldFairVal := config.GRADING_QUEUE_FAIR.Get();
+    defer config.GRADING_QUEUE_FAIR.Set(oldFairVal);
+
+    testCases := []struct{fair bool; running []string; expected []string}{
+        {false, nil, []string{"a1", "a2", "a3", "b1", "c1", "b2"}},
+        {true, nil, []string{"a1", "b1", "c1", "a2", "b2", "a3"}},
+        {true, []string{"a"}, []string{"b1", "c1", "a1", "b2", "a2", "a3"}},
+        {true, []string{"a", "b", "b"}, []string{"c1", "a1", "a2", "b1", "a3", "b2"}},
+    };
+
+    for i, testCase := range testCases {
+        config.GRADING_QUEUE_FAIR.Set(testCase.fair);
+
+        testQueue := newGradingQueue();
+        for _, id := range []string{"a1", "a2", "a3", "b1", "c1", "b2"} {
+            testQueue.push(&GradingJob{ID: id, User: id[0:1]});
+        }
+
+        for _, user := range testCase.running {
+            testQueue.running[user]++;
+        }
+
+        actual := make([]string, 0, len(testCase.expected));
+        for _, job := range testQueue.ordered() {
+            actual = append(actual, job.ID);
+        }
+
+        if (!slices.Equal(testCase.expected, actual)) {
+            test.Errorf("Case %d: Unexpected order. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual);
+        }
+    }
+}
+
+func TestGradingQueueRecover(test *testing.T) {
+    queueDir, err := util.MkDirTemp("autograder-test-grading-queue-");
+    if (err != nil) {
+        test.Fatalf("Failed to create temp dir: '%v'.", err);
+    }
+    defer util.RemoveDirent(queueDir);
+
+    assignment := db.MustGetTestAssignment();
+    submissionPath := filepath.Join(config.GetCourseImportDir(), "_tests", "COURSE101", "HW0", "test-submissions", "solution");
+
+    oldQueue := newGradingQueue();
+    expected := make([]string, 0);
+    for i := 0; i < 3; i++ {
+        job := &GradingJob{ID: util.UUID(), CourseID: assignment.GetCourse().GetID(), AssignmentID: assignment.GetID(), User: BASE_TEST_USER};
+        err := oldQueue.add(job, submissionPath, queueDir);
+        if (err != nil) {
+            test.Fatalf("Failed to add job: '%v'.", err);
+        }
+
+        expected = append(expected, job.ID);
+    }
+
+    // Garbage in the queue dir should be cleaned up.
+    util.MkDir(filepath.Join(queueDir, "garbage"));
+
+    newQueue := newGradingQueue();
+    err = newQueue.recover(queueDir);
+    if (err != nil) {
+        test.Fatalf("Failed to recover queue: '%v'.", err);
+    }
+
+    actual := make([]string, 0);
+    for _, job := range newQueue.pending {
+        actual = append(actual, job.ID);
+
+        if (!util.PathExists(filepath.Join(queueDir, job.ID, QUEUE_SUBMISSION_DIRNAME, "submission.py"))) {
+            test.Errorf("Submission files for job '%s' were not persisted.", job.ID);
+        }
+    }
+
+    if (!slices.Equal(expected, actual)) {
+        test.Fatalf("Unexpected recovered jobs. Expected: '%v', Actual: '%v'.", expected, actual);
+    }
+
+    if (util.PathExists(filepath.Join(queueDir, "garbage"))) {
+        test.Fatalf("Unreadable job was not removed.");
+    }
+
+    if (newQueue.nextSequence != 3) {
+        test.Fatalf("Unexpected next sequence. Expected: 3, Actual: %d.", newQueue.nextSequence);
+    }
+}
+
+func TestGradeQueued(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    oldDockerVal := config.DOCKER_DISABLE.Get();
+    config.DOCKER_DISABLE.Set(true);
+    defer config.DOCKER_DISABLE.Set(oldDockerVal);
+
+    assignment := db.MustGetTestAssignment();
+    assignment.Invocation = []string{"sh", "-c", `echo '{"name": "hw0", "questions": []}' > "$0"`, "<outpath>"};
+
+    err := db.SaveCourse(assignment.GetCourse());
+    if (err != nil) {
+        test.Fatalf("Failed to save assignment: '%v'.", err);
+    }
+
+    submissionPath := filepath.Join(config.GetCourseImportDir(), "_tests", "COURSE101", "HW0", "test-submissions", "solution");
+
+    jobID, err := QueueGrade(assignment, submissionPath, "queue_" + BASE_TEST_USER, TEST_MESSAGE, false);
+    if (err != nil) {
+        test.Fatalf("Failed to queue submission: '%v'.", err);
+    }
+
+    result, reject, err := WaitForGradingJob(jobID);
+    if (err != nil) {
+        test.Fatalf("Failed to grade submission: '%v'.", err);
+    }
+
+    if (reject != nil) {
+        test.Fatalf("Submission was rejected: '%s'.", reject.String());
+    }
+
+    if ((result == nil) || (result.Info == nil)) {
+        test.Fatalf("Did not get a grading result.");
+    }
+
+    // The submission time is when the job was queued (not when grading started).
+    queue.lock.Lock();
+    queueTime := queue.jobs[jobID].QueueTime;
+    queue.lock.Unlock();
+
+    if (result.Info.SubmissionTime != queueTime) {
+        test.Fatalf("Unexpected submission time. Expected: '%s', Actual: '%s'.", queueTime, result.Info.SubmissionTime);
+    }
+
+    status := GetGradingJobStatus(jobID);
+    if ((status == nil) || (status.State != JOB_STATE_DONE) || (status.Result != result)) {
+        test.Fatalf("Unexpected status: '%+v'.", status);
+    }
+
+    if (util.PathExists(filepath.Join(config.GetGradingQueueDir(), jobID))) {
+        test.Fatalf("Finished job was not removed from disk.");
+    }
+}
+
+func TestGradeSubmissionTime(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    oldDockerVal := config.DOCKER_DISABLE.Get();
+    config.DOCKER_DISABLE.Set(true);
+    defer config.DOCKER_DISABLE.Set(oldDockerVal);
+
+    assignment := db.MustGetTestAssignment();
+    assignment.Invocation = []string{"sh", "-c", `echo '{"name": "hw0", "questions": []}' > "$0"`, "<outpath>"};
+
+    submissionPath := filepath.Join(config.GetCourseImportDir(), "_tests", "COURSE101", "HW0", "test-submissions", "solution");
+    submissionTime := common.TimestampFromTime(time.Now().Add(-1 * time.Hour));
+
+    options := GradeOptions{NoDocker: true, SubmissionTime: submissionTime};
+    result, reject, err := Grade(assignment, submissionPath, "time_" + BASE_TEST_USER, TEST_MESSAGE, false, options);
+    if (err != nil) {
+        test.Fatalf("Failed to grade submission: '%v'.", err);
+    }
+
+    if (reject != nil) {
+        test.Fatalf("Submission was rejected: '%s'.", reject.String());
+    }
+
+    if (result.Info.SubmissionTime != submissionTime) {
+        test.Fatalf("Unexpected submission time. Expected: '%s', Actual: '%s'.", submissionTime, result.Info.SubmissionTime);
+    }
+
+    if (result.Info.GradingStartTime == submissionTime) {
+        test.Fatalf("Grading start time was not set separately from the submission time.");
+    }
+
+    scoringTime := result.Info.ToScoringInfo().SubmissionTime;
+    if (scoringTime != submissionTime) {
+        test.Fatalf("Unexpected scoring time. Expected: '%s', Actual: '%s'.", submissionTime, scoringTime);
+    }
+}
+
+func TestGradingQueuePanic(test *testing.T) {
+    queueDir := config.GetGradingQueueDir();
+    job := &GradingJob{ID: util.UUID(), User: "panic_" + BASE_TEST_USER};
+
+    testQueue := newGradingQueue();
+    util.MkDir(filepath.Join(queueDir, job.ID));
+    testQueue.push(job);
+
+    job = testQueue.next();
+    result, reject, err := job.gradeSafely(func() (*model.GradingResult, RejectReason, error) {
+        panic("test panic");
+    });
+
+    if ((result != nil) || (reject != nil)) {
+        test.Fatalf("Got a result/rejection from a panicked job: '%v', '%v'.", result, reject);
+    }
+
+    if ((err == nil) || !strings.Contains(err.Error(), "test panic")) {
+        test.Fatalf("Did not get the panic as an error, got: '%v'.", err);
+    }
+
+    testQueue.finish(job, result, reject, err);
+
+    if (job.state != JOB_STATE_DONE) {
+        test.Fatalf("Panicked job is not done: '%s'.", job.state);
+    }
+
+    if (util.PathExists(filepath.Join(queueDir, job.ID))) {
+        test.Fatalf("Panicked job was not removed from disk.");
+    }
+}
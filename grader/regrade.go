@@ -0,0 +1,255 @@
+package grader
+
+import (
+    "fmt"
+    "os"
+    "slices"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/docker"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+type RegradeOptions struct {
+    // Regrade all of each user's submissions (instead of just their most recent one).
+    All bool
+    // If not empty, only regrade submissions from these users.
+    Users []string
+    // If not empty, only regrade these (full) submission IDs.
+    // All and Users are ignored.
+    SubmissionIDs []string
+    // Grade, but do not save the new results.
+    DryRun bool
+
+    GradeOptions
+}
+
+// The outcome of regrading a single submission.
+type RegradeResult struct {
+    User string `json:"user"`
+    SubmissionID string `json:"submission-id"`
+    MaxPoints float64 `json:"max-points"`
+    OldScore float64 `json:"old-score"`
+    NewScore float64 `json:"new-score"`
+
+    // Set if this submission could not be regraded (in which case it was left unchanged).
+    Error string `json:"error,omitempty"`
+}
+
+func (this *RegradeResult) ScoreChange() float64 {
+    return this.NewScore - this.OldScore;
+}
+
+func (this *RegradeResult) Changed() bool {
+    return ((this.Error == "") && (this.ScoreChange() != 0));
+}
+
+// Re-run the assignment's grader on stored submissions (using their saved input files),
+// and replace each old result with the new one.
+// Each submission keeps its ID, message, and original submission (grading start) time,
+// so late policies will treat it the same as the original submission.
+// Submissions are regraded through the grading queue (so they share its workers with new submissions),
+// and this will wait for all of them to finish.
+// A failure to regrade a single submission is reported in its result (and does not stop the others),
+// the returned error is for failures that stop the entire regrade.
+func Regrade(assignment *model.Assignment, options RegradeOptions) ([]*RegradeResult, error) {
+    submissions, err := getRegradeSubmissions(assignment, options);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    err = docker.BuildImageFromSourceQuick(assignment);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to build assignment '%s' docker image: '%w'.", assignment.FullID(), err);
+    }
+
+    results := make([]*RegradeResult, 0, len(submissions));
+    jobIDs := make([]string, 0, len(submissions));
+
+    for _, submission := range submissions {
+        result := &RegradeResult{
+            User: submission.Info.User,
+            SubmissionID: submission.Info.ID,
+            MaxPoints: submission.Info.MaxPoints,
+            OldScore: submission.Info.Score,
+            NewScore: submission.Info.Score,
+        };
+
+        jobID, err := queueRegrade(assignment, submission, options);
+        if (err != nil) {
+            log.Warn("Failed to queue submission for regrading.", err, assignment, log.NewAttr("submission", submission.Info.ID));
+            result.Error = err.Error();
+        }
+
+        results = append(results, result);
+        jobIDs = append(jobIDs, jobID);
+    }
+
+    for i, result := range results {
+        if (jobIDs[i] == "") {
+            continue;
+        }
+
+        newSubmission, _, err := WaitForGradingJob(jobIDs[i]);
+        if (err != nil) {
+            log.Warn("Failed to regrade submission.", err, assignment, log.NewAttr("submission", result.SubmissionID));
+            result.Error = err.Error();
+        } else {
+            result.MaxPoints = newSubmission.Info.MaxPoints;
+            result.NewScore = newSubmission.Info.Score;
+        }
+    }
+
+    return results, nil;
+}
+
+// Add a stored submission to the grading queue to be regraded, and return the job's ID.
+func queueRegrade(assignment *model.Assignment, submission *model.GradingResult, options RegradeOptions) (string, error) {
+    StartGradingQueue();
+
+    tempDir, err := util.MkDirTemp("autograder-regrade-");
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to create temp dir: '%w'.", err);
+    }
+    defer os.RemoveAll(tempDir);
+
+    err = util.GzipBytesToDirectory(tempDir, submission.InputFilesGZip);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to write stored input files: '%w'.", err);
+    }
+
+    gradeOptions := options.GradeOptions;
+
+    job := &GradingJob{
+        ID: util.UUID(),
+        CourseID: assignment.GetCourse().GetID(),
+        AssignmentID: assignment.GetID(),
+        User: submission.Info.User,
+        Message: submission.Info.Message,
+        QueueTime: common.NowTimestamp(),
+        RegradeShortID: submission.Info.ShortID,
+        RegradeDryRun: options.DryRun,
+        options: &gradeOptions,
+    };
+
+    return job.ID, queue.add(job, tempDir, config.GetGradingQueueDir());
+}
+
+// Regrade the stored submission this job points to, using the (queued) input files at submissionPath.
+func (this *GradingJob) regrade(assignment *model.Assignment, submissionPath string, options GradeOptions) (
+        *model.GradingResult, RejectReason, error) {
+    oldSubmission, err := db.GetSubmissionContents(assignment, this.User, this.RegradeShortID);
+    if (err != nil) {
+        return nil, nil, fmt.Errorf("Failed to get submission '%s' for regrading: '%w'.", this.RegradeShortID, err);
+    }
+
+    if (oldSubmission == nil) {
+        return nil, nil, fmt.Errorf("Could not find submission '%s' for regrading.", this.RegradeShortID);
+    }
+
+    newSubmission, err := regradeSubmission(assignment, oldSubmission, submissionPath, this.RegradeDryRun, options);
+    return newSubmission, nil, err;
+}
+
+func regradeSubmission(assignment *model.Assignment, oldSubmission *model.GradingResult, submissionPath string,
+        dryRun bool, options GradeOptions) (*model.GradingResult, error) {
+    oldInfo := oldSubmission.Info;
+
+    lock := lockSubmissions(assignment, oldInfo.User);
+    defer lock.Unlock();
+
+    newSubmission, err := runGrader(assignment, submissionPath, oldInfo.User, oldInfo.Message, oldInfo.ShortID,
+            oldSubmission.InputFilesGZip, options);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    newSubmission.Info.GradingStartTime = oldInfo.GradingStartTime;
+    newSubmission.Info.SubmissionTime = oldInfo.SubmissionTime;
+
+    if (dryRun) {
+        return newSubmission, nil;
+    }
+
+    err = db.SaveSubmission(assignment, newSubmission);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to save regraded submission: '%w'.", err);
+    }
+
+    return newSubmission, nil;
+}
+
+// Get the stored submissions (with their input files) that the options select.
+func getRegradeSubmissions(assignment *model.Assignment, options RegradeOptions) ([]*model.GradingResult, error) {
+    submissions := make([]*model.GradingResult, 0);
+
+    if (len(options.SubmissionIDs) > 0) {
+        for _, submissionID := range options.SubmissionIDs {
+            parts := common.SplitFullSubmissionID(submissionID);
+            if ((len(parts) != 4) || (parts[0] != assignment.GetCourse().GetID()) || (parts[1] != assignment.GetID())) {
+                return nil, fmt.Errorf("Submission ID '%s' is not a full submission ID for assignment '%s'.", submissionID, assignment.FullID());
+            }
+
+            submission, err := db.GetSubmissionContents(assignment, parts[2], parts[3]);
+            if (err != nil) {
+                return nil, fmt.Errorf("Failed to get submission '%s': '%w'.", submissionID, err);
+            }
+
+            if (submission == nil) {
+                return nil, fmt.Errorf("Could not find submission '%s'.", submissionID);
+            }
+
+            submissions = append(submissions, submission);
+        }
+
+        return submissions, nil;
+    }
+
+    users := options.Users;
+    if (len(users) == 0) {
+        courseUsers, err := db.GetUsers(assignment.GetCourse());
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to get users: '%w'.", err);
+        }
+
+        for email, _ := range courseUsers {
+            users = append(users, email);
+        }
+    }
+
+    slices.Sort(users);
+
+    for _, user := range users {
+        var userSubmissions []*model.GradingResult;
+
+        if (options.All) {
+            attempts, err := db.GetSubmissionAttempts(assignment, user);
+            if (err != nil) {
+                return nil, fmt.Errorf("Failed to get submissions for user '%s': '%w'.", user, err);
+            }
+
+            userSubmissions = attempts;
+        } else {
+            submission, err := db.GetSubmissionContents(assignment, user, "");
+            if (err != nil) {
+                return nil, fmt.Errorf("Failed to get most recent submission for user '%s': '%w'.", user, err);
+            }
+
+            if (submission != nil) {
+                userSubmissions = []*model.GradingResult{submission};
+            }
+        }
+
+        for _, submission := range userSubmissions {
+            if (submission != nil) {
+                submissions = append(submissions, submission);
+            }
+        }
+    }
+
+    return submissions, nil;
+}
@@ -0,0 +1,152 @@
+package grader
+
+import (
+    "testing"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+)
+
+const TEST_REGRADE_GRADER = `echo '{"name": "hw0", "questions": [{"name": "Q1", "max_points": 2, "score": 1.5}]}' > "$0"`;
+
+func TestRegrade(test *testing.T) {
+    testCases := []struct{options RegradeOptions; expectedIDs []string}{
+        // Most recent.
+        {
+            RegradeOptions{},
+            []string{"course101::hw0::student@test.com::1697406272"},
+        },
+        {
+            RegradeOptions{Users: []string{"student@test.com", "grader@test.com"}},
+            []string{"course101::hw0::student@test.com::1697406272"},
+        },
+        {
+            RegradeOptions{Users: []string{"grader@test.com"}},
+            []string{},
+        },
+
+        // All.
+        {
+            RegradeOptions{All: true},
+            []string{
+                "course101::hw0::student@test.com::1697406256",
+                "course101::hw0::student@test.com::1697406265",
+                "course101::hw0::student@test.com::1697406272",
+            },
+        },
+
+        // Selected.
+        {
+            RegradeOptions{SubmissionIDs: []string{"course101::hw0::student@test.com::1697406265"}},
+            []string{"course101::hw0::student@test.com::1697406265"},
+        },
+        {
+            RegradeOptions{SubmissionIDs: []string{"course101::hw0::student@test.com::1697406265"}, DryRun: true},
+            []string{"course101::hw0::student@test.com::1697406265"},
+        },
+    };
+
+    oldDockerVal := config.DOCKER_DISABLE.Get();
+    config.DOCKER_DISABLE.Set(true);
+    defer config.DOCKER_DISABLE.Set(oldDockerVal);
+
+    for i, testCase := range testCases {
+        db.ResetForTesting();
+
+        assignment := db.MustGetTestAssignment();
+        assignment.Invocation = []string{"sh", "-c", TEST_REGRADE_GRADER, "<outpath>"};
+
+        // Queued jobs load the assignment from the database.
+        err := db.SaveCourse(assignment.GetCourse());
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to save assignment: '%v'.", i, err);
+        }
+
+        oldInfos := make(map[string]*model.GradingInfo);
+        for _, id := range testCase.expectedIDs {
+            info, err := db.GetSubmissionResult(assignment, "student@test.com", id);
+            if (err != nil) {
+                test.Fatalf("Case %d: Failed to get old submission '%s': '%v'.", i, id, err);
+            }
+
+            oldInfos[id] = info;
+        }
+
+        testCase.options.NoDocker = true;
+
+        results, err := Regrade(assignment, testCase.options);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to regrade: '%v'.", i, err);
+            continue;
+        }
+
+        if (len(results) != len(testCase.expectedIDs)) {
+            test.Errorf("Case %d: Unexpected number of results. Expected: %d, Actual: %d.", i, len(testCase.expectedIDs), len(results));
+            continue;
+        }
+
+        for j, result := range results {
+            oldInfo := oldInfos[testCase.expectedIDs[j]];
+
+            if (result.SubmissionID != testCase.expectedIDs[j]) {
+                test.Errorf("Case %d: Unexpected submission. Expected: '%s', Actual: '%s'.", i, testCase.expectedIDs[j], result.SubmissionID);
+                continue;
+            }
+
+            if (result.Error != "") {
+                test.Errorf("Case %d: Regrading '%s' failed: '%s'.", i, result.SubmissionID, result.Error);
+                continue;
+            }
+
+            if ((result.OldScore != oldInfo.Score) || (result.NewScore != 1.5) || (result.Changed() != (oldInfo.Score != 1.5))) {
+                test.Errorf("Case %d: Unexpected result: '%+v'.", i, result);
+                continue;
+            }
+
+            newInfo, err := db.GetSubmissionResult(assignment, "student@test.com", result.SubmissionID);
+            if (err != nil) {
+                test.Errorf("Case %d: Failed to get new submission '%s': '%v'.", i, result.SubmissionID, err);
+                continue;
+            }
+
+            expectedScore := 1.5;
+            if (testCase.options.DryRun) {
+                expectedScore = oldInfo.Score;
+            }
+
+            if (newInfo.Score != expectedScore) {
+                test.Errorf("Case %d: Unexpected stored score. Expected: %f, Actual: %f.", i, expectedScore, newInfo.Score);
+                continue;
+            }
+
+            if (newInfo.GradingStartTime != oldInfo.GradingStartTime) {
+                test.Errorf("Case %d: Submission time was not kept. Expected: '%s', Actual: '%s'.", i, oldInfo.GradingStartTime, newInfo.GradingStartTime);
+                continue;
+            }
+        }
+
+        // Regrading should not add any submissions.
+        history, err := db.GetSubmissionHistory(assignment, "student@test.com");
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to get history: '%v'.", i, err);
+        }
+
+        if (len(history) != 3) {
+            test.Errorf("Case %d: Unexpected number of submissions. Expected: 3, Actual: %d.", i, len(history));
+        }
+    }
+
+    db.ResetForTesting();
+}
+
+func TestRegradeBadSubmissionID(test *testing.T) {
+    assignment := db.MustGetTestAssignment();
+
+    for i, id := range []string{"1697406265", "course101::hw1::student@test.com::1697406265", "course101::hw0::student@test.com::0"} {
+        _, err := Regrade(assignment, RegradeOptions{SubmissionIDs: []string{id}});
+        if (err == nil) {
+            test.Errorf("Case %d: Did not get an error for bad submission ID '%s'.", i, id);
+        }
+    }
+}
@@ -2,14 +2,21 @@ package grader
 
 import (
     "fmt"
+    "io/fs"
+    "path"
+    "path/filepath"
+    "strings"
     "time"
 
     "github.com/edulinq/autograder/common"
     "github.com/edulinq/autograder/config"
     "github.com/edulinq/autograder/db"
     "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
 )
 
+const BYTES_PER_KB = 1024;
+
 // Reasons a submission can be rejected.
 type RejectReason interface {
     String() string
@@ -38,45 +45,232 @@ func (this *RejectWindowMax) String() string {
             nextTime.Format(time.RFC1123), delta.String());
 }
 
-func checkForRejection(assignment *model.Assignment, submissionPath string, user string, message string) (RejectReason, error) {
-    return checkSubmissionLimit(assignment, user);
+type RejectNotOpen struct {
+    OpenDate time.Time
+}
+
+func (this *RejectNotOpen) String() string {
+    delta := this.OpenDate.Sub(time.Now());
+    return fmt.Sprintf("Assignment is not open for submissions yet. It opens at %s (in %s).",
+            this.OpenDate.Format(time.RFC1123), delta.Round(time.Second).String());
+}
+
+type RejectClosed struct {
+    CloseDate time.Time
+}
+
+func (this *RejectClosed) String() string {
+    return fmt.Sprintf("Assignment is closed for submissions. It closed at %s.", this.CloseDate.Format(time.RFC1123));
+}
+
+type RejectTooManyFiles struct {
+    Count int
+    Max int
+}
+
+func (this *RejectTooManyFiles) String() string {
+    return fmt.Sprintf("Submission has too many files (%d), the max is %d.", this.Count, this.Max);
+}
+
+type RejectSubmissionTooLarge struct {
+    SizeKB float64
+    MaxKB int
+}
+
+func (this *RejectSubmissionTooLarge) String() string {
+    return fmt.Sprintf("Submission is too large (%s KB), the max is %d KB.", util.FloatToStr(this.SizeKB), this.MaxKB);
+}
+
+type RejectFileTooLarge struct {
+    Path string
+    SizeKB float64
+    MaxKB int
+}
+
+func (this *RejectFileTooLarge) String() string {
+    return fmt.Sprintf("File '%s' is too large (%s KB), the max is %d KB.", this.Path, util.FloatToStr(this.SizeKB), this.MaxKB);
+}
+
+type RejectForbiddenFile struct {
+    Path string
+    Pattern string
 }
 
-func checkSubmissionLimit(assignment *model.Assignment, email string) (RejectReason, error) {
-    // Do not check for submission limits in testing mode.
+func (this *RejectForbiddenFile) String() string {
+    return fmt.Sprintf("File '%s' is not allowed to be submitted (matches '%s').", this.Path, this.Pattern);
+}
+
+type RejectFileNotAllowed struct {
+    Path string
+    Allowed []string
+}
+
+func (this *RejectFileNotAllowed) String() string {
+    return fmt.Sprintf("File '%s' is not allowed to be submitted, only files matching these patterns are allowed: '%s'.",
+            this.Path, strings.Join(this.Allowed, "', '"));
+}
+
+type RejectMissingFile struct {
+    Path string
+}
+
+func (this *RejectMissingFile) String() string {
+    return fmt.Sprintf("Submission is missing a required file: '%s'.", this.Path);
+}
+
+// Dates and limits are checked against the submission time (when the submission was made, not when it is graded).
+func checkForRejection(assignment *model.Assignment, submissionPath string, user string, message string,
+        submissionTime time.Time) (RejectReason, error) {
+    reject, err := checkSubmissionFiles(assignment.SubmissionFileRules, submissionPath);
+    if ((reject != nil) || (err != nil)) {
+        return reject, err;
+    }
+
+    // Do not check for submission restrictions in testing mode.
     if (config.TESTING_MODE.Get()) {
         return nil, nil;
     }
 
-    user, err := db.GetUser(assignment.GetCourse(), email);
+    courseUser, err := db.GetUser(assignment.GetCourse(), user);
     if (err != nil) {
         return nil, err;
     }
 
-    if (user == nil) {
-        return nil, fmt.Errorf("Unable to find user: '%s'.", email);
+    if (courseUser == nil) {
+        return nil, fmt.Errorf("Unable to find user: '%s'.", user);
     }
 
     // User that are >= grader are not subject to submission restrictions.
-    if (user.Role >= model.RoleGrader) {
+    if (courseUser.Role >= model.RoleGrader) {
+        return nil, nil;
+    }
+
+    extension, err := db.GetExtension(assignment, user);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get extension: '%w'.", err);
+    }
+
+    reject = checkSubmissionDates(assignment, extension, submissionTime);
+    if (reject != nil) {
+        return reject, nil;
+    }
+
+    return checkSubmissionLimit(assignment, user, extension, submissionTime);
+}
+
+// The extension may be nil.
+func checkSubmissionDates(assignment *model.Assignment, extension *model.Extension, now time.Time) RejectReason {
+    if (!assignment.OpenDate.IsZero()) {
+        openDate := assignment.OpenDate.MustTime();
+        if (now.Before(openDate)) {
+            return &RejectNotOpen{openDate};
+        }
+    }
+
+    if (!assignment.CloseDate.IsZero()) {
+        closeDate := extension.GetCloseDate(assignment.DueDate, assignment.CloseDate.MustTime());
+        if (!now.Before(closeDate)) {
+            return &RejectClosed{closeDate};
+        }
+    }
+
+    return nil;
+}
+
+// Check the submission's files against the assignment's rules.
+// Returns the first violation found.
+func checkSubmissionFiles(rules *model.SubmissionFileRules, submissionPath string) (RejectReason, error) {
+    if (rules == nil) {
         return nil, nil;
     }
 
+    // Relative path to size (in bytes).
+    files := make(map[string]int64);
+    paths := make([]string, 0);
+    var totalSize int64 = 0;
+
+    err := filepath.WalkDir(submissionPath, func(path string, dirent fs.DirEntry, err error) error {
+        if (err != nil) {
+            return err;
+        }
+
+        if (dirent.IsDir()) {
+            return nil;
+        }
+
+        info, err := dirent.Info();
+        if (err != nil) {
+            return err;
+        }
+
+        relpath, err := filepath.Rel(submissionPath, path);
+        if (err != nil) {
+            return err;
+        }
+
+        relpath = filepath.ToSlash(relpath);
+
+        files[relpath] = info.Size();
+        paths = append(paths, relpath);
+        totalSize += info.Size();
+
+        return nil;
+    });
+
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to list submission files in '%s': '%w'.", submissionPath, err);
+    }
+
+    if ((rules.MaxFileCount > 0) && (len(paths) > rules.MaxFileCount)) {
+        return &RejectTooManyFiles{len(paths), rules.MaxFileCount}, nil;
+    }
+
+    if ((rules.MaxTotalSizeKB > 0) && (totalSize > (int64(rules.MaxTotalSizeKB) * BYTES_PER_KB))) {
+        return &RejectSubmissionTooLarge{float64(totalSize) / BYTES_PER_KB, rules.MaxTotalSizeKB}, nil;
+    }
+
+    for _, relpath := range paths {
+        pattern := model.MatchFilePattern(rules.ForbiddenFiles, relpath);
+        if (pattern != "") {
+            return &RejectForbiddenFile{relpath, pattern}, nil;
+        }
+
+        if ((len(rules.AllowedFiles) > 0) && (model.MatchFilePattern(rules.AllowedFiles, relpath) == "")) {
+            return &RejectFileNotAllowed{relpath, rules.AllowedFiles}, nil;
+        }
+
+        size := files[relpath];
+        if ((rules.MaxFileSizeKB > 0) && (size > (int64(rules.MaxFileSizeKB) * BYTES_PER_KB))) {
+            return &RejectFileTooLarge{relpath, float64(size) / BYTES_PER_KB, rules.MaxFileSizeKB}, nil;
+        }
+    }
+
+    for _, requiredPath := range rules.RequiredFiles {
+        _, ok := files[path.Clean(requiredPath)];
+        if (!ok) {
+            return &RejectMissingFile{requiredPath}, nil;
+        }
+    }
+
+    return nil, nil;
+}
+
+// The extension may be nil.
+func checkSubmissionLimit(assignment *model.Assignment, email string, extension *model.Extension, now time.Time) (RejectReason, error) {
     limit := assignment.GetSubmissionLimit();
     if (limit == nil) {
         return nil, nil;
     }
 
-    now := time.Now();
-
     history, err := db.GetSubmissionHistory(assignment, email);
     if (err != nil) {
         return nil, err;
     }
 
-    if (*limit.Max >= 0) {
-        if (len(history) >= *limit.Max) {
-            return &RejectMaxAttempts{*limit.Max}, nil;
+    maxAttempts := extension.GetMaxAttempts(*limit.Max);
+    if (maxAttempts >= 0) {
+        if (len(history) >= maxAttempts) {
+            return &RejectMaxAttempts{maxAttempts}, nil;
         }
     }
 
@@ -105,9 +299,9 @@ func checkSubmissionLimitWindow(window *model.SubmittionLimitWindow,
 
     windowCount := 0;
     for _, item := range history {
-        itemTime, err := item.GradingStartTime.Time();
+        itemTime, err := item.GetSubmissionTime().Time();
         if (err != nil) {
-            return nil, fmt.Errorf("Unable to deserialize submission (%s) time ('%s'): '%w'.", item.ID, item.GradingStartTime, err);
+            return nil, fmt.Errorf("Unable to deserialize submission (%s) time ('%s'): '%w'.", item.ID, item.GetSubmissionTime(), err);
         }
 
         if (itemTime.After(windowStart)) {
@@ -1,14 +1,17 @@
 package grader
 
 import (
+    "os"
     "path/filepath"
     "reflect"
     "testing"
+    "time"
 
     "github.com/edulinq/autograder/common"
     "github.com/edulinq/autograder/config"
     "github.com/edulinq/autograder/db"
     "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
 )
 
 var SUBMISSION_RELPATH string = filepath.Join("test-submissions", "solution");
@@ -73,7 +76,7 @@ func testMaxWindowAttemps(test *testing.T, user string, expectReject bool) {
     // Make a submission that should pass.
     result, _, _ := submitForRejection(test, assignment, user, nil);
 
-    expectedTime, err := result.Info.GradingStartTime.Time();
+    expectedTime, err := result.Info.GetSubmissionTime().Time();
     if (err != nil) {
         test.Fatalf("Failed to parse expected time: '%v'.", err);
     }
@@ -133,3 +136,192 @@ func submitForRejection(test *testing.T, assignment *model.Assignment, user stri
 
     return result, reject, err;
 }
+
+func TestRejectSubmissionFiles(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    oldDockerVal := config.DOCKER_DISABLE.Get();
+    config.DOCKER_DISABLE.Set(true);
+    defer config.DOCKER_DISABLE.Set(oldDockerVal);
+
+    assignment := db.MustGetTestAssignment();
+    assignment.SubmissionLimit = &model.SubmissionLimitInfo{};
+    assignment.Invocation = []string{"sh", "-c", TEST_REGRADE_GRADER, "<outpath>"};
+
+    assignment.SubmissionFileRules = &model.SubmissionFileRules{RequiredFiles: []string{"submission.py", "README.md"}};
+    submitForRejection(test, assignment, "other@test.com", &RejectMissingFile{"README.md"});
+
+    // File rules are not bypassed by graders.
+    assignment.SubmissionFileRules = &model.SubmissionFileRules{ForbiddenFiles: []string{"*.json"}};
+    submitForRejection(test, assignment, "grader@test.com", &RejectForbiddenFile{"test-submission.json", "*.json"});
+
+    assignment.SubmissionFileRules = &model.SubmissionFileRules{RequiredFiles: []string{"submission.py"}, AllowedFiles: []string{"*.py", "*.json"}};
+    submitForRejection(test, assignment, "other@test.com", nil);
+}
+
+func TestCheckSubmissionFiles(test *testing.T) {
+    tempDir, err := util.MkDirTemp("autograder-test-reject-files-");
+    if (err != nil) {
+        test.Fatalf("Failed to create temp dir: '%v'.", err);
+    }
+    defer os.RemoveAll(tempDir);
+
+    files := map[string]int{
+        "a.py": 100,
+        "b.txt": 2000,
+        "src/c.py": 1500,
+    };
+
+    err = util.MkDir(filepath.Join(tempDir, "src"));
+    if (err != nil) {
+        test.Fatalf("Failed to create dir: '%v'.", err);
+    }
+
+    for relpath, size := range files {
+        err = util.WriteBinaryFile(make([]byte, size), filepath.Join(tempDir, relpath));
+        if (err != nil) {
+            test.Fatalf("Failed to write '%s': '%v'.", relpath, err);
+        }
+    }
+
+    testCases := []struct{rules *model.SubmissionFileRules; expected RejectReason}{
+        {nil, nil},
+        {&model.SubmissionFileRules{}, nil},
+
+        {&model.SubmissionFileRules{MaxFileCount: 3}, nil},
+        {&model.SubmissionFileRules{MaxFileCount: 2}, &RejectTooManyFiles{3, 2}},
+
+        {&model.SubmissionFileRules{MaxTotalSizeKB: 4}, nil},
+        {&model.SubmissionFileRules{MaxTotalSizeKB: 3}, &RejectSubmissionTooLarge{3600.0 / 1024, 3}},
+
+        {&model.SubmissionFileRules{MaxFileSizeKB: 2}, nil},
+        {&model.SubmissionFileRules{MaxFileSizeKB: 1}, &RejectFileTooLarge{"b.txt", 2000.0 / 1024, 1}},
+
+        {&model.SubmissionFileRules{ForbiddenFiles: []string{"*.java"}}, nil},
+        {&model.SubmissionFileRules{ForbiddenFiles: []string{"*.java", "c.py"}}, &RejectForbiddenFile{"src/c.py", "c.py"}},
+        {&model.SubmissionFileRules{ForbiddenFiles: []string{"src/*"}}, &RejectForbiddenFile{"src/c.py", "src/*"}},
+
+        {&model.SubmissionFileRules{AllowedFiles: []string{"*.py", "*.txt"}}, nil},
+        {&model.SubmissionFileRules{AllowedFiles: []string{"*.py"}}, &RejectFileNotAllowed{"b.txt", []string{"*.py"}}},
+
+        {&model.SubmissionFileRules{RequiredFiles: []string{"a.py", "src/c.py"}}, nil},
+        {&model.SubmissionFileRules{RequiredFiles: []string{"a.py", "c.py"}}, &RejectMissingFile{"c.py"}},
+    };
+
+    for i, testCase := range testCases {
+        reject, err := checkSubmissionFiles(testCase.rules, tempDir);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to check files: '%v'.", i, err);
+            continue;
+        }
+
+        if (!reflect.DeepEqual(testCase.expected, reject)) {
+            test.Errorf("Case %d: Unexpected rejection. Expected: '%+v', Actual: '%+v'.", i, testCase.expected, reject);
+            continue;
+        }
+    }
+}
+
+func TestRejectSubmissionDates(test *testing.T) {
+    testCases := []struct{openDate string; closeDate string; user string; expected RejectReason}{
+        {"", "", "student@test.com", nil},
+        {"2000-01-01T00:00:00Z", "3000-01-01T00:00:00Z", "student@test.com", nil},
+        {"3000-01-01T00:00:00Z", "", "student@test.com", &RejectNotOpen{common.MustTimestampFromString("3000-01-01T00:00:00Z").MustTime()}},
+        {"", "2000-01-01T00:00:00Z", "student@test.com", &RejectClosed{common.MustTimestampFromString("2000-01-01T00:00:00Z").MustTime()}},
+
+        // Graders and above are not subject to dates.
+        {"3000-01-01T00:00:00Z", "", "grader@test.com", nil},
+        {"", "2000-01-01T00:00:00Z", "admin@test.com", nil},
+    };
+
+    oldDockerVal := config.DOCKER_DISABLE.Get();
+    config.DOCKER_DISABLE.Set(true);
+    defer config.DOCKER_DISABLE.Set(oldDockerVal);
+
+    for _, testCase := range testCases {
+        db.ResetForTesting();
+
+        assignment := db.MustGetTestAssignment();
+        assignment.SubmissionLimit = &model.SubmissionLimitInfo{};
+        assignment.Invocation = []string{"sh", "-c", TEST_REGRADE_GRADER, "<outpath>"};
+        assignment.OpenDate = common.Timestamp(testCase.openDate);
+        assignment.CloseDate = common.Timestamp(testCase.closeDate);
+
+        submitForRejection(test, assignment, testCase.user, testCase.expected);
+    }
+
+    db.ResetForTesting();
+}
+
+func TestRejectSubmissionExtension(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    oldDockerVal := config.DOCKER_DISABLE.Get();
+    config.DOCKER_DISABLE.Set(true);
+    defer config.DOCKER_DISABLE.Set(oldDockerVal);
+
+    assignment := db.MustGetTestAssignment();
+    assignment.Invocation = []string{"sh", "-c", TEST_REGRADE_GRADER, "<outpath>"};
+    assignment.CloseDate = "2000-01-01T00:00:00Z";
+
+    // Student has three existing submissions.
+    maxValue := 3;
+    assignment.SubmissionLimit = &model.SubmissionLimitInfo{Max: &maxValue};
+
+    submitForRejection(test, assignment, "student@test.com", &RejectClosed{assignment.CloseDate.MustTime()});
+
+    err := db.SaveExtension(assignment, &model.Extension{User: "student@test.com", DueDate: "3000-01-01T00:00:00Z", ExtraAttempts: 1});
+    if (err != nil) {
+        test.Fatalf("Failed to save extension: '%v'.", err);
+    }
+
+    submitForRejection(test, assignment, "student@test.com", nil);
+    submitForRejection(test, assignment, "student@test.com", &RejectMaxAttempts{4});
+}
+
+// A submission made before the close date (but graded after it, e.g., after waiting in the queue) is not rejected.
+func TestRejectSubmissionTime(test *testing.T) {
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    oldDockerVal := config.DOCKER_DISABLE.Get();
+    config.DOCKER_DISABLE.Set(true);
+    defer config.DOCKER_DISABLE.Set(oldDockerVal);
+
+    config.TESTING_MODE.Set(false);
+    defer config.TESTING_MODE.Set(true);
+
+    closeDate := time.Now().Add(-1 * time.Minute);
+
+    assignment := db.MustGetTestAssignment();
+    assignment.SubmissionLimit = &model.SubmissionLimitInfo{};
+    assignment.Invocation = []string{"sh", "-c", TEST_REGRADE_GRADER, "<outpath>"};
+    assignment.CloseDate = common.TimestampFromTime(closeDate);
+
+    err := assignment.SubmissionLimit.Validate();
+    if (err != nil) {
+        test.Fatalf("Failed to validate submission limit: '%v'.", err);
+    }
+
+    submissionPath := filepath.Join(assignment.GetSourceDir(), SUBMISSION_RELPATH);
+
+    testCases := []struct{ submissionTime time.Time; expected RejectReason }{
+        {closeDate.Add(-1 * time.Minute), nil},
+        {closeDate.Add(time.Minute), &RejectClosed{assignment.CloseDate.MustTime()}},
+    };
+
+    for i, testCase := range testCases {
+        options := GradeOptions{NoDocker: true, SubmissionTime: common.TimestampFromTime(testCase.submissionTime)};
+        _, reject, err := Grade(assignment, submissionPath, "student@test.com", TEST_MESSAGE, true, options);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to grade: '%v'.", i, err);
+            continue;
+        }
+
+        if (!reflect.DeepEqual(testCase.expected, reject)) {
+            test.Errorf("Case %d: Unexpected rejection. Expected: '%+v', Actual: '%+v'.", i, testCase.expected, reject);
+        }
+    }
+}
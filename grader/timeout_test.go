@@ -0,0 +1,76 @@
+package grader
+
+import (
+    "errors"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+)
+
+func TestNoDockerTimeout(test *testing.T) {
+    user := "timeout_" + BASE_TEST_USER;
+
+    db.ResetForTesting();
+    defer db.ResetForTesting();
+
+    oldDockerVal := config.DOCKER_DISABLE.Get();
+    config.DOCKER_DISABLE.Set(true);
+    defer config.DOCKER_DISABLE.Set(oldDockerVal);
+
+    assignment := db.MustGetTestAssignment();
+    assignment.Invocation = []string{"sh", "-c", "echo 'partial output'; sleep 30"};
+    assignment.TimeoutSecs = 1;
+
+    submissionPath := filepath.Join(config.GetCourseImportDir(), "_tests", "COURSE101", "HW0", "test-submissions", "solution");
+
+    startTime := time.Now();
+    result, reject, err := Grade(assignment, submissionPath, user, TEST_MESSAGE, false, GradeOptions{NoDocker: true});
+    duration := time.Since(startTime);
+
+    if (reject != nil) {
+        test.Fatalf("Submission was rejected: '%s'.", reject.String());
+    }
+
+    if (!errors.Is(err, ErrGradingTimeout)) {
+        test.Fatalf("Did not get a timeout error, got: '%v'.", err);
+    }
+
+    if (duration > (10 * time.Second)) {
+        test.Fatalf("Grading was not stopped in time, took %s.", duration);
+    }
+
+    if ((result == nil) || (!result.TimedOut)) {
+        test.Fatalf("Result is not marked as timed out: '%v'.", result);
+    }
+
+    if (!strings.Contains(result.Stdout, "partial output")) {
+        test.Fatalf("Partial output was not kept, found: '%s'.", result.Stdout);
+    }
+
+    history, err := db.GetSubmissionHistory(assignment, user);
+    if (err != nil) {
+        test.Fatalf("Failed to get submission history: '%v'.", err);
+    }
+
+    // The timed out submission is saved (with no score).
+    if (len(history) != 1) {
+        test.Fatalf("Unexpected number of submissions. Expected: 1, Actual: %d.", len(history));
+    }
+
+    if (!history[0].TimedOut || (history[0].Score != 0) || (history[0].ShortID != result.Info.ShortID)) {
+        test.Fatalf("Unexpected timed out submission: '%+v'.", history[0]);
+    }
+
+    contents, err := db.GetSubmissionContents(assignment, user, history[0].ShortID);
+    if (err != nil) {
+        test.Fatalf("Failed to get submission contents: '%v'.", err);
+    }
+
+    if ((contents == nil) || (contents.Info == nil) || !contents.Info.TimedOut) {
+        test.Fatalf("Timed out submission was not saved: '%v'.", contents);
+    }
+}
@@ -2,8 +2,8 @@ package canvas
 
 import (
     "fmt"
+    "time"
 
-    "github.com/edulinq/autograder/common"
     "github.com/edulinq/autograder/lms/lmstypes"
     "github.com/edulinq/autograder/util"
 )
@@ -17,8 +17,7 @@ func (this *CanvasBackend) FetchAssignment(assignmentID string) (*lmstypes.Assig
         this.CourseID, assignmentID);
     url := this.BaseURL + apiEndpoint;
 
-    headers := this.standardHeaders();
-    body, _, err := common.GetWithHeaders(url, headers);
+    body, _, err := this.get(url);
 
     if (err != nil) {
         return nil, fmt.Errorf("Failed to fetch assignment: '%w'.", err);
@@ -46,8 +45,6 @@ func (this *CanvasBackend) fetchAssignments(rewriteLinks bool) ([]*lmstypes.Assi
         this.CourseID, PAGE_SIZE);
     url := this.BaseURL + apiEndpoint;
 
-    headers := this.standardHeaders();
-
     assignments := make([]*lmstypes.Assignment, 0);
 
     for (url != "") {
@@ -60,7 +57,7 @@ func (this *CanvasBackend) fetchAssignments(rewriteLinks bool) ([]*lmstypes.Assi
             }
         }
 
-        body, responseHeaders, err := common.GetWithHeaders(url, headers);
+        body, responseHeaders, err := this.get(url);
         if (err != nil) {
             return nil, fmt.Errorf("Failed to fetch users: '%w'.", err);
         }
@@ -84,3 +81,72 @@ func (this *CanvasBackend) fetchAssignments(rewriteLinks bool) ([]*lmstypes.Assi
 
     return assignments, nil;
 }
+
+func (this *CanvasBackend) CreateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+    this.getAPILock();
+    defer this.releaseAPILock();
+
+    apiEndpoint := fmt.Sprintf(
+        "/api/v1/courses/%s/assignments",
+        this.CourseID);
+    url := this.BaseURL + apiEndpoint;
+
+    body, _, err := this.post(url, assignmentForm(assignment));
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to create assignment: '%w'.", err);
+    }
+
+    var newAssignment Assignment;
+    err = util.JSONFromString(body, &newAssignment);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to unmarshal created assignment: '%w'.", err);
+    }
+
+    return newAssignment.ToLMSType(), nil;
+}
+
+func (this *CanvasBackend) UpdateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+    this.getAPILock();
+    defer this.releaseAPILock();
+
+    if (assignment.ID == "") {
+        return nil, fmt.Errorf("Cannot update an assignment without an ID.");
+    }
+
+    apiEndpoint := fmt.Sprintf(
+        "/api/v1/courses/%s/assignments/%s",
+        this.CourseID, assignment.ID);
+    url := this.BaseURL + apiEndpoint;
+
+    body, _, err := this.put(url, assignmentForm(assignment));
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to update assignment '%s': '%w'.", assignment.ID, err);
+    }
+
+    var newAssignment Assignment;
+    err = util.JSONFromString(body, &newAssignment);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to unmarshal updated assignment: '%w'.", err);
+    }
+
+    return newAssignment.ToLMSType(), nil;
+}
+
+// Only non-empty fields are sent, so Canvas will leave the others alone.
+func assignmentForm(assignment *lmstypes.Assignment) map[string]string {
+    form := make(map[string]string);
+
+    if (assignment.Name != "") {
+        form["assignment[name]"] = assignment.Name;
+    }
+
+    if ((assignment.DueDate != nil) && !assignment.DueDate.IsZero()) {
+        form["assignment[due_at]"] = assignment.DueDate.Format(time.RFC3339);
+    }
+
+    if (!util.IsZero(assignment.MaxPoints)) {
+        form["assignment[points_possible]"] = util.FloatToStr(assignment.MaxPoints);
+    }
+
+    return form;
+}
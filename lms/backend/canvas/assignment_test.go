@@ -1,6 +1,9 @@
 package canvas
 
 import (
+    "net/http"
+    "net/http/httptest"
+    "reflect"
     "testing"
 
     "github.com/edulinq/autograder/lms/lmstypes"
@@ -48,3 +51,81 @@ func TestFetchAssignmentsBase(test *testing.T) {
                 expectedJSON, actualJSON);
     }
 }
+
+func TestAssignmentForm(test *testing.T) {
+    dueDate := mustParseTime("2024-01-15T23:59:00Z");
+
+    testCases := []struct{assignment *lmstypes.Assignment; expected map[string]string}{
+        {
+            &lmstypes.Assignment{},
+            map[string]string{},
+        },
+        {
+            &lmstypes.Assignment{ID: "1", Name: "HW0", DueDate: dueDate, MaxPoints: 10.5},
+            map[string]string{
+                "assignment[name]": "HW0",
+                "assignment[due_at]": "2024-01-15T23:59:00Z",
+                "assignment[points_possible]": "10.5",
+            },
+        },
+    };
+
+    for i, testCase := range testCases {
+        form := assignmentForm(testCase.assignment);
+        if (!reflect.DeepEqual(testCase.expected, form)) {
+            test.Errorf("Case %d: Form not as expected. Expected: '%v', Actual: '%v'.", i, testCase.expected, form);
+            continue;
+        }
+    }
+}
+
+func TestCreateUpdateAssignment(test *testing.T) {
+    // Echo back the submitted assignment (with an ID).
+    var requests []string;
+    echoServer := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+        request.ParseForm();
+        requests = append(requests, request.Method + " " + request.URL.Path);
+
+        assignment := Assignment{
+            ID: "55",
+            Name: request.PostForm.Get("assignment[name]"),
+            CanvasCourseID: TEST_COURSE_ID,
+        };
+
+        response.Write([]byte(util.MustToJSON(assignment)));
+    }));
+    defer echoServer.Close();
+
+    backend, err := NewBackend(TEST_COURSE_ID, TEST_TOKEN, echoServer.URL);
+    if (err != nil) {
+        test.Fatalf("Failed to create backend: '%v'.", err);
+    }
+
+    created, err := backend.CreateAssignment(&lmstypes.Assignment{Name: "HW0"});
+    if (err != nil) {
+        test.Fatalf("Failed to create assignment: '%v'.", err);
+    }
+
+    if ((created.ID != "55") || (created.Name != "HW0")) {
+        test.Fatalf("Created assignment not as expected: '%+v'.", created);
+    }
+
+    _, err = backend.UpdateAssignment(&lmstypes.Assignment{ID: "55", Name: "HW0"});
+    if (err != nil) {
+        test.Fatalf("Failed to update assignment: '%v'.", err);
+    }
+
+    _, err = backend.UpdateAssignment(&lmstypes.Assignment{Name: "HW0"});
+    if (err == nil) {
+        test.Fatalf("Updating an assignment without an ID did not fail.");
+    }
+
+    expected := []string{
+        "POST /api/v1/courses/12345/assignments",
+        "PUT /api/v1/courses/12345/assignments/55",
+    };
+
+    if (!reflect.DeepEqual(expected, requests)) {
+        test.Fatalf("Requests not as expected. Expected: '%v', Actual: '%v'.", expected, requests);
+    }
+}
@@ -1,14 +1,19 @@
 package canvas
 
 import (
+    "errors"
     "fmt"
     "time"
 
-    "github.com/edulinq/autograder/common"
     "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/log"
 )
 
+// A failed comment does not stop the remaining comments from being updated.
+// The returned error (if any) has an error for each failed comment.
 func (this *CanvasBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
+    var errs error = nil;
+
     for i, comment := range comments {
         if (i != 0) {
             time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC));
@@ -16,11 +21,13 @@ func (this *CanvasBackend) UpdateComments(assignmentID string, comments []*lmsty
 
         err := this.UpdateComment(assignmentID, comment);
         if (err != nil) {
-            return fmt.Errorf("Failed on comment %d: '%w'.", i, err);
+            log.Warn("Failed to update comment.", log.NewAttr("assignment-id", assignmentID),
+                    log.NewAttr("comment-id", comment.ID), err);
+            errs = errors.Join(errs, fmt.Errorf("Failed on comment %d (%s): '%w'.", i, comment.ID, err));
         }
     }
 
-    return nil;
+    return errs;
 }
 
 func (this *CanvasBackend) UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error {
@@ -35,8 +42,7 @@ func (this *CanvasBackend) UpdateComment(assignmentID string, comment *lmstypes.
     form := make(map[string]string, 1);
     form["comment"] = comment.Text;
 
-    headers := this.standardHeaders();
-    _, _, err := common.PutWithHeaders(url, form, headers);
+    _, _, err := this.put(url, form);
 
     if (err != nil) {
         return fmt.Errorf("Failed to update comments: '%w'.", err);
@@ -1,20 +1,42 @@
 package canvas
 
 import (
+    "errors"
     "fmt"
+    "math/rand"
+    "net/http"
     neturl "net/url"
+    "strconv"
     "strings"
     "sync"
     "time"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/log"
 )
 
 const (
     PAGE_SIZE int = 75
     POST_PAGE_SIZE int = 75;
     HEADER_LINK string = "Link";
+    HEADER_RATE_LIMIT_REMAINING string = "X-Rate-Limit-Remaining";
+    HEADER_RETRY_AFTER string = "Retry-After";
     UPLOAD_SLEEP_TIME_SEC = int64(0.5 * float64(time.Second));
+
+    MAX_RETRIES int = 5;
+
+    // Canvas gives each token a bucket of 700 "units" that slowly refills.
+    // Once fewer than this many remain, start slowing down.
+    RATE_LIMIT_LOW_WATERMARK float64 = 200.0;
 )
 
+// Timing for retries and throttling (variables so that tests can speed them up).
+// Retries back off exponentially from the base delay (with up to the same amount of jitter), up to the max delay.
+// Throttling sleeps up to the max throttle delay, scaled by how far below the low watermark the remaining rate limit is.
+var retryBaseDelay time.Duration = 1 * time.Second;
+var retryMaxDelay time.Duration = 60 * time.Second;
+var throttleMaxDelay time.Duration = 5 * time.Second;
+
 // Lock for each API token being used.
 // Note that it is possible to have multiple backends with the same token.
 // {string: *sync.Mutex}.
@@ -43,6 +65,138 @@ func (this *CanvasBackend) standardHeaders() map[string][]string {
     };
 }
 
+func (this *CanvasBackend) get(url string) (string, map[string][]string, error) {
+    return this.request(http.MethodGet, url, nil);
+}
+
+func (this *CanvasBackend) post(url string, form map[string]string) (string, map[string][]string, error) {
+    return this.request(http.MethodPost, url, form);
+}
+
+func (this *CanvasBackend) put(url string, form map[string]string) (string, map[string][]string, error) {
+    return this.request(http.MethodPut, url, form);
+}
+
+// Make a request to Canvas (the caller should hold the API lock).
+// Requests that were rate limited are always retried.
+// Requests that hit a server/network error are only retried if they are idempotent (POSTs may add duplicate comments).
+// After each successful request, slow down if the rate limit is running low.
+func (this *CanvasBackend) request(verb string, url string, form map[string]string) (string, map[string][]string, error) {
+    headers := this.standardHeaders();
+
+    for attempt := 0; ; attempt++ {
+        var body string;
+        var responseHeaders map[string][]string;
+        var err error;
+
+        switch (verb) {
+            case http.MethodGet:
+                body, responseHeaders, err = common.GetWithHeaders(url, headers);
+            case http.MethodPost:
+                body, responseHeaders, err = common.PostWithHeaders(url, form, headers);
+            case http.MethodPut:
+                body, responseHeaders, err = common.PutWithHeaders(url, form, headers);
+            default:
+                return "", nil, fmt.Errorf("Unsupported Canvas request method '%s'.", verb);
+        }
+
+        if (err == nil) {
+            throttle(responseHeaders);
+            return body, responseHeaders, nil;
+        }
+
+        retry, delay := checkRetry(verb, err, attempt);
+        if (!retry || (attempt >= MAX_RETRIES)) {
+            return "", nil, err;
+        }
+
+        log.Warn("Canvas request failed, retrying.",
+                log.NewAttr("method", verb), log.NewAttr("url", url),
+                log.NewAttr("attempt", attempt + 1), log.NewAttr("delay", delay.String()), err);
+
+        time.Sleep(delay);
+    }
+}
+
+// Check if a failed request should be retried, and how long to wait before doing so.
+func checkRetry(verb string, err error, attempt int) (bool, time.Duration) {
+    delay := min(retryMaxDelay, retryBaseDelay * time.Duration(1 << attempt));
+    if (retryBaseDelay > 0) {
+        delay += time.Duration(rand.Int63n(int64(retryBaseDelay)));
+    }
+
+    var statusErr *common.HTTPStatusError;
+    if (!errors.As(err, &statusErr)) {
+        // Could not reach Canvas.
+        return (verb != http.MethodPost), delay;
+    }
+
+    if (isRateLimited(statusErr)) {
+        retryAfter := parseRetryAfter(statusErr.Headers);
+        if (retryAfter > delay) {
+            delay = min(retryMaxDelay, retryAfter);
+        }
+
+        return true, delay;
+    }
+
+    if (statusErr.Code >= http.StatusInternalServerError) {
+        return (verb != http.MethodPost), delay;
+    }
+
+    return false, 0;
+}
+
+// Canvas signals throttling with a 403 (and a specific message), but a 429 is also respected.
+func isRateLimited(statusErr *common.HTTPStatusError) bool {
+    if (statusErr.Code == http.StatusTooManyRequests) {
+        return true;
+    }
+
+    return ((statusErr.Code == http.StatusForbidden) && strings.Contains(statusErr.Body, "Rate Limit Exceeded"));
+}
+
+// Returns zero if there is no (valid) Retry-After header.
+func parseRetryAfter(headers map[string][]string) time.Duration {
+    value := http.Header(headers).Get(HEADER_RETRY_AFTER);
+    if (value == "") {
+        return 0;
+    }
+
+    seconds, err := strconv.ParseFloat(value, 64);
+    if ((err != nil) || (seconds < 0)) {
+        return 0;
+    }
+
+    return time.Duration(seconds * float64(time.Second));
+}
+
+// Sleep if the remaining rate limit is below the low watermark.
+func throttle(headers map[string][]string) {
+    delay := getThrottleDelay(headers);
+    if (delay <= 0) {
+        return;
+    }
+
+    log.Debug("Canvas rate limit is running low, throttling.", log.NewAttr("delay", delay.String()));
+    time.Sleep(delay);
+}
+
+func getThrottleDelay(headers map[string][]string) time.Duration {
+    value := http.Header(headers).Get(HEADER_RATE_LIMIT_REMAINING);
+    if (value == "") {
+        return 0;
+    }
+
+    remaining, err := strconv.ParseFloat(value, 64);
+    if ((err != nil) || (remaining >= RATE_LIMIT_LOW_WATERMARK)) {
+        return 0;
+    }
+
+    remaining = max(0.0, remaining);
+    return time.Duration(float64(throttleMaxDelay) * (1.0 - (remaining / RATE_LIMIT_LOW_WATERMARK)));
+}
+
 // See if the response headers have a next link.
 // Returns the link or an empty string.
 func fetchNextCanvasLink(headers map[string][]string) string {
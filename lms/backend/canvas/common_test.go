@@ -0,0 +1,195 @@
+package canvas
+
+import (
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/edulinq/autograder/lms/lmstypes"
+)
+
+// A handler that responds with a sequence of statuses (then OK), and counts the requests per path.
+type flakyHandler struct {
+    statuses []int
+    headers map[string]string
+    body string
+
+    lock sync.Mutex
+    counts map[string]int
+}
+
+func (this *flakyHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+    this.lock.Lock();
+    count := this.counts[request.URL.Path];
+    this.counts[request.URL.Path]++;
+    this.lock.Unlock();
+
+    for key, value := range this.headers {
+        response.Header().Set(key, value);
+    }
+
+    if (count < len(this.statuses)) {
+        response.WriteHeader(this.statuses[count]);
+        response.Write([]byte(this.body));
+        return;
+    }
+
+    response.Write([]byte("{}"));
+}
+
+func runFlakyServer(test *testing.T, handler *flakyHandler) *CanvasBackend {
+    handler.counts = make(map[string]int);
+
+    flakyServer := httptest.NewServer(handler);
+    test.Cleanup(flakyServer.Close);
+
+    oldBaseDelay := retryBaseDelay;
+    oldThrottleDelay := throttleMaxDelay;
+    retryBaseDelay = time.Millisecond;
+    throttleMaxDelay = time.Millisecond;
+    test.Cleanup(func() {
+        retryBaseDelay = oldBaseDelay;
+        throttleMaxDelay = oldThrottleDelay;
+    });
+
+    backend, err := NewBackend(TEST_COURSE_ID, "flaky-token", flakyServer.URL);
+    if (err != nil) {
+        test.Fatalf("Failed to create backend: '%v'.", err);
+    }
+
+    return backend;
+}
+
+func TestCanvasRequestRetry(test *testing.T) {
+    testCases := []struct{
+        statuses []int
+        body string
+        isPost bool
+        expectedCount int
+        expectError bool
+    }{
+        {nil, "", false, 1, false},
+        {[]int{http.StatusTooManyRequests}, "", false, 2, false},
+        {[]int{http.StatusForbidden}, "403 Forbidden (Rate Limit Exceeded)", false, 2, false},
+        {[]int{http.StatusServiceUnavailable, http.StatusBadGateway}, "", false, 3, false},
+
+        // Rate limits are retried for posts, but server errors are not.
+        {[]int{http.StatusTooManyRequests}, "", true, 2, false},
+        {[]int{http.StatusServiceUnavailable}, "", true, 1, true},
+
+        // Other errors are not retried.
+        {[]int{http.StatusForbidden}, "Unauthorized", false, 1, true},
+        {[]int{http.StatusNotFound}, "", false, 1, true},
+
+        // Retries are bounded.
+        {[]int{500, 500, 500, 500, 500, 500, 500}, "", false, MAX_RETRIES + 1, true},
+    };
+
+    for i, testCase := range testCases {
+        handler := &flakyHandler{statuses: testCase.statuses, body: testCase.body};
+        backend := runFlakyServer(test, handler);
+
+        var err error;
+        if (testCase.isPost) {
+            err = backend.updateAssignmentScores(TEST_ASSIGNMENT_ID, []*lmstypes.SubmissionScore{&lmstypes.SubmissionScore{UserID: "1"}});
+        } else {
+            _, err = backend.FetchAssignment(TEST_ASSIGNMENT_ID);
+        }
+
+        if (testCase.expectError && (err == nil)) {
+            test.Errorf("Case %d: Did not get an expected error.", i);
+            continue;
+        }
+
+        if (!testCase.expectError && (err != nil)) {
+            test.Errorf("Case %d: Got an unexpected error: '%v'.", i, err);
+            continue;
+        }
+
+        count := 0;
+        for _, pathCount := range handler.counts {
+            count += pathCount;
+        }
+
+        if (testCase.expectedCount != count) {
+            test.Errorf("Case %d: Unexpected number of requests. Expected: %d, Actual: %d.", i, testCase.expectedCount, count);
+            continue;
+        }
+    }
+}
+
+func TestCanvasPartialScoreUpload(test *testing.T) {
+    // The first page will always fail.
+    statuses := make([]int, MAX_RETRIES + 1);
+    for i := range statuses {
+        statuses[i] = http.StatusTooManyRequests;
+    }
+
+    handler := &flakyHandler{statuses: statuses};
+    backend := runFlakyServer(test, handler);
+
+    scores := make([]*lmstypes.SubmissionScore, 0, POST_PAGE_SIZE + 1);
+    for i := 0; i < (POST_PAGE_SIZE + 1); i++ {
+        scores = append(scores, &lmstypes.SubmissionScore{UserID: fmt.Sprintf("%d", i)});
+    }
+
+    err := backend.UpdateAssignmentScores(TEST_ASSIGNMENT_ID, scores);
+    if (err == nil) {
+        test.Fatalf("Did not get an error for a failed page.");
+    }
+
+    // All the retries for the first page, and one request for the second page.
+    path := fmt.Sprintf("/api/v1/courses/%s/assignments/%s/submissions/update_grades", TEST_COURSE_ID, TEST_ASSIGNMENT_ID);
+    expectedCount := MAX_RETRIES + 2;
+    if (expectedCount != handler.counts[path]) {
+        test.Fatalf("Unexpected number of requests. Expected: %d, Actual: %d.", expectedCount, handler.counts[path]);
+    }
+}
+
+func TestCanvasGetThrottleDelay(test *testing.T) {
+    testCases := []struct{value string; expected time.Duration}{
+        {"", 0},
+        {"ZZZ", 0},
+        {"700", 0},
+        {fmt.Sprintf("%f", RATE_LIMIT_LOW_WATERMARK), 0},
+        {fmt.Sprintf("%f", RATE_LIMIT_LOW_WATERMARK / 2.0), throttleMaxDelay / 2},
+        {"0", throttleMaxDelay},
+        {"-10", throttleMaxDelay},
+    };
+
+    for i, testCase := range testCases {
+        headers := make(map[string][]string);
+        if (testCase.value != "") {
+            headers[HEADER_RATE_LIMIT_REMAINING] = []string{testCase.value};
+        }
+
+        delay := getThrottleDelay(headers);
+        if (testCase.expected != delay) {
+            test.Errorf("Case %d: Unexpected delay. Expected: '%s', Actual: '%s'.", i, testCase.expected, delay);
+            continue;
+        }
+    }
+}
+
+func TestCanvasParseRetryAfter(test *testing.T) {
+    testCases := []struct{value string; expected time.Duration}{
+        {"", 0},
+        {"ZZZ", 0},
+        {"-1", 0},
+        {"3", 3 * time.Second},
+        {"0.5", 500 * time.Millisecond},
+    };
+
+    for i, testCase := range testCases {
+        headers := map[string][]string{HEADER_RETRY_AFTER: []string{testCase.value}};
+
+        delay := parseRetryAfter(headers);
+        if (testCase.expected != delay) {
+            test.Errorf("Case %d: Unexpected delay. Expected: '%s', Actual: '%s'.", i, testCase.expected, delay);
+            continue;
+        }
+    }
+}
@@ -1,6 +1,7 @@
 package canvas
 
 import (
+    "slices"
     "time"
 
     "github.com/edulinq/autograder/lms/lmstypes"
@@ -41,6 +42,12 @@ type Enrollment struct {
     Type string `json:"type"`
     EnrollmentState string `json:"enrollment_state"`
     Role string `json:"role"`
+    SectionID string `json:"course_section_id"`
+}
+
+type Section struct {
+    ID string `json:"id"`
+    Name string `json:"name"`
 }
 
 // Canvas enrollment to autograder role.
@@ -88,12 +95,34 @@ func (this *User) GetRole() model.UserRole {
     return maxRole;
 }
 
-func (this *User) ToLMSType() *lmstypes.User {
+// Get the (sorted) names of the user's sections.
+// Sections missing from |sectionNames| (section ID to name) will use their ID.
+func (this *User) GetSections(sectionNames map[string]string) []string {
+    sections := make([]string, 0, len(this.Enrollments));
+    for _, enrollment := range this.Enrollments {
+        if (enrollment.SectionID == "") {
+            continue;
+        }
+
+        name, ok := sectionNames[enrollment.SectionID];
+        if (!ok) {
+            name = enrollment.SectionID;
+        }
+
+        sections = append(sections, name);
+    }
+
+    slices.Sort(sections);
+    return slices.Compact(sections);
+}
+
+func (this *User) ToLMSType(sectionNames map[string]string) *lmstypes.User {
     return &lmstypes.User{
         ID: this.ID,
         Name: this.Name,
         Email: this.Email,
         Role: this.GetRole(),
+        Sections: this.GetSections(sectionNames),
     };
 }
 
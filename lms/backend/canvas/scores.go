@@ -1,11 +1,13 @@
 package canvas
 
 import (
+    "errors"
     "fmt"
+    "strings"
     "time"
 
-    "github.com/edulinq/autograder/common"
     "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/log"
     "github.com/edulinq/autograder/util"
 )
 
@@ -18,8 +20,7 @@ func (this *CanvasBackend) FetchAssignmentScore(assignmentID string, userID stri
         this.CourseID, assignmentID, userID);
     url := this.BaseURL + apiEndpoint;
 
-    headers := this.standardHeaders();
-    body, _, err := common.GetWithHeaders(url, headers);
+    body, _, err := this.get(url);
     if (err != nil) {
         return nil, fmt.Errorf("Failed to fetch score.");
     }
@@ -46,8 +47,6 @@ func (this *CanvasBackend) fetchAssignmentScores(assignmentID string, rewriteLin
         this.CourseID, assignmentID, PAGE_SIZE);
     url := this.BaseURL + apiEndpoint;
 
-    headers := this.standardHeaders();
-
     scores := make([]*lmstypes.SubmissionScore, 0);
 
     for (url != "") {
@@ -60,7 +59,7 @@ func (this *CanvasBackend) fetchAssignmentScores(assignmentID string, rewriteLin
             }
         }
 
-        body, responseHeaders, err := common.GetWithHeaders(url, headers);
+        body, responseHeaders, err := this.get(url);
 
         if (err != nil) {
             return nil, fmt.Errorf("Failed to fetch scores.");
@@ -86,7 +85,12 @@ func (this *CanvasBackend) fetchAssignmentScores(assignmentID string, rewriteLin
     return scores, nil;
 }
 
+// A failed page does not stop the remaining pages from being uploaded.
+// Since uploading a score again just overwrites it, failed pages can be resumed by uploading again.
+// The returned error (if any) has an error for each failed page.
 func (this *CanvasBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+    var errs error = nil;
+
     for page := 0; (page * POST_PAGE_SIZE) < len(scores); page++ {
         startIndex := page * POST_PAGE_SIZE;
         endIndex := min(len(scores), ((page + 1) * POST_PAGE_SIZE));
@@ -97,11 +101,18 @@ func (this *CanvasBackend) UpdateAssignmentScores(assignmentID string, scores []
 
         err := this.updateAssignmentScores(assignmentID, scores[startIndex:endIndex]);
         if (err != nil) {
-            return fmt.Errorf("Failed on page %d: '%w'.", page, err);
+            userIDs := make([]string, 0, endIndex - startIndex);
+            for _, score := range scores[startIndex:endIndex] {
+                userIDs = append(userIDs, score.UserID);
+            }
+
+            log.Warn("Failed to upload page of scores.", log.NewAttr("assignment-id", assignmentID),
+                    log.NewAttr("page", page), log.NewAttr("user-ids", userIDs), err);
+            errs = errors.Join(errs, fmt.Errorf("Failed on page %d (users: %s): '%w'.", page, strings.Join(userIDs, ", "), err));
         }
     }
 
-    return nil;
+    return errs;
 }
 
 func (this *CanvasBackend) updateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
@@ -117,8 +128,6 @@ func (this *CanvasBackend) updateAssignmentScores(assignmentID string, scores []
         this.CourseID, assignmentID);
     url := this.BaseURL + apiEndpoint;
 
-    headers := this.standardHeaders();
-
     form := make(map[string]string);
 
     for _, score := range scores {
@@ -133,7 +142,7 @@ func (this *CanvasBackend) updateAssignmentScores(assignmentID string, scores []
         }
     }
 
-    _, _, err := common.PostWithHeaders(url, form, headers);
+    _, _, err := this.post(url, form);
     if (err != nil) {
         return fmt.Errorf("Failed to upload scores: '%w'.", err);
     }
@@ -4,7 +4,6 @@ import (
     "fmt"
     neturl "net/url"
 
-    "github.com/edulinq/autograder/common"
     "github.com/edulinq/autograder/lms/lmstypes"
     "github.com/edulinq/autograder/log"
     "github.com/edulinq/autograder/util"
@@ -23,13 +22,14 @@ func (this *CanvasBackend) fetchUsers(rewriteLinks bool) ([]*lmstypes.User, erro
         this.CourseID, PAGE_SIZE);
     url := this.BaseURL + apiEndpoint;
 
-    headers := this.standardHeaders();
+    sectionNames, err := this.fetchSectionNames();
+    if (err != nil) {
+        return nil, err;
+    }
 
     users := make([]*lmstypes.User, 0);
 
     for (url != "") {
-        var err error;
-
         if (rewriteLinks) {
             url, err = this.rewriteLink(url);
             if (err != nil) {
@@ -37,7 +37,7 @@ func (this *CanvasBackend) fetchUsers(rewriteLinks bool) ([]*lmstypes.User, erro
             }
         }
 
-        body, responseHeaders, err := common.GetWithHeaders(url, headers);
+        body, responseHeaders, err := this.get(url);
         if (err != nil) {
             return nil, fmt.Errorf("Failed to fetch users: '%w'.", err);
         }
@@ -53,7 +53,7 @@ func (this *CanvasBackend) fetchUsers(rewriteLinks bool) ([]*lmstypes.User, erro
                 continue;
             }
 
-            users = append(users, user.ToLMSType());
+            users = append(users, user.ToLMSType(sectionNames));
         }
 
         url = fetchNextCanvasLink(responseHeaders);
@@ -71,8 +71,7 @@ func (this *CanvasBackend) FetchUser(email string) (*lmstypes.User, error) {
         this.CourseID, neturl.QueryEscape(email));
     url := this.BaseURL + apiEndpoint;
 
-    headers := this.standardHeaders();
-    body, _, err := common.GetWithHeaders(url, headers);
+    body, _, err := this.get(url);
     if (err != nil) {
         return nil, fmt.Errorf("Failed to fetch user '%s': '%w'.", email, err);
     }
@@ -89,5 +88,46 @@ func (this *CanvasBackend) FetchUser(email string) (*lmstypes.User, error) {
         return nil, nil;
     }
 
-    return pageUsers[0].ToLMSType(), nil;
+    sectionNames, err := this.fetchSectionNames();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return pageUsers[0].ToLMSType(sectionNames), nil;
+}
+
+// Get a mapping of section IDs to names.
+// The caller should hold the API lock.
+func (this *CanvasBackend) fetchSectionNames() (map[string]string, error) {
+    apiEndpoint := fmt.Sprintf(
+        "/api/v1/courses/%s/sections?per_page=%d",
+        this.CourseID, PAGE_SIZE);
+    url := this.BaseURL + apiEndpoint;
+
+    sectionNames := make(map[string]string);
+
+    for (url != "") {
+        body, responseHeaders, err := this.get(url);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to fetch sections: '%w'.", err);
+        }
+
+        var pageSections []*Section;
+        err = util.JSONFromString(body, &pageSections);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to unmarshal sections page: '%w'.", err);
+        }
+
+        for _, section := range pageSections {
+            if (section == nil) {
+                continue;
+            }
+
+            sectionNames[section.ID] = section.Name;
+        }
+
+        url = fetchNextCanvasLink(responseHeaders);
+    }
+
+    return sectionNames, nil;
 }
@@ -18,6 +18,7 @@ func TestCanvasUserGetBase(test *testing.T) {
                 Name: "owner",
                 Email: "owner@test.com",
                 Role: model.RoleOwner,
+                Sections: []string{"Course Staff"},
             },
         },
         {
@@ -27,6 +28,7 @@ func TestCanvasUserGetBase(test *testing.T) {
                 Name: "admin",
                 Email: "admin@test.com",
                 Role: model.RoleAdmin,
+                Sections: []string{"Lecture 01"},
             },
         },
         {
@@ -36,6 +38,7 @@ func TestCanvasUserGetBase(test *testing.T) {
                 Name: "student",
                 Email: "student@test.com",
                 Role: model.RoleStudent,
+                Sections: []string{"Lab 02", "Lecture 01"},
             },
         },
     };
@@ -47,7 +50,7 @@ func TestCanvasUserGetBase(test *testing.T) {
             continue;
         }
 
-        if (*testCase.expected != *user) {
+        if (!reflect.DeepEqual(testCase.expected, user)) {
             test.Errorf("Case %d: User not as expected. Expected: '%+v', Actual: '%+v'.", i, testCase.expected, user);
             continue;
         }
@@ -61,18 +64,21 @@ func TestCanvasUsersGetBase(test *testing.T) {
             Name: "student",
             Email: "student@test.com",
             Role: model.RoleStudent,
+            Sections: []string{"Lab 02", "Lecture 01"},
         },
         &lmstypes.User{
             ID: "00020",
             Name: "admin",
             Email: "admin@test.com",
             Role: model.RoleAdmin,
+            Sections: []string{"Lecture 01"},
         },
         &lmstypes.User{
             ID: "00010",
             Name: "owner",
             Email: "owner@test.com",
             Role: model.RoleOwner,
+            Sections: []string{"Course Staff"},
         },
     };
 
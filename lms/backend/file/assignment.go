@@ -0,0 +1,87 @@
+package file
+
+import (
+    "fmt"
+    "strconv"
+    "time"
+
+    "github.com/edulinq/autograder/lms/lmstypes"
+)
+
+// Assignments have the keys: id, name, due-date (RFC 3339), and max-points.
+// Only the id is required.
+func (this *FileBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
+    records, err := readRecords(this.AssignmentsPath);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch assignments: '%w'.", err);
+    }
+
+    assignments := make([]*lmstypes.Assignment, 0, len(records));
+    for i, record := range records {
+        assignment, err := this.parseAssignment(record);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to parse assignment %d in '%s': '%w'.", i, this.AssignmentsPath, err);
+        }
+
+        assignments = append(assignments, assignment);
+    }
+
+    return assignments, nil;
+}
+
+func (this *FileBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
+    assignments, err := this.FetchAssignments();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch assignment '%s': '%w'.", assignmentID, err);
+    }
+
+    for _, assignment := range assignments {
+        if (assignment.ID == assignmentID) {
+            return assignment, nil;
+        }
+    }
+
+    return nil, fmt.Errorf("Could not find assignment '%s' in '%s'.", assignmentID, this.AssignmentsPath);
+}
+
+func (this *FileBackend) parseAssignment(record map[string]string) (*lmstypes.Assignment, error) {
+    if (record["id"] == "") {
+        return nil, fmt.Errorf("Assignment is missing an id.");
+    }
+
+    assignment := lmstypes.Assignment{
+        ID: record["id"],
+        Name: record["name"],
+        LMSCourseID: this.CourseID,
+    };
+
+    if (record["due-date"] != "") {
+        dueDate, err := time.Parse(time.RFC3339, record["due-date"]);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to parse due date '%s': '%w'.", record["due-date"], err);
+        }
+
+        assignment.DueDate = &dueDate;
+    }
+
+    if (record["max-points"] != "") {
+        maxPoints, err := strconv.ParseFloat(record["max-points"], 64);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to parse max points '%s': '%w'.", record["max-points"], err);
+        }
+
+        assignment.MaxPoints = maxPoints;
+    }
+
+    return &assignment, nil;
+}
+
+// The assignments file is a part of the course's source, which is replaced whenever the course is updated.
+// So assignments should be added/edited there instead.
+func (this *FileBackend) CreateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+    return nil, fmt.Errorf("The file LMS does not support creating assignments, add them to '%s' instead.", this.AssignmentsPath);
+}
+
+func (this *FileBackend) UpdateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+    return nil, fmt.Errorf("The file LMS does not support updating assignments, edit them in '%s' instead.", this.AssignmentsPath);
+}
@@ -0,0 +1,58 @@
+package file
+
+import (
+    "reflect"
+    "testing"
+
+    "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/util"
+)
+
+var expectedAssignments []*lmstypes.Assignment = []*lmstypes.Assignment{
+    &lmstypes.Assignment{
+        ID: "hw0",
+        Name: "Homework 0",
+        LMSCourseID: TEST_COURSE_ID,
+        DueDate: mustParseTime("2024-01-15T23:59:00Z"),
+        MaxPoints: 100.0,
+    },
+    &lmstypes.Assignment{
+        ID: "hw1",
+        Name: "Homework 1",
+        LMSCourseID: TEST_COURSE_ID,
+    },
+};
+
+func TestFileAssignmentsGetBase(test *testing.T) {
+    for _, extension := range []string{"csv", "json"} {
+        assignments, err := mustGetTestBackend(extension).FetchAssignments();
+        if (err != nil) {
+            test.Errorf("Case '%s': Failed to fetch assignments: '%v'.", extension, err);
+            continue;
+        }
+
+        if (!reflect.DeepEqual(expectedAssignments, assignments)) {
+            test.Errorf("Case '%s': Assignments not as expected. Expected: '%s', Actual: '%s'.",
+                    extension, util.MustToJSONIndent(expectedAssignments), util.MustToJSONIndent(assignments));
+            continue;
+        }
+    }
+}
+
+func TestFileAssignmentGetBase(test *testing.T) {
+    backend := mustGetTestBackend("csv");
+
+    assignment, err := backend.FetchAssignment("hw0");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(expectedAssignments[0], assignment)) {
+        test.Fatalf("Assignment not as expected. Expected: '%+v', Actual: '%+v'.", expectedAssignments[0], assignment);
+    }
+
+    _, err = backend.FetchAssignment("zzz");
+    if (err == nil) {
+        test.Fatalf("Fetching a missing assignment did not fail.");
+    }
+}
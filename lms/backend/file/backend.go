@@ -0,0 +1,43 @@
+// An LMS backend that is backed by plain files instead of an LMS server.
+// Users and assignments are read from CSV or JSON files (chosen by extension) that live with the course,
+// and all score and comment uploads are appended to a gradebook file (JSON Lines).
+// The gradebook is never rewritten, so it also serves as a history of everything the autograder has uploaded.
+package file
+
+import (
+    "fmt"
+)
+
+type FileBackend struct {
+    CourseID string
+    UsersPath string
+    AssignmentsPath string
+    GradebookPath string
+}
+
+func NewBackend(courseID string, usersPath string, assignmentsPath string, gradebookPath string) (*FileBackend, error) {
+    if (courseID == "") {
+        return nil, fmt.Errorf("File LMS course ID cannot be empty.");
+    }
+
+    if (usersPath == "") {
+        return nil, fmt.Errorf("File LMS users path (users-path) cannot be empty.");
+    }
+
+    if (assignmentsPath == "") {
+        return nil, fmt.Errorf("File LMS assignments path (assignments-path) cannot be empty.");
+    }
+
+    if (gradebookPath == "") {
+        return nil, fmt.Errorf("File LMS gradebook path (gradebook-path) cannot be empty.");
+    }
+
+    backend := FileBackend{
+        CourseID: courseID,
+        UsersPath: usersPath,
+        AssignmentsPath: assignmentsPath,
+        GradebookPath: gradebookPath,
+    };
+
+    return &backend, nil;
+}
@@ -0,0 +1,54 @@
+package file
+
+import (
+    "fmt"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/lms/lmstypes"
+)
+
+func (this *FileBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
+    this.lockGradebook();
+    defer this.unlockGradebook();
+
+    entries, err := this.readEntries(assignmentID);
+    if (err != nil) {
+        return fmt.Errorf("Failed to read gradebook to update comments: '%w'.", err);
+    }
+
+    _, commentUsers, err := replayEntries(entries);
+    if (err != nil) {
+        return fmt.Errorf("Failed to replay gradebook to update comments: '%w'.", err);
+    }
+
+    now := common.NowTimestamp();
+    newEntries := make([]*gradebookEntry, 0, len(comments));
+
+    for i, comment := range comments {
+        userID, ok := commentUsers[comment.ID];
+        if (!ok) {
+            return fmt.Errorf("Failed on comment %d: Could not find comment '%s' for assignment '%s'.", i, comment.ID, assignmentID);
+        }
+
+        newEntries = append(newEntries, &gradebookEntry{
+            Type: ENTRY_TYPE_COMMENT,
+            Time: now,
+            AssignmentID: assignmentID,
+            UserID: userID,
+            CommentID: comment.ID,
+            Author: comment.Author,
+            Text: comment.Text,
+        });
+    }
+
+    err = this.appendEntries(newEntries);
+    if (err != nil) {
+        return fmt.Errorf("Failed to update comments: '%w'.", err);
+    }
+
+    return nil;
+}
+
+func (this *FileBackend) UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error {
+    return this.UpdateComments(assignmentID, []*lmstypes.SubmissionComment{comment});
+}
@@ -0,0 +1,72 @@
+package file
+
+import (
+    "encoding/csv"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/edulinq/autograder/util"
+)
+
+const LIST_SEPARATOR = ";"
+
+// Read a file of records (maps of column/key to value).
+// Files ending in ".json" must contain a list of objects with string values,
+// all other files are read as CSV with a header row.
+func readRecords(path string) ([]map[string]string, error) {
+    if (!util.PathExists(path)) {
+        return nil, fmt.Errorf("File LMS data file does not exist: '%s'.", path);
+    }
+
+    if (strings.ToLower(filepath.Ext(path)) == ".json") {
+        var records []map[string]string;
+        err := util.JSONFromFile(path, &records);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read JSON records from '%s': '%w'.", path, err);
+        }
+
+        return records, nil;
+    }
+
+    return readCSVRecords(path);
+}
+
+func readCSVRecords(path string) ([]map[string]string, error) {
+    file, err := os.Open(path);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to open CSV file '%s': '%w'.", path, err);
+    }
+    defer file.Close();
+
+    reader := csv.NewReader(file);
+    reader.TrimLeadingSpace = true;
+    reader.Comment = '#';
+
+    rows, err := reader.ReadAll();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to parse CSV file '%s': '%w'.", path, err);
+    }
+
+    if (len(rows) == 0) {
+        return nil, fmt.Errorf("CSV file '%s' is missing a header row.", path);
+    }
+
+    header := rows[0];
+    for i, column := range header {
+        header[i] = strings.ToLower(strings.TrimSpace(column));
+    }
+
+    records := make([]map[string]string, 0, len(rows) - 1);
+    for _, row := range rows[1:] {
+        record := make(map[string]string, len(header));
+        for i, column := range header {
+            record[column] = strings.TrimSpace(row[i]);
+        }
+
+        records = append(records, record);
+    }
+
+    return records, nil;
+}
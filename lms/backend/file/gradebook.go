@@ -0,0 +1,192 @@
+package file
+
+import (
+    "bufio"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "sync"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/util"
+)
+
+const (
+    ENTRY_TYPE_SCORE = "score"
+    ENTRY_TYPE_COMMENT = "comment"
+
+    COMMENT_AUTHOR = "autograder"
+)
+
+// A single line in the gradebook.
+// Score entries set a user's score (keeping any existing comments).
+// Comment entries add a comment to a user's score, or replace the text of an existing comment (with the same ID).
+type gradebookEntry struct {
+    Type string `json:"type"`
+    Time common.Timestamp `json:"time"`
+    AssignmentID string `json:"assignment-id"`
+    UserID string `json:"user-id"`
+
+    Score float64 `json:"score,omitempty"`
+    SubmissionTime common.Timestamp `json:"submission-time,omitempty"`
+
+    CommentID string `json:"comment-id,omitempty"`
+    Author string `json:"author,omitempty"`
+    Text string `json:"text,omitempty"`
+}
+
+// Locks are per gradebook path.
+var gradebookLocks sync.Map;
+
+func (this *FileBackend) lockGradebook() {
+    lock, _ := gradebookLocks.LoadOrStore(this.GradebookPath, &sync.Mutex{});
+    lock.(*sync.Mutex).Lock();
+}
+
+func (this *FileBackend) unlockGradebook() {
+    lock, _ := gradebookLocks.LoadOrStore(this.GradebookPath, &sync.Mutex{});
+    lock.(*sync.Mutex).Unlock();
+}
+
+// Read all the gradebook entries for an assignment (in the order they were written).
+// The caller should hold the gradebook lock.
+func (this *FileBackend) readEntries(assignmentID string) ([]*gradebookEntry, error) {
+    entries := make([]*gradebookEntry, 0);
+
+    if (!util.PathExists(this.GradebookPath)) {
+        return entries, nil;
+    }
+
+    file, err := os.Open(this.GradebookPath);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to open gradebook '%s': '%w'.", this.GradebookPath, err);
+    }
+    defer file.Close();
+
+    scanner := bufio.NewScanner(file);
+    scanner.Buffer(make([]byte, 0, 64 * 1024), 16 * 1024 * 1024);
+
+    lineNumber := 0;
+    for scanner.Scan() {
+        lineNumber++;
+
+        line := strings.TrimSpace(scanner.Text());
+        if (line == "") {
+            continue;
+        }
+
+        var entry gradebookEntry;
+        err = util.JSONFromString(line, &entry);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to parse line %d of gradebook '%s': '%w'.", lineNumber, this.GradebookPath, err);
+        }
+
+        if (entry.AssignmentID != assignmentID) {
+            continue;
+        }
+
+        entries = append(entries, &entry);
+    }
+
+    err = scanner.Err();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to read gradebook '%s': '%w'.", this.GradebookPath, err);
+    }
+
+    return entries, nil;
+}
+
+// Append entries to the gradebook.
+// The caller should hold the gradebook lock.
+func (this *FileBackend) appendEntries(entries []*gradebookEntry) error {
+    if (len(entries) == 0) {
+        return nil;
+    }
+
+    var builder strings.Builder;
+    for _, entry := range entries {
+        text, err := util.ToJSON(entry);
+        if (err != nil) {
+            return fmt.Errorf("Failed to serialize gradebook entry: '%w'.", err);
+        }
+
+        builder.WriteString(text);
+        builder.WriteString("\n");
+    }
+
+    err := os.MkdirAll(filepath.Dir(this.GradebookPath), 0755);
+    if (err != nil) {
+        return fmt.Errorf("Failed to create dir for gradebook '%s': '%w'.", this.GradebookPath, err);
+    }
+
+    file, err := os.OpenFile(this.GradebookPath, os.O_APPEND | os.O_CREATE | os.O_WRONLY, 0644);
+    if (err != nil) {
+        return fmt.Errorf("Failed to open gradebook '%s' for writing: '%w'.", this.GradebookPath, err);
+    }
+    defer file.Close();
+
+    _, err = file.WriteString(builder.String());
+    if (err != nil) {
+        return fmt.Errorf("Failed to write to gradebook '%s': '%w'.", this.GradebookPath, err);
+    }
+
+    return nil;
+}
+
+// Replay the gradebook entries for an assignment into the current scores (in the order users were first scored).
+// Also returns the user each comment (by ID) belongs to.
+func replayEntries(entries []*gradebookEntry) ([]*lmstypes.SubmissionScore, map[string]string, error) {
+    scores := make([]*lmstypes.SubmissionScore, 0);
+    userScores := make(map[string]*lmstypes.SubmissionScore);
+    comments := make(map[string]*lmstypes.SubmissionComment);
+    commentUsers := make(map[string]string);
+
+    for i, entry := range entries {
+        score := userScores[entry.UserID];
+        if (score == nil) {
+            score = &lmstypes.SubmissionScore{
+                UserID: entry.UserID,
+                Comments: make([]*lmstypes.SubmissionComment, 0),
+            };
+
+            userScores[entry.UserID] = score;
+            scores = append(scores, score);
+        }
+
+        switch (entry.Type) {
+            case ENTRY_TYPE_SCORE:
+                score.Score = entry.Score;
+
+                submissionTime, err := entry.SubmissionTime.Time();
+                if (err != nil) {
+                    return nil, nil, fmt.Errorf("Gradebook entry %d has a bad submission time: '%w'.", i, err);
+                }
+
+                score.Time = submissionTime;
+            case ENTRY_TYPE_COMMENT:
+                comment := comments[entry.CommentID];
+                if (comment != nil) {
+                    comment.Text = entry.Text;
+                    comment.Time = string(entry.Time);
+                    continue;
+                }
+
+                comment = &lmstypes.SubmissionComment{
+                    ID: entry.CommentID,
+                    Author: entry.Author,
+                    Text: entry.Text,
+                    Time: string(entry.Time),
+                };
+
+                comments[entry.CommentID] = comment;
+                commentUsers[entry.CommentID] = entry.UserID;
+                score.Comments = append(score.Comments, comment);
+            default:
+                return nil, nil, fmt.Errorf("Gradebook entry %d has an unknown type: '%s'.", i, entry.Type);
+        }
+    }
+
+    return scores, commentUsers, nil;
+}
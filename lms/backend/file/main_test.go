@@ -0,0 +1,52 @@
+package file
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "github.com/edulinq/autograder/util"
+)
+
+const TEST_COURSE_ID = "course101"
+
+var testdataDir string = util.ShouldAbs("testdata");
+
+func TestMain(suite *testing.M) {
+    // Run inside a func so defers will run before os.Exit().
+    code := func() int {
+        defer util.RemoveRecordedTempDirs();
+        return suite.Run();
+    }();
+
+    os.Exit(code);
+}
+
+// Get a backend using the given data file extension ("csv" or "json") and a new (empty) gradebook.
+func mustGetTestBackend(extension string) *FileBackend {
+    tempDir, err := util.MkDirTemp("test-file-lms-");
+    if (err != nil) {
+        panic(fmt.Sprintf("Failed to make temp dir: '%v'.", err));
+    }
+
+    backend, err := NewBackend(TEST_COURSE_ID,
+            filepath.Join(testdataDir, "users." + extension),
+            filepath.Join(testdataDir, "assignments." + extension),
+            filepath.Join(tempDir, "gradebook.jsonl"));
+    if (err != nil) {
+        panic(fmt.Sprintf("Failed to create backend: '%v'.", err));
+    }
+
+    return backend;
+}
+
+func mustParseTime(text string) *time.Time {
+    instance, err := time.Parse(time.RFC3339, text);
+    if (err != nil) {
+        panic(fmt.Sprintf("Failed to parse time '%s': '%v'.", text, err));
+    }
+
+    return &instance;
+}
@@ -0,0 +1,91 @@
+package file
+
+import (
+    "fmt"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/util"
+)
+
+func (this *FileBackend) FetchAssignmentScores(assignmentID string) ([]*lmstypes.SubmissionScore, error) {
+    this.lockGradebook();
+    defer this.unlockGradebook();
+
+    entries, err := this.readEntries(assignmentID);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch scores for assignment '%s': '%w'.", assignmentID, err);
+    }
+
+    scores, _, err := replayEntries(entries);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to replay gradebook for assignment '%s': '%w'.", assignmentID, err);
+    }
+
+    return scores, nil;
+}
+
+// Returns nil if the user has no score.
+func (this *FileBackend) FetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+    scores, err := this.FetchAssignmentScores(assignmentID);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    for _, score := range scores {
+        if (score.UserID == userID) {
+            return score, nil;
+        }
+    }
+
+    return nil, nil;
+}
+
+// Comments without an ID are added (and given an ID), comments with an ID replace the existing comment.
+func (this *FileBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+    this.lockGradebook();
+    defer this.unlockGradebook();
+
+    now := common.NowTimestamp();
+    entries := make([]*gradebookEntry, 0, len(scores));
+
+    for _, score := range scores {
+        entries = append(entries, &gradebookEntry{
+            Type: ENTRY_TYPE_SCORE,
+            Time: now,
+            AssignmentID: assignmentID,
+            UserID: score.UserID,
+            Score: score.Score,
+            SubmissionTime: common.TimestampFromTime(score.Time),
+        });
+
+        for _, comment := range score.Comments {
+            entry := &gradebookEntry{
+                Type: ENTRY_TYPE_COMMENT,
+                Time: now,
+                AssignmentID: assignmentID,
+                UserID: score.UserID,
+                CommentID: comment.ID,
+                Author: comment.Author,
+                Text: comment.Text,
+            };
+
+            if (entry.CommentID == "") {
+                entry.CommentID = util.UUID();
+            }
+
+            if (entry.Author == "") {
+                entry.Author = COMMENT_AUTHOR;
+            }
+
+            entries = append(entries, entry);
+        }
+    }
+
+    err := this.appendEntries(entries);
+    if (err != nil) {
+        return fmt.Errorf("Failed to update scores for assignment '%s': '%w'.", assignmentID, err);
+    }
+
+    return nil;
+}
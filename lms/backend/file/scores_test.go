@@ -0,0 +1,121 @@
+package file
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/util"
+)
+
+func TestFileScoresEmptyGradebook(test *testing.T) {
+    backend := mustGetTestBackend("csv");
+
+    scores, err := backend.FetchAssignmentScores("hw0");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch scores: '%v'.", err);
+    }
+
+    if (len(scores) != 0) {
+        test.Fatalf("Found scores in an empty gradebook: '%s'.", util.MustToJSONIndent(scores));
+    }
+
+    score, err := backend.FetchAssignmentScore("hw0", "1");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch score: '%v'.", err);
+    }
+
+    if (score != nil) {
+        test.Fatalf("Found a score in an empty gradebook: '%+v'.", score);
+    }
+}
+
+func TestFileScoresUpdateAndComments(test *testing.T) {
+    backend := mustGetTestBackend("csv");
+
+    scores := []*lmstypes.SubmissionScore{
+        &lmstypes.SubmissionScore{
+            UserID: "1",
+            Score: 10,
+            Time: *mustParseTime("2024-01-10T10:00:00Z"),
+            Comments: []*lmstypes.SubmissionComment{
+                &lmstypes.SubmissionComment{Text: "first"},
+            },
+        },
+        &lmstypes.SubmissionScore{
+            UserID: "3",
+            Score: 20,
+            Time: *mustParseTime("2024-01-11T10:00:00Z"),
+        },
+    };
+
+    err := backend.UpdateAssignmentScores("hw0", scores);
+    if (err != nil) {
+        test.Fatalf("Failed to update scores: '%v'.", err);
+    }
+
+    // A different assignment should not be affected.
+    err = backend.UpdateAssignmentScores("hw1", []*lmstypes.SubmissionScore{&lmstypes.SubmissionScore{UserID: "1", Score: 99}});
+    if (err != nil) {
+        test.Fatalf("Failed to update other scores: '%v'.", err);
+    }
+
+    score, err := backend.FetchAssignmentScore("hw0", "1");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch score: '%v'.", err);
+    }
+
+    if ((score == nil) || (score.Score != 10) || (!score.Time.Equal(scores[0].Time)) || (len(score.Comments) != 1)) {
+        test.Fatalf("Score not as expected: '%s'.", util.MustToJSONIndent(score));
+    }
+
+    comment := score.Comments[0];
+    if ((comment.ID == "") || (comment.Author != COMMENT_AUTHOR) || (comment.Text != "first")) {
+        test.Fatalf("Comment not as expected: '%+v'.", comment);
+    }
+
+    // Update the comment and the score (which should keep the comment).
+    comment.Text = "second";
+    err = backend.UpdateComment("hw0", comment);
+    if (err != nil) {
+        test.Fatalf("Failed to update comment: '%v'.", err);
+    }
+
+    err = backend.UpdateAssignmentScores("hw0", []*lmstypes.SubmissionScore{&lmstypes.SubmissionScore{UserID: "1", Score: 15}});
+    if (err != nil) {
+        test.Fatalf("Failed to update score: '%v'.", err);
+    }
+
+    scores, err = backend.FetchAssignmentScores("hw0");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch scores: '%v'.", err);
+    }
+
+    if ((len(scores) != 2) || (scores[0].UserID != "1") || (scores[1].UserID != "3")) {
+        test.Fatalf("Scores not as expected: '%s'.", util.MustToJSONIndent(scores));
+    }
+
+    if ((scores[0].Score != 15) || (len(scores[0].Comments) != 1) || (scores[0].Comments[0].ID != comment.ID) || (scores[0].Comments[0].Text != "second")) {
+        test.Fatalf("Updated score not as expected: '%s'.", util.MustToJSONIndent(scores[0]));
+    }
+
+    // The gradebook is append-only.
+    text, err := util.ReadFile(backend.GradebookPath);
+    if (err != nil) {
+        test.Fatalf("Failed to read gradebook: '%v'.", err);
+    }
+
+    lines := strings.Split(strings.TrimSpace(text), "\n");
+    if (len(lines) != 6) {
+        test.Fatalf("Unexpected number of gradebook lines. Expected: 6, Actual: %d.", len(lines));
+    }
+}
+
+func TestFileUpdateMissingComment(test *testing.T) {
+    backend := mustGetTestBackend("csv");
+
+    err := backend.UpdateComment("hw0", &lmstypes.SubmissionComment{ID: "zzz", Text: "zzz"});
+    if (err == nil) {
+        test.Fatalf("Updating a missing comment did not fail.");
+    }
+}
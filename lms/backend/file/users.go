@@ -0,0 +1,93 @@
+package file
+
+import (
+    "fmt"
+    "slices"
+    "strings"
+
+    "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+)
+
+// Users have the keys: id, email, name, role, sections, and groups.
+// Only the email is required, the id will default to the email and the role to "student".
+// Sections and groups are separated by semicolons,
+// and are only synced if their key is present (so an empty value means the user is in none).
+func (this *FileBackend) FetchUsers() ([]*lmstypes.User, error) {
+    records, err := readRecords(this.UsersPath);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch users: '%w'.", err);
+    }
+
+    users := make([]*lmstypes.User, 0, len(records));
+    for i, record := range records {
+        email := record["email"];
+        if (email == "") {
+            return nil, fmt.Errorf("User %d in '%s' is missing an email.", i, this.UsersPath);
+        }
+
+        id := record["id"];
+        if (id == "") {
+            id = email;
+        }
+
+        var role model.UserRole = model.RoleStudent;
+        if (record["role"] != "") {
+            role = model.GetRole(record["role"]);
+            if (role == model.RoleUnknown) {
+                return nil, fmt.Errorf("User %d in '%s' has an unknown role: '%s'.", i, this.UsersPath, record["role"]);
+            }
+        }
+
+        users = append(users, &lmstypes.User{
+            ID: id,
+            Name: record["name"],
+            Email: email,
+            Role: role,
+            Sections: getList(record, "sections"),
+            Groups: getList(record, "groups"),
+        });
+    }
+
+    return users, nil;
+}
+
+func (this *FileBackend) FetchUser(email string) (*lmstypes.User, error) {
+    users, err := this.FetchUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch user '%s': '%w'.", email, err);
+    }
+
+    for _, user := range users {
+        if (strings.EqualFold(user.Email, email)) {
+            return user, nil;
+        }
+    }
+
+    log.Info("Did not find a matching user in the LMS users file.", log.NewAttr("email", email));
+    return nil, nil;
+}
+
+// Get a semicolon-separated list from a record.
+// Returns nil if the key is missing, and an empty (non-nil) list if the value is empty.
+func getList(record map[string]string, key string) []string {
+    text, ok := record[key];
+    if (!ok) {
+        return nil;
+    }
+
+    values := make([]string, 0);
+
+    for _, value := range strings.Split(text, LIST_SEPARATOR) {
+        value = strings.TrimSpace(value);
+        if (value == "") {
+            continue;
+        }
+
+        values = append(values, value);
+    }
+
+    slices.Sort(values);
+    return values;
+}
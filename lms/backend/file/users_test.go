@@ -0,0 +1,117 @@
+package file
+
+import (
+    "reflect"
+    "testing"
+
+    "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+// The CSV file has every column, so empty sections/groups are empty lists.
+var expectedCSVUsers []*lmstypes.User = []*lmstypes.User{
+    &lmstypes.User{
+        ID: "1",
+        Name: "owner",
+        Email: "owner@test.com",
+        Role: model.RoleOwner,
+        Sections: []string{},
+        Groups: []string{},
+    },
+    &lmstypes.User{
+        ID: "student@test.com",
+        Name: "student",
+        Email: "student@test.com",
+        Role: model.RoleStudent,
+        Sections: []string{"Lab 02", "Lecture 01"},
+        Groups: []string{"Team A"},
+    },
+    &lmstypes.User{
+        ID: "3",
+        Name: "grader",
+        Email: "grader@test.com",
+        Role: model.RoleGrader,
+        Sections: []string{"Lab 02"},
+        Groups: []string{},
+    },
+};
+
+// The JSON file leaves out keys, so missing sections/groups are nil.
+var expectedJSONUsers []*lmstypes.User = []*lmstypes.User{
+    &lmstypes.User{
+        ID: "1",
+        Name: "owner",
+        Email: "owner@test.com",
+        Role: model.RoleOwner,
+    },
+    &lmstypes.User{
+        ID: "student@test.com",
+        Name: "student",
+        Email: "student@test.com",
+        Role: model.RoleStudent,
+        Sections: []string{"Lab 02", "Lecture 01"},
+        Groups: []string{"Team A"},
+    },
+    &lmstypes.User{
+        ID: "3",
+        Name: "grader",
+        Email: "grader@test.com",
+        Role: model.RoleGrader,
+        Sections: []string{"Lab 02"},
+    },
+};
+
+func TestFileUsersGetBase(test *testing.T) {
+    testCases := map[string][]*lmstypes.User{
+        "csv": expectedCSVUsers,
+        "json": expectedJSONUsers,
+    };
+
+    for extension, expectedUsers := range testCases {
+        users, err := mustGetTestBackend(extension).FetchUsers();
+        if (err != nil) {
+            test.Errorf("Case '%s': Failed to fetch users: '%v'.", extension, err);
+            continue;
+        }
+
+        if (!reflect.DeepEqual(expectedUsers, users)) {
+            test.Errorf("Case '%s': Users not as expected. Expected: '%s', Actual: '%s'.",
+                    extension, util.MustToJSONIndent(expectedUsers), util.MustToJSONIndent(users));
+            continue;
+        }
+    }
+}
+
+func TestFileUserGetBase(test *testing.T) {
+    testCases := []struct{email string; expected *lmstypes.User}{
+        {"owner@test.com", expectedCSVUsers[0]},
+        {"STUDENT@test.com", expectedCSVUsers[1]},
+        {"ZZZ@test.com", nil},
+    };
+
+    backend := mustGetTestBackend("csv");
+
+    for i, testCase := range testCases {
+        user, err := backend.FetchUser(testCase.email);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to fetch user: '%v'.", i, err);
+            continue;
+        }
+
+        if (!reflect.DeepEqual(testCase.expected, user)) {
+            test.Errorf("Case %d: User not as expected. Expected: '%+v', Actual: '%+v'.", i, testCase.expected, user);
+            continue;
+        }
+    }
+}
+
+func TestFileUsersMissingFile(test *testing.T) {
+    backend := mustGetTestBackend("csv");
+    backend.UsersPath = backend.UsersPath + ".zzz";
+
+    _, err := backend.FetchUsers();
+    if (err == nil) {
+        test.Fatalf("Fetching users from a missing file did not fail.");
+    }
+}
@@ -0,0 +1,60 @@
+package moodle
+
+import (
+    "fmt"
+
+    "github.com/edulinq/autograder/lms/lmstypes"
+)
+
+func (this *MoodleBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
+    assignments, err := this.FetchAssignments();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    for _, assignment := range assignments {
+        if (assignment.ID == assignmentID) {
+            return assignment, nil;
+        }
+    }
+
+    return nil, fmt.Errorf("Could not find assignment '%s' in Moodle course '%s'.", assignmentID, this.CourseID);
+}
+
+func (this *MoodleBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
+    args := map[string]string{
+        "courseids[0]": this.CourseID,
+    };
+
+    var result coursesAssignments;
+    err := this.fetch("mod_assign_get_assignments", args, &result);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch assignments: '%w'.", err);
+    }
+
+    assignments := make([]*lmstypes.Assignment, 0);
+    for _, course := range result.Courses {
+        if (course == nil) {
+            continue;
+        }
+
+        for _, assignment := range course.Assignments {
+            if (assignment == nil) {
+                continue;
+            }
+
+            assignments = append(assignments, assignment.ToLMSType());
+        }
+    }
+
+    return assignments, nil;
+}
+
+// Moodle's web services cannot create (or edit) course modules.
+func (this *MoodleBackend) CreateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+    return nil, fmt.Errorf("Moodle does not support creating assignments through web services.");
+}
+
+func (this *MoodleBackend) UpdateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+    return nil, fmt.Errorf("Moodle does not support updating assignments through web services.");
+}
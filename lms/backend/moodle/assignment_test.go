@@ -0,0 +1,62 @@
+package moodle
+
+import (
+    "testing"
+
+    "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/util"
+)
+
+var expectedAssignment lmstypes.Assignment = lmstypes.Assignment{
+    ID: TEST_ASSIGNMENT_ID,
+    Name: "Assignment 0",
+    LMSCourseID: "12345",
+    DueDate: mustParseTime("2023-10-06T06:59:59Z"),
+    MaxPoints: 100.0,
+};
+
+// Has no due date and is graded on a scale.
+var expectedScaleAssignment lmstypes.Assignment = lmstypes.Assignment{
+    ID: "98766",
+    Name: "Assignment 1",
+    LMSCourseID: "12345",
+};
+
+func TestFetchAssignmentBase(test *testing.T) {
+    assignment, err := testBackend.FetchAssignment(TEST_ASSIGNMENT_ID);
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment: '%v'.", err);
+    }
+
+    // Can't compare directly because of time.Time.
+    // Use JSON instead.
+    expectedJSON := util.MustToJSONIndent(expectedAssignment);
+    actualJSON := util.MustToJSONIndent(assignment);
+
+    if (expectedJSON != actualJSON) {
+        test.Fatalf("Assignment not as expected. Expected: '%s', Actual: '%s'.",
+                expectedJSON, actualJSON);
+    }
+
+    _, err = testBackend.FetchAssignment("ZZZ");
+    if (err == nil) {
+        test.Fatalf("Fetching an unknown assignment did not fail.");
+    }
+}
+
+func TestFetchAssignmentsBase(test *testing.T) {
+    assignments, err := testBackend.FetchAssignments();
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignments: '%v'.", err);
+    }
+
+    // Can't compare directly because of time.Time.
+    // Use JSON instead.
+    expectedJSON := util.MustToJSONIndent([]*lmstypes.Assignment{&expectedAssignment, &expectedScaleAssignment});
+    actualJSON := util.MustToJSONIndent(assignments);
+
+    if (expectedJSON != actualJSON) {
+        test.Fatalf("Assignments not as expected. Expected: '%s', Actual: '%s'.",
+                expectedJSON, actualJSON);
+    }
+}
@@ -0,0 +1,39 @@
+// An LMS backend for Moodle's web services (REST protocol).
+// The web service user (whose token is used) needs the capabilities for all the functions used here,
+// e.g., the "moodle/grade:viewall" capability to fetch everyone's scores.
+package moodle
+
+import (
+    "fmt"
+    "strings"
+)
+
+type MoodleBackend struct {
+    CourseID string
+    APIToken string
+    BaseURL string
+}
+
+func NewBackend(moodleCourseID string, apiToken string, baseURL string) (*MoodleBackend, error) {
+    if (moodleCourseID == "") {
+        return nil, fmt.Errorf("Moodle course ID (course-id) cannot be empty.");
+    }
+
+    if (apiToken == "") {
+        return nil, fmt.Errorf("Moodle API token (api-token) cannot be empty.");
+    }
+
+    if (baseURL == "") {
+        return nil, fmt.Errorf("Moodle base URL (base-url) cannot be empty.");
+    }
+
+    baseURL = strings.TrimSuffix(baseURL, "/");
+
+    backend := MoodleBackend{
+        CourseID: moodleCourseID,
+        APIToken: apiToken,
+        BaseURL: baseURL,
+    };
+
+    return &backend, nil;
+}
@@ -0,0 +1,42 @@
+package moodle
+
+import (
+    "fmt"
+    "time"
+
+    "github.com/edulinq/autograder/lms/lmstypes"
+)
+
+func (this *MoodleBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
+    for i, comment := range comments {
+        if (i != 0) {
+            time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC));
+        }
+
+        err := this.UpdateComment(assignmentID, comment);
+        if (err != nil) {
+            return fmt.Errorf("Failed on comment %d: '%w'.", i, err);
+        }
+    }
+
+    return nil;
+}
+
+// Moodle's feedback comment can only be changed along with the grade,
+// so the user's current score is fetched and saved again with the new comment.
+// The comment's author is the user whose grade the comment is on.
+func (this *MoodleBackend) UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error {
+    score, err := this.FetchAssignmentScore(assignmentID, comment.Author);
+    if (err != nil) {
+        return fmt.Errorf("Failed to fetch score to update comment: '%w'.", err);
+    }
+
+    score.Comments = []*lmstypes.SubmissionComment{comment};
+
+    err = this.updateAssignmentScores(assignmentID, []*lmstypes.SubmissionScore{score});
+    if (err != nil) {
+        return fmt.Errorf("Failed to update comment: '%w'.", err);
+    }
+
+    return nil;
+}
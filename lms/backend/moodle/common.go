@@ -0,0 +1,102 @@
+package moodle
+
+import (
+    "fmt"
+    neturl "net/url"
+    "sync"
+    "time"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/util"
+)
+
+const (
+    REST_ENDPOINT string = "/webservice/rest/server.php";
+    POST_PAGE_SIZE int = 75;
+    UPLOAD_SLEEP_TIME_SEC = int64(0.5 * float64(time.Second));
+)
+
+// Moodle reports errors with a normal (200) response that holds an exception.
+type webServiceException struct {
+    Exception string `json:"exception"`
+    ErrorCode string `json:"errorcode"`
+    Message string `json:"message"`
+}
+
+// Lock for each API token being used.
+// Note that it is possible to have multiple backends with the same token.
+// {string: *sync.Mutex}.
+var apiLocks sync.Map;
+
+func (this *MoodleBackend) getAPILock() {
+    this.ensureAPILock();
+    lock, _ := apiLocks.Load(this.APIToken);
+    lock.(*sync.Mutex).Lock();
+}
+
+func (this *MoodleBackend) releaseAPILock() {
+    this.ensureAPILock();
+    lock, _ := apiLocks.Load(this.APIToken);
+    lock.(*sync.Mutex).Unlock();
+}
+
+func (this *MoodleBackend) ensureAPILock() {
+    apiLocks.LoadOrStore(this.APIToken, &sync.Mutex{});
+}
+
+// Call a web service function that only reads data, and unmarshal the result.
+// The arguments go in the URL (Moodle accepts arguments from both the URL and form),
+// so that requests can be told apart when recorded (see config.STORE_HTTP).
+// The token always goes in the form.
+func (this *MoodleBackend) fetch(function string, args map[string]string, result any) error {
+    query := neturl.Values{};
+    for key, value := range args {
+        query.Set(key, value);
+    }
+
+    // Get text as it was entered (e.g., comments with JSON), instead of as formatted HTML.
+    query.Set("moodlewssettingraw", "1");
+
+    return this.call(function, query, map[string]string{}, result);
+}
+
+// Call a web service function that changes data.
+// The arguments go in the form, since they may be too large for a URL.
+func (this *MoodleBackend) update(function string, args map[string]string) error {
+    return this.call(function, neturl.Values{}, args, nil);
+}
+
+// The result may be nil if the caller does not care about it.
+func (this *MoodleBackend) call(function string, query neturl.Values, form map[string]string, result any) error {
+    this.getAPILock();
+    defer this.releaseAPILock();
+
+    query.Set("wsfunction", function);
+    query.Set("moodlewsrestformat", "json");
+
+    form["wstoken"] = this.APIToken;
+
+    url := fmt.Sprintf("%s%s?%s", this.BaseURL, REST_ENDPOINT, query.Encode());
+
+    body, err := common.Post(url, form);
+    if (err != nil) {
+        return fmt.Errorf("Failed to call Moodle function '%s': '%w'.", function, err);
+    }
+
+    var exception webServiceException;
+    err = util.JSONFromString(body, &exception);
+    if ((err == nil) && (exception.Exception != "")) {
+        return fmt.Errorf("Moodle function '%s' failed ('%s'): '%s'.", function, exception.ErrorCode, exception.Message);
+    }
+
+    if (result == nil) {
+        return nil;
+    }
+
+    err = util.JSONFromString(body, result);
+    if (err != nil) {
+        return fmt.Errorf("Failed to unmarshal result of Moodle function '%s': '%w'.", function, err);
+    }
+
+    return nil;
+}
@@ -0,0 +1,194 @@
+package moodle
+
+import (
+    "embed"
+    "fmt"
+    "io/fs"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "os"
+    "strings"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/util"
+)
+
+const (
+    TEST_COURSE_ID = "12345"
+    TEST_ASSIGNMENT_ID = "98765"
+    TEST_TOKEN = "ABC123"
+)
+
+var server *httptest.Server;
+var serverURL string;
+
+//go:embed testdata/http
+var httpDataDir embed.FS;
+
+var testBackend *MoodleBackend;
+var testHandler *testMoodleHandler;
+
+func TestMain(suite *testing.M) {
+    var err error;
+
+    // Run inside a func so defers will run before os.Exit().
+    code := func() int {
+        db.PrepForTestingMain();
+        defer db.CleanupTestingMain();
+
+        err = startTestServer();
+        if (err != nil) {
+            panic(err);
+        }
+        defer stopTestServer();
+
+        testBackend, err = NewBackend(TEST_COURSE_ID, TEST_TOKEN, serverURL);
+        if (err != nil) {
+            panic(err);
+        }
+
+        return suite.Run();
+    }();
+
+    os.Exit(code);
+}
+
+func startTestServer() error {
+    if (server != nil) {
+        return fmt.Errorf("Test server already started.");
+    }
+
+    requests, err := loadRequests();
+    if (err != nil) {
+        return err;
+    }
+
+    testHandler = &testMoodleHandler{requests: requests};
+    server = httptest.NewServer(testHandler);
+    serverURL = server.URL;
+
+    return nil;
+}
+
+// Moodle requests are matched on the URL (which holds the arguments for fetches).
+// The forms of all requests (which hold the arguments for updates) are recorded.
+type testMoodleHandler struct {
+    requests map[string]*common.SavedHTTPRequest
+
+    lock sync.Mutex
+    forms []url.Values
+}
+
+func (this *testMoodleHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+    err := request.ParseForm();
+    if (err != nil) {
+        http.Error(response, fmt.Sprintf("Bad form: '%v'.", err), http.StatusBadRequest);
+        return;
+    }
+
+    if (request.PostForm.Get("wstoken") != TEST_TOKEN) {
+        response.Write([]byte(`{"exception": "moodle_exception", "errorcode": "invalidtoken", "message": "Invalid token - token not found"}`));
+        return;
+    }
+
+    this.lock.Lock();
+    this.forms = append(this.forms, request.PostForm);
+    this.lock.Unlock();
+
+    key := fmt.Sprintf("%s::%s?%s", request.Method, request.URL.Path, request.URL.RawQuery);
+    savedRequest := this.requests[key];
+    if (savedRequest == nil) {
+        fmt.Printf("ERROR 404: '%s'.\n", key);
+        http.NotFound(response, request);
+        return;
+    }
+
+    for key, value := range savedRequest.ResponseHeaders {
+        response.Header()[key] = value;
+    }
+
+    response.WriteHeader(savedRequest.ResponseCode);
+    _, err = response.Write([]byte(savedRequest.ResponseBody));
+    if (err != nil) {
+        panic(err);
+    }
+}
+
+// Get (and clear) the forms of all requests since the last call.
+func (this *testMoodleHandler) takeForms() []url.Values {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    forms := this.forms;
+    this.forms = nil;
+
+    return forms;
+}
+
+func loadRequests() (map[string]*common.SavedHTTPRequest, error) {
+    requests := make(map[string]*common.SavedHTTPRequest);
+
+    err := fs.WalkDir(httpDataDir, ".", func(path string, info fs.DirEntry, err error) error {
+        if (err != nil) {
+            return err;
+        }
+
+        if (info.IsDir()) {
+            return nil;
+        }
+
+        if (!strings.HasSuffix(info.Name(), ".json")) {
+            return nil;
+        }
+
+        data, err := httpDataDir.ReadFile(path);
+        if (err != nil) {
+            return fmt.Errorf("Failed to read embedded test file '%s': '%w'.", path, err);
+        }
+
+        var request common.SavedHTTPRequest;
+        err = util.JSONFromString(string(data), &request);
+        if (err != nil) {
+            return fmt.Errorf("Failed to JSON parse test file '%s': '%w'.", path, err);
+        }
+
+        uri, err := url.Parse(request.URL);
+        if (err != nil) {
+            return fmt.Errorf("Failed to parse test URL '%s': '%w'.", request.URL, err);
+        }
+
+        key := fmt.Sprintf("%s::%s?%s", request.Method, uri.Path, uri.RawQuery);
+        requests[key] = &request;
+
+        return nil;
+    });
+
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to walk embeded test dir: '%w'.", err);
+    }
+
+    return requests, nil;
+}
+
+func stopTestServer() {
+    if (server != nil) {
+        server.Close();
+
+        server = nil;
+        serverURL = "";
+    }
+}
+
+func mustParseTime(text string) *time.Time {
+    instance, err := time.Parse(time.RFC3339, text);
+    if (err != nil) {
+        panic(fmt.Sprintf("Failed to parse time '%s': '%v'.", text, err));
+    }
+
+    return &instance;
+}
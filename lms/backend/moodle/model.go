@@ -0,0 +1,182 @@
+package moodle
+
+import (
+    "fmt"
+    "slices"
+    "strings"
+    "time"
+
+    "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/model"
+)
+
+const (
+    GRADE_ITEM_TYPE_MOD = "mod";
+    GRADE_ITEM_MODULE_ASSIGN = "assign";
+
+    // The "assignfeedback_comments" plugin's editor.
+    FEEDBACK_COMMENTS_EDITOR = "assignfeedback_comments_editor";
+    // Moodle's FORMAT_PLAIN.
+    TEXT_FORMAT_PLAIN = 2;
+)
+
+type User struct {
+    ID int64 `json:"id"`
+    Name string `json:"fullname"`
+    Email string `json:"email"`
+    Roles []Role `json:"roles"`
+    Groups []Group `json:"groups"`
+}
+
+type Role struct {
+    ID int64 `json:"roleid"`
+    ShortName string `json:"shortname"`
+}
+
+type Group struct {
+    ID int64 `json:"id"`
+    Name string `json:"name"`
+}
+
+type coursesAssignments struct {
+    Courses []*courseAssignments `json:"courses"`
+}
+
+type courseAssignments struct {
+    ID int64 `json:"id"`
+    Assignments []*Assignment `json:"assignments"`
+}
+
+type Assignment struct {
+    ID int64 `json:"id"`
+    CourseID int64 `json:"course"`
+    Name string `json:"name"`
+    // Unix time (seconds), zero if there is no due date.
+    DueDate int64 `json:"duedate"`
+    // Negative for assignments graded on a scale (instead of with points).
+    MaxPoints float64 `json:"grade"`
+}
+
+type gradeReport struct {
+    UserGrades []*userGrades `json:"usergrades"`
+}
+
+type userGrades struct {
+    UserID int64 `json:"userid"`
+    GradeItems []*GradeItem `json:"gradeitems"`
+}
+
+// Moodle has one (feedback) comment per grade,
+// instead of a list of comments on a submission.
+type GradeItem struct {
+    ID int64 `json:"id"`
+    ItemType string `json:"itemtype"`
+    ItemModule string `json:"itemmodule"`
+    ItemInstance int64 `json:"iteminstance"`
+    // Nil if the user has not been graded.
+    Score *float64 `json:"graderaw"`
+    // Unix time (seconds).
+    SubmittedDate int64 `json:"gradedatesubmitted"`
+    GradedDate int64 `json:"gradedategraded"`
+    Feedback string `json:"feedback"`
+}
+
+// Moodle role (short name) to autograder role.
+// These are Moodle's default roles, custom roles are treated as "other".
+var roleMapping map[string]model.UserRole = map[string]model.UserRole{
+    "guest": model.RoleOther,
+    "student": model.RoleStudent,
+    "teacher": model.RoleGrader,
+    "manager": model.RoleAdmin,
+    "editingteacher": model.RoleOwner,
+};
+
+func (this *User) GetRole() model.UserRole {
+    var maxRole model.UserRole = model.RoleOther;
+    for _, moodleRole := range this.Roles {
+        role := roleMapping[moodleRole.ShortName];
+        if (role > maxRole) {
+            maxRole = role;
+        }
+    }
+
+    return maxRole;
+}
+
+// Moodle courses do not have sections (so they are left nil), only groups.
+func (this *User) ToLMSType() *lmstypes.User {
+    groups := make([]string, 0, len(this.Groups));
+    for _, group := range this.Groups {
+        groups = append(groups, group.Name);
+    }
+
+    slices.Sort(groups);
+
+    return &lmstypes.User{
+        ID: formatID(this.ID),
+        Name: this.Name,
+        Email: this.Email,
+        Role: this.GetRole(),
+        Groups: groups,
+    };
+}
+
+func (this *Assignment) ToLMSType() *lmstypes.Assignment {
+    var dueDate *time.Time = nil;
+    if (this.DueDate > 0) {
+        instance := time.Unix(this.DueDate, 0).UTC();
+        dueDate = &instance;
+    }
+
+    return &lmstypes.Assignment{
+        ID: formatID(this.ID),
+        Name: this.Name,
+        LMSCourseID: formatID(this.CourseID),
+        DueDate: dueDate,
+        MaxPoints: max(0.0, this.MaxPoints),
+    };
+}
+
+func (this *GradeItem) IsAssignment(assignmentID string) bool {
+    return ((this.ItemType == GRADE_ITEM_TYPE_MOD) && (this.ItemModule == GRADE_ITEM_MODULE_ASSIGN) &&
+            (formatID(this.ItemInstance) == assignmentID));
+}
+
+// The feedback comment is identified by the grade item (ID) and user (author).
+func (this *GradeItem) ToLMSType(userID int64) *lmstypes.SubmissionScore {
+    score := 0.0;
+    if (this.Score != nil) {
+        score = *this.Score;
+    }
+
+    submissionTime := time.Time{};
+    if (this.SubmittedDate > 0) {
+        submissionTime = time.Unix(this.SubmittedDate, 0).UTC();
+    }
+
+    comments := make([]*lmstypes.SubmissionComment, 0, 1);
+    if (strings.TrimSpace(this.Feedback) != "") {
+        commentTime := "";
+        if (this.GradedDate > 0) {
+            commentTime = time.Unix(this.GradedDate, 0).UTC().Format(time.RFC3339);
+        }
+
+        comments = append(comments, &lmstypes.SubmissionComment{
+            ID: formatID(this.ID),
+            Author: formatID(userID),
+            Text: this.Feedback,
+            Time: commentTime,
+        });
+    }
+
+    return &lmstypes.SubmissionScore{
+        UserID: formatID(userID),
+        Score: score,
+        Time: submissionTime,
+        Comments: comments,
+    };
+}
+
+func formatID(id int64) string {
+    return fmt.Sprintf("%d", id);
+}
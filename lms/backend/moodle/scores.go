@@ -0,0 +1,116 @@
+package moodle
+
+import (
+    "fmt"
+    "time"
+
+    "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/util"
+)
+
+func (this *MoodleBackend) FetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+    scores, err := this.fetchAssignmentScores(assignmentID, userID);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    if (len(scores) != 1) {
+        return nil, fmt.Errorf("Could not find score for user '%s' on assignment '%s'.", userID, assignmentID);
+    }
+
+    return scores[0], nil;
+}
+
+func (this *MoodleBackend) FetchAssignmentScores(assignmentID string) ([]*lmstypes.SubmissionScore, error) {
+    return this.fetchAssignmentScores(assignmentID, "");
+}
+
+// Scores (and feedback) come from the grader report.
+// An empty user ID fetches the scores for all users.
+func (this *MoodleBackend) fetchAssignmentScores(assignmentID string, userID string) ([]*lmstypes.SubmissionScore, error) {
+    args := map[string]string{
+        "courseid": this.CourseID,
+    };
+
+    if (userID != "") {
+        args["userid"] = userID;
+    }
+
+    var report gradeReport;
+    err := this.fetch("gradereport_user_get_grade_items", args, &report);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch scores: '%w'.", err);
+    }
+
+    scores := make([]*lmstypes.SubmissionScore, 0, len(report.UserGrades));
+    for _, grades := range report.UserGrades {
+        if (grades == nil) {
+            continue;
+        }
+
+        for _, item := range grades.GradeItems {
+            if ((item != nil) && item.IsAssignment(assignmentID)) {
+                scores = append(scores, item.ToLMSType(grades.UserID));
+                break;
+            }
+        }
+    }
+
+    return scores, nil;
+}
+
+func (this *MoodleBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+    for page := 0; (page * POST_PAGE_SIZE) < len(scores); page++ {
+        startIndex := page * POST_PAGE_SIZE;
+        endIndex := min(len(scores), ((page + 1) * POST_PAGE_SIZE));
+
+        if (page != 0) {
+            time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC));
+        }
+
+        err := this.updateAssignmentScores(assignmentID, scores[startIndex:endIndex]);
+        if (err != nil) {
+            return fmt.Errorf("Failed on page %d: '%w'.", page, err);
+        }
+    }
+
+    return nil;
+}
+
+func (this *MoodleBackend) updateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+    if (len(scores) > POST_PAGE_SIZE) {
+        return fmt.Errorf("Too many score upload requests at once. Found %d, max %d.", len(scores), POST_PAGE_SIZE);
+    }
+
+    form := map[string]string{
+        "assignmentid": assignmentID,
+        "applytoall": "0",
+    };
+
+    for i, score := range scores {
+        prefix := fmt.Sprintf("grades[%d]", i);
+
+        form[prefix + "[userid]"] = score.UserID;
+        form[prefix + "[grade]"] = util.FloatToStr(score.Score);
+        form[prefix + "[attemptnumber]"] = "-1";
+        form[prefix + "[addattempt]"] = "0";
+        form[prefix + "[workflowstate]"] = "";
+
+        if (len(score.Comments) > 1) {
+            return fmt.Errorf("Scores to upload can have at most one comment. Student '%s' for assignment '%s' has %d.", score.UserID, assignmentID, len(score.Comments));
+        }
+
+        // Setting the feedback replaces any existing feedback.
+        for _, comment := range score.Comments {
+            form[prefix + "[plugindata][" + FEEDBACK_COMMENTS_EDITOR + "][text]"] = comment.Text;
+            form[prefix + "[plugindata][" + FEEDBACK_COMMENTS_EDITOR + "][format]"] = fmt.Sprintf("%d", TEXT_FORMAT_PLAIN);
+        }
+    }
+
+    err := this.update("mod_assign_save_grades", form);
+    if (err != nil) {
+        return fmt.Errorf("Failed to upload scores: '%w'.", err);
+    }
+
+    return nil;
+}
@@ -0,0 +1,145 @@
+package moodle
+
+import (
+    "testing"
+    "time"
+
+    "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/util"
+)
+
+var testScore lmstypes.SubmissionScore = lmstypes.SubmissionScore{
+    UserID: "40",
+    Score: 100.0,
+    Time: *mustParseTime("2023-10-03T20:26:08Z"),
+    Comments: []*lmstypes.SubmissionComment{
+        &lmstypes.SubmissionComment{
+            ID: "7001",
+            Author: "40",
+            Text: "{\n\"id\": \"course101::hw0::student@test.com::1696364768\",\n\"submission-time\": \"2023-10-03T20:26:08.951546Z\",\n\"upload-time\": \"2023-10-07T13:04:54.412979316-05:00\",\n\"raw-score\": 100,\n\"score\": 100,\n\"lock\": false,\n\"late-date-usage\": 0,\n\"num-days-late\": 0,\n\"reject\": false,\n\"__autograder__v01__\": 0\n}",
+            Time: "2023-10-07T13:04:54Z",
+        },
+    },
+};
+
+// A user that has not been graded yet.
+var testEmptyScore lmstypes.SubmissionScore = lmstypes.SubmissionScore{
+    UserID: "10",
+    Score: 0.0,
+    Time: time.Time{},
+    Comments: []*lmstypes.SubmissionComment{},
+};
+
+func TestFetchAssignmentScoreBase(test *testing.T) {
+    score, err := testBackend.FetchAssignmentScore(TEST_ASSIGNMENT_ID, "40");
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment score: '%v'.", err);
+    }
+
+    // Can't compare directly because of time.Time.
+    // Use JSON instead.
+    expectedJSON := util.MustToJSONIndent(testScore);
+    actualJSON := util.MustToJSONIndent(score);
+
+    if (expectedJSON != actualJSON) {
+        test.Fatalf("Score not as expected. Expected: '%s', Actual: '%s'.",
+                expectedJSON, actualJSON);
+    }
+}
+
+func TestFetchAssignmentScoresBase(test *testing.T) {
+    scores, err := testBackend.FetchAssignmentScores(TEST_ASSIGNMENT_ID);
+    if (err != nil) {
+        test.Fatalf("Failed to fetch assignment scores: '%v'.", err);
+    }
+
+    expected := []*lmstypes.SubmissionScore{
+        &testScore,
+        &testEmptyScore,
+    };
+
+    // Can't compare directly because of time.Time.
+    // Use JSON instead.
+    expectedJSON := util.MustToJSONIndent(expected);
+    actualJSON := util.MustToJSONIndent(scores);
+
+    if (expectedJSON != actualJSON) {
+        test.Fatalf("Scores not as expected. Expected: '%s', Actual: '%s'.",
+                expectedJSON, actualJSON);
+    }
+}
+
+func TestUpdateAssignmentScoresBase(test *testing.T) {
+    testHandler.takeForms();
+
+    scores := []*lmstypes.SubmissionScore{
+        &lmstypes.SubmissionScore{
+            UserID: "40",
+            Score: 95.5,
+            Comments: []*lmstypes.SubmissionComment{&lmstypes.SubmissionComment{Text: "{}"}},
+        },
+        &lmstypes.SubmissionScore{
+            UserID: "10",
+            Score: 0,
+        },
+    };
+
+    err := testBackend.UpdateAssignmentScores(TEST_ASSIGNMENT_ID, scores);
+    if (err != nil) {
+        test.Fatalf("Failed to update scores: '%v'.", err);
+    }
+
+    forms := testHandler.takeForms();
+    if (len(forms) != 1) {
+        test.Fatalf("Unexpected number of requests. Expected: 1, Actual: %d.", len(forms));
+    }
+
+    expected := map[string]string{
+        "assignmentid": TEST_ASSIGNMENT_ID,
+        "grades[0][userid]": "40",
+        "grades[0][grade]": "95.5",
+        "grades[0][plugindata][assignfeedback_comments_editor][text]": "{}",
+        "grades[1][userid]": "10",
+        "grades[1][grade]": "0",
+        "grades[1][plugindata][assignfeedback_comments_editor][text]": "",
+    };
+
+    for key, value := range expected {
+        if (forms[0].Get(key) != value) {
+            test.Errorf("Unexpected value for '%s'. Expected: '%s', Actual: '%s'.", key, value, forms[0].Get(key));
+        }
+    }
+}
+
+func TestUpdateCommentBase(test *testing.T) {
+    testHandler.takeForms();
+
+    comment := &lmstypes.SubmissionComment{
+        ID: "7001",
+        Author: "40",
+        Text: "{}",
+    };
+
+    err := testBackend.UpdateComment(TEST_ASSIGNMENT_ID, comment);
+    if (err != nil) {
+        test.Fatalf("Failed to update comment: '%v'.", err);
+    }
+
+    // The score is fetched, and then saved with the new comment.
+    forms := testHandler.takeForms();
+    if (len(forms) != 2) {
+        test.Fatalf("Unexpected number of requests. Expected: 2, Actual: %d.", len(forms));
+    }
+
+    expected := map[string]string{
+        "grades[0][userid]": "40",
+        "grades[0][grade]": "100",
+        "grades[0][plugindata][assignfeedback_comments_editor][text]": "{}",
+    };
+
+    for key, value := range expected {
+        if (forms[1].Get(key) != value) {
+            test.Errorf("Unexpected value for '%s'. Expected: '%s', Actual: '%s'.", key, value, forms[1].Get(key));
+        }
+    }
+}
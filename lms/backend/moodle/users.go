@@ -0,0 +1,49 @@
+package moodle
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/log"
+)
+
+func (this *MoodleBackend) FetchUsers() ([]*lmstypes.User, error) {
+    args := map[string]string{
+        "courseid": this.CourseID,
+    };
+
+    var moodleUsers []*User;
+    err := this.fetch("core_enrol_get_enrolled_users", args, &moodleUsers);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch users: '%w'.", err);
+    }
+
+    users := make([]*lmstypes.User, 0, len(moodleUsers));
+    for _, user := range moodleUsers {
+        if (user == nil) {
+            continue;
+        }
+
+        users = append(users, user.ToLMSType());
+    }
+
+    return users, nil;
+}
+
+// Moodle cannot search the users in a course, so all the course's users are fetched.
+func (this *MoodleBackend) FetchUser(email string) (*lmstypes.User, error) {
+    users, err := this.FetchUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch user '%s': '%w'.", email, err);
+    }
+
+    for _, user := range users {
+        if (strings.EqualFold(user.Email, email)) {
+            return user, nil;
+        }
+    }
+
+    log.Info("Did not find a matching user in moodle.", log.NewAttr("email", email));
+    return nil, nil;
+}
@@ -0,0 +1,87 @@
+package moodle
+
+import (
+    "reflect"
+    "testing"
+
+    "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+var expectedUsers []*lmstypes.User = []*lmstypes.User{
+    &lmstypes.User{
+        ID: "40",
+        Name: "student",
+        Email: "student@test.com",
+        Role: model.RoleStudent,
+        Groups: []string{"Lab A", "Lab B"},
+    },
+    &lmstypes.User{
+        ID: "30",
+        Name: "grader",
+        Email: "grader@test.com",
+        Role: model.RoleGrader,
+        Groups: []string{},
+    },
+    &lmstypes.User{
+        ID: "20",
+        Name: "admin",
+        Email: "admin@test.com",
+        Role: model.RoleAdmin,
+        Groups: []string{},
+    },
+    &lmstypes.User{
+        ID: "10",
+        Name: "owner",
+        Email: "owner@test.com",
+        Role: model.RoleOwner,
+        Groups: []string{},
+    },
+};
+
+func TestMoodleUserGetBase(test *testing.T) {
+    testCases := []struct{email string; expected *lmstypes.User}{
+        {"owner@test.com", expectedUsers[3]},
+        {"admin@test.com", expectedUsers[2]},
+        {"STUDENT@test.com", expectedUsers[0]},
+        {"ZZZ@test.com", nil},
+    };
+
+    for i, testCase := range testCases {
+        user, err := testBackend.FetchUser(testCase.email);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to fetch user: '%v'.", i, err);
+            continue;
+        }
+
+        if (!reflect.DeepEqual(testCase.expected, user)) {
+            test.Errorf("Case %d: User not as expected. Expected: '%+v', Actual: '%+v'.", i, testCase.expected, user);
+            continue;
+        }
+    }
+}
+
+func TestMoodleUsersGetBase(test *testing.T) {
+    users, err := testBackend.FetchUsers();
+    if (err != nil) {
+        test.Fatalf("Failed to fetch users: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(expectedUsers, users)) {
+        test.Fatalf("Users not as expected. Expected: '%s', Actual: '%s'.",
+                util.MustToJSONIndent(expectedUsers), util.MustToJSONIndent(users));
+    }
+}
+
+func TestMoodleBadToken(test *testing.T) {
+    backend, err := NewBackend(TEST_COURSE_ID, "ZZZ", serverURL);
+    if (err != nil) {
+        test.Fatalf("Failed to create backend: '%v'.", err);
+    }
+
+    _, err = backend.FetchUsers();
+    if (err == nil) {
+        test.Fatalf("Fetching users with a bad token did not fail.");
+    }
+}
@@ -10,6 +10,8 @@ import (
 // Settings to help in testing.
 var failUpdateAssignmentScores bool = false;
 var usersModifier FetchUsersModifier = nil;
+var assignments []*lmstypes.Assignment = nil;
+var failCreateAssignmentName string = "";
 
 type TestLMSBackend struct {
     CourseID string
@@ -42,14 +44,50 @@ func ClearUsersModifier() {
     usersModifier = nil;
 }
 
+// Set the assignments returned from FetchAssignments().
+func SetAssignments(newAssignments []*lmstypes.Assignment) {
+    assignments = newAssignments;
+}
+
+// Make CreateAssignment() fail for assignments with this name.
+func SetFailCreateAssignmentName(name string) {
+    failCreateAssignmentName = name;
+}
+
+// Clear any assignment settings (see SetAssignments() and SetFailCreateAssignmentName()).
+func ClearAssignments() {
+    assignments = nil;
+    failCreateAssignmentName = "";
+}
+
 func (this *TestLMSBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
-    return nil, nil;
+    return assignments, nil;
 }
 
 func (this *TestLMSBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
     return nil, nil;
 }
 
+// New assignments get an ID based on their name.
+func (this *TestLMSBackend) CreateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+    if ((failCreateAssignmentName != "") && (assignment.Name == failCreateAssignmentName)) {
+        return nil, fmt.Errorf("Induced Failure");
+    }
+
+    newAssignment := *assignment;
+    newAssignment.ID = "lms-" + assignment.Name;
+    newAssignment.LMSCourseID = this.CourseID;
+
+    return &newAssignment, nil;
+}
+
+func (this *TestLMSBackend) UpdateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+    newAssignment := *assignment;
+    newAssignment.LMSCourseID = this.CourseID;
+
+    return &newAssignment, nil;
+}
+
 func (this *TestLMSBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
     return nil;
 }
@@ -46,5 +46,7 @@ func UserFromAGUser(user *model.User) *lmstypes.User {
         Name: user.Name,
         Email: user.Email,
         Role: user.Role,
+        Sections: user.Sections,
+        Groups: user.Groups,
     };
 }
@@ -4,6 +4,8 @@ import (
     "fmt"
 
     "github.com/edulinq/autograder/lms/backend/canvas"
+    "github.com/edulinq/autograder/lms/backend/file"
+    "github.com/edulinq/autograder/lms/backend/moodle"
     "github.com/edulinq/autograder/lms/backend/test"
     "github.com/edulinq/autograder/lms/lmstypes"
     "github.com/edulinq/autograder/model"
@@ -12,6 +14,10 @@ import (
 type lmsBackend interface {
     FetchAssignments() ([]*lmstypes.Assignment, error)
     FetchAssignment(assignmentID string) (*lmstypes.Assignment, error)
+    // Create a new assignment (the ID of the given assignment is ignored) and return it (with its new ID).
+    CreateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error)
+    // Update the name, due date, and max points of an existing assignment (empty values are left alone).
+    UpdateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error)
 
     UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error
     UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error
@@ -37,6 +43,20 @@ func getBackend(course *model.Course) (lmsBackend, error) {
                 return nil, err;
             }
 
+            return backend, nil;
+        case model.LMS_TYPE_FILE:
+            backend, err := getFileBackend(course, adapter);
+            if (err != nil) {
+                return nil, err;
+            }
+
+            return backend, nil;
+        case model.LMS_TYPE_MOODLE:
+            backend, err := moodle.NewBackend(adapter.LMSCourseID, adapter.APIToken, adapter.BaseURL);
+            if (err != nil) {
+                return nil, err;
+            }
+
             return backend, nil;
         case model.LMS_TYPE_TEST:
             backend, err := test.NewBackend(course.GetID());
@@ -50,6 +70,31 @@ func getBackend(course *model.Course) (lmsBackend, error) {
     }
 }
 
+func getFileBackend(course *model.Course, adapter *model.LMSAdapter) (*file.FileBackend, error) {
+    lmsCourseID := adapter.LMSCourseID;
+    if (lmsCourseID == "") {
+        lmsCourseID = course.GetID();
+    }
+
+    usersPath, err := adapter.GetFilePath(course, adapter.UsersPath);
+    if (err != nil) {
+        return nil, fmt.Errorf("Bad file LMS users path: '%w'.", err);
+    }
+
+    assignmentsPath, err := adapter.GetFilePath(course, adapter.AssignmentsPath);
+    if (err != nil) {
+        return nil, fmt.Errorf("Bad file LMS assignments path: '%w'.", err);
+    }
+
+    // The gradebook lives outside the course's source dir so it survives course updates.
+    gradebookPath, err := adapter.GetGradebookPath(course);
+    if (err != nil) {
+        return nil, fmt.Errorf("Bad file LMS gradebook path: '%w'.", err);
+    }
+
+    return file.NewBackend(lmsCourseID, usersPath, assignmentsPath, gradebookPath);
+}
+
 func FetchAssignment(course *model.Course, assignmentID string) (*lmstypes.Assignment, error) {
     backend, err := getBackend(course);
     if (err != nil) {
@@ -68,6 +113,24 @@ func FetchAssignments(course *model.Course) ([]*lmstypes.Assignment, error) {
     return backend.FetchAssignments();
 }
 
+func CreateAssignment(course *model.Course, assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+    backend, err := getBackend(course);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return backend.CreateAssignment(assignment);
+}
+
+func UpdateAssignment(course *model.Course, assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+    backend, err := getBackend(course);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return backend.UpdateAssignment(assignment);
+}
+
 func UpdateComments(course *model.Course, assignmentID string, comments []*lmstypes.SubmissionComment) error {
     backend, err := getBackend(course);
     if (err != nil) {
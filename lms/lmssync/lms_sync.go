@@ -3,6 +3,7 @@ package lmssync
 import (
     "errors"
     "fmt"
+    "slices"
     "strings"
 
     "github.com/edulinq/autograder/common"
@@ -25,6 +26,14 @@ func SyncLMS(course *model.Course, dryRun bool, sendEmails bool) (*model.LMSSync
         return nil, err;
     }
 
+    var assignmentPush *model.AssignmentPushResult = nil;
+    if (course.GetLMSAdapter().PushAssignments) {
+        assignmentPush, err = PushAssignments(course, dryRun);
+        if (err != nil) {
+            return nil, err;
+        }
+    }
+
     assignmentSync, err := syncAssignments(course, dryRun);
     if (err != nil) {
         return nil, err;
@@ -32,6 +41,7 @@ func SyncLMS(course *model.Course, dryRun bool, sendEmails bool) (*model.LMSSync
 
     result := &model.LMSSyncResult{
         UserSync: userSync,
+        AssignmentPush: assignmentPush,
         AssignmentSync: assignmentSync,
     };
 
@@ -90,6 +100,11 @@ func syncLMSUsers(course *model.Course, dryRun bool, sendEmails bool, lmsUsers m
         syncEmails = getAllEmails(localUsers, lmsUsers);
     }
 
+    serverUsers, err := db.GetServerUsers();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch server users: '%w'.", err);
+    }
+
     syncResult := model.NewUserSyncResult();
 
     for _, email := range syncEmails {
@@ -98,6 +113,12 @@ func syncLMSUsers(course *model.Course, dryRun bool, sendEmails bool, lmsUsers m
             return nil, err;
         }
 
+        // Users with a server user get their credentials from it.
+        if ((resolveResult != nil) && (resolveResult.Add != nil) && (serverUsers[email] != nil)) {
+            resolveResult.Add.Credentials = model.Credentials{};
+            resolveResult.ClearTextPassword = "";
+        }
+
         if (resolveResult != nil) {
             syncResult.AddResolveResult(resolveResult);
         }
@@ -117,7 +138,7 @@ func syncLMSUsers(course *model.Course, dryRun bool, sendEmails bool, lmsUsers m
 
         for _, newUser := range syncResult.Add {
             pass := syncResult.ClearTextPasswords[newUser.Email];
-            err = errors.Join(err, model.SendUserAddEmail(course, newUser, pass, true, false, dryRun, true));
+            err = errors.Join(err, model.SendUserAddEmail(course, newUser, pass, (pass != ""), false, dryRun, true));
         }
 
         if (err != nil) {
@@ -136,6 +157,18 @@ func mergeUsers(localUser *model.User, lmsUser *lmstypes.User, mergeAttributes b
         changed = true;
     }
 
+    // Like the LMS ID, sections/groups are owned by the LMS.
+    // But, they are only replaced if the LMS actually provided them.
+    if ((lmsUser.Sections != nil) && !slices.Equal(localUser.Sections, lmsUser.Sections)) {
+        localUser.Sections = lmsUser.Sections;
+        changed = true;
+    }
+
+    if ((lmsUser.Groups != nil) && !slices.Equal(localUser.Groups, lmsUser.Groups)) {
+        localUser.Groups = lmsUser.Groups;
+        changed = true;
+    }
+
     if (!mergeAttributes) {
         return changed;
     }
@@ -183,6 +216,8 @@ func resolveUserSync(course *model.Course, localUsers map[string]*model.User,
             Name: lmsUser.Name,
             Role: lmsUser.Role,
             LMSID: lmsUser.ID,
+            Sections: lmsUser.Sections,
+            Groups: lmsUser.Groups,
         };
 
         hashPass := util.Sha256HexFromString(pass);
@@ -296,7 +331,9 @@ func syncAssignments(course *model.Course, dryRun bool) (*model.AssignmentSyncRe
 
     for localID, lmsIndex := range matches {
         localName := localAssignments[localID].GetName();
-        changed := mergeAssignment(localAssignments[localID], lmsAssignments[lmsIndex]);
+        changed, conflicts := mergeAssignment(localAssignments[localID], lmsAssignments[lmsIndex], adapter.SyncAssignmentDetails);
+        result.Conflicts = append(result.Conflicts, conflicts...);
+
         if (changed) {
             result.SyncedAssignments = append(result.SyncedAssignments, model.AssignmentInfo{localID, localName});
         } else {
@@ -314,8 +351,13 @@ func syncAssignments(course *model.Course, dryRun bool) (*model.AssignmentSyncRe
     return result, nil;
 }
 
-func mergeAssignment(localAssignment *model.Assignment, lmsAssignment *lmstypes.Assignment) bool {
+// Merge LMS information into a local assignment.
+// Empty local fields are always filled.
+// A local due date or max points that differs from the LMS is reported as a conflict,
+// and is replaced with the LMS value if |overwrite| is true.
+func mergeAssignment(localAssignment *model.Assignment, lmsAssignment *lmstypes.Assignment, overwrite bool) (bool, []model.AssignmentConflict) {
     changed := false;
+    conflicts := make([]model.AssignmentConflict, 0);
 
     if (localAssignment.LMSID == "") {
         localAssignment.LMSID = lmsAssignment.ID;
@@ -327,15 +369,49 @@ func mergeAssignment(localAssignment *model.Assignment, lmsAssignment *lmstypes.
         changed = true;
     }
 
-    if (localAssignment.DueDate.IsZero() && (lmsAssignment.DueDate != nil) && !lmsAssignment.DueDate.IsZero()) {
-        localAssignment.DueDate = common.TimestampFromTime(*lmsAssignment.DueDate);
-        changed = true;
+    if ((lmsAssignment.DueDate != nil) && !lmsAssignment.DueDate.IsZero()) {
+        lmsDueDate := common.TimestampFromTime(*lmsAssignment.DueDate);
+
+        if (localAssignment.DueDate.IsZero()) {
+            localAssignment.DueDate = lmsDueDate;
+            changed = true;
+        } else if (!localAssignment.DueDate.MustTime().Equal(*lmsAssignment.DueDate)) {
+            conflicts = append(conflicts, model.AssignmentConflict{
+                ID: localAssignment.GetID(),
+                Name: localAssignment.GetName(),
+                Field: model.ASSIGNMENT_FIELD_DUE_DATE,
+                LocalValue: string(localAssignment.DueDate),
+                LMSValue: string(lmsDueDate),
+                Overwritten: overwrite,
+            });
+
+            if (overwrite) {
+                localAssignment.DueDate = lmsDueDate;
+                changed = true;
+            }
+        }
     }
 
-    if (util.IsZero(localAssignment.MaxPoints) && !util.IsZero(lmsAssignment.MaxPoints)) {
-        localAssignment.MaxPoints = lmsAssignment.MaxPoints;
-        changed = true;
+    if (!util.IsZero(lmsAssignment.MaxPoints)) {
+        if (util.IsZero(localAssignment.MaxPoints)) {
+            localAssignment.MaxPoints = lmsAssignment.MaxPoints;
+            changed = true;
+        } else if (!util.IsClose(localAssignment.MaxPoints, lmsAssignment.MaxPoints)) {
+            conflicts = append(conflicts, model.AssignmentConflict{
+                ID: localAssignment.GetID(),
+                Name: localAssignment.GetName(),
+                Field: model.ASSIGNMENT_FIELD_MAX_POINTS,
+                LocalValue: util.FloatToStr(localAssignment.MaxPoints),
+                LMSValue: util.FloatToStr(lmsAssignment.MaxPoints),
+                Overwritten: overwrite,
+            });
+
+            if (overwrite) {
+                localAssignment.MaxPoints = lmsAssignment.MaxPoints;
+                changed = true;
+            }
+        }
     }
 
-    return changed;
+    return changed, conflicts;
 }
@@ -1,9 +1,12 @@
 package lmssync
 
 import (
+    "reflect"
     "slices"
     "testing"
+    "time"
 
+    "github.com/edulinq/autograder/common"
     "github.com/edulinq/autograder/db"
     "github.com/edulinq/autograder/email"
     "github.com/edulinq/autograder/lms/lmstypes"
@@ -23,6 +26,7 @@ type SyncLMSTestCase struct {
 func reset() {
     db.ResetForTesting();
     lmstest.ClearUsersModifier();
+    lmstest.ClearAssignments();
 }
 
 func TestCourseSyncLMSUserEmails(test *testing.T) {
@@ -311,3 +315,127 @@ var addEmails []*email.Message = []*email.Message{
         HTML: false,
     },
 };
+
+func TestMergeUsersSectionsGroups(test *testing.T) {
+    testCases := []struct{
+        lmsSections []string
+        lmsGroups []string
+        expectedChanged bool
+        expectedSections []string
+        expectedGroups []string
+    }{
+        // The LMS does not provide sections/groups.
+        {nil, nil, false, []string{"Lecture 01"}, []string{"Team A"}},
+
+        // Same.
+        {[]string{"Lecture 01"}, []string{"Team A"}, false, []string{"Lecture 01"}, []string{"Team A"}},
+
+        // Changed.
+        {[]string{"Lecture 02"}, nil, true, []string{"Lecture 02"}, []string{"Team A"}},
+        {nil, []string{"Team B"}, true, []string{"Lecture 01"}, []string{"Team B"}},
+
+        // Removed from all.
+        {[]string{}, []string{}, true, []string{}, []string{}},
+    };
+
+    for i, testCase := range testCases {
+        localUser := &model.User{
+            Email: "student@test.com",
+            LMSID: "lms-student@test.com",
+            Sections: []string{"Lecture 01"},
+            Groups: []string{"Team A"},
+        };
+
+        lmsUser := &lmstypes.User{
+            ID: "lms-student@test.com",
+            Email: "student@test.com",
+            Sections: testCase.lmsSections,
+            Groups: testCase.lmsGroups,
+        };
+
+        changed := mergeUsers(localUser, lmsUser, false);
+
+        if (changed != testCase.expectedChanged) {
+            test.Errorf("Case %d: Unexpected changed. Expected: %v, Actual: %v.", i, testCase.expectedChanged, changed);
+            continue;
+        }
+
+        if (!reflect.DeepEqual(testCase.expectedSections, localUser.Sections)) {
+            test.Errorf("Case %d: Unexpected sections. Expected: '%v', Actual: '%v'.", i, testCase.expectedSections, localUser.Sections);
+            continue;
+        }
+
+        if (!reflect.DeepEqual(testCase.expectedGroups, localUser.Groups)) {
+            test.Errorf("Case %d: Unexpected groups. Expected: '%v', Actual: '%v'.", i, testCase.expectedGroups, localUser.Groups);
+            continue;
+        }
+    }
+}
+
+func TestMergeAssignmentDetails(test *testing.T) {
+    lmsDueDate := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC);
+    lmsAssignment := &lmstypes.Assignment{ID: "lms-hw0", Name: "LMS HW0", DueDate: &lmsDueDate, MaxPoints: 100};
+
+    testCases := []struct{
+        dueDate common.Timestamp
+        maxPoints float64
+        overwrite bool
+        expectedChanged bool
+        expectedDueDate common.Timestamp
+        expectedMaxPoints float64
+        expectedConflicts []string
+    }{
+        // Empty values are always filled.
+        {"", 0, false, true, "2024-01-10T00:00:00Z", 100, []string{}},
+
+        // Same values (in a different time zone) are not conflicts.
+        {"2024-01-09T18:00:00-06:00", 100, false, false, "2024-01-09T18:00:00-06:00", 100, []string{}},
+
+        // Conflicts are kept without overwrite.
+        {"2024-01-11T00:00:00Z", 50, false, false, "2024-01-11T00:00:00Z", 50,
+                []string{model.ASSIGNMENT_FIELD_DUE_DATE, model.ASSIGNMENT_FIELD_MAX_POINTS}},
+        {"2024-01-11T00:00:00Z", 100, false, false, "2024-01-11T00:00:00Z", 100,
+                []string{model.ASSIGNMENT_FIELD_DUE_DATE}},
+
+        // Conflicts are replaced with overwrite.
+        {"2024-01-11T00:00:00Z", 50, true, true, "2024-01-10T00:00:00Z", 100,
+                []string{model.ASSIGNMENT_FIELD_DUE_DATE, model.ASSIGNMENT_FIELD_MAX_POINTS}},
+    };
+
+    for i, testCase := range testCases {
+        assignment := &model.Assignment{
+            ID: "hw0",
+            Name: "hw0",
+            LMSID: "lms-hw0",
+            DueDate: testCase.dueDate,
+            MaxPoints: testCase.maxPoints,
+        };
+
+        changed, conflicts := mergeAssignment(assignment, lmsAssignment, testCase.overwrite);
+
+        if (testCase.expectedChanged != changed) {
+            test.Errorf("Case %d: Unexpected changed. Expected: %v, Actual: %v.", i, testCase.expectedChanged, changed);
+            continue;
+        }
+
+        if ((testCase.expectedDueDate != assignment.DueDate) || (testCase.expectedMaxPoints != assignment.MaxPoints)) {
+            test.Errorf("Case %d: Unexpected assignment values. Expected: ('%s', %f), Actual: ('%s', %f).",
+                    i, testCase.expectedDueDate, testCase.expectedMaxPoints, assignment.DueDate, assignment.MaxPoints);
+            continue;
+        }
+
+        fields := make([]string, 0, len(conflicts));
+        for _, conflict := range conflicts {
+            fields = append(fields, conflict.Field);
+
+            if (conflict.Overwritten != testCase.overwrite) {
+                test.Errorf("Case %d: Unexpected overwritten value on conflict: '%+v'.", i, conflict);
+            }
+        }
+
+        if (!reflect.DeepEqual(testCase.expectedConflicts, fields)) {
+            test.Errorf("Case %d: Unexpected conflicts. Expected: '%v', Actual: '%v'.", i, testCase.expectedConflicts, fields);
+            continue;
+        }
+    }
+}
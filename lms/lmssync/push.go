@@ -0,0 +1,192 @@
+package lmssync
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/lms"
+    "github.com/edulinq/autograder/lms/lmstypes"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/util"
+)
+
+// Push local assignments to the LMS.
+// Assignments without an LMS ID are first linked to an existing (unlinked) LMS assignment with the same name (like syncing),
+// and are only created in the LMS (getting the new LMS ID) if there is no such assignment.
+// Linked assignments have their name, due date, and max points updated in the LMS (if they differ).
+// Local values that are empty are never pushed.
+// A failure for one assignment does not stop the others:
+// any new links are still saved, and the (partial) result is returned along with the error.
+func PushAssignments(course *model.Course, dryRun bool) (*model.AssignmentPushResult, error) {
+    result := model.NewAssignmentPushResult();
+
+    lmsAssignmentsSlice, err := lms.FetchAssignments(course);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get assignments: '%w'.", err);
+    }
+
+    lmsAssignments := make(map[string]*lmstypes.Assignment, len(lmsAssignmentsSlice));
+    for _, lmsAssignment := range lmsAssignmentsSlice {
+        lmsAssignments[lmsAssignment.ID] = lmsAssignment;
+    }
+
+    // LMS assignments that are already linked cannot be matched by name.
+    linkedLMSIDs := make(map[string]bool);
+    for _, assignment := range course.GetAssignments() {
+        if (assignment.GetLMSID() != "") {
+            linkedLMSIDs[assignment.GetLMSID()] = true;
+        }
+    }
+
+    changed := false;
+    var errs error = nil;
+
+    for _, assignment := range course.GetSortedAssignments() {
+        info := model.AssignmentInfo{ID: assignment.GetID(), Name: assignment.GetName()};
+        localAssignment := toLMSAssignment(assignment);
+
+        var lmsAssignment *lmstypes.Assignment = nil;
+
+        if (assignment.GetLMSID() != "") {
+            lmsAssignment = lmsAssignments[assignment.GetLMSID()];
+            if (lmsAssignment == nil) {
+                log.Warn("Could not find linked LMS assignment.", assignment, log.NewAttr("lms-id", assignment.GetLMSID()));
+                result.MissingAssignments = append(result.MissingAssignments, info);
+                continue;
+            }
+        } else {
+            matches := matchLMSAssignments(localAssignment.Name, lmsAssignmentsSlice, linkedLMSIDs);
+
+            if (len(matches) > 1) {
+                log.Warn("Assignment name matches multiple LMS assignments.", assignment, log.NewAttr("name", localAssignment.Name));
+                result.AmbiguousAssignments = append(result.AmbiguousAssignments, info);
+                continue;
+            }
+
+            if (len(matches) == 0) {
+                if (dryRun) {
+                    log.Info("Dry Run: Skipping creation of LMS assignment.", assignment);
+                } else {
+                    newAssignment, err := lms.CreateAssignment(course, localAssignment);
+                    if (err != nil) {
+                        errs = errors.Join(errs, fmt.Errorf("Failed to create LMS assignment for '%s': '%w'.", assignment.GetID(), err));
+                        result.FailedAssignments = append(result.FailedAssignments, info);
+                        continue;
+                    }
+
+                    assignment.LMSID = newAssignment.ID;
+                    changed = true;
+                }
+
+                result.CreatedAssignments = append(result.CreatedAssignments, info);
+                continue;
+            }
+
+            lmsAssignment = matches[0];
+            linkedLMSIDs[lmsAssignment.ID] = true;
+            localAssignment.ID = lmsAssignment.ID;
+
+            if (dryRun) {
+                log.Info("Dry Run: Skipping linking of LMS assignment.", assignment, log.NewAttr("lms-id", lmsAssignment.ID));
+            } else {
+                assignment.LMSID = lmsAssignment.ID;
+                changed = true;
+            }
+
+            result.LinkedAssignments = append(result.LinkedAssignments, info);
+        }
+
+        if (!assignmentDiffers(localAssignment, lmsAssignment)) {
+            result.UnchangedAssignments = append(result.UnchangedAssignments, info);
+            continue;
+        }
+
+        if (dryRun) {
+            log.Info("Dry Run: Skipping update of LMS assignment.", assignment);
+        } else {
+            _, err = lms.UpdateAssignment(course, localAssignment);
+            if (err != nil) {
+                errs = errors.Join(errs, fmt.Errorf("Failed to update LMS assignment for '%s': '%w'.", assignment.GetID(), err));
+                result.FailedAssignments = append(result.FailedAssignments, info);
+                continue;
+            }
+        }
+
+        result.UpdatedAssignments = append(result.UpdatedAssignments, info);
+    }
+
+    if (!dryRun && changed) {
+        err = db.SaveCourse(course);
+        if (err != nil) {
+            errs = errors.Join(errs, fmt.Errorf("Failed to save course: '%w'.", err));
+        }
+    }
+
+    if (errs != nil) {
+        return result, fmt.Errorf("Failed to push some assignments: '%w'.", errs);
+    }
+
+    return result, nil;
+}
+
+// Get the (unlinked) LMS assignments with a name (matched the same way as syncing).
+func matchLMSAssignments(name string, lmsAssignments []*lmstypes.Assignment, linkedLMSIDs map[string]bool) []*lmstypes.Assignment {
+    matches := make([]*lmstypes.Assignment, 0);
+    if (name == "") {
+        return matches;
+    }
+
+    for _, lmsAssignment := range lmsAssignments {
+        if (linkedLMSIDs[lmsAssignment.ID]) {
+            continue;
+        }
+
+        if (strings.EqualFold(name, lmsAssignment.Name)) {
+            matches = append(matches, lmsAssignment);
+        }
+    }
+
+    return matches;
+}
+
+// Assignments without a name use their ID as the LMS name.
+func toLMSAssignment(assignment *model.Assignment) *lmstypes.Assignment {
+    lmsAssignment := &lmstypes.Assignment{
+        ID: assignment.GetLMSID(),
+        Name: assignment.GetName(),
+        MaxPoints: assignment.MaxPoints,
+    };
+
+    if (lmsAssignment.Name == "") {
+        lmsAssignment.Name = assignment.GetID();
+    }
+
+    if (!assignment.DueDate.IsZero()) {
+        dueDate := assignment.DueDate.MustTime();
+        lmsAssignment.DueDate = &dueDate;
+    }
+
+    return lmsAssignment;
+}
+
+// Check if any (non-empty) local value differs from the LMS.
+func assignmentDiffers(localAssignment *lmstypes.Assignment, lmsAssignment *lmstypes.Assignment) bool {
+    if (localAssignment.Name != lmsAssignment.Name) {
+        return true;
+    }
+
+    if (localAssignment.DueDate != nil) {
+        if ((lmsAssignment.DueDate == nil) || !localAssignment.DueDate.Equal(*lmsAssignment.DueDate)) {
+            return true;
+        }
+    }
+
+    if (!util.IsZero(localAssignment.MaxPoints) && !util.IsClose(localAssignment.MaxPoints, lmsAssignment.MaxPoints)) {
+        return true;
+    }
+
+    return false;
+}
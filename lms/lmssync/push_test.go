@@ -0,0 +1,170 @@
+package lmssync
+
+import (
+    "reflect"
+    "testing"
+
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/lms/lmstypes"
+    lmstest "github.com/edulinq/autograder/lms/backend/test"
+    "github.com/edulinq/autograder/model"
+)
+
+func TestPushAssignments(test *testing.T) {
+    for _, dryRun := range []bool{true, false} {
+        reset();
+
+        course := db.MustGetTestCourse();
+
+        result, err := PushAssignments(course, dryRun);
+        if (err != nil) {
+            test.Errorf("Case %v: Failed to push assignments: '%v'.", dryRun, err);
+            continue;
+        }
+
+        expected := model.NewAssignmentPushResult();
+        expected.CreatedAssignments = append(expected.CreatedAssignments, model.AssignmentInfo{ID: "hw0", Name: "Homework 0"});
+
+        if (!reflect.DeepEqual(expected, result)) {
+            test.Errorf("Case %v: Unexpected result. Expected: '%+v', Actual: '%+v'.", dryRun, expected, result);
+            continue;
+        }
+
+        expectedLMSID := "lms-Homework 0";
+        if (dryRun) {
+            expectedLMSID = "";
+        }
+
+        assignment := db.MustGetTestAssignment();
+        if (expectedLMSID != assignment.GetLMSID()) {
+            test.Errorf("Case %v: Unexpected LMS ID. Expected: '%s', Actual: '%s'.", dryRun, expectedLMSID, assignment.GetLMSID());
+            continue;
+        }
+    }
+
+    reset();
+}
+
+func TestPushAssignmentsMissing(test *testing.T) {
+    reset();
+    defer reset();
+
+    course := db.MustGetTestCourse();
+    course.GetAssignment("hw0").LMSID = "zzz";
+
+    // The test LMS has no assignments, so a linked assignment cannot be found.
+    result, err := PushAssignments(course, false);
+    if (err != nil) {
+        test.Fatalf("Failed to push assignments: '%v'.", err);
+    }
+
+    expected := model.NewAssignmentPushResult();
+    expected.MissingAssignments = append(expected.MissingAssignments, model.AssignmentInfo{ID: "hw0", Name: "Homework 0"});
+
+    if (!reflect.DeepEqual(expected, result)) {
+        test.Fatalf("Unexpected result. Expected: '%+v', Actual: '%+v'.", expected, result);
+    }
+}
+
+func TestPushAssignmentsLinkByName(test *testing.T) {
+    for _, dryRun := range []bool{true, false} {
+        reset();
+
+        course := db.MustGetTestCourse();
+
+        // An LMS assignment that already matches the local assignment (other than the case of the name).
+        lmsAssignment := toLMSAssignment(course.GetAssignment("hw0"));
+        lmsAssignment.ID = "lms-existing";
+        lmsAssignment.Name = "homework 0";
+        lmstest.SetAssignments([]*lmstypes.Assignment{lmsAssignment});
+
+        result, err := PushAssignments(course, dryRun);
+        if (err != nil) {
+            test.Errorf("Case %v: Failed to push assignments: '%v'.", dryRun, err);
+            continue;
+        }
+
+        expected := model.NewAssignmentPushResult();
+        expected.LinkedAssignments = append(expected.LinkedAssignments, model.AssignmentInfo{ID: "hw0", Name: "Homework 0"});
+        expected.UpdatedAssignments = append(expected.UpdatedAssignments, model.AssignmentInfo{ID: "hw0", Name: "Homework 0"});
+
+        if (!reflect.DeepEqual(expected, result)) {
+            test.Errorf("Case %v: Unexpected result. Expected: '%+v', Actual: '%+v'.", dryRun, expected, result);
+            continue;
+        }
+
+        expectedLMSID := "lms-existing";
+        if (dryRun) {
+            expectedLMSID = "";
+        }
+
+        assignment := db.MustGetTestAssignment();
+        if (expectedLMSID != assignment.GetLMSID()) {
+            test.Errorf("Case %v: Unexpected LMS ID. Expected: '%s', Actual: '%s'.", dryRun, expectedLMSID, assignment.GetLMSID());
+            continue;
+        }
+    }
+
+    reset();
+}
+
+func TestPushAssignmentsAmbiguous(test *testing.T) {
+    reset();
+    defer reset();
+
+    lmstest.SetAssignments([]*lmstypes.Assignment{
+        &lmstypes.Assignment{ID: "lms-1", Name: "Homework 0"},
+        &lmstypes.Assignment{ID: "lms-2", Name: "Homework 0"},
+    });
+
+    course := db.MustGetTestCourse();
+
+    result, err := PushAssignments(course, false);
+    if (err != nil) {
+        test.Fatalf("Failed to push assignments: '%v'.", err);
+    }
+
+    expected := model.NewAssignmentPushResult();
+    expected.AmbiguousAssignments = append(expected.AmbiguousAssignments, model.AssignmentInfo{ID: "hw0", Name: "Homework 0"});
+
+    if (!reflect.DeepEqual(expected, result)) {
+        test.Fatalf("Unexpected result. Expected: '%+v', Actual: '%+v'.", expected, result);
+    }
+
+    if (db.MustGetTestAssignment().GetLMSID() != "") {
+        test.Fatalf("Ambiguous assignment was linked: '%s'.", db.MustGetTestAssignment().GetLMSID());
+    }
+}
+
+func TestPushAssignmentsPartialFailure(test *testing.T) {
+    reset();
+    defer reset();
+
+    course := db.MustGetTestCourse();
+
+    otherAssignment := *course.GetAssignment("hw0");
+    otherAssignment.ID = "hw1";
+    otherAssignment.Name = "Homework 1";
+    course.Assignments[otherAssignment.ID] = &otherAssignment;
+
+    lmstest.SetFailCreateAssignmentName("Homework 1");
+
+    result, err := PushAssignments(course, false);
+    if (err == nil) {
+        test.Fatalf("Did not get an error for a failed creation.");
+    }
+
+    expected := model.NewAssignmentPushResult();
+    expected.CreatedAssignments = append(expected.CreatedAssignments, model.AssignmentInfo{ID: "hw0", Name: "Homework 0"});
+    expected.FailedAssignments = append(expected.FailedAssignments, model.AssignmentInfo{ID: "hw1", Name: "Homework 1"});
+
+    if (!reflect.DeepEqual(expected, result)) {
+        test.Fatalf("Unexpected result. Expected: '%+v', Actual: '%+v'.", expected, result);
+    }
+
+    // The successful creation is still saved.
+    lmsID := db.MustGetTestAssignment().GetLMSID();
+    if (lmsID != "lms-Homework 0") {
+        test.Fatalf("New LMS ID was not saved. Expected: 'lms-Homework 0', Actual: '%s'.", lmsID);
+    }
+}
@@ -11,6 +11,10 @@ type User struct {
     Name string
     Email string
     Role model.UserRole
+    // The names of the course sections/groups the user is in.
+    // Nil if the LMS does not provide them (which is different from an empty list, i.e., in none).
+    Sections []string
+    Groups []string
 }
 
 type SubmissionScore struct {
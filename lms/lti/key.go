@@ -0,0 +1,108 @@
+package lti
+
+import (
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/sha256"
+    "crypto/x509"
+    "encoding/hex"
+    "encoding/pem"
+    "fmt"
+    "os"
+    "path/filepath"
+    "sync"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/oidc"
+    "github.com/edulinq/autograder/util"
+)
+
+const (
+    TOOL_KEY_FILENAME = "lti.key";
+    TOOL_KEY_BITS = 2048;
+)
+
+var (
+    toolKeyLock sync.Mutex;
+    toolKeyPath string;
+    toolKey *rsa.PrivateKey;
+)
+
+// Get the key set (with only the tool's public key) that LMSs use to verify this tool's tokens.
+func GetToolKeySet() (*oidc.JSONWebKeySet, error) {
+    key, keyID, err := getToolKey();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    keySet := &oidc.JSONWebKeySet{
+        Keys: []oidc.JSONWebKey{oidc.NewRSAJSONWebKey(keyID, &key.PublicKey)},
+    };
+
+    return keySet, nil;
+}
+
+// Get the key this tool signs its tokens with (and the key's ID).
+// The key is stored in (or generated into) the config dir.
+func getToolKey() (*rsa.PrivateKey, string, error) {
+    toolKeyLock.Lock();
+    defer toolKeyLock.Unlock();
+
+    path := filepath.Join(config.GetConfigDir(), TOOL_KEY_FILENAME);
+    if ((toolKey == nil) || (toolKeyPath != path)) {
+        key, err := loadOrCreateToolKey(path);
+        if (err != nil) {
+            return nil, "", err;
+        }
+
+        toolKeyPath = path;
+        toolKey = key;
+    }
+
+    hash := sha256.Sum256(toolKey.PublicKey.N.Bytes());
+    return toolKey, hex.EncodeToString(hash[:8]), nil;
+}
+
+func loadOrCreateToolKey(path string) (*rsa.PrivateKey, error) {
+    if (util.PathExists(path)) {
+        text, err := util.ReadFile(path);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read LTI tool key: '%w'.", err);
+        }
+
+        block, _ := pem.Decode([]byte(text));
+        if (block == nil) {
+            return nil, fmt.Errorf("LTI tool key file '%s' is not PEM.", path);
+        }
+
+        key, err := x509.ParsePKCS1PrivateKey(block.Bytes);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to parse LTI tool key '%s': '%w'.", path, err);
+        }
+
+        return key, nil;
+    }
+
+    key, err := rsa.GenerateKey(rand.Reader, TOOL_KEY_BITS);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to generate LTI tool key: '%w'.", err);
+    }
+
+    err = util.MkDir(filepath.Dir(path));
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to make dir for LTI tool key: '%w'.", err);
+    }
+
+    block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)};
+
+    // The key is a secret, only the owner should be able to read it.
+    err = os.WriteFile(path, pem.EncodeToMemory(block), 0600);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to write LTI tool key '%s': '%w'.", path, err);
+    }
+
+    log.Info("Generated a new LTI tool key.", log.NewAttr("path", path));
+
+    return key, nil;
+}
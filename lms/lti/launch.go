@@ -0,0 +1,325 @@
+package lti
+
+import (
+    "crypto/subtle"
+    "fmt"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/oidc"
+)
+
+const (
+    LTI_VERSION = "1.3.0";
+    MESSAGE_TYPE_RESOURCE_LINK = "LtiResourceLinkRequest";
+
+    // Custom parameters (set in the LMS when the tool is registered or linked) that are used to pick the launch's assignment.
+    // Custom parameters can be set by course staff in some LMSs, so they are never used to pick the course or user.
+    CUSTOM_ASSIGNMENT_ID = "assignment_id";
+    CUSTOM_LMS_ASSIGNMENT_ID = "lms_assignment_id";
+)
+
+// The claims of an LTI launch (ID token) that we use.
+type launchClaims struct {
+    Issuer string `json:"iss"`
+    Subject string `json:"sub"`
+    Audience oidc.Audience `json:"aud"`
+    AuthorizedParty string `json:"azp,omitempty"`
+    // Unix time (seconds).
+    Expiration int64 `json:"exp"`
+    IssuedAt int64 `json:"iat"`
+    Nonce string `json:"nonce"`
+
+    Email string `json:"email,omitempty"`
+
+    MessageType string `json:"https://purl.imsglobal.org/spec/lti/claim/message_type"`
+    Version string `json:"https://purl.imsglobal.org/spec/lti/claim/version"`
+    DeploymentID string `json:"https://purl.imsglobal.org/spec/lti/claim/deployment_id"`
+    Context *launchContext `json:"https://purl.imsglobal.org/spec/lti/claim/context,omitempty"`
+    Custom map[string]string `json:"https://purl.imsglobal.org/spec/lti/claim/custom,omitempty"`
+    Endpoint *launchEndpoint `json:"https://purl.imsglobal.org/spec/lti-ags/claim/endpoint,omitempty"`
+}
+
+type launchContext struct {
+    ID string `json:"id"`
+}
+
+type launchEndpoint struct {
+    Scope []string `json:"scope,omitempty"`
+    LineItem string `json:"lineitem,omitempty"`
+}
+
+// The result of a successful launch.
+type Launch struct {
+    Course *model.Course
+    User *model.User
+    // Nil if the launch was not for a specific assignment.
+    Assignment *model.Assignment
+}
+
+var (
+    platformsLock sync.Mutex;
+    // Providers are only used to hold (and refresh) platform keys.
+    // Keyed by key set URL.
+    platforms map[string]*oidc.Provider = make(map[string]*oidc.Provider);
+)
+
+// Finish a launch using the state and ID token that the LMS posted.
+// A state can only be used once.
+func FinishLaunch(state string, idToken string) (*Launch, error) {
+    pending := takePendingLaunch(state);
+    if (pending == nil) {
+        return nil, fmt.Errorf("Unknown or expired LTI launch state.");
+    }
+
+    registration, err := getRegistration(pending.Issuer, pending.ClientID);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    var claims launchClaims;
+    err = getPlatform(registration).VerifySignature(idToken, &claims);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to verify LTI launch: '%w'.", err);
+    }
+
+    err = claims.validate(registration, pending.Nonce, time.Now());
+    if (err != nil) {
+        return nil, err;
+    }
+
+    course, err := claims.getCourse(registration);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    user, err := claims.getUser(course);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    assignment, err := claims.getAssignment(course);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    result := &Launch{
+        Course: course,
+        User: user,
+        Assignment: assignment,
+    };
+
+    return result, nil;
+}
+
+func getPlatform(registration *model.LTIRegistration) *oidc.Provider {
+    platformsLock.Lock();
+    defer platformsLock.Unlock();
+
+    platform := platforms[registration.KeySetURL];
+    if ((platform == nil) || (platform.Issuer != registration.Issuer)) {
+        platform = oidc.NewProvider(registration.Issuer, registration.KeySetURL);
+        platforms[registration.KeySetURL] = platform;
+    }
+
+    return platform;
+}
+
+func (this *launchClaims) validate(registration *model.LTIRegistration, nonce string, now time.Time) error {
+    if (this.Issuer != registration.Issuer) {
+        return fmt.Errorf("LTI launch has the wrong issuer. Expected: '%s', Actual: '%s'.", registration.Issuer, this.Issuer);
+    }
+
+    if (!this.Audience.Contains(registration.ClientID)) {
+        return fmt.Errorf("LTI launch is not for this client ('%s'), audience: '%v'.", registration.ClientID, this.Audience);
+    }
+
+    if ((len(this.Audience) > 1) && (this.AuthorizedParty != registration.ClientID)) {
+        return fmt.Errorf("LTI launch has multiple audiences, but is not authorized for this client ('%s').", registration.ClientID);
+    }
+
+    err := oidc.CheckTokenTimes(this.Expiration, this.IssuedAt, now);
+    if (err != nil) {
+        return err;
+    }
+
+    if (subtle.ConstantTimeCompare([]byte(this.Nonce), []byte(nonce)) != 1) {
+        return fmt.Errorf("LTI launch has the wrong nonce.");
+    }
+
+    if (this.Version != LTI_VERSION) {
+        return fmt.Errorf("Unsupported LTI version: '%s'.", this.Version);
+    }
+
+    if (this.MessageType != MESSAGE_TYPE_RESOURCE_LINK) {
+        return fmt.Errorf("Unsupported LTI message type: '%s'.", this.MessageType);
+    }
+
+    if (this.Subject == "") {
+        return fmt.Errorf("LTI launch does not have a subject (user).");
+    }
+
+    if ((registration.DeploymentID != "") && (this.DeploymentID != registration.DeploymentID)) {
+        return fmt.Errorf("LTI launch is from an unknown deployment: '%s'.", this.DeploymentID);
+    }
+
+    return nil;
+}
+
+// Find the (single) course this launch is for.
+// Only courses with the (verified) launch registration are considered.
+// Courses are matched by their LTI context ID, or by LMS course ID (using the context ID).
+func (this *launchClaims) getCourse(registration *model.LTIRegistration) (*model.Course, error) {
+    contextID := "";
+    if (this.Context != nil) {
+        contextID = this.Context.ID;
+    }
+
+    lmsCourseID := contextID;
+
+    courses, err := getRegisteredCourses(registration.Issuer, registration.ClientID);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    var match *model.Course = nil;
+    for _, course := range courses {
+        courseRegistration := getCourseRegistration(course);
+        if (!registration.SamePlatform(courseRegistration)) {
+            continue;
+        }
+
+        matches := false;
+        if (courseRegistration.ContextID != "") {
+            matches = (courseRegistration.ContextID == contextID);
+        } else {
+            matches = ((lmsCourseID != "") && (course.GetLMSAdapter().LMSCourseID == lmsCourseID));
+        }
+
+        if (!matches) {
+            continue;
+        }
+
+        if (match != nil) {
+            return nil, fmt.Errorf("LTI launch (context '%s') matches multiple courses: '%s' and '%s'.", contextID, match.GetID(), course.GetID());
+        }
+
+        match = course;
+    }
+
+    if (match == nil) {
+        return nil, fmt.Errorf("LTI launch (context '%s', LMS course '%s') does not match any course.", contextID, lmsCourseID);
+    }
+
+    return match, nil;
+}
+
+// Find the course user this launch is for.
+// Users are matched by the LTI ID saved from a previous launch.
+// On a user's first launch, they are matched (and their LTI ID is saved)
+// only by the platform's claims about the user: the subject (as an LMS ID) or the email.
+// Any other user must have their LTI ID set by a course admin.
+// A user that is already linked to a different LTI ID is never relinked.
+func (this *launchClaims) getUser(course *model.Course) (*model.User, error) {
+    users, err := db.GetUsers(course);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get users for course '%s': '%w'.", course.GetID(), err);
+    }
+
+    for _, user := range users {
+        if (user.LTIID == this.Subject) {
+            return user, nil;
+        }
+    }
+
+    var match *model.User = nil;
+    for _, user := range users {
+        matches := (user.LMSID == this.Subject);
+        matches = matches || ((this.Email != "") && strings.EqualFold(user.Email, this.Email));
+
+        if (!matches) {
+            continue;
+        }
+
+        if (match != nil) {
+            return nil, fmt.Errorf("LTI launch user ('%s', email '%s') matches multiple users in course '%s': '%s' and '%s'.",
+                    this.Subject, this.Email, course.GetID(), match.Email, user.Email);
+        }
+
+        match = user;
+    }
+
+    if (match == nil) {
+        return nil, fmt.Errorf("LTI launch user ('%s', email '%s') is not linked to any user in course '%s' (a course admin can set the user's LTI ID).",
+                this.Subject, this.Email, course.GetID());
+    }
+
+    if (match.LTIID != "") {
+        return nil, fmt.Errorf("LTI launch user ('%s', email '%s') matches user '%s' in course '%s', who is already linked to a different LTI user.",
+                this.Subject, this.Email, match.Email, course.GetID());
+    }
+
+    match.LTIID = this.Subject;
+
+    err = db.SaveUser(course, match);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to save LTI ID for user '%s': '%w'.", match.Email, err);
+    }
+
+    log.Debug("Linked user to LTI ID.", course, log.NewUserAttr(match.Email));
+
+    return match, nil;
+}
+
+// Find the assignment this launch is for (nil if the launch is not for an assignment).
+// Assignments are matched by ID or LMS ID (from custom parameters).
+// If the course posts scores, the assignment's line item is saved.
+func (this *launchClaims) getAssignment(course *model.Course) (*model.Assignment, error) {
+    var assignment *model.Assignment = nil;
+
+    assignmentID := this.Custom[CUSTOM_ASSIGNMENT_ID];
+    lmsAssignmentID := this.Custom[CUSTOM_LMS_ASSIGNMENT_ID];
+
+    if (assignmentID != "") {
+        assignment = course.GetAssignment(assignmentID);
+        if (assignment == nil) {
+            return nil, fmt.Errorf("LTI launch is for an unknown assignment: '%s'.", assignmentID);
+        }
+    } else if (lmsAssignmentID != "") {
+        for _, otherAssignment := range course.GetAssignments() {
+            if (otherAssignment.LMSID == lmsAssignmentID) {
+                assignment = otherAssignment;
+                break;
+            }
+        }
+
+        if (assignment == nil) {
+            return nil, fmt.Errorf("LTI launch is for an unknown LMS assignment: '%s'.", lmsAssignmentID);
+        }
+    } else {
+        return nil, nil;
+    }
+
+    if (!getCourseRegistration(course).PostScores || (this.Endpoint == nil)) {
+        return assignment, nil;
+    }
+
+    if ((this.Endpoint.LineItem == "") || (this.Endpoint.LineItem == assignment.LTILineItem)) {
+        return assignment, nil;
+    }
+
+    assignment.LTILineItem = this.Endpoint.LineItem;
+
+    err := db.SaveCourse(course);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to save LTI line item for assignment '%s': '%w'.", assignment.GetID(), err);
+    }
+
+    log.Debug("Linked assignment to LTI line item.", assignment, log.NewAttr("line-item", assignment.LTILineItem));
+
+    return assignment, nil;
+}
@@ -0,0 +1,361 @@
+package lti
+
+import (
+    "net/url"
+    "strings"
+    "testing"
+
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+)
+
+func TestLaunchBase(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := StartMockPlatform();
+    defer platform.Close();
+
+    course := platform.MustRegisterTestCourse(true);
+    course.GetAssignment(db.TEST_ASSIGNMENT_ID).LMSID = "lms-hw0";
+
+    err := db.SaveCourse(course);
+    if (err != nil) {
+        test.Fatalf("Failed to save course: '%v'.", err);
+    }
+
+    lineItem := platform.GetLineItemURL("1");
+
+    testCases := []struct {
+        custom map[string]string
+        endpoint map[string]any
+        expectedAssignment string
+        expectedLineItem string
+    }{
+        {map[string]string{}, nil, "", ""},
+        {map[string]string{CUSTOM_ASSIGNMENT_ID: "hw0"}, nil, "hw0", ""},
+        {map[string]string{CUSTOM_LMS_ASSIGNMENT_ID: "lms-hw0"}, nil, "hw0", ""},
+        {map[string]string{CUSTOM_ASSIGNMENT_ID: "hw0"}, map[string]any{"lineitem": lineItem}, "hw0", lineItem},
+        {map[string]string{}, map[string]any{"lineitem": lineItem}, "", ""},
+    };
+
+    for i, testCase := range testCases {
+        state, nonce := startTestLogin(test, platform);
+        if (state == "") {
+            continue;
+        }
+
+        // The LTI subject is opaque, so the user is matched by email.
+        claims := platform.GetClaims(nonce, "lti-student", TEST_LMS_COURSE_ID, testCase.custom);
+        claims["email"] = "student@test.com";
+
+        if (testCase.endpoint != nil) {
+            claims["https://purl.imsglobal.org/spec/lti-ags/claim/endpoint"] = testCase.endpoint;
+        }
+
+        launch, err := FinishLaunch(state, platform.SignLaunch(claims));
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to finish launch: '%v'.", i, err);
+            continue;
+        }
+
+        if (launch.Course.GetID() != "course101") {
+            test.Errorf("Case %d: Unexpected course. Expected: 'course101', Actual: '%s'.", i, launch.Course.GetID());
+        }
+
+        if (launch.User.Email != "student@test.com") {
+            test.Errorf("Case %d: Unexpected user: '%s'.", i, launch.User.Email);
+        }
+
+        assignmentID := "";
+        if (launch.Assignment != nil) {
+            assignmentID = launch.Assignment.GetID();
+        }
+
+        if (assignmentID != testCase.expectedAssignment) {
+            test.Errorf("Case %d: Unexpected assignment. Expected: '%s', Actual: '%s'.", i, testCase.expectedAssignment, assignmentID);
+        }
+
+        user, err := db.GetUser(launch.Course, "student@test.com");
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to get user: '%v'.", i, err);
+            continue;
+        }
+
+        if (user.LTIID != "lti-student") {
+            test.Errorf("Case %d: LTI ID was not saved. Expected: 'lti-student', Actual: '%s'.", i, user.LTIID);
+        }
+
+        if (testCase.expectedLineItem != "") {
+            lineItem := db.MustGetTestAssignment().LTILineItem;
+            if (lineItem != testCase.expectedLineItem) {
+                test.Errorf("Case %d: Unexpected line item. Expected: '%s', Actual: '%s'.", i, testCase.expectedLineItem, lineItem);
+            }
+        }
+
+        // A state can only be used once.
+        _, err = FinishLaunch(state, platform.SignLaunch(claims));
+        if (err == nil) {
+            test.Errorf("Case %d: Launch state was used twice.", i);
+        }
+    }
+}
+
+func TestLaunchBadClaims(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := StartMockPlatform();
+    defer platform.Close();
+
+    platform.MustRegisterTestCourse(false);
+
+    testCases := []struct {
+        key string
+        value any
+    }{
+        {"iss", "https://evil.test"},
+        {"aud", "some-other-client"},
+        {"aud", []string{TEST_CLIENT_ID, "some-other-client"}},
+        {"exp", 1},
+        {"nonce", "wrong-nonce"},
+        {"sub", ""},
+        {"sub", "unknown-user"},
+        {"https://purl.imsglobal.org/spec/lti/claim/version", "1.1"},
+        {"https://purl.imsglobal.org/spec/lti/claim/message_type", "LtiDeepLinkingRequest"},
+        {"https://purl.imsglobal.org/spec/lti/claim/deployment_id", "other-deployment"},
+        {"https://purl.imsglobal.org/spec/lti/claim/context", map[string]any{"id": "other-course"}},
+        {"https://purl.imsglobal.org/spec/lti/claim/custom", map[string]string{CUSTOM_ASSIGNMENT_ID: "zzz"}},
+        {"https://purl.imsglobal.org/spec/lti/claim/custom", map[string]string{CUSTOM_LMS_ASSIGNMENT_ID: "zzz"}},
+    };
+
+    for i, testCase := range testCases {
+        state, nonce := startTestLogin(test, platform);
+        if (state == "") {
+            continue;
+        }
+
+        claims := platform.GetClaims(nonce, "lms-student@test.com", TEST_LMS_COURSE_ID, nil);
+        claims[testCase.key] = testCase.value;
+
+        _, err := FinishLaunch(state, platform.SignLaunch(claims));
+        if (err == nil) {
+            test.Errorf("Case %d: Bad launch ('%s') did not fail.", i, testCase.key);
+        }
+    }
+
+    // A launch signed by another key.
+    state, nonce := startTestLogin(test, platform);
+    claims := platform.GetClaims(nonce, "lms-student@test.com", TEST_LMS_COURSE_ID, nil);
+
+    otherPlatform := StartMockPlatform();
+    token := otherPlatform.SignLaunch(claims);
+    otherPlatform.Close();
+
+    _, err := FinishLaunch(state, token);
+    if (err == nil) {
+        test.Fatalf("Launch signed by the wrong key did not fail.");
+    }
+
+    // An unknown state.
+    _, err = FinishLaunch("unknown-state", platform.SignLaunch(claims));
+    if (err == nil) {
+        test.Fatalf("Launch with an unknown state did not fail.");
+    }
+}
+
+func TestLaunchNoRelink(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := StartMockPlatform();
+    defer platform.Close();
+
+    course := platform.MustRegisterTestCourse(false);
+
+    user, err := db.GetUser(course, "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get user: '%v'.", err);
+    }
+
+    user.LTIID = "lti-student";
+
+    err = db.SaveUser(course, user);
+    if (err != nil) {
+        test.Fatalf("Failed to save user: '%v'.", err);
+    }
+
+    // Another LTI user with the student's email.
+    state, nonce := startTestLogin(test, platform);
+    claims := platform.GetClaims(nonce, "lti-other", TEST_LMS_COURSE_ID, nil);
+    claims["email"] = "student@test.com";
+
+    _, err = FinishLaunch(state, platform.SignLaunch(claims));
+    if (err == nil) {
+        test.Fatalf("Launch for an already linked user did not fail.");
+    }
+
+    user, err = db.GetUser(course, "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get user: '%v'.", err);
+    }
+
+    if (user.LTIID != "lti-student") {
+        test.Fatalf("User was relinked. Expected: 'lti-student', Actual: '%s'.", user.LTIID);
+    }
+}
+
+func TestLaunchCustomParamsNotTrusted(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := StartMockPlatform();
+    defer platform.Close();
+
+    course := platform.MustRegisterTestCourse(false);
+
+    // Course staff setting custom parameters to launch as an unlinked student.
+    state, nonce := startTestLogin(test, platform);
+    custom := map[string]string{"lms_user_id": "lms-student@test.com", "lms_course_id": TEST_LMS_COURSE_ID};
+    claims := platform.GetClaims(nonce, "lti-staff", TEST_LMS_COURSE_ID, custom);
+
+    _, err := FinishLaunch(state, platform.SignLaunch(claims));
+    if (err == nil) {
+        test.Fatalf("Launch using custom user parameters did not fail.");
+    }
+
+    user, err := db.GetUser(course, "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get user: '%v'.", err);
+    }
+
+    if (user.LTIID != "") {
+        test.Fatalf("User was linked from custom parameters: '%s'.", user.LTIID);
+    }
+}
+
+func TestGetRegistrationConflict(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := StartMockPlatform();
+    defer platform.Close();
+
+    platform.MustRegisterTestCourse(false);
+
+    _, err := getRegistration(platform.Server.URL, TEST_CLIENT_ID);
+    if (err != nil) {
+        test.Fatalf("Failed to get registration: '%v'.", err);
+    }
+
+    // Another course that uses the same issuer and client, but its own key set.
+    otherCourse := db.MustGetCourse("course-languages");
+    registration := platform.GetRegistration(false);
+    registration.KeySetURL = "https://evil.test/keys";
+    otherCourse.LMS = &model.LMSAdapter{
+        Type: model.LMS_TYPE_TEST,
+        LTI: registration,
+    };
+
+    err = db.SaveCourse(otherCourse);
+    if (err != nil) {
+        test.Fatalf("Failed to save course: '%v'.", err);
+    }
+
+    _, err = getRegistration(platform.Server.URL, TEST_CLIENT_ID);
+    if (err == nil) {
+        test.Fatalf("Conflicting registrations did not fail.");
+    }
+}
+
+func TestStartLoginBase(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := StartMockPlatform();
+    defer platform.Close();
+
+    platform.MustRegisterTestCourse(false);
+
+    request := &LoginRequest{
+        Issuer: platform.Server.URL,
+        LoginHint: "some-hint",
+        MessageHint: "some-message-hint",
+    };
+
+    authURL, err := StartLogin(request);
+    if (err != nil) {
+        test.Fatalf("Failed to start login: '%v'.", err);
+    }
+
+    if (!strings.HasPrefix(authURL, platform.Server.URL + "/auth?")) {
+        test.Fatalf("Unexpected auth URL: '%s'.", authURL);
+    }
+
+    parsedURL, err := url.Parse(authURL);
+    if (err != nil) {
+        test.Fatalf("Failed to parse auth URL: '%v'.", err);
+    }
+
+    query := parsedURL.Query();
+
+    expected := map[string]string{
+        "scope": "openid",
+        "response_type": "id_token",
+        "response_mode": "form_post",
+        "prompt": "none",
+        "client_id": TEST_CLIENT_ID,
+        "redirect_uri": TEST_LAUNCH_URL,
+        "login_hint": "some-hint",
+        "lti_message_hint": "some-message-hint",
+    };
+
+    for key, value := range expected {
+        if (query.Get(key) != value) {
+            test.Errorf("Unexpected value for '%s'. Expected: '%s', Actual: '%s'.", key, value, query.Get(key));
+        }
+    }
+
+    if ((query.Get("state") == "") || (query.Get("nonce") == "")) {
+        test.Fatalf("Missing state or nonce: '%s'.", authURL);
+    }
+}
+
+func TestStartLoginBadRequest(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := StartMockPlatform();
+    defer platform.Close();
+
+    platform.MustRegisterTestCourse(false);
+
+    testCases := []*LoginRequest{
+        &LoginRequest{Issuer: platform.Server.URL},
+        &LoginRequest{LoginHint: "some-hint"},
+        &LoginRequest{Issuer: "https://unknown.test", LoginHint: "some-hint"},
+        &LoginRequest{Issuer: platform.Server.URL, LoginHint: "some-hint", ClientID: "unknown-client"},
+    };
+
+    for i, testCase := range testCases {
+        _, err := StartLogin(testCase);
+        if (err == nil) {
+            test.Errorf("Case %d: Bad login request did not fail.", i);
+        }
+    }
+}
+
+// Start a login and get the state and nonce (empty on error).
+func startTestLogin(test *testing.T, platform *MockPlatform) (string, string) {
+    request := &LoginRequest{
+        Issuer: platform.Server.URL,
+        LoginHint: "some-hint",
+    };
+
+    authURL, err := StartLogin(request);
+    if (err != nil) {
+        test.Errorf("Failed to start login: '%v'.", err);
+        return "", "";
+    }
+
+    parsedURL, err := url.Parse(authURL);
+    if (err != nil) {
+        test.Errorf("Failed to parse auth URL: '%v'.", err);
+        return "", "";
+    }
+
+    return parsedURL.Query().Get("state"), parsedURL.Query().Get("nonce");
+}
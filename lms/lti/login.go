@@ -0,0 +1,202 @@
+package lti
+
+// LTI 1.3 lets an LMS (the "platform") launch this server (the "tool"), e.g., from a link in a course.
+// A launch is an OIDC login that the LMS starts:
+// the LMS sends the user to our login endpoint (StartLogin()),
+// we send them back to the LMS's auth endpoint,
+// and the LMS posts a signed ID token (with LTI claims) to our launch endpoint (FinishLaunch()).
+//
+// LMS IDs in launches are not always the same as the IDs used by the LMS's API
+// (e.g., Canvas uses opaque IDs for LTI contexts and users).
+// So, courses and users can be matched using custom parameters that are set when the tool is registered with the LMS
+// (see the CUSTOM_* constants).
+
+import (
+    "fmt"
+    "net/url"
+    "slices"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/oidc"
+    "github.com/edulinq/autograder/util"
+)
+
+// The parameters an LMS sends to start a launch.
+type LoginRequest struct {
+    Issuer string
+    LoginHint string
+    MessageHint string
+    TargetLinkURI string
+    // May be empty if there is only one registration for the issuer.
+    ClientID string
+}
+
+// A launch that was started (the user was sent to the LMS), but not finished.
+type pendingLaunch struct {
+    Issuer string
+    ClientID string
+    Nonce string
+    Expiration time.Time
+}
+
+var (
+    pendingLaunchesLock sync.Mutex;
+    // Keyed by state.
+    pendingLaunches map[string]*pendingLaunch = make(map[string]*pendingLaunch);
+)
+
+// Start a launch and get the URL (at the LMS) to send the user to.
+// Unlike oidc.StartLogin(), the state is not tied to the user's browser (with a cookie),
+// since launches usually happen in an iframe (where cookies are often blocked).
+// Instead, states can only be used once and the nonce is checked.
+func StartLogin(request *LoginRequest) (string, error) {
+    if ((request.Issuer == "") || (request.LoginHint == "")) {
+        return "", fmt.Errorf("LTI login requests must have an issuer and login hint.");
+    }
+
+    registration, err := getRegistration(request.Issuer, request.ClientID);
+    if (err != nil) {
+        return "", err;
+    }
+
+    state, err := util.RandHex(oidc.RANDOM_VALUE_LENGTH);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to generate state: '%w'.", err);
+    }
+
+    nonce, err := util.RandHex(oidc.RANDOM_VALUE_LENGTH);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to generate nonce: '%w'.", err);
+    }
+
+    authURL, err := url.Parse(registration.AuthLoginURL);
+    if (err != nil) {
+        return "", fmt.Errorf("LTI auth login URL is not a valid URL: '%w'.", err);
+    }
+
+    query := authURL.Query();
+    query.Set("scope", "openid");
+    query.Set("response_type", "id_token");
+    query.Set("response_mode", "form_post");
+    query.Set("prompt", "none");
+    query.Set("client_id", registration.ClientID);
+    query.Set("redirect_uri", config.LTI_LAUNCH_URL.Get());
+    query.Set("login_hint", request.LoginHint);
+    query.Set("state", state);
+    query.Set("nonce", nonce);
+
+    if (request.MessageHint != "") {
+        query.Set("lti_message_hint", request.MessageHint);
+    }
+
+    authURL.RawQuery = query.Encode();
+
+    now := time.Now();
+
+    pendingLaunchesLock.Lock();
+    defer pendingLaunchesLock.Unlock();
+
+    // Forget abandoned launches.
+    for otherState, launch := range pendingLaunches {
+        if (!now.Before(launch.Expiration)) {
+            delete(pendingLaunches, otherState);
+        }
+    }
+
+    pendingLaunches[state] = &pendingLaunch{
+        Issuer: registration.Issuer,
+        ClientID: registration.ClientID,
+        Nonce: nonce,
+        Expiration: now.Add(oidc.LOGIN_TIMEOUT),
+    };
+
+    return authURL.String(), nil;
+}
+
+func takePendingLaunch(state string) *pendingLaunch {
+    pendingLaunchesLock.Lock();
+    defer pendingLaunchesLock.Unlock();
+
+    launch := pendingLaunches[state];
+    delete(pendingLaunches, state);
+
+    if ((launch == nil) || !time.Now().Before(launch.Expiration)) {
+        return nil;
+    }
+
+    return launch;
+}
+
+// Get all the courses registered with this issuer and client.
+// An empty client ID matches any client.
+func getRegisteredCourses(issuer string, clientID string) ([]*model.Course, error) {
+    courses, err := db.GetCourses();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to get courses: '%w'.", err);
+    }
+
+    results := make([]*model.Course, 0);
+    for _, course := range courses {
+        registration := getCourseRegistration(course);
+        if (registration == nil) {
+            continue;
+        }
+
+        if ((registration.Issuer != issuer) || ((clientID != "") && (registration.ClientID != clientID))) {
+            continue;
+        }
+
+        results = append(results, course);
+    }
+
+    slices.SortFunc(results, func(a *model.Course, b *model.Course) int {
+        return strings.Compare(a.GetID(), b.GetID());
+    });
+
+    return results, nil;
+}
+
+// Get the registration for an issuer and client.
+// All courses registered with the same issuer and client must have the same registration (other than the context),
+// otherwise a single course could (e.g.) supply its own key set and sign launches for other courses.
+func getRegistration(issuer string, clientID string) (*model.LTIRegistration, error) {
+    courses, err := getRegisteredCourses(issuer, clientID);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    if (len(courses) == 0) {
+        return nil, fmt.Errorf("No courses are registered with LTI issuer '%s' and client '%s'.", issuer, clientID);
+    }
+
+    registration := getCourseRegistration(courses[0]);
+    for _, course := range courses[1:] {
+        otherRegistration := getCourseRegistration(course);
+
+        if (otherRegistration.ClientID != registration.ClientID) {
+            return nil, fmt.Errorf("LTI issuer '%s' has multiple clients, a client ID is required.", issuer);
+        }
+
+        if (!registration.SamePlatform(otherRegistration)) {
+            return nil, fmt.Errorf("Courses '%s' and '%s' have conflicting LTI registrations for issuer '%s' and client '%s'.",
+                    courses[0].GetID(), course.GetID(), issuer, registration.ClientID);
+        }
+    }
+
+    return registration, nil;
+}
+
+// Returns nil if the course is not registered for LTI.
+func getCourseRegistration(course *model.Course) *model.LTIRegistration {
+    adapter := course.GetLMSAdapter();
+    if (adapter == nil) {
+        return nil;
+    }
+
+    return adapter.LTI;
+}
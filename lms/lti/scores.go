@@ -0,0 +1,278 @@
+package lti
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "sync"
+    "time"
+
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/oidc"
+    "github.com/edulinq/autograder/util"
+)
+
+const (
+    SCOPE_SCORE = "https://purl.imsglobal.org/spec/lti-ags/scope/score";
+    SCORE_CONTENT_TYPE = "application/vnd.ims.lis.v1.score+json";
+    CLIENT_ASSERTION_TYPE = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer";
+
+    // How long the tool's client assertions are good for.
+    CLIENT_ASSERTION_DURATION = 5 * time.Minute;
+
+    // The longest a single request to the LMS (for a token or to post a score) may take.
+    REQUEST_TIMEOUT = 30 * time.Second;
+
+    // How many scores can be waiting to be posted before new ones are dropped.
+    SCORE_QUEUE_SIZE = 1000;
+    // How many times to try posting a queued score (and how long to wait between tries).
+    SCORE_POST_ATTEMPTS = 3;
+    SCORE_RETRY_DELAY = 10 * time.Second;
+)
+
+type clientAssertionClaims struct {
+    Issuer string `json:"iss"`
+    Subject string `json:"sub"`
+    Audience string `json:"aud"`
+    // Unix time (seconds).
+    IssuedAt int64 `json:"iat"`
+    Expiration int64 `json:"exp"`
+    ID string `json:"jti"`
+}
+
+type accessTokenResponse struct {
+    AccessToken string `json:"access_token"`
+    // Seconds.
+    ExpiresIn int64 `json:"expires_in"`
+}
+
+type accessToken struct {
+    Token string
+    Expiration time.Time
+}
+
+type score struct {
+    UserID string `json:"userId"`
+    ScoreGiven float64 `json:"scoreGiven"`
+    ScoreMaximum float64 `json:"scoreMaximum"`
+    ActivityProgress string `json:"activityProgress"`
+    GradingProgress string `json:"gradingProgress"`
+    Timestamp string `json:"timestamp"`
+}
+
+// A score waiting to be posted.
+type queuedScore struct {
+    assignment *model.Assignment
+    email string
+    scoreGiven float64
+    scoreMaximum float64
+}
+
+var (
+    accessTokensLock sync.Mutex;
+    // Keyed by "<token url>::<client id>".
+    accessTokens map[string]*accessToken = make(map[string]*accessToken);
+
+    httpClient *http.Client = &http.Client{Timeout: REQUEST_TIMEOUT};
+
+    scoreQueue chan *queuedScore = make(chan *queuedScore, SCORE_QUEUE_SIZE);
+    scoreQueueStartOnce sync.Once;
+)
+
+// Post a score (see PostScore()) in the background, retrying on failure.
+// Scores are posted one at a time (in the order they were queued),
+// and will be dropped (and logged) if the queue is full.
+func QueueScore(assignment *model.Assignment, email string, scoreGiven float64, scoreMaximum float64) {
+    scoreQueueStartOnce.Do(func() {
+        go postQueuedScores();
+    });
+
+    item := &queuedScore{
+        assignment: assignment,
+        email: email,
+        scoreGiven: scoreGiven,
+        scoreMaximum: scoreMaximum,
+    };
+
+    select {
+        case scoreQueue <- item:
+        default:
+            log.Warn("LTI score queue is full, dropping score.", assignment, log.NewUserAttr(email));
+    }
+}
+
+// Post queued scores forever.
+func postQueuedScores() {
+    for item := range scoreQueue {
+        for attempt := 1; ; attempt++ {
+            _, err := PostScore(item.assignment, item.email, item.scoreGiven, item.scoreMaximum);
+            if (err == nil) {
+                break;
+            }
+
+            if (attempt >= SCORE_POST_ATTEMPTS) {
+                log.Warn("Failed to post LTI score.", err, item.assignment, log.NewUserAttr(item.email), log.NewAttr("attempts", attempt));
+                break;
+            }
+
+            time.Sleep(SCORE_RETRY_DELAY);
+        }
+    }
+}
+
+// Post a user's score for an assignment back to the LMS (via LTI Assignment and Grade Services).
+// Does nothing if the course does not post scores,
+// or if the assignment or user has not been launched (so there is no line item or LTI ID).
+// Returns true if a score was posted.
+func PostScore(assignment *model.Assignment, email string, scoreGiven float64, scoreMaximum float64) (bool, error) {
+    course := assignment.GetCourse();
+
+    registration := getCourseRegistration(course);
+    if ((registration == nil) || !registration.PostScores || (assignment.LTILineItem == "")) {
+        return false, nil;
+    }
+
+    user, err := db.GetUser(course, email);
+    if (err != nil) {
+        return false, fmt.Errorf("Failed to get user '%s': '%w'.", email, err);
+    }
+
+    if ((user == nil) || (user.LTIID == "")) {
+        return false, nil;
+    }
+
+    token, err := getAccessToken(registration);
+    if (err != nil) {
+        return false, err;
+    }
+
+    scoresURL, err := getScoresURL(assignment.LTILineItem);
+    if (err != nil) {
+        return false, err;
+    }
+
+    content := score{
+        UserID: user.LTIID,
+        ScoreGiven: scoreGiven,
+        ScoreMaximum: scoreMaximum,
+        ActivityProgress: "Completed",
+        GradingProgress: "FullyGraded",
+        Timestamp: time.Now().Format(time.RFC3339Nano),
+    };
+
+    body, err := util.ToJSON(content);
+    if (err != nil) {
+        return false, fmt.Errorf("Failed to serialize LTI score: '%w'.", err);
+    }
+
+    request, err := http.NewRequest("POST", scoresURL, bytes.NewBufferString(body));
+    if (err != nil) {
+        return false, fmt.Errorf("Failed to create LTI score request on URL '%s': '%w'.", scoresURL, err);
+    }
+
+    request.Header.Set("Content-Type", SCORE_CONTENT_TYPE);
+    request.Header.Set("Authorization", "Bearer " + token);
+
+    response, err := httpClient.Do(request);
+    if (err != nil) {
+        return false, fmt.Errorf("Failed to post LTI score to URL '%s': '%w'.", scoresURL, err);
+    }
+    defer response.Body.Close();
+
+    // LMSs respond with different successful codes (e.g., 200 or 204).
+    if ((response.StatusCode < 200) || (response.StatusCode >= 300)) {
+        responseBody, _ := io.ReadAll(response.Body);
+        return false, fmt.Errorf("Got a non-OK status code '%d' when posting LTI score to URL '%s': '%s'.",
+                response.StatusCode, scoresURL, string(responseBody));
+    }
+
+    return true, nil;
+}
+
+// The scores endpoint is the line item's URL with "/scores" added to the path (before any query).
+func getScoresURL(lineItem string) (string, error) {
+    scoresURL, err := url.Parse(lineItem);
+    if (err != nil) {
+        return "", fmt.Errorf("LTI line item is not a valid URL: '%w'.", err);
+    }
+
+    scoresURL.Path += "/scores";
+    return scoresURL.String(), nil;
+}
+
+// Get an access token (for posting scores) from the LMS.
+// The tool authenticates with a client assertion (a JWT signed by the tool's key).
+func getAccessToken(registration *model.LTIRegistration) (string, error) {
+    key := registration.TokenURL + "::" + registration.ClientID;
+    now := time.Now();
+
+    accessTokensLock.Lock();
+    defer accessTokensLock.Unlock();
+
+    token := accessTokens[key];
+    if ((token != nil) && now.Add(oidc.CLOCK_SKEW).Before(token.Expiration)) {
+        return token.Token, nil;
+    }
+
+    toolKey, keyID, err := getToolKey();
+    if (err != nil) {
+        return "", err;
+    }
+
+    claims := clientAssertionClaims{
+        Issuer: registration.ClientID,
+        Subject: registration.ClientID,
+        Audience: registration.TokenURL,
+        IssuedAt: now.Unix(),
+        Expiration: now.Add(CLIENT_ASSERTION_DURATION).Unix(),
+        ID: util.UUID(),
+    };
+
+    assertion, err := oidc.SignToken(toolKey, keyID, claims);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to create LTI client assertion: '%w'.", err);
+    }
+
+    form := url.Values{};
+    form.Set("grant_type", "client_credentials");
+    form.Set("client_assertion_type", CLIENT_ASSERTION_TYPE);
+    form.Set("client_assertion", assertion);
+    form.Set("scope", SCOPE_SCORE);
+
+    httpResponse, err := httpClient.PostForm(registration.TokenURL, form);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to get LTI access token from URL '%s': '%w'.", registration.TokenURL, err);
+    }
+    defer httpResponse.Body.Close();
+
+    body, err := io.ReadAll(httpResponse.Body);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to read LTI access token response from URL '%s': '%w'.", registration.TokenURL, err);
+    }
+
+    if (httpResponse.StatusCode != http.StatusOK) {
+        return "", fmt.Errorf("Got a non-OK status code '%d' when getting LTI access token from URL '%s': '%s'.",
+                httpResponse.StatusCode, registration.TokenURL, string(body));
+    }
+
+    var response accessTokenResponse;
+    err = util.JSONFromString(string(body), &response);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to parse LTI access token response: '%w'.", err);
+    }
+
+    if (response.AccessToken == "") {
+        return "", fmt.Errorf("LTI access token response does not have an access token.");
+    }
+
+    accessTokens[key] = &accessToken{
+        Token: response.AccessToken,
+        Expiration: now.Add(time.Duration(response.ExpiresIn) * time.Second),
+    };
+
+    return response.AccessToken, nil;
+}
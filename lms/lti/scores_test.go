@@ -0,0 +1,188 @@
+package lti
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/edulinq/autograder/db"
+)
+
+func TestPostScoreBase(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := StartMockPlatform();
+    defer platform.Close();
+
+    course := platform.MustRegisterTestCourse(true);
+    assignment := course.GetAssignment(db.TEST_ASSIGNMENT_ID);
+
+    // The assignment has not been launched yet.
+    posted, err := PostScore(assignment, "student@test.com", 1, 2);
+    if (err != nil) {
+        test.Fatalf("Failed to post score without a line item: '%v'.", err);
+    }
+
+    if (posted) {
+        test.Fatalf("Score was posted without a line item.");
+    }
+
+    assignment.LTILineItem = platform.GetLineItemURL("1") + "?type_id=1";
+
+    // The user has not been launched yet.
+    posted, err = PostScore(assignment, "student@test.com", 1, 2);
+    if (err != nil) {
+        test.Fatalf("Failed to post score without an LTI ID: '%v'.", err);
+    }
+
+    if (posted) {
+        test.Fatalf("Score was posted without an LTI ID.");
+    }
+
+    user := db.MustGetUsers(course)["student@test.com"];
+    user.LTIID = "lti-student";
+
+    err = db.SaveUser(course, user);
+    if (err != nil) {
+        test.Fatalf("Failed to save user: '%v'.", err);
+    }
+
+    // Post twice to use a cached access token.
+    for i := 0; i < 2; i++ {
+        posted, err = PostScore(assignment, "student@test.com", float64(i), 2);
+        if (err != nil) {
+            test.Fatalf("Case %d: Failed to post score: '%v'.", i, err);
+        }
+
+        if (!posted) {
+            test.Fatalf("Case %d: Score was not posted.", i);
+        }
+    }
+
+    scores := platform.GetScores("1");
+    if (len(scores) != 2) {
+        test.Fatalf("Unexpected number of scores. Expected: 2, Actual: %d.", len(scores));
+    }
+
+    expected := map[string]any{
+        "userId": "lti-student",
+        "scoreGiven": 1.0,
+        "scoreMaximum": 2.0,
+        "activityProgress": "Completed",
+        "gradingProgress": "FullyGraded",
+    };
+
+    for key, value := range expected {
+        if (scores[1][key] != value) {
+            test.Errorf("Unexpected value for '%s'. Expected: '%v', Actual: '%v'.", key, value, scores[1][key]);
+        }
+    }
+}
+
+func TestPostScoreDisabled(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := StartMockPlatform();
+    defer platform.Close();
+
+    course := platform.MustRegisterTestCourse(false);
+    assignment := course.GetAssignment(db.TEST_ASSIGNMENT_ID);
+    assignment.LTILineItem = platform.GetLineItemURL("1");
+
+    posted, err := PostScore(assignment, "student@test.com", 1, 2);
+    if (err != nil) {
+        test.Fatalf("Failed to post score: '%v'.", err);
+    }
+
+    if (posted) {
+        test.Fatalf("Score was posted for a course that does not post scores.");
+    }
+}
+
+func TestQueueScore(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := StartMockPlatform();
+    defer platform.Close();
+
+    course := platform.MustRegisterTestCourse(true);
+    assignment := course.GetAssignment(db.TEST_ASSIGNMENT_ID);
+    assignment.LTILineItem = platform.GetLineItemURL("queued");
+
+    user := db.MustGetUsers(course)["student@test.com"];
+    user.LTIID = "lti-student";
+
+    err := db.SaveUser(course, user);
+    if (err != nil) {
+        test.Fatalf("Failed to save user: '%v'.", err);
+    }
+
+    QueueScore(assignment, "student@test.com", 1, 2);
+
+    for i := 0; i < 100; i++ {
+        if (len(platform.GetScores("queued")) > 0) {
+            break;
+        }
+
+        time.Sleep(50 * time.Millisecond);
+    }
+
+    scores := platform.GetScores("queued");
+    if (len(scores) != 1) {
+        test.Fatalf("Unexpected number of scores. Expected: 1, Actual: %d.", len(scores));
+    }
+
+    if (scores[0]["scoreGiven"] != 1.0) {
+        test.Fatalf("Unexpected score: '%v'.", scores[0]);
+    }
+}
+
+func TestPostScoreTimeout(test *testing.T) {
+    defer db.ResetForTesting();
+
+    platform := StartMockPlatform();
+    defer platform.Close();
+
+    // An LMS that never answers.
+    done := make(chan struct{});
+    hungServer := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+        <-done;
+    }));
+    defer hungServer.Close();
+    defer close(done);
+
+    oldClient := httpClient;
+    httpClient = &http.Client{Timeout: 100 * time.Millisecond};
+    defer func() {
+        httpClient = oldClient;
+    }();
+
+    course := platform.MustRegisterTestCourse(true);
+    course.LMS.LTI.TokenURL = hungServer.URL + "/token";
+
+    assignment := course.GetAssignment(db.TEST_ASSIGNMENT_ID);
+    assignment.LTILineItem = hungServer.URL + "/lineitems/1";
+
+    user := db.MustGetUsers(course)["student@test.com"];
+    user.LTIID = "lti-student";
+
+    err := db.SaveUser(course, user);
+    if (err != nil) {
+        test.Fatalf("Failed to save user: '%v'.", err);
+    }
+
+    start := time.Now();
+    posted, err := PostScore(assignment, "student@test.com", 1, 2);
+    if (err == nil) {
+        test.Fatalf("Did not get an error from a hung LMS.");
+    }
+
+    if (posted) {
+        test.Fatalf("Score was posted to a hung LMS.");
+    }
+
+    if (time.Since(start) > (5 * time.Second)) {
+        test.Fatalf("Posting to a hung LMS did not time out.");
+    }
+}
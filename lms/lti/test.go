@@ -0,0 +1,236 @@
+package lti
+
+import (
+    "crypto/rand"
+    "crypto/rsa"
+    "fmt"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
+    "github.com/edulinq/autograder/oidc"
+    "github.com/edulinq/autograder/util"
+)
+
+const (
+    TEST_CLIENT_ID = "autograder-lti-test";
+    TEST_DEPLOYMENT_ID = "test-deployment";
+    TEST_KEY_ID = "test-platform-key";
+    TEST_LAUNCH_URL = "http://autograder.test/api/v03/lms/lti/launch";
+    TEST_LMS_COURSE_ID = "lms-course101";
+)
+
+// A minimal LTI platform (LMS) for testing.
+// It serves its keys, gives out access tokens (to clients with valid assertions), and records posted scores.
+type MockPlatform struct {
+    Server *httptest.Server
+    Key *rsa.PrivateKey
+
+    lock sync.Mutex
+    accessToken string
+    // Line item path to the scores posted to it.
+    scores map[string][]map[string]any
+}
+
+// Start a mock platform and point the LTI config at this test server.
+// Callers should close the platform when done: `defer platform.Close();`.
+func StartMockPlatform() *MockPlatform {
+    key, err := rsa.GenerateKey(rand.Reader, 2048);
+    if (err != nil) {
+        panic(err);
+    }
+
+    platform := &MockPlatform{
+        Key: key,
+        accessToken: util.UUID(),
+        scores: make(map[string][]map[string]any),
+    };
+
+    mux := http.NewServeMux();
+    mux.HandleFunc("/keys", platform.handleKeys);
+    mux.HandleFunc("/tool-keys", platform.handleToolKeys);
+    mux.HandleFunc("/token", platform.handleToken);
+    mux.HandleFunc("/lineitems/", platform.handleScores);
+
+    platform.Server = httptest.NewServer(mux);
+
+    config.LTI_LAUNCH_URL.Set(TEST_LAUNCH_URL);
+
+    return platform;
+}
+
+func (this *MockPlatform) Close() {
+    this.Server.Close();
+
+    config.LTI_LAUNCH_URL.Set("");
+}
+
+// Get a registration for this platform.
+func (this *MockPlatform) GetRegistration(postScores bool) *model.LTIRegistration {
+    return &model.LTIRegistration{
+        Issuer: this.Server.URL,
+        ClientID: TEST_CLIENT_ID,
+        DeploymentID: TEST_DEPLOYMENT_ID,
+        AuthLoginURL: this.Server.URL + "/auth",
+        KeySetURL: this.Server.URL + "/keys",
+        TokenURL: this.Server.URL + "/token",
+        PostScores: postScores,
+    };
+}
+
+// Register the test course (COURSE101) with this platform,
+// and give its users LMS IDs ("lms-<email>").
+// Callers should reset the db when done: `defer db.ResetForTesting();`.
+func (this *MockPlatform) MustRegisterTestCourse(postScores bool) *model.Course {
+    course := db.MustGetTestCourse();
+    course.LMS = &model.LMSAdapter{
+        Type: model.LMS_TYPE_TEST,
+        LMSCourseID: TEST_LMS_COURSE_ID,
+        LTI: this.GetRegistration(postScores),
+    };
+
+    err := db.SaveCourse(course);
+    if (err != nil) {
+        panic(err);
+    }
+
+    users := db.MustGetUsers(course);
+    for _, user := range users {
+        user.LMSID = "lms-" + user.Email;
+    }
+
+    err = db.SaveUsers(course, users);
+    if (err != nil) {
+        panic(err);
+    }
+
+    return course;
+}
+
+func (this *MockPlatform) GetLineItemURL(id string) string {
+    return this.Server.URL + "/lineitems/" + id;
+}
+
+// Get launch claims that would pass verification.
+func (this *MockPlatform) GetClaims(nonce string, subject string, contextID string, custom map[string]string) map[string]any {
+    now := time.Now();
+
+    return map[string]any{
+        "iss": this.Server.URL,
+        "sub": subject,
+        "aud": TEST_CLIENT_ID,
+        "exp": now.Add(time.Hour).Unix(),
+        "iat": now.Unix(),
+        "nonce": nonce,
+        "https://purl.imsglobal.org/spec/lti/claim/message_type": MESSAGE_TYPE_RESOURCE_LINK,
+        "https://purl.imsglobal.org/spec/lti/claim/version": LTI_VERSION,
+        "https://purl.imsglobal.org/spec/lti/claim/deployment_id": TEST_DEPLOYMENT_ID,
+        "https://purl.imsglobal.org/spec/lti/claim/context": map[string]any{"id": contextID},
+        "https://purl.imsglobal.org/spec/lti/claim/custom": custom,
+    };
+}
+
+// Sign a launch (ID token) with the platform's key.
+func (this *MockPlatform) SignLaunch(claims map[string]any) string {
+    token, err := oidc.SignToken(this.Key, TEST_KEY_ID, claims);
+    if (err != nil) {
+        panic(err);
+    }
+
+    return token;
+}
+
+// Get the scores posted to a line item (by its ID).
+func (this *MockPlatform) GetScores(id string) []map[string]any {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    return this.scores["/lineitems/" + id];
+}
+
+func (this *MockPlatform) handleKeys(response http.ResponseWriter, request *http.Request) {
+    keySet := oidc.JSONWebKeySet{
+        Keys: []oidc.JSONWebKey{oidc.NewRSAJSONWebKey(TEST_KEY_ID, &this.Key.PublicKey)},
+    };
+
+    response.Write([]byte(util.MustToJSON(keySet)));
+}
+
+// The platform would normally be given the tool's key set URL when the tool is registered.
+func (this *MockPlatform) handleToolKeys(response http.ResponseWriter, request *http.Request) {
+    keySet, err := GetToolKeySet();
+    if (err != nil) {
+        http.Error(response, err.Error(), http.StatusInternalServerError);
+        return;
+    }
+
+    response.Write([]byte(util.MustToJSON(keySet)));
+}
+
+func (this *MockPlatform) handleToken(response http.ResponseWriter, request *http.Request) {
+    if ((request.PostFormValue("grant_type") != "client_credentials") ||
+            (request.PostFormValue("client_assertion_type") != CLIENT_ASSERTION_TYPE) ||
+            (request.PostFormValue("scope") != SCOPE_SCORE)) {
+        http.Error(response, `{"error": "invalid_request"}`, http.StatusBadRequest);
+        return;
+    }
+
+    var claims clientAssertionClaims;
+    tool := oidc.NewProvider(TEST_CLIENT_ID, this.Server.URL + "/tool-keys");
+    err := tool.VerifySignature(request.PostFormValue("client_assertion"), &claims);
+    if ((err != nil) ||
+            (claims.Issuer != TEST_CLIENT_ID) ||
+            (claims.Subject != TEST_CLIENT_ID) ||
+            (claims.Audience != (this.Server.URL + "/token"))) {
+        http.Error(response, `{"error": "invalid_client"}`, http.StatusUnauthorized);
+        return;
+    }
+
+    result := map[string]any{
+        "access_token": this.accessToken,
+        "token_type": "Bearer",
+        "expires_in": 3600,
+        "scope": SCOPE_SCORE,
+    };
+
+    response.Write([]byte(util.MustToJSON(result)));
+}
+
+func (this *MockPlatform) handleScores(response http.ResponseWriter, request *http.Request) {
+    lineItem, found := strings.CutSuffix(request.URL.Path, "/scores");
+    if ((request.Method != "POST") || !found) {
+        http.Error(response, "Not found.", http.StatusNotFound);
+        return;
+    }
+
+    if ((request.Header.Get("Authorization") != ("Bearer " + this.accessToken)) ||
+            (request.Header.Get("Content-Type") != SCORE_CONTENT_TYPE)) {
+        http.Error(response, "Unauthorized.", http.StatusUnauthorized);
+        return;
+    }
+
+    body, err := io.ReadAll(request.Body);
+    if (err != nil) {
+        http.Error(response, fmt.Sprintf("Failed to read body: '%v'.", err), http.StatusBadRequest);
+        return;
+    }
+
+    var score map[string]any;
+    err = util.JSONFromString(string(body), &score);
+    if (err != nil) {
+        http.Error(response, fmt.Sprintf("Bad score: '%v'.", err), http.StatusBadRequest);
+        return;
+    }
+
+    this.lock.Lock();
+    this.scores[lineItem] = append(this.scores[lineItem], score);
+    this.lock.Unlock();
+
+    response.WriteHeader(http.StatusNoContent);
+}
@@ -23,12 +23,19 @@ type Assignment struct {
     SortID string `json:"sort-id,omitempty"`
 
     DueDate common.Timestamp `json:"due-date,omitempty"`
+    // Submissions before the open date or after the close date are rejected.
+    OpenDate common.Timestamp `json:"open-date,omitempty"`
+    CloseDate common.Timestamp `json:"close-date,omitempty"`
     MaxPoints float64 `json:"max-points,omitempty"`
 
     LMSID string `json:"lms-id,omitempty"`
+    // The LTI line item (gradebook column) to post scores to.
+    // Set on the first LTI launch of this assignment.
+    LTILineItem string `json:"lti-line-item,omitempty"`
     LatePolicy *LateGradingPolicy `json:"late-policy,omitempty"`
 
     SubmissionLimit *SubmissionLimitInfo `json:"submission-limit,omitempty"`
+    SubmissionFileRules *SubmissionFileRules `json:"submission-file-rules,omitempty"`
 
     docker.ImageInfo
 
@@ -108,6 +115,20 @@ func (this *Assignment) Validate() error {
         return fmt.Errorf("Due date is not a valid timestamp: '%w'.", err);
     }
 
+    // Inherit open/close dates from course (or leave empty).
+    if (this.OpenDate.IsZero()) {
+        this.OpenDate = this.Course.OpenDate;
+    }
+
+    if (this.CloseDate.IsZero()) {
+        this.CloseDate = this.Course.CloseDate;
+    }
+
+    err = validateOpenCloseDates(this.OpenDate, this.CloseDate);
+    if (err != nil) {
+        return err;
+    }
+
     if (this.MaxPoints < 0.0) {
         return fmt.Errorf("Max points cannot be negative: %f.", this.MaxPoints);
     }
@@ -126,6 +147,16 @@ func (this *Assignment) Validate() error {
         }
     }
 
+    // Inherit submission file rules from course or leave nil.
+    if (this.SubmissionFileRules == nil) {
+        this.SubmissionFileRules = this.Course.SubmissionFileRules;
+    }
+
+    err = this.SubmissionFileRules.Validate();
+    if (err != nil) {
+        return fmt.Errorf("Failed to validate submission file rules: '%w'.", err);
+    }
+
     // Inherit late policy from course or default to empty.
     if (this.LatePolicy == nil) {
         if (this.Course.LatePolicy != nil) {
@@ -144,6 +175,16 @@ func (this *Assignment) Validate() error {
         return fmt.Errorf("Relative source dir must not be empty.")
     }
 
+    // Inherit the grading timeout from course (or leave it as the default).
+    if (this.ImageInfo.TimeoutSecs == 0) {
+        this.ImageInfo.TimeoutSecs = this.Course.TimeoutSecs;
+    }
+
+    // Inherit resource limits from course (or leave nil to use the server maximums).
+    if (this.ImageInfo.ResourceLimits == nil) {
+        this.ImageInfo.ResourceLimits = this.Course.ResourceLimits;
+    }
+
     this.ImageInfo.Name = this.ImageName();
     this.ImageInfo.BaseDir = this.GetSourceDir();
 
@@ -155,6 +196,28 @@ func (this *Assignment) Validate() error {
     return nil;
 }
 
+func validateOpenCloseDates(openDate common.Timestamp, closeDate common.Timestamp) error {
+    err := openDate.Validate();
+    if (err != nil) {
+        return fmt.Errorf("Open date is not a valid timestamp: '%w'.", err);
+    }
+
+    err = closeDate.Validate();
+    if (err != nil) {
+        return fmt.Errorf("Close date is not a valid timestamp: '%w'.", err);
+    }
+
+    if (openDate.IsZero() || closeDate.IsZero()) {
+        return nil;
+    }
+
+    if (!openDate.MustTime().Before(closeDate.MustTime())) {
+        return fmt.Errorf("Open date ('%s') must be before close date ('%s').", openDate, closeDate);
+    }
+
+    return nil;
+}
+
 func (this *Assignment) GetCacheDir() string {
     dir := filepath.Join(this.Course.GetCacheDir(), "assignment_" + this.ID);
     util.MkDir(dir);
@@ -43,3 +43,31 @@ func ReadAssignmentConfig(course *Course, path string) (*Assignment, error) {
 
     return &assignment, nil;
 }
+
+// Load an assignment from JSON text (e.g. as stored in a database) and add it to the course.
+// The assignment must already have a relative source dir (which all saved assignments will).
+func ReadAssignmentConfigFromJSON(course *Course, text string) (*Assignment, error) {
+    if (course == nil) {
+        return nil, fmt.Errorf("Cannot load an assignment without a course.");
+    }
+
+    var assignment Assignment;
+    err := util.JSONFromString(text, &assignment);
+    if (err != nil) {
+        return nil, fmt.Errorf("Could not load assignment config from JSON: '%w'.", err);
+    }
+
+    assignment.Course = course;
+
+    err = assignment.Validate();
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to validate assignment config '%s': '%w'.", assignment.ID, err);
+    }
+
+    err = course.AddAssignment(&assignment);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to add assignment '%s' to course: '%w'.", assignment.ID, err);
+    }
+
+    return &assignment, nil;
+}
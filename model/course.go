@@ -23,12 +23,25 @@ type Course struct {
 
     LMS *LMSAdapter `json:"lms,omitempty"`
 
+    // Common open/close dates that assignments can inherit.
+    OpenDate common.Timestamp `json:"open-date,omitempty"`
+    CloseDate common.Timestamp `json:"close-date,omitempty"`
+
     // A common late policy that assignments can inherit.
     LatePolicy *LateGradingPolicy `json:"late-policy,omitempty"`
 
     // A common submission limit that assignments can inherit.
     SubmissionLimit *SubmissionLimitInfo `json:"submission-limit,omitempty"`
 
+    // Common submission file rules that assignments can inherit.
+    SubmissionFileRules *SubmissionFileRules `json:"submission-file-rules,omitempty"`
+
+    // A common grading timeout (in seconds) that assignments can inherit.
+    TimeoutSecs int `json:"timeout-secs,omitempty"`
+
+    // Common grading container limits that assignments can inherit.
+    ResourceLimits *docker.ResourceLimits `json:"resource-limits,omitempty"`
+
     Backup []*tasks.BackupTask `json:"backup,omitempty"`
     CourseUpdate []*tasks.CourseUpdateTask `json:"course-update,omitempty"`
     Report []*tasks.ReportTask `json:"report,omitempty"`
@@ -107,6 +120,11 @@ func (this *Course) Validate() error {
         }
     }
 
+    err = validateOpenCloseDates(this.OpenDate, this.CloseDate);
+    if (err != nil) {
+        return err;
+    }
+
     if (this.LatePolicy != nil) {
         err = this.LatePolicy.Validate();
         if (err != nil) {
@@ -121,6 +139,20 @@ func (this *Course) Validate() error {
         }
     }
 
+    err = this.SubmissionFileRules.Validate();
+    if (err != nil) {
+        return fmt.Errorf("Failed to validate submission file rules: '%w'.", err);
+    }
+
+    if (this.TimeoutSecs < 0) {
+        return fmt.Errorf("Timeout cannot be negative: %d.", this.TimeoutSecs);
+    }
+
+    err = this.ResourceLimits.Validate();
+    if (err != nil) {
+        return fmt.Errorf("Failed to validate resource limits: '%w'.", err);
+    }
+
     // Register tasks.
     this.scheduledTasks = make([]tasks.ScheduledTask, 0);
 
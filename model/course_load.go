@@ -62,6 +62,14 @@ func ReadCourseConfig(path string) (*Course, error) {
 
     course.Assignments = make(map[string]*Assignment);
 
+    if ((course.LMS != nil) && (course.LMS.RelCourseDir == "")) {
+        // Force LMS files to be relative to the course's base source dir.
+        course.LMS.RelCourseDir, err = filepath.Rel(course.GetBaseSourceDir(), util.ShouldAbs(filepath.Dir(path)));
+        if (err != nil) {
+            return nil, fmt.Errorf("Could not compute relative course dir for LMS (%s): '%w'.", path, err);
+        }
+    }
+
     err = course.Validate();
     if (err != nil) {
         return nil, fmt.Errorf("Could not validate course config (%s): '%w'.", path, err);
@@ -69,3 +77,22 @@ func ReadCourseConfig(path string) (*Course, error) {
 
     return &course, nil;
 }
+
+// Load just the course config (and validate) from JSON text (e.g. as stored in a database).
+// Do not load any assignments or other resources.
+func ReadCourseConfigFromJSON(text string) (*Course, error) {
+    var course Course;
+    err := util.JSONFromString(text, &course);
+    if (err != nil) {
+        return nil, fmt.Errorf("Could not load course config from JSON: '%w'.", err);
+    }
+
+    course.Assignments = make(map[string]*Assignment);
+
+    err = course.Validate();
+    if (err != nil) {
+        return nil, fmt.Errorf("Could not validate course config '%s': '%w'.", course.ID, err);
+    }
+
+    return &course, nil;
+}
@@ -0,0 +1,103 @@
+package model
+
+// It is expected that any password passed into functions here
+// are already a hex encoding of a sha256 hash of the original cleartext
+// see util.Sha256Hex().
+
+import (
+    "crypto/subtle"
+    "encoding/hex"
+    "fmt"
+
+    "golang.org/x/crypto/argon2"
+
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/util"
+)
+
+const (
+    DEFAULT_PASSWORD_LEN = 32;
+    SALT_LENGTH_BYTES = 16;
+
+    ARGON2_KEY_LEN_BYTES = 32;
+    ARGON2_MEM_KB = 64 * 1024;
+    ARGON2_THREADS = 4;
+    ARGON2_TIME = 1;
+)
+
+// Everything needed to authenticate as a user.
+// Credentials are held by a server user (shared by all courses),
+// or by a course user that does not (yet) have a server user.
+type Credentials struct {
+    Pass string `json:"pass"`
+    Salt string `json:"salt"`
+
+    Tokens []*APIToken `json:"tokens,omitempty"`
+
+    // Incremented to invalidate all of this user's outstanding session tokens.
+    SessionVersion int `json:"session-version,omitempty"`
+
+    PasswordReset *PasswordReset `json:"password-reset,omitempty"`
+
+    // Recent failed logins (see LoginFailures).
+    LoginFailures *LoginFailures `json:"login-failures,omitempty"`
+}
+
+// Sets the password and generates a new salt.
+// The passed in passowrd should actually be a hash of the cleartext password.
+func (this *Credentials) SetPassword(hashPass string) error {
+    salt, err := util.RandBytes(SALT_LENGTH_BYTES);
+    if (err != nil) {
+        return fmt.Errorf("Could not generate salt: '%w'.", err);
+    }
+
+    pass := generateHash(hashPass, salt);
+
+    this.Salt = hex.EncodeToString(salt);
+    this.Pass = hex.EncodeToString(pass);
+
+    // A new password ends all existing sessions.
+    this.EndSessions();
+
+    return nil;
+}
+
+// Set a random passowrd, and return the cleartext (not hash) password.
+func (this *Credentials) SetRandomPassword() (string, error) {
+    pass, err := util.RandHex(DEFAULT_PASSWORD_LEN)
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to generate random password: '%s'.", err);
+    }
+
+    hashPass := util.Sha256HexFromString(pass);
+    err = this.SetPassword(hashPass);
+    if (err != nil) {
+        return "", err;
+    }
+
+    return pass, nil;
+}
+
+// Return true if the password matches the hash, false otherwise.
+// Any errors (which can only come from bad hex strings) will be logged and ignored (false will be returned).
+func (this *Credentials) CheckPassword(hashPass string) bool {
+    thisHash, err := hex.DecodeString(this.Pass);
+    if (err != nil) {
+        log.Warn("Bad password hash.", err);
+        return false;
+    }
+
+    salt, err := hex.DecodeString(this.Salt);
+    if (err != nil) {
+        log.Warn("Bad salt.", err);
+        return false;
+    }
+
+    otherHash := generateHash(hashPass, salt);
+
+    return (subtle.ConstantTimeCompare(thisHash, otherHash) == 1);
+}
+
+func generateHash(hashPass string, salt []byte) []byte {
+    return argon2.IDKey([]byte(hashPass), salt, ARGON2_TIME, ARGON2_MEM_KB, ARGON2_THREADS, ARGON2_KEY_LEN_BYTES);
+}
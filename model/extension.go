@@ -0,0 +1,137 @@
+package model
+
+import (
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/edulinq/autograder/common"
+)
+
+const HOURS_PER_DAY = 24;
+
+// An exception to an assignment's policies for a single user (e.g., an accommodation).
+type Extension struct {
+    CourseID string `json:"course-id"`
+    AssignmentID string `json:"assignment-id"`
+    User string `json:"user"`
+
+    // If set, replaces the assignment's due date for this user.
+    // The assignment's close date is pushed back by the same amount.
+    DueDate common.Timestamp `json:"due-date,omitempty"`
+    // Added to the assignment's max number of attempts.
+    ExtraAttempts int `json:"extra-attempts,omitempty"`
+    // Days that a submission may be late without a penalty.
+    // The assignment's close date is pushed back by the same amount.
+    ExtraLateDays int `json:"extra-late-days,omitempty"`
+    // Do not apply any late penalty to this user.
+    WaiveLatePenalty bool `json:"waive-late-penalty,omitempty"`
+
+    Reason string `json:"reason,omitempty"`
+    GrantedBy string `json:"granted-by,omitempty"`
+    GrantedTime common.Timestamp `json:"granted-time,omitempty"`
+}
+
+func (this *Extension) Validate() error {
+    var err error;
+
+    this.CourseID, err = common.ValidateID(this.CourseID);
+    if (err != nil) {
+        return fmt.Errorf("Invalid course ID: '%w'.", err);
+    }
+
+    this.AssignmentID, err = common.ValidateID(this.AssignmentID);
+    if (err != nil) {
+        return fmt.Errorf("Invalid assignment ID: '%w'.", err);
+    }
+
+    this.User = strings.TrimSpace(this.User);
+    if (this.User == "") {
+        return fmt.Errorf("Extensions must have a user.");
+    }
+
+    err = this.DueDate.Validate();
+    if (err != nil) {
+        return fmt.Errorf("Due date is not a valid timestamp: '%w'.", err);
+    }
+
+    if (this.ExtraAttempts < 0) {
+        return fmt.Errorf("Extra attempts cannot be negative: %d.", this.ExtraAttempts);
+    }
+
+    if (this.ExtraLateDays < 0) {
+        return fmt.Errorf("Extra late days cannot be negative: %d.", this.ExtraLateDays);
+    }
+
+    err = this.GrantedTime.Validate();
+    if (err != nil) {
+        return fmt.Errorf("Granted time is not a valid timestamp: '%w'.", err);
+    }
+
+    return nil;
+}
+
+// Get the due date for the user with this extension (which may be nil).
+func (this *Extension) GetDueDate(dueDate time.Time) time.Time {
+    if ((this == nil) || this.DueDate.IsZero()) {
+        return dueDate;
+    }
+
+    return this.DueDate.MustTime();
+}
+
+// Get the close date for the user with this extension (which may be nil).
+// The assignment's due date may be zero.
+func (this *Extension) GetCloseDate(dueDate common.Timestamp, closeDate time.Time) time.Time {
+    if (this == nil) {
+        return closeDate;
+    }
+
+    if (!this.DueDate.IsZero()) {
+        // Without an assignment due date, just make sure the extended due date is before the close date.
+        base := closeDate;
+        if (!dueDate.IsZero()) {
+            base = dueDate.MustTime();
+        }
+
+        shift := this.DueDate.MustTime().Sub(base);
+        if (shift > 0) {
+            closeDate = closeDate.Add(shift);
+        }
+    }
+
+    return closeDate.Add(time.Duration(this.ExtraLateDays * HOURS_PER_DAY) * time.Hour);
+}
+
+// Get the max number of attempts for the user with this extension (which may be nil).
+// A negative max means infinite attempts.
+func (this *Extension) GetMaxAttempts(max int) int {
+    if ((this == nil) || (max < 0)) {
+        return max;
+    }
+
+    return max + this.ExtraAttempts;
+}
+
+// Get the number of days late that should be penalized for the user with this extension (which may be nil).
+func (this *Extension) GetPenalizedDaysLate(daysLate int) int {
+    if (this == nil) {
+        return daysLate;
+    }
+
+    if (this.WaiveLatePenalty) {
+        return 0;
+    }
+
+    return max(0, daysLate - this.ExtraLateDays);
+}
+
+// Order extensions by assignment and then user.
+func CompareExtensions(a *Extension, b *Extension) int {
+    result := strings.Compare(a.AssignmentID, b.AssignmentID);
+    if (result != 0) {
+        return result;
+    }
+
+    return strings.Compare(a.User, b.User);
+}
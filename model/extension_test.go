@@ -0,0 +1,81 @@
+package model
+
+import (
+    "testing"
+    "time"
+
+    "github.com/edulinq/autograder/common"
+)
+
+func TestExtensionValidate(test *testing.T) {
+    testCases := []struct{extension Extension; valid bool}{
+        {Extension{CourseID: "c", AssignmentID: "a", User: "u"}, true},
+        {Extension{CourseID: "c", AssignmentID: "a", User: "u", DueDate: "2024-01-01T00:00:00Z"}, true},
+
+        {Extension{CourseID: "c", AssignmentID: "a", User: ""}, false},
+        {Extension{CourseID: "", AssignmentID: "a", User: "u"}, false},
+        {Extension{CourseID: "c", AssignmentID: "a", User: "u", DueDate: "tomorrow"}, false},
+        {Extension{CourseID: "c", AssignmentID: "a", User: "u", ExtraAttempts: -1}, false},
+        {Extension{CourseID: "c", AssignmentID: "a", User: "u", ExtraLateDays: -1}, false},
+    };
+
+    for i, testCase := range testCases {
+        err := testCase.extension.Validate();
+        if (testCase.valid != (err == nil)) {
+            test.Errorf("Case %d: Unexpected validation result. Expected valid: %v, Error: '%v'.", i, testCase.valid, err);
+        }
+    }
+}
+
+func TestExtensionGetCloseDate(test *testing.T) {
+    dueDate := common.Timestamp("2024-01-10T00:00:00Z");
+    closeDate := common.Timestamp("2024-01-15T00:00:00Z").MustTime();
+
+    testCases := []struct{extension *Extension; dueDate common.Timestamp; expected string}{
+        {nil, dueDate, "2024-01-15T00:00:00Z"},
+        {&Extension{}, dueDate, "2024-01-15T00:00:00Z"},
+        {&Extension{DueDate: "2024-01-12T00:00:00Z"}, dueDate, "2024-01-17T00:00:00Z"},
+        {&Extension{ExtraLateDays: 2}, dueDate, "2024-01-17T00:00:00Z"},
+        {&Extension{DueDate: "2024-01-12T00:00:00Z", ExtraLateDays: 1}, dueDate, "2024-01-18T00:00:00Z"},
+
+        // An earlier due date does not move the close date.
+        {&Extension{DueDate: "2024-01-01T00:00:00Z"}, dueDate, "2024-01-15T00:00:00Z"},
+
+        // Without an assignment due date, the close date is at least the extended due date.
+        {&Extension{DueDate: "2024-01-20T00:00:00Z"}, "", "2024-01-20T00:00:00Z"},
+        {&Extension{DueDate: "2024-01-12T00:00:00Z"}, "", "2024-01-15T00:00:00Z"},
+    };
+
+    for i, testCase := range testCases {
+        actual := testCase.extension.GetCloseDate(testCase.dueDate, closeDate);
+        expected := common.Timestamp(testCase.expected).MustTime();
+
+        if (!expected.Equal(actual)) {
+            test.Errorf("Case %d: Unexpected close date. Expected: '%s', Actual: '%s'.", i, expected, actual.Format(time.RFC3339));
+        }
+    }
+}
+
+func TestExtensionAdjustments(test *testing.T) {
+    testCases := []struct{extension *Extension; max int; daysLate int; expectedMax int; expectedDaysLate int}{
+        {nil, 3, 2, 3, 2},
+        {&Extension{}, 3, 2, 3, 2},
+        {&Extension{ExtraAttempts: 2}, 3, 2, 5, 2},
+        {&Extension{ExtraAttempts: 2}, -1, 2, -1, 2},
+        {&Extension{ExtraLateDays: 1}, 3, 2, 3, 1},
+        {&Extension{ExtraLateDays: 3}, 3, 2, 3, 0},
+        {&Extension{WaiveLatePenalty: true}, 3, 2, 3, 0},
+    };
+
+    for i, testCase := range testCases {
+        actualMax := testCase.extension.GetMaxAttempts(testCase.max);
+        if (actualMax != testCase.expectedMax) {
+            test.Errorf("Case %d: Unexpected max attempts. Expected: %d, Actual: %d.", i, testCase.expectedMax, actualMax);
+        }
+
+        actualDaysLate := testCase.extension.GetPenalizedDaysLate(testCase.daysLate);
+        if (actualDaysLate != testCase.expectedDaysLate) {
+            test.Errorf("Case %d: Unexpected days late. Expected: %d, Actual: %d.", i, testCase.expectedDaysLate, actualDaysLate);
+        }
+    }
+}
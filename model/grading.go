@@ -14,6 +14,15 @@ type GradingResult struct {
     OutputFilesGZip map[string][]byte `json:"output-files-gzip"`
     Stdout string `json:"stdout"`
     Stderr string `json:"stderr"`
+
+    // The grader was stopped because it ran longer than the assignment's timeout.
+    // Info will only have the autograder's fields (with no score, see GradingInfo.TimedOut),
+    // and Stdout and Stderr will hold any output produced before the timeout.
+    TimedOut bool `json:"timed-out,omitempty"`
+
+    // The grader was killed because it used more than the assignment's memory limit.
+    // Like with a timeout, Info will have no score but any output will be kept.
+    OutOfMemory bool `json:"out-of-memory,omitempty"`
 }
 
 type GradingInfo struct {
@@ -26,6 +35,13 @@ type GradingInfo struct {
     Message string `json:"message"`
     MaxPoints float64 `json:"max_points"`
     Score float64 `json:"score"`
+    // When the submission was made (which may be before grading started, e.g., if it waited in the grading queue).
+    // Older submissions may not have this, see GetSubmissionTime().
+    SubmissionTime common.Timestamp `json:"submission_time,omitempty"`
+    // Set if the grader did not finish (so there is no score),
+    // because it ran longer than the assignment's timeout or used more than its memory limit.
+    TimedOut bool `json:"timed-out,omitempty"`
+    OutOfMemory bool `json:"out-of-memory,omitempty"`
 
     // Information generally filled out by the grader.
     Name string `json:"name"`
@@ -56,10 +72,19 @@ func (this *GradingResult) GetCombinedOutput() string {
     return fmt.Sprintf("--- stdout ---\n%s\n--------------\n--- stderr ---\n%s\n--------------", this.Stdout, this.Stderr);
 }
 
+// Get when this submission was made (falling back to when grading started for older submissions).
+func (this GradingInfo) GetSubmissionTime() common.Timestamp {
+    if (!this.SubmissionTime.IsZero()) {
+        return this.SubmissionTime;
+    }
+
+    return this.GradingStartTime;
+}
+
 func (this GradingInfo) ToScoringInfo() *ScoringInfo {
     return &ScoringInfo{
         ID: this.ID,
-        SubmissionTime: this.GradingStartTime,
+        SubmissionTime: this.GetSubmissionTime(),
         RawScore: this.Score,
         AutograderStructVersion: SCORING_INFO_STRUCT_VERSION,
     };
@@ -2,12 +2,21 @@ package model
 
 import (
     "fmt"
+    "path/filepath"
     "strings"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/util"
 )
 
 const (
     LMS_TYPE_CANVAS = "canvas"
+    LMS_TYPE_FILE = "file"
+    LMS_TYPE_MOODLE = "moodle"
     LMS_TYPE_TEST = "test"
+
+    DEFAULT_FILE_LMS_USERS_PATH = "lms/users.csv"
+    DEFAULT_FILE_LMS_ASSIGNMENTS_PATH = "lms/assignments.csv"
 )
 
 type LMSAdapter struct {
@@ -18,6 +27,14 @@ type LMSAdapter struct {
     APIToken string `json:"api-token,omitempty"`
     BaseURL string `json:"base-url,omitempty"`
 
+    // File LMS options.
+    // Paths must be relative to (and inside of) the course's directory.
+    UsersPath string `json:"users-path,omitempty"`
+    AssignmentsPath string `json:"assignments-path,omitempty"`
+    // Must be relative to (and inside of) the gradebooks dir (which is kept when the course is updated).
+    // Defaults to "<course id>.jsonl".
+    GradebookPath string `json:"gradebook-path,omitempty"`
+
     // Behavior options.
 
     SyncUserAttributes bool `json:"sync-user-attributes,omitempty"`
@@ -25,6 +42,19 @@ type LMSAdapter struct {
     SyncUserRemoves bool `json:"sync-user-removes,omitempty"`
 
     SyncAssignments bool `json:"sync-assignments,omitempty"`
+    // When syncing assignments, overwrite local due dates and max points that differ from the LMS.
+    // Otherwise, differences are only reported as conflicts (empty local values are always filled).
+    SyncAssignmentDetails bool `json:"sync-assignment-details,omitempty"`
+    // Before syncing assignments, create LMS assignments for local assignments without an LMS ID,
+    // and update the name, due date, and max points of LMS assignments to match local assignments.
+    PushAssignments bool `json:"push-assignments,omitempty"`
+
+    // Launching this course from the LMS.
+    LTI *LTIRegistration `json:"lti,omitempty"`
+
+    // Ignore these fields in JSON.
+    // The course's directory, relative to the course's base source dir (like Assignment.RelSourceDir).
+    RelCourseDir string `json:"_rel_course-dir,omitempty"`
 }
 
 func (this *LMSAdapter) Validate() error {
@@ -33,5 +63,51 @@ func (this *LMSAdapter) Validate() error {
     }
     this.Type = strings.ToLower(this.Type);
 
+    if (this.Type == LMS_TYPE_FILE) {
+        if (this.UsersPath == "") {
+            this.UsersPath = DEFAULT_FILE_LMS_USERS_PATH;
+        }
+
+        if (this.AssignmentsPath == "") {
+            this.AssignmentsPath = DEFAULT_FILE_LMS_ASSIGNMENTS_PATH;
+        }
+
+        if (!filepath.IsLocal(this.UsersPath)) {
+            return fmt.Errorf("File LMS users path (users-path) must be a relative path inside the course's dir: '%s'.", this.UsersPath);
+        }
+
+        if (!filepath.IsLocal(this.AssignmentsPath)) {
+            return fmt.Errorf("File LMS assignments path (assignments-path) must be a relative path inside the course's dir: '%s'.", this.AssignmentsPath);
+        }
+
+        if ((this.GradebookPath != "") && !filepath.IsLocal(this.GradebookPath)) {
+            return fmt.Errorf("File LMS gradebook path (gradebook-path) must be a relative path inside the gradebooks dir: '%s'.", this.GradebookPath);
+        }
+    }
+
+    if (this.LTI != nil) {
+        err := this.LTI.Validate();
+        if (err != nil) {
+            return err;
+        }
+    }
+
     return nil;
 }
+
+// Get the full path for a (file LMS) path.
+// The path must be inside the course's directory (even after resolving symbolic links).
+func (this *LMSAdapter) GetFilePath(course *Course, path string) (string, error) {
+    return util.JoinInsideDir(filepath.Join(course.GetBaseSourceDir(), this.RelCourseDir), path);
+}
+
+// Get the full path for a (file LMS) gradebook.
+// The path must be inside the gradebooks dir (even after resolving symbolic links).
+func (this *LMSAdapter) GetGradebookPath(course *Course) (string, error) {
+    path := this.GradebookPath;
+    if (path == "") {
+        path = course.GetID() + ".jsonl";
+    }
+
+    return util.JoinInsideDir(config.GetGradebooksDir(), path);
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+    "testing"
+)
+
+func TestLMSAdapterValidateFilePaths(test *testing.T) {
+    testCases := []struct{ adapter LMSAdapter; ok bool }{
+        {LMSAdapter{Type: LMS_TYPE_FILE}, true},
+        {LMSAdapter{Type: LMS_TYPE_FILE, UsersPath: "data/users.json", GradebookPath: "course101/grades.jsonl"}, true},
+
+        {LMSAdapter{Type: LMS_TYPE_FILE, UsersPath: "/etc/passwd"}, false},
+        {LMSAdapter{Type: LMS_TYPE_FILE, UsersPath: "../other/users.csv"}, false},
+        {LMSAdapter{Type: LMS_TYPE_FILE, AssignmentsPath: "lms/../../assignments.csv"}, false},
+        {LMSAdapter{Type: LMS_TYPE_FILE, GradebookPath: "/tmp/grades.jsonl"}, false},
+        {LMSAdapter{Type: LMS_TYPE_FILE, GradebookPath: "../config.json"}, false},
+    };
+
+    for i, testCase := range testCases {
+        err := testCase.adapter.Validate();
+        if (testCase.ok && (err != nil)) {
+            test.Errorf("Case %d: Failed to validate valid adapter: '%v'.", i, err);
+        } else if (!testCase.ok && (err == nil)) {
+            test.Errorf("Case %d: Invalid adapter did not fail validation.", i);
+        }
+    }
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+    "time"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/config"
+)
+
+// Failed logins for a user (or from an IP address).
+// Once the number of failures reaches a threshold, logins are locked out for a duration that doubles with every additional failure.
+// Failures are forgotten after a while without any new failures (see config.LOCKOUT_MAX_DURATION_SECS).
+type LoginFailures struct {
+    Count int `json:"count"`
+    LastFailure common.Timestamp `json:"last-failure"`
+    LockedUntil common.Timestamp `json:"locked-until,omitempty"`
+}
+
+func (this *LoginFailures) IsLocked(now time.Time) bool {
+    if ((this == nil) || this.LockedUntil.IsZero()) {
+        return false;
+    }
+
+    lockedUntil, err := this.LockedUntil.Time();
+    if (err != nil) {
+        return false;
+    }
+
+    return now.Before(lockedUntil);
+}
+
+// Check if these failures are old enough to be forgotten.
+func (this *LoginFailures) IsStale(now time.Time) bool {
+    if ((this == nil) || this.LastFailure.IsZero()) {
+        return true;
+    }
+
+    lastFailure, err := this.LastFailure.Time();
+    if (err != nil) {
+        return true;
+    }
+
+    return !now.Before(lastFailure.Add(getMaxLockoutDuration()));
+}
+
+// Record a failed login.
+// A non-positive threshold disables lockouts (but failures are still counted).
+// Returns true if this failure locked out further logins.
+func (this *LoginFailures) Record(now time.Time, threshold int) bool {
+    if (this.IsStale(now)) {
+        *this = LoginFailures{};
+    }
+
+    this.Count++;
+    this.LastFailure = common.TimestampFromTime(now);
+
+    if ((threshold <= 0) || (this.Count < threshold)) {
+        return false;
+    }
+
+    this.LockedUntil = common.TimestampFromTime(now.Add(getLockoutDuration(this.Count - threshold)));
+    return true;
+}
+
+// Check if the user with these credentials is currently locked out.
+func (this *Credentials) IsLocked(now time.Time) bool {
+    return this.LoginFailures.IsLocked(now);
+}
+
+// Record a failed login for the user with these credentials.
+// Returns true if the user is now locked out.
+func (this *Credentials) RecordFailedLogin(now time.Time) bool {
+    if (this.LoginFailures == nil) {
+        this.LoginFailures = &LoginFailures{};
+    }
+
+    return this.LoginFailures.Record(now, config.LOCKOUT_THRESHOLD.Get());
+}
+
+// Forget all failed logins (and any lockout).
+// Returns true if there was anything to clear.
+func (this *Credentials) ClearFailedLogins() bool {
+    if (this.LoginFailures == nil) {
+        return false;
+    }
+
+    this.LoginFailures = nil;
+    return true;
+}
+
+// Get the lockout duration for a number of failures past the threshold.
+func getLockoutDuration(extraFailures int) time.Duration {
+    maxDuration := getMaxLockoutDuration();
+
+    duration := time.Duration(config.LOCKOUT_DURATION_SECS.Get()) * time.Second;
+    for i := 0; (i < extraFailures) && (duration < maxDuration); i++ {
+        duration *= 2;
+    }
+
+    return min(duration, maxDuration);
+}
+
+func getMaxLockoutDuration() time.Duration {
+    return time.Duration(config.LOCKOUT_MAX_DURATION_SECS.Get()) * time.Second;
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+    "testing"
+    "time"
+
+    "github.com/edulinq/autograder/config"
+)
+
+func TestLoginFailures(test *testing.T) {
+    oldThreshold := config.LOCKOUT_THRESHOLD.Get();
+    defer config.LOCKOUT_THRESHOLD.Set(oldThreshold);
+    config.LOCKOUT_THRESHOLD.Set(3);
+
+    duration := time.Duration(config.LOCKOUT_DURATION_SECS.Get()) * time.Second;
+    maxDuration := time.Duration(config.LOCKOUT_MAX_DURATION_SECS.Get()) * time.Second;
+
+    credentials := &Credentials{};
+    now := time.Now();
+
+    // Failures under the threshold do not lock.
+    for i := 0; i < 2; i++ {
+        if (credentials.RecordFailedLogin(now)) {
+            test.Fatalf("Failure %d caused a lockout.", i);
+        }
+
+        if (credentials.IsLocked(now)) {
+            test.Fatalf("Locked after failure %d.", i);
+        }
+    }
+
+    // Each failure at or over the threshold doubles the lockout.
+    for i, expected := range []time.Duration{duration, 2 * duration, 4 * duration} {
+        if (!credentials.RecordFailedLogin(now)) {
+            test.Fatalf("Case %d: Failure did not cause a lockout.", i);
+        }
+
+        if (!credentials.IsLocked(now.Add(expected - time.Second))) {
+            test.Fatalf("Case %d: Not locked before the lockout ends.", i);
+        }
+
+        if (credentials.IsLocked(now.Add(expected))) {
+            test.Fatalf("Case %d: Still locked after the lockout ends.", i);
+        }
+    }
+
+    // The lockout is capped.
+    for i := 0; i < 64; i++ {
+        credentials.RecordFailedLogin(now);
+    }
+
+    if (credentials.IsLocked(now.Add(maxDuration))) {
+        test.Fatalf("Lockout is longer than the max duration.");
+    }
+
+    // Old failures are forgotten.
+    later := now.Add(maxDuration);
+    if (credentials.RecordFailedLogin(later)) {
+        test.Fatalf("Old failures were not forgotten.");
+    }
+
+    if (credentials.LoginFailures.Count != 1) {
+        test.Fatalf("Unexpected failure count. Expected: 1, Actual: %d.", credentials.LoginFailures.Count);
+    }
+
+    if (!credentials.ClearFailedLogins()) {
+        test.Fatalf("Failures were not cleared.");
+    }
+
+    if (credentials.ClearFailedLogins()) {
+        test.Fatalf("Failures were cleared twice.");
+    }
+
+    // A threshold of zero disables lockouts.
+    config.LOCKOUT_THRESHOLD.Set(0);
+
+    for i := 0; i < 10; i++ {
+        if (credentials.RecordFailedLogin(now)) {
+            test.Fatalf("Failure %d caused a lockout when lockouts are disabled.", i);
+        }
+    }
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+    "fmt"
+)
+
+// How an LMS (platform) is registered to launch this server as an LTI 1.3 tool.
+// All the URLs are provided by the LMS when the tool is registered.
+type LTIRegistration struct {
+    Issuer string `json:"issuer"`
+    ClientID string `json:"client-id"`
+    // If set, only launches from this deployment are accepted.
+    DeploymentID string `json:"deployment-id,omitempty"`
+
+    AuthLoginURL string `json:"auth-login-url"`
+    KeySetURL string `json:"key-set-url"`
+    // Only needed to post scores.
+    TokenURL string `json:"token-url,omitempty"`
+
+    // The LTI context ID for this course.
+    // If empty, launches are matched by using their context ID as the LMS course ID.
+    ContextID string `json:"context-id,omitempty"`
+
+    // Post scores back to the LMS (via LTI Assignment and Grade Services) after grading.
+    PostScores bool `json:"post-scores,omitempty"`
+}
+
+func (this *LTIRegistration) Validate() error {
+    if ((this.Issuer == "") || (this.ClientID == "")) {
+        return fmt.Errorf("LTI registration must have an issuer and client ID.");
+    }
+
+    if ((this.AuthLoginURL == "") || (this.KeySetURL == "")) {
+        return fmt.Errorf("LTI registration must have an auth login URL and key set URL.");
+    }
+
+    if (this.PostScores && (this.TokenURL == "")) {
+        return fmt.Errorf("LTI registration must have a token URL to post scores.");
+    }
+
+    return nil;
+}
+
+// Check if two registrations point to the same platform (issuer, client, and platform endpoints).
+// Course-specific fields (e.g., the context) are ignored.
+func (this *LTIRegistration) SamePlatform(other *LTIRegistration) bool {
+    if ((this == nil) || (other == nil)) {
+        return (this == other);
+    }
+
+    return ((this.Issuer == other.Issuer) &&
+            (this.ClientID == other.ClientID) &&
+            (this.AuthLoginURL == other.AuthLoginURL) &&
+            (this.KeySetURL == other.KeySetURL) &&
+            (this.TokenURL == other.TokenURL));
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+    "crypto/subtle"
+    "fmt"
+    "net/url"
+    "time"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/email"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/util"
+)
+
+const PASSWORD_RESET_TOKEN_LENGTH = 32;
+
+// A pending (or used) password reset.
+// Only a hash of the token is stored.
+type PasswordReset struct {
+    // Empty once the token has been used.
+    Hash string `json:"hash,omitempty"`
+    ExpirationTime common.Timestamp `json:"expiration-time,omitempty"`
+    // Kept after the token has been used for rate limiting.
+    RequestTime common.Timestamp `json:"request-time"`
+}
+
+// Check if enough time has passed since this user's last reset request.
+func (this *Credentials) CanRequestPasswordReset(now time.Time) bool {
+    if ((this.PasswordReset == nil) || this.PasswordReset.RequestTime.IsZero()) {
+        return true;
+    }
+
+    requestTime, err := this.PasswordReset.RequestTime.Time();
+    if (err != nil) {
+        return true;
+    }
+
+    minInterval := time.Duration(config.PASSWORD_RESET_MIN_INTERVAL_SECS.Get()) * time.Second;
+    return !now.Before(requestTime.Add(minInterval));
+}
+
+// Start a new password reset (replacing any existing one), and return the cleartext token.
+// The caller is responsible for rate limiting (see CanRequestPasswordReset()).
+func (this *Credentials) StartPasswordReset(now time.Time) (string, error) {
+    token, err := util.RandHex(PASSWORD_RESET_TOKEN_LENGTH);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to generate password reset token: '%w'.", err);
+    }
+
+    duration := time.Duration(config.PASSWORD_RESET_DURATION_SECS.Get()) * time.Second;
+
+    this.PasswordReset = &PasswordReset{
+        Hash: util.Sha256HexFromString(token),
+        ExpirationTime: common.TimestampFromTime(now.Add(duration)),
+        RequestTime: common.TimestampFromTime(now),
+    };
+
+    return token, nil;
+}
+
+// If the token matches this user's pending reset, set the new password and use up the token.
+// Returns true if the password was changed.
+func (this *Credentials) ConfirmPasswordReset(token string, hashPass string, now time.Time) (bool, error) {
+    if ((this.PasswordReset == nil) || (this.PasswordReset.Hash == "")) {
+        return false, nil;
+    }
+
+    hash := util.Sha256HexFromString(token);
+    if (subtle.ConstantTimeCompare([]byte(this.PasswordReset.Hash), []byte(hash)) != 1) {
+        return false, nil;
+    }
+
+    expirationTime, err := this.PasswordReset.ExpirationTime.Time();
+    if ((err != nil) || !now.Before(expirationTime)) {
+        return false, nil;
+    }
+
+    err = this.SetPassword(hashPass);
+    if (err != nil) {
+        return false, err;
+    }
+
+    this.PasswordReset.Hash = "";
+    this.PasswordReset.ExpirationTime = "";
+
+    return true, nil;
+}
+
+func SendPasswordResetEmail(course *Course, user *User, token string) error {
+    subject, body := composePasswordResetEmail(course, user, token);
+
+    err := email.Send([]string{user.Email}, subject, body, false);
+    if (err != nil) {
+        log.Error("Failed to send password reset email.", err, course, log.NewUserAttr(user.Email));
+        return err;
+    }
+
+    log.Info("Password reset email sent.", course, log.NewUserAttr(user.Email));
+
+    return nil;
+}
+
+func composePasswordResetEmail(course *Course, user *User, token string) (string, string) {
+    subject := fmt.Sprintf("Autograder %s -- Password Reset", course.GetID());
+
+    body :=
+        "Hello,\n" +
+        fmt.Sprintf("\nA password reset was requested for '%s' in the course '%s'.\n", user.Email, course.GetDisplayName()) +
+        "If you did not request this, you can ignore this email.\n";
+
+    baseURL := config.PASSWORD_RESET_URL.Get();
+    if (baseURL != "") {
+        query := url.Values{};
+        query.Set("course-id", course.GetID());
+        query.Set("user-email", user.Email);
+        query.Set("token", token);
+
+        body += fmt.Sprintf("\nTo set a new password, visit: %s?%s\n", baseURL, query.Encode());
+    } else {
+        body += fmt.Sprintf("\nYour password reset token is '%s' (no quotes).\n", token);
+    }
+
+    body += fmt.Sprintf("This can only be used once and expires in %d minutes.\n", config.PASSWORD_RESET_DURATION_SECS.Get() / 60);
+
+    return subject, body;
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/util"
+)
+
+func TestPasswordReset(test *testing.T) {
+    user := NewUser("alice@test.com", "", RoleStudent);
+    now := time.Now();
+
+    if (!user.CanRequestPasswordReset(now)) {
+        test.Fatalf("Cannot request a first reset.");
+    }
+
+    token, err := user.StartPasswordReset(now);
+    if (err != nil) {
+        test.Fatalf("Failed to start reset: '%v'.", err);
+    }
+
+    minInterval := time.Duration(config.PASSWORD_RESET_MIN_INTERVAL_SECS.Get()) * time.Second;
+    if (user.CanRequestPasswordReset(now.Add(minInterval - time.Second))) {
+        test.Fatalf("Reset requests were not rate limited.");
+    }
+
+    if (!user.CanRequestPasswordReset(now.Add(minInterval))) {
+        test.Fatalf("Cannot request a reset after the min interval.");
+    }
+
+    pass := util.Sha256HexFromString("new-pass");
+    duration := time.Duration(config.PASSWORD_RESET_DURATION_SECS.Get()) * time.Second;
+
+    testCases := []struct{token string; now time.Time; expected bool}{
+        {token + "0", now, false},
+        {"", now, false},
+        {token, now.Add(duration), false},
+        {token, now, true},
+        // Already used.
+        {token, now, false},
+    };
+
+    for i, testCase := range testCases {
+        changed, err := user.ConfirmPasswordReset(testCase.token, pass, testCase.now);
+        if (err != nil) {
+            test.Errorf("Case %d: Failed to confirm: '%v'.", i, err);
+            continue;
+        }
+
+        if (changed != testCase.expected) {
+            test.Errorf("Case %d: Unexpected result. Expected: '%v', Actual: '%v'.", i, testCase.expected, changed);
+        }
+    }
+
+    if (!user.CheckPassword(pass)) {
+        test.Fatalf("Password was not changed.");
+    }
+
+    // Used resets still count towards the rate limit.
+    if (user.CanRequestPasswordReset(now)) {
+        test.Fatalf("Rate limit was lost after a reset was used.");
+    }
+}
+
+func TestComposePasswordResetEmailURL(test *testing.T) {
+    defer config.PASSWORD_RESET_URL.Set(config.PASSWORD_RESET_URL.Get());
+    config.PASSWORD_RESET_URL.Set("https://test.edulinq.org/reset");
+
+    course := &Course{ID: "course101"};
+    user := NewUser("alice@test.com", "", RoleStudent);
+
+    _, body := composePasswordResetEmail(course, user, "abc123");
+
+    expected := "https://test.edulinq.org/reset?course-id=course101&token=abc123&user-email=alice%40test.com";
+    if (!strings.Contains(body, expected)) {
+        test.Fatalf("Email body does not contain link. Expected: '%s', Body: '%s'.", expected, body);
+    }
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+    "github.com/edulinq/autograder/log"
+)
+
+// A user account that is shared by all the courses on this server.
+// Once a server user exists, its credentials are used instead of the credentials of any course user with the same email.
+// Course users then act as enrollments, holding course-specific information (like role and LMS ID).
+type ServerUser struct {
+    Email string `json:"email"`
+    Name string `json:"name"`
+
+    Credentials
+}
+
+// Create a server user using the information (including credentials) from a course user.
+func NewServerUserFromCourseUser(user *User) *ServerUser {
+    serverUser := &ServerUser{
+        Email: user.Email,
+        Name: user.Name,
+        Credentials: user.Credentials,
+    };
+
+    // Do not share the token slice with the course user.
+    serverUser.Tokens = append([]*APIToken(nil), user.Tokens...);
+
+    return serverUser;
+}
+
+func (this *ServerUser) LogValue() []*log.Attr {
+    return []*log.Attr{log.NewUserAttr(this.Email)};
+}
+
+// Get the credentials that are used to authenticate a course user.
+// The server user may be nil (if the course user does not have one).
+func GetCredentials(user *User, serverUser *ServerUser) *Credentials {
+    if (serverUser != nil) {
+        return &serverUser.Credentials;
+    }
+
+    return &user.Credentials;
+}
+
+// Get the course ID that a session should be tied to.
+// Sessions for server users are good for every course (an empty course ID).
+func GetSessionCourseID(courseID string, serverUser *ServerUser) string {
+    if (serverUser != nil) {
+        return "";
+    }
+
+    return courseID;
+}
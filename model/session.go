@@ -0,0 +1,193 @@
+package model
+
+// Session tokens are short-lived tokens given out by a successful password authentication,
+// so that clients do not need to hold onto a password (hash) between requests.
+// A session token is '<SESSION_TOKEN_PREFIX>.<payload>.<signature>',
+// where the payload is base64 JSON and the signature is a base64 HMAC-SHA256 of the payload.
+// Nothing about a session is stored server-side except the user's session version,
+// which is incremented to revoke all of a user's sessions at once (e.g., on logout or password change).
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/hex"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/util"
+)
+
+const (
+    SESSION_TOKEN_PREFIX = "session";
+    SESSION_KEY_FILENAME = "session.key";
+    SESSION_KEY_LENGTH_BYTES = 32;
+)
+
+type sessionPayload struct {
+    CourseID string `json:"course-id"`
+    Email string `json:"email"`
+    Version int `json:"version"`
+    // Unix time (seconds).
+    Expiration int64 `json:"expiration"`
+}
+
+var (
+    sessionKeyLock sync.Mutex;
+    sessionKeyPath string;
+    sessionKey []byte;
+)
+
+func IsSessionToken(token string) bool {
+    return strings.HasPrefix(token, SESSION_TOKEN_PREFIX + TOKEN_SEPARATOR);
+}
+
+// Create a new session token for the user with these credentials.
+// An empty course ID means that the session is good for any course (see GetSessionCourseID()).
+// Returns the token and when it expires.
+func (this *Credentials) NewSessionToken(email string, courseID string) (string, common.Timestamp, error) {
+    key, err := getSessionKey();
+    if (err != nil) {
+        return "", "", err;
+    }
+
+    expiration := time.Now().Add(time.Duration(config.SESSION_DURATION_SECS.Get()) * time.Second);
+
+    payload := sessionPayload{
+        CourseID: courseID,
+        Email: email,
+        Version: this.SessionVersion,
+        Expiration: expiration.Unix(),
+    };
+
+    text, err := util.ToJSON(payload);
+    if (err != nil) {
+        return "", "", fmt.Errorf("Failed to serialize session payload: '%w'.", err);
+    }
+
+    encodedPayload := base64.RawURLEncoding.EncodeToString([]byte(text));
+    signature := base64.RawURLEncoding.EncodeToString(signSession(key, encodedPayload));
+
+    token := strings.Join([]string{SESSION_TOKEN_PREFIX, encodedPayload, signature}, TOKEN_SEPARATOR);
+    return token, common.TimestampFromTime(expiration), nil;
+}
+
+// Return true if the session token is valid for the user with these credentials in the given course.
+// Any errors will be logged and ignored (false will be returned).
+func (this *Credentials) CheckSessionToken(email string, courseID string, token string) bool {
+    parts := strings.Split(token, TOKEN_SEPARATOR);
+    if ((len(parts) != 3) || (parts[0] != SESSION_TOKEN_PREFIX)) {
+        return false;
+    }
+
+    signature, err := base64.RawURLEncoding.DecodeString(parts[2]);
+    if (err != nil) {
+        return false;
+    }
+
+    key, err := getSessionKey();
+    if (err != nil) {
+        log.Error("Failed to get session key.", err);
+        return false;
+    }
+
+    if (!hmac.Equal(signature, signSession(key, parts[1]))) {
+        return false;
+    }
+
+    text, err := base64.RawURLEncoding.DecodeString(parts[1]);
+    if (err != nil) {
+        return false;
+    }
+
+    var payload sessionPayload;
+    err = util.JSONFromString(string(text), &payload);
+    if (err != nil) {
+        log.Warn("Failed to parse signed session payload.", err, log.NewUserAttr(email));
+        return false;
+    }
+
+    if ((payload.CourseID != courseID) || (payload.Email != email) || (payload.Version != this.SessionVersion)) {
+        return false;
+    }
+
+    return time.Now().Before(time.Unix(payload.Expiration, 0));
+}
+
+// Invalidate all existing session tokens for the user with these credentials.
+func (this *Credentials) EndSessions() {
+    this.SessionVersion++;
+}
+
+func signSession(key []byte, encodedPayload string) []byte {
+    mac := hmac.New(sha256.New, key);
+    mac.Write([]byte(encodedPayload));
+    return mac.Sum(nil);
+}
+
+// Get the key used to sign sessions.
+// Prefer the key from the config, and fall back to a key stored in (or generated into) the config dir.
+func getSessionKey() ([]byte, error) {
+    text := config.SESSION_KEY.Get();
+    if (text != "") {
+        key, err := hex.DecodeString(text);
+        if (err != nil) {
+            return nil, fmt.Errorf("Session key ('%s') is not valid hex: '%w'.", config.SESSION_KEY.Key, err);
+        }
+
+        return key, nil;
+    }
+
+    sessionKeyLock.Lock();
+    defer sessionKeyLock.Unlock();
+
+    path := filepath.Join(config.GetConfigDir(), SESSION_KEY_FILENAME);
+    if ((sessionKey != nil) && (sessionKeyPath == path)) {
+        return sessionKey, nil;
+    }
+
+    var key []byte;
+
+    if (util.PathExists(path)) {
+        text, err := util.ReadFile(path);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to read session key: '%w'.", err);
+        }
+
+        key, err = hex.DecodeString(strings.TrimSpace(text));
+        if (err != nil) {
+            return nil, fmt.Errorf("Session key file '%s' is not valid hex: '%w'.", path, err);
+        }
+    } else {
+        var err error;
+        key, err = util.RandBytes(SESSION_KEY_LENGTH_BYTES);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to generate session key: '%w'.", err);
+        }
+
+        err = util.MkDir(filepath.Dir(path));
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to make dir for session key: '%w'.", err);
+        }
+
+        // The key is a secret, only the owner should be able to read it.
+        err = os.WriteFile(path, []byte(hex.EncodeToString(key)), 0600);
+        if (err != nil) {
+            return nil, fmt.Errorf("Failed to write session key '%s': '%w'.", path, err);
+        }
+
+        log.Info("Generated a new session key.", log.NewAttr("path", path));
+    }
+
+    sessionKeyPath = path;
+    sessionKey = key;
+
+    return key, nil;
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+    "testing"
+
+    "github.com/edulinq/autograder/config"
+)
+
+func TestCheckSessionToken(test *testing.T) {
+    defer config.SESSION_DURATION_SECS.Set(config.SESSION_DURATION_SECS.Get());
+    defer config.SESSION_KEY.Set(config.SESSION_KEY.Get());
+
+    user := NewUser("alice@test.com", "", RoleStudent);
+
+    token, _, err := user.NewSessionToken(user.Email, "course101");
+    if (err != nil) {
+        test.Fatalf("Failed to create session: '%v'.", err);
+    }
+
+    if (!IsSessionToken(token)) {
+        test.Fatalf("Session token is not recognized as a session token: '%s'.", token);
+    }
+
+    if (!user.CheckSessionToken(user.Email, "course101", token)) {
+        test.Fatalf("Valid session was rejected.");
+    }
+
+    if (user.CheckSessionToken(user.Email, "course102", token)) {
+        test.Fatalf("Session was accepted for a different course.");
+    }
+
+    otherUser := NewUser("bob@test.com", "", RoleStudent);
+    if (user.CheckSessionToken(otherUser.Email, "course101", token)) {
+        test.Fatalf("Session was accepted for a different user.");
+    }
+
+    // Use a different signing key.
+    config.SESSION_KEY.Set("00112233445566778899aabbccddeeff");
+    if (user.CheckSessionToken(user.Email, "course101", token)) {
+        test.Fatalf("Session was accepted with a different key.");
+    }
+
+    config.SESSION_DURATION_SECS.Set(-1);
+    token, _, err = user.NewSessionToken(user.Email, "course101");
+    if (err != nil) {
+        test.Fatalf("Failed to create expired session: '%v'.", err);
+    }
+
+    if (user.CheckSessionToken(user.Email, "course101", token)) {
+        test.Fatalf("Expired session was accepted.");
+    }
+
+    config.SESSION_DURATION_SECS.Set(60);
+    token, _, err = user.NewSessionToken(user.Email, "course101");
+    if (err != nil) {
+        test.Fatalf("Failed to create session: '%v'.", err);
+    }
+
+    user.EndSessions();
+    if (user.CheckSessionToken(user.Email, "course101", token)) {
+        test.Fatalf("Session was accepted after sessions were ended.");
+    }
+}
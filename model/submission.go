@@ -19,6 +19,18 @@ type SubmissionHistoryItem struct {
     MaxPoints float64 `json:"max_points"`
     Score float64 `json:"score"`
     GradingStartTime common.Timestamp `json:"grading_start_time"`
+    SubmissionTime common.Timestamp `json:"submission_time,omitempty"`
+    TimedOut bool `json:"timed-out,omitempty"`
+    OutOfMemory bool `json:"out-of-memory,omitempty"`
+}
+
+// Get when this submission was made (falling back to when grading started for older submissions).
+func (this SubmissionHistoryItem) GetSubmissionTime() common.Timestamp {
+    if (!this.SubmissionTime.IsZero()) {
+        return this.SubmissionTime;
+    }
+
+    return this.GradingStartTime;
 }
 
 func (this GradingInfo) ToHistoryItem() *SubmissionHistoryItem {
@@ -32,5 +44,8 @@ func (this GradingInfo) ToHistoryItem() *SubmissionHistoryItem {
         MaxPoints: this.MaxPoints,
         Score: this.Score,
         GradingStartTime: this.GradingStartTime,
+        SubmissionTime: this.SubmissionTime,
+        TimedOut: this.TimedOut,
+        OutOfMemory: this.OutOfMemory,
     };
 }
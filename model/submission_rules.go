@@ -0,0 +1,72 @@
+package model
+
+import (
+    "fmt"
+    "path"
+)
+
+// Rules that the files in a submission must follow before the submission will be graded.
+// All paths are relative to the submission's root and use '/' as a separator.
+// Patterns are globs (see path.Match()) that are matched against both a file's full (relative) path and its base name,
+// e.g. "*.py" matches "a.py" and "src/b.py".
+type SubmissionFileRules struct {
+    // Files that must be present.
+    RequiredFiles []string `json:"required-files,omitempty"`
+    // If not empty, every file must match at least one of these patterns.
+    AllowedFiles []string `json:"allowed-files,omitempty"`
+    // No file may match any of these patterns.
+    ForbiddenFiles []string `json:"forbidden-files,omitempty"`
+
+    // Zero means no limit for all the following.
+    MaxFileSizeKB int `json:"max-file-size-kb,omitempty"`
+    MaxTotalSizeKB int `json:"max-total-size-kb,omitempty"`
+    MaxFileCount int `json:"max-file-count,omitempty"`
+}
+
+func (this *SubmissionFileRules) Validate() error {
+    if (this == nil) {
+        return nil;
+    }
+
+    for _, pattern := range append(append([]string{}, this.AllowedFiles...), this.ForbiddenFiles...) {
+        _, err := path.Match(pattern, "");
+        if (err != nil) {
+            return fmt.Errorf("Invalid file pattern '%s': '%w'.", pattern, err);
+        }
+    }
+
+    for _, requiredPath := range this.RequiredFiles {
+        if (requiredPath == "") {
+            return fmt.Errorf("Required file paths cannot be empty.");
+        }
+    }
+
+    if (this.MaxFileSizeKB < 0) {
+        return fmt.Errorf("Max file size cannot be negative: %d.", this.MaxFileSizeKB);
+    }
+
+    if (this.MaxTotalSizeKB < 0) {
+        return fmt.Errorf("Max total size cannot be negative: %d.", this.MaxTotalSizeKB);
+    }
+
+    if (this.MaxFileCount < 0) {
+        return fmt.Errorf("Max file count cannot be negative: %d.", this.MaxFileCount);
+    }
+
+    return nil;
+}
+
+// Get the first pattern that matches the path (or an empty string).
+// Patterns are assumed to be valid.
+func MatchFilePattern(patterns []string, relpath string) string {
+    for _, pattern := range patterns {
+        fullMatch, _ := path.Match(pattern, relpath);
+        baseMatch, _ := path.Match(pattern, path.Base(relpath));
+
+        if (fullMatch || baseMatch) {
+            return pattern;
+        }
+    }
+
+    return "";
+}
@@ -2,14 +2,44 @@ package model
 
 type LMSSyncResult struct {
     UserSync *UserSyncResult `json:"user-sync"`
+    AssignmentPush *AssignmentPushResult `json:"assignment-push,omitempty"`
     AssignmentSync *AssignmentSyncResult `json:"assignment-sync"`
 }
 
+// The result of pushing local assignments to the LMS.
+type AssignmentPushResult struct {
+    CreatedAssignments []AssignmentInfo `json:"created-assignments"`
+    // Assignments without an LMS ID that were linked to an existing LMS assignment (by name).
+    // These are also reported as updated or unchanged.
+    LinkedAssignments []AssignmentInfo `json:"linked-assignments"`
+    UpdatedAssignments []AssignmentInfo `json:"updated-assignments"`
+    UnchangedAssignments []AssignmentInfo `json:"unchanged-assignments"`
+    // Assignments with an LMS ID that could not be found in the LMS.
+    MissingAssignments []AssignmentInfo `json:"missing-assignments"`
+    // Assignments without an LMS ID whose name matches multiple LMS assignments (nothing is created or updated).
+    AmbiguousAssignments []AssignmentInfo `json:"ambiguous-assignments"`
+    // Assignments that could not be created/updated in the LMS.
+    FailedAssignments []AssignmentInfo `json:"failed-assignments"`
+}
+
+func NewAssignmentPushResult() *AssignmentPushResult {
+    return &AssignmentPushResult{
+        CreatedAssignments: make([]AssignmentInfo, 0),
+        LinkedAssignments: make([]AssignmentInfo, 0),
+        UpdatedAssignments: make([]AssignmentInfo, 0),
+        UnchangedAssignments: make([]AssignmentInfo, 0),
+        MissingAssignments: make([]AssignmentInfo, 0),
+        AmbiguousAssignments: make([]AssignmentInfo, 0),
+        FailedAssignments: make([]AssignmentInfo, 0),
+    };
+}
+
 type AssignmentSyncResult struct {
     SyncedAssignments []AssignmentInfo `json:"synced-assignments"`
     AmbiguousMatches []AssignmentInfo `json:"ambiguous-matches"`
     NonMatchedAssignments []AssignmentInfo `json:"non-matched-assignments"`
     UnchangedAssignments []AssignmentInfo `json:"unchanged-assignments"`
+    Conflicts []AssignmentConflict `json:"conflicts"`
 }
 
 type AssignmentInfo struct {
@@ -17,12 +47,29 @@ type AssignmentInfo struct {
     Name string `json:"name"`
 }
 
+const (
+    ASSIGNMENT_FIELD_DUE_DATE = "due-date"
+    ASSIGNMENT_FIELD_MAX_POINTS = "max-points"
+)
+
+// A field where the local assignment and the LMS assignment disagree.
+type AssignmentConflict struct {
+    ID string `json:"id"`
+    Name string `json:"name"`
+    Field string `json:"field"`
+    LocalValue string `json:"local-value"`
+    LMSValue string `json:"lms-value"`
+    // If the local value was replaced with the LMS value.
+    Overwritten bool `json:"overwritten"`
+}
+
 func NewAssignmentSyncResult() *AssignmentSyncResult {
     return &AssignmentSyncResult{
         SyncedAssignments: make([]AssignmentInfo, 0),
         AmbiguousMatches: make([]AssignmentInfo, 0),
         NonMatchedAssignments: make([]AssignmentInfo, 0),
         UnchangedAssignments: make([]AssignmentInfo, 0),
+        Conflicts: make([]AssignmentConflict, 0),
     };
 }
 
@@ -0,0 +1,193 @@
+package model
+
+// API tokens let a user authenticate without their password.
+// A token's cleartext is '<id>.<secret>' and only a hash of it is stored.
+// Since tokens are long and random (unlike passwords), a single SHA-256 is used instead of Argon2.
+
+import (
+    "bytes"
+    "crypto/subtle"
+    "encoding/json"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/util"
+)
+
+const (
+    TOKEN_ID_LENGTH = 8;
+    TOKEN_SECRET_LENGTH = 32;
+    TOKEN_SEPARATOR = ".";
+)
+
+// What a token is allowed to do.
+// A token can never do more than its user's role allows.
+type TokenScope int;
+
+const (
+    TokenScopeUnknown TokenScope = 0
+    // Only endpoints that do not change anything.
+    TokenScopeRead               = 10
+    // Read endpoints and making submissions.
+    TokenScopeSubmit             = 20
+    // Anything the user can do.
+    TokenScopeAdmin              = 30
+)
+
+var tokenScopeToString = map[TokenScope]string{
+    TokenScopeAdmin:   "admin",
+    TokenScopeSubmit:  "submit",
+    TokenScopeRead:    "read",
+    TokenScopeUnknown: "unknown",
+}
+
+var stringToTokenScope = map[string]TokenScope{
+    "admin":   TokenScopeAdmin,
+    "submit":  TokenScopeSubmit,
+    "read":    TokenScopeRead,
+    "unknown": TokenScopeUnknown,
+}
+
+func (this TokenScope) String() string {
+    return tokenScopeToString[this];
+}
+
+func GetTokenScope(text string) TokenScope {
+    return stringToTokenScope[strings.ToLower(text)];
+}
+
+func (this TokenScope) MarshalJSON() ([]byte, error) {
+    buffer := bytes.NewBufferString(`"`);
+    buffer.WriteString(tokenScopeToString[this]);
+    buffer.WriteString(`"`);
+    return buffer.Bytes(), nil;
+}
+
+func (this *TokenScope) UnmarshalJSON(data []byte) error {
+    var temp string;
+
+    err := json.Unmarshal(data, &temp);
+    if (err != nil) {
+        return err;
+    }
+
+    temp = strings.ToLower(temp);
+
+    var ok bool;
+    *this, ok = stringToTokenScope[temp];
+    if (!ok) {
+        *this = TokenScopeUnknown;
+        return fmt.Errorf("Unknown TokenScope value: '%s'.", temp);
+    }
+
+    return nil;
+}
+
+type APIToken struct {
+    ID string `json:"id"`
+    Name string `json:"name"`
+    Scope TokenScope `json:"scope"`
+    // Hex SHA-256 of the token's cleartext.
+    Hash string `json:"hash"`
+
+    CreationTime common.Timestamp `json:"creation-time"`
+    // An empty expiration time means that the token never expires.
+    ExpirationTime common.Timestamp `json:"expiration-time,omitempty"`
+}
+
+// Create a new token and return it along with its cleartext.
+// The cleartext is not stored anywhere and cannot be recovered.
+func NewAPIToken(name string, scope TokenScope, expirationTime common.Timestamp) (*APIToken, string, error) {
+    if (scope == TokenScopeUnknown) {
+        return nil, "", fmt.Errorf("Tokens must have a known scope.");
+    }
+
+    err := expirationTime.Validate();
+    if (err != nil) {
+        return nil, "", fmt.Errorf("Expiration time is not a valid timestamp: '%w'.", err);
+    }
+
+    id, err := util.RandHex(TOKEN_ID_LENGTH);
+    if (err != nil) {
+        return nil, "", fmt.Errorf("Failed to generate token ID: '%w'.", err);
+    }
+
+    secret, err := util.RandHex(TOKEN_SECRET_LENGTH);
+    if (err != nil) {
+        return nil, "", fmt.Errorf("Failed to generate token secret: '%w'.", err);
+    }
+
+    cleartext := id + TOKEN_SEPARATOR + secret;
+
+    token := &APIToken{
+        ID: id,
+        Name: name,
+        Scope: scope,
+        Hash: util.Sha256HexFromString(cleartext),
+        CreationTime: common.NowTimestamp(),
+        ExpirationTime: expirationTime,
+    };
+
+    return token, cleartext, nil;
+}
+
+func (this *APIToken) IsExpired(now time.Time) bool {
+    if (this.ExpirationTime.IsZero()) {
+        return false;
+    }
+
+    expirationTime, err := this.ExpirationTime.Time();
+    if (err != nil) {
+        return true;
+    }
+
+    return !now.Before(expirationTime);
+}
+
+func (this *Credentials) GetToken(id string) *APIToken {
+    for _, token := range this.Tokens {
+        if (token.ID == id) {
+            return token;
+        }
+    }
+
+    return nil;
+}
+
+// Remove a token and return true if it existed.
+func (this *Credentials) RemoveToken(id string) bool {
+    for i, token := range this.Tokens {
+        if (token.ID == id) {
+            this.Tokens = append(this.Tokens[:i], this.Tokens[i + 1:]...);
+            return true;
+        }
+    }
+
+    return false;
+}
+
+// Get the (unexpired) token matching this cleartext, or nil if there is no matching token.
+func (this *Credentials) CheckToken(cleartext string) *APIToken {
+    id, _, found := strings.Cut(cleartext, TOKEN_SEPARATOR);
+    if (!found) {
+        return nil;
+    }
+
+    token := this.GetToken(id);
+    if (token == nil) {
+        return nil;
+    }
+
+    hash := util.Sha256HexFromString(cleartext);
+    if (subtle.ConstantTimeCompare([]byte(token.Hash), []byte(hash)) != 1) {
+        return nil;
+    }
+
+    if (token.IsExpired(time.Now())) {
+        return nil;
+    }
+
+    return token;
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+    "testing"
+
+    "github.com/edulinq/autograder/util"
+)
+
+func TestCheckToken(test *testing.T) {
+    user := &User{};
+
+    token, cleartext, err := NewAPIToken("a", TokenScopeRead, "");
+    if (err != nil) {
+        test.Fatalf("Failed to create token: '%v'.", err);
+    }
+
+    expiredToken, expiredCleartext, err := NewAPIToken("b", TokenScopeAdmin, "2000-01-01T00:00:00Z");
+    if (err != nil) {
+        test.Fatalf("Failed to create expired token: '%v'.", err);
+    }
+
+    user.Tokens = []*APIToken{token, expiredToken};
+
+    testCases := []struct{cleartext string; expected *APIToken}{
+        {cleartext, token},
+        {expiredCleartext, nil},
+        {cleartext + "0", nil},
+        {token.ID, nil},
+        {token.ID + ".", nil},
+        {"", nil},
+    };
+
+    for i, testCase := range testCases {
+        actual := user.CheckToken(testCase.cleartext);
+        if (actual != testCase.expected) {
+            test.Errorf("Case %d: Unexpected token. Expected: '%+v', Actual: '%+v'.", i, testCase.expected, actual);
+        }
+    }
+
+    if (!user.RemoveToken(token.ID)) {
+        test.Fatalf("Failed to remove token.");
+    }
+
+    if (user.CheckToken(cleartext) != nil) {
+        test.Fatalf("Removed token still works.");
+    }
+
+    if (user.RemoveToken(token.ID)) {
+        test.Fatalf("Removed a token twice.");
+    }
+}
+
+func TestNewAPITokenBadArgs(test *testing.T) {
+    _, _, err := NewAPIToken("a", TokenScopeUnknown, "");
+    if (err == nil) {
+        test.Errorf("Did not get an error for an unknown scope.");
+    }
+
+    _, _, err = NewAPIToken("a", TokenScopeRead, "tomorrow");
+    if (err == nil) {
+        test.Errorf("Did not get an error for a bad expiration time.");
+    }
+}
+
+func TestTokenScopeJSON(test *testing.T) {
+    for _, scope := range []TokenScope{TokenScopeRead, TokenScopeSubmit, TokenScopeAdmin} {
+        var actual TokenScope;
+        util.MustJSONFromString(util.MustToJSON(scope), &actual);
+
+        if (actual != scope) {
+            test.Errorf("Scope did not survive JSON. Expected: '%s', Actual: '%s'.", scope, actual);
+        }
+    }
+
+    var scope TokenScope;
+    err := util.JSONFromString(`"zzz"`, &scope);
+    if (err == nil) {
+        test.Errorf("Did not get an error for an unknown scope.");
+    }
+}
@@ -1,45 +1,34 @@
 package model
 
-// It is expected that any password passed into functions here
-// are already a hex encoding of a sha256 hash of the original cleartext
-// see util.Sha256Hex().
-
 import (
-    "crypto/subtle"
-    "encoding/hex"
     "fmt"
     "slices"
     "strings"
     "time"
 
-    "golang.org/x/crypto/argon2"
-
     "github.com/edulinq/autograder/config"
     "github.com/edulinq/autograder/email"
     "github.com/edulinq/autograder/log"
-    "github.com/edulinq/autograder/util"
 )
 
-const (
-    DEFAULT_PASSWORD_LEN = 32;
-    SALT_LENGTH_BYTES = 16;
-
-    ARGON2_KEY_LEN_BYTES = 32;
-    ARGON2_MEM_KB = 64 * 1024;
-    ARGON2_THREADS = 4;
-    ARGON2_TIME = 1;
-
-    EMAIL_SLEEP_TIME = int64(1.5 * float64(time.Second));
-)
+const EMAIL_SLEEP_TIME = int64(1.5 * float64(time.Second));
 
 type User struct {
     Email string `json:"email"`
     Name string `json:"name"`
     Role UserRole `json:"role"`
-    Pass string `json:"pass"`
-    Salt string `json:"salt"`
+
+    // Only used if the user does not have a server user.
+    Credentials
 
     LMSID string `json:"lms-id"`
+    // The user's ID in LTI launches (which may be different than their LMS ID).
+    // Set on the user's first LTI launch.
+    LTIID string `json:"lti-id,omitempty"`
+
+    // The names of the LMS sections/groups the user is in (set by LMS syncs).
+    Sections []string `json:"sections,omitempty"`
+    Groups []string `json:"groups,omitempty"`
 }
 
 func NewUser(email string, name string, role UserRole) *User {
@@ -50,60 +39,24 @@ func NewUser(email string, name string, role UserRole) *User {
     };
 }
 
-func (this *User) LogValue() []*log.Attr {
-    return []*log.Attr{log.NewUserAttr(this.Email)};
+// Check if the user is in a section (case-insensitive).
+func (this *User) InSection(section string) bool {
+    return containsFold(this.Sections, section);
 }
 
-// Sets the password and generates a new salt.
-// The passed in passowrd should actually be a hash of the cleartext password.
-func (this *User) SetPassword(hashPass string) error {
-    salt, err := util.RandBytes(SALT_LENGTH_BYTES);
-    if (err != nil) {
-        return fmt.Errorf("Could not generate salt: '%w'.", err);
-    }
-
-    pass := generateHash(hashPass, salt);
-
-    this.Salt = hex.EncodeToString(salt);
-    this.Pass = hex.EncodeToString(pass);
-
-    return nil;
+// Check if the user is in a group (case-insensitive).
+func (this *User) InGroup(group string) bool {
+    return containsFold(this.Groups, group);
 }
 
-// Set a random passowrd, and return the cleartext (not hash) password.
-func (this *User) SetRandomPassword() (string, error) {
-    pass, err := util.RandHex(DEFAULT_PASSWORD_LEN)
-    if (err != nil) {
-        return "", fmt.Errorf("Failed to generate random password: '%s'.", err);
-    }
-
-    hashPass := util.Sha256HexFromString(pass);
-    err = this.SetPassword(hashPass);
-    if (err != nil) {
-        return "", err;
-    }
-
-    return pass, nil;
+func containsFold(values []string, target string) bool {
+    return slices.ContainsFunc(values, func(value string) bool {
+        return strings.EqualFold(value, target);
+    });
 }
 
-// Return true if the password matches the hash, false otherwise.
-// Any errors (which can only come from bad hex strings) will be logged and ignored (false will be returned).
-func (this *User) CheckPassword(hashPass string) bool {
-    thisHash, err := hex.DecodeString(this.Pass);
-    if (err != nil) {
-        log.Warn("Bad password hash for user.", err, this);
-        return false;
-    }
-
-    salt, err := hex.DecodeString(this.Salt);
-    if (err != nil) {
-        log.Warn("Bad salt for user.", err, this);
-        return false;
-    }
-
-    otherHash := generateHash(hashPass, salt);
-
-    return (subtle.ConstantTimeCompare(thisHash, otherHash) == 1);
+func (this *User) LogValue() []*log.Attr {
+    return []*log.Attr{log.NewUserAttr(this.Email)};
 }
 
 // Merge another user's information into this user (email will not be merged).
@@ -120,6 +73,7 @@ func (this *User) Merge(other *User) bool {
     if ((other.Pass != "") && (this.Pass != other.Pass)) {
         this.Pass = other.Pass;
         this.Salt = other.Salt;
+        this.EndSessions();
         changed = true;
     }
 
@@ -133,11 +87,22 @@ func (this *User) Merge(other *User) bool {
         changed = true;
     }
 
-    return changed;
-}
+    if ((other.LTIID != "") && (this.LTIID != other.LTIID)) {
+        this.LTIID = other.LTIID;
+        changed = true;
+    }
+
+    if ((other.Sections != nil) && !slices.Equal(this.Sections, other.Sections)) {
+        this.Sections = other.Sections;
+        changed = true;
+    }
 
-func generateHash(hashPass string, salt []byte) []byte {
-    return argon2.IDKey([]byte(hashPass), salt, ARGON2_TIME, ARGON2_MEM_KB, ARGON2_THREADS, ARGON2_KEY_LEN_BYTES);
+    if ((other.Groups != nil) && !slices.Equal(this.Groups, other.Groups)) {
+        this.Groups = other.Groups;
+        changed = true;
+    }
+
+    return changed;
 }
 
 func SendUserAddEmail(course *Course, user *User, pass string, generatedPass bool, userExists bool, dryRun bool, sleep bool) error {
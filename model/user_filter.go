@@ -0,0 +1,49 @@
+package model
+
+// Filter users by their LMS section and/or group.
+// Empty fields match all users.
+type UserFilter struct {
+    Section string
+    Group string
+}
+
+func (this *UserFilter) IsEmpty() bool {
+    return ((this == nil) || ((this.Section == "") && (this.Group == "")));
+}
+
+// A nil/empty filter matches all users (even nil ones).
+func (this *UserFilter) Matches(user *User) bool {
+    if (this.IsEmpty()) {
+        return true;
+    }
+
+    if (user == nil) {
+        return false;
+    }
+
+    if ((this.Section != "") && !user.InSection(this.Section)) {
+        return false;
+    }
+
+    if ((this.Group != "") && !user.InGroup(this.Group)) {
+        return false;
+    }
+
+    return true;
+}
+
+// Get only the users that match this filter.
+func (this *UserFilter) FilterUsers(users map[string]*User) map[string]*User {
+    if (this.IsEmpty()) {
+        return users;
+    }
+
+    result := make(map[string]*User);
+    for email, user := range users {
+        if (this.Matches(user)) {
+            result[email] = user;
+        }
+    }
+
+    return result;
+}
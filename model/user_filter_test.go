@@ -0,0 +1,55 @@
+package model
+
+import (
+    "testing"
+)
+
+func TestUserFilterMatches(test *testing.T) {
+    user := &User{
+        Email: "student@test.com",
+        Sections: []string{"Lab 02", "Lecture 01"},
+        Groups: []string{"Team A"},
+    };
+
+    testCases := []struct{filter *UserFilter; user *User; expected bool}{
+        {nil, user, true},
+        {nil, nil, true},
+        {&UserFilter{}, user, true},
+        {&UserFilter{}, nil, true},
+        {&UserFilter{Section: "Lab 02"}, user, true},
+        {&UserFilter{Section: "lab 02"}, user, true},
+        {&UserFilter{Section: "Lab 01"}, user, false},
+        {&UserFilter{Group: "team a"}, user, true},
+        {&UserFilter{Group: "Team B"}, user, false},
+        {&UserFilter{Section: "Lecture 01", Group: "Team A"}, user, true},
+        {&UserFilter{Section: "Lecture 01", Group: "Team B"}, user, false},
+        {&UserFilter{Section: "Lab 02"}, nil, false},
+        {&UserFilter{Section: "Lab 02"}, &User{}, false},
+    };
+
+    for i, testCase := range testCases {
+        actual := testCase.filter.Matches(testCase.user);
+        if (testCase.expected != actual) {
+            test.Errorf("Case %d: Filter match not as expected. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual);
+        }
+    }
+}
+
+func TestUserFilterFilterUsers(test *testing.T) {
+    users := map[string]*User{
+        "a@test.com": &User{Email: "a@test.com", Sections: []string{"Lab 01"}},
+        "b@test.com": &User{Email: "b@test.com", Sections: []string{"Lab 02"}},
+        "c@test.com": &User{Email: "c@test.com"},
+    };
+
+    var filter *UserFilter = nil;
+    if (len(filter.FilterUsers(users)) != 3) {
+        test.Fatalf("Nil filter did not return all users.");
+    }
+
+    filter = &UserFilter{Section: "Lab 01"};
+    actual := filter.FilterUsers(users);
+    if ((len(actual) != 1) || (actual["a@test.com"] == nil)) {
+        test.Fatalf("Filtered users not as expected. Actual: '%v'.", actual);
+    }
+}
@@ -0,0 +1,68 @@
+package oidc
+
+import (
+    "crypto/subtle"
+    "fmt"
+    "time"
+)
+
+// The claims of an ID token that we use.
+type IDTokenClaims struct {
+    Issuer string `json:"iss"`
+    Subject string `json:"sub"`
+    Audience Audience `json:"aud"`
+    // Unix time (seconds).
+    Expiration int64 `json:"exp"`
+    IssuedAt int64 `json:"iat"`
+    Nonce string `json:"nonce"`
+
+    Email string `json:"email"`
+    EmailVerified bool `json:"email_verified"`
+    Name string `json:"name"`
+}
+
+// Verify an ID token's signature and claims,
+// and ensure that it has a verified email (since emails are how users are identified).
+func (this *Provider) VerifyIDToken(rawToken string, clientID string, nonce string, now time.Time) (*IDTokenClaims, error) {
+    var claims IDTokenClaims;
+    err := this.VerifySignature(rawToken, &claims);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to verify ID token: '%w'.", err);
+    }
+
+    err = claims.validate(this.Issuer, clientID, nonce, now);
+    if (err != nil) {
+        return nil, err;
+    }
+
+    return &claims, nil;
+}
+
+func (this *IDTokenClaims) validate(issuer string, clientID string, nonce string, now time.Time) error {
+    if (this.Issuer != issuer) {
+        return fmt.Errorf("ID token has the wrong issuer. Expected: '%s', Actual: '%s'.", issuer, this.Issuer);
+    }
+
+    if (!this.Audience.Contains(clientID)) {
+        return fmt.Errorf("ID token is not for this client ('%s'), audience: '%v'.", clientID, this.Audience);
+    }
+
+    err := CheckTokenTimes(this.Expiration, this.IssuedAt, now);
+    if (err != nil) {
+        return err;
+    }
+
+    if (subtle.ConstantTimeCompare([]byte(this.Nonce), []byte(nonce)) != 1) {
+        return fmt.Errorf("ID token has the wrong nonce.");
+    }
+
+    if (this.Email == "") {
+        return fmt.Errorf("ID token does not have an email.");
+    }
+
+    if (!this.EmailVerified) {
+        return fmt.Errorf("ID token's email ('%s') is not verified.", this.Email);
+    }
+
+    return nil;
+}
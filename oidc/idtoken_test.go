@@ -0,0 +1,83 @@
+package oidc
+
+import (
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestVerifyIDToken(test *testing.T) {
+    mock := StartMockProvider("student@test.com");
+    defer mock.Close();
+
+    provider, err := GetProvider();
+    if (err != nil) {
+        test.Fatalf("Failed to get provider: '%v'.", err);
+    }
+
+    const nonce = "test-nonce";
+    goodHeader := map[string]any{"alg": "RS256", "kid": TEST_KEY_ID};
+    now := time.Now();
+
+    testCases := []struct{header map[string]any; changes map[string]any; valid bool}{
+        {goodHeader, nil, true},
+        {goodHeader, map[string]any{"aud": []string{"other", TEST_CLIENT_ID}}, true},
+        // Missing key IDs are allowed when the provider only has one key.
+        {map[string]any{"alg": "RS256"}, nil, true},
+
+        {map[string]any{"alg": "none", "kid": TEST_KEY_ID}, nil, false},
+        {map[string]any{"alg": "HS256", "kid": TEST_KEY_ID}, nil, false},
+        {map[string]any{"alg": "RS256", "kid": "ZZZ"}, nil, false},
+
+        {goodHeader, map[string]any{"iss": "https://evil.test.com"}, false},
+        {goodHeader, map[string]any{"aud": "other"}, false},
+        {goodHeader, map[string]any{"aud": []string{"other"}}, false},
+        {goodHeader, map[string]any{"exp": now.Add(-time.Hour).Unix()}, false},
+        {goodHeader, map[string]any{"iat": now.Add(time.Hour).Unix()}, false},
+        {goodHeader, map[string]any{"nonce": "ZZZ"}, false},
+        {goodHeader, map[string]any{"nonce": ""}, false},
+        {goodHeader, map[string]any{"email": ""}, false},
+        {goodHeader, map[string]any{"email_verified": false}, false},
+        {goodHeader, map[string]any{"email_verified": nil}, false},
+    };
+
+    for i, testCase := range testCases {
+        claims := mock.GetClaims(nonce);
+        for key, value := range testCase.changes {
+            claims[key] = value;
+        }
+
+        token := mock.SignIDToken(testCase.header, claims);
+
+        result, err := provider.VerifyIDToken(token, TEST_CLIENT_ID, nonce, now);
+        if (testCase.valid && (err != nil)) {
+            test.Errorf("Case %d: Failed to verify a valid token: '%v'.", i, err);
+            continue;
+        }
+
+        if (!testCase.valid) {
+            if (err == nil) {
+                test.Errorf("Case %d: Verified an invalid token.", i);
+            }
+
+            continue;
+        }
+
+        if (result.Email != "student@test.com") {
+            test.Errorf("Case %d: Unexpected email: '%s'.", i, result.Email);
+        }
+    }
+
+    // Tampering with the claims breaks the signature.
+    token := mock.SignIDToken(goodHeader, mock.GetClaims(nonce));
+    otherToken := mock.SignIDToken(goodHeader, mock.GetClaims("other-nonce"));
+
+    parts := strings.Split(token, ".");
+    otherParts := strings.Split(otherToken, ".");
+    tamperedToken := strings.Join([]string{parts[0], otherParts[1], parts[2]}, ".");
+
+    _, err = provider.VerifyIDToken(tamperedToken, TEST_CLIENT_ID, "other-nonce", now);
+    if (err == nil) {
+        test.Fatalf("Verified a token with a bad signature.");
+    }
+}
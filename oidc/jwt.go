@@ -0,0 +1,194 @@
+package oidc
+
+// A minimal implementation of the parts of JWTs (RS256 only) and JWKs that OIDC (and LTI) need.
+
+import (
+    "crypto"
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "math/big"
+    "slices"
+    "strings"
+    "time"
+
+    "github.com/edulinq/autograder/util"
+)
+
+// How much clock difference with other servers to allow.
+const CLOCK_SKEW = 2 * time.Minute;
+
+type JSONWebKey struct {
+    KeyType string `json:"kty"`
+    KeyID string `json:"kid"`
+    Use string `json:"use,omitempty"`
+    Algorithm string `json:"alg,omitempty"`
+    N string `json:"n"`
+    E string `json:"e"`
+}
+
+type JSONWebKeySet struct {
+    Keys []JSONWebKey `json:"keys"`
+}
+
+type tokenHeader struct {
+    Algorithm string `json:"alg"`
+    KeyID string `json:"kid,omitempty"`
+    Type string `json:"typ,omitempty"`
+}
+
+// The audience claim may be either a single string or a list of strings.
+type Audience []string;
+
+func NewRSAJSONWebKey(keyID string, key *rsa.PublicKey) JSONWebKey {
+    return JSONWebKey{
+        KeyType: "RSA",
+        KeyID: keyID,
+        Use: "sig",
+        Algorithm: "RS256",
+        N: base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+        E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+    };
+}
+
+func (this *Audience) UnmarshalJSON(data []byte) error {
+    var single string;
+    err := json.Unmarshal(data, &single);
+    if (err == nil) {
+        *this = Audience{single};
+        return nil;
+    }
+
+    var multiple []string;
+    err = json.Unmarshal(data, &multiple);
+    if (err != nil) {
+        return fmt.Errorf("Audience is neither a string nor a list of strings: '%w'.", err);
+    }
+
+    *this = Audience(multiple);
+    return nil;
+}
+
+func (this Audience) Contains(clientID string) bool {
+    return slices.Contains(this, clientID);
+}
+
+// Check the expiration and issued at times (unix seconds) of a token.
+func CheckTokenTimes(expiration int64, issuedAt int64, now time.Time) error {
+    if (!now.Before(time.Unix(expiration, 0).Add(CLOCK_SKEW))) {
+        return fmt.Errorf("Token is expired.");
+    }
+
+    if (time.Unix(issuedAt, 0).After(now.Add(CLOCK_SKEW))) {
+        return fmt.Errorf("Token was issued in the future.");
+    }
+
+    return nil;
+}
+
+// Verify a token's (RS256) signature with the provider's keys, and decode its claims.
+// The claims themselves are not checked.
+func (this *Provider) VerifySignature(rawToken string, claims any) error {
+    parts := strings.Split(rawToken, ".");
+    if (len(parts) != 3) {
+        return fmt.Errorf("Token does not have three parts.");
+    }
+
+    var header tokenHeader;
+    err := decodeJSONPart(parts[0], &header);
+    if (err != nil) {
+        return fmt.Errorf("Failed to decode token header: '%w'.", err);
+    }
+
+    if (header.Algorithm != "RS256") {
+        return fmt.Errorf("Unsupported token algorithm: '%s'.", header.Algorithm);
+    }
+
+    signature, err := base64.RawURLEncoding.DecodeString(parts[2]);
+    if (err != nil) {
+        return fmt.Errorf("Token signature is not valid base64: '%w'.", err);
+    }
+
+    key, err := this.getKey(header.KeyID);
+    if (err != nil) {
+        return err;
+    }
+
+    digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]));
+    err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature);
+    if (err != nil) {
+        return fmt.Errorf("Token has a bad signature: '%w'.", err);
+    }
+
+    err = decodeJSONPart(parts[1], claims);
+    if (err != nil) {
+        return fmt.Errorf("Failed to decode token claims: '%w'.", err);
+    }
+
+    return nil;
+}
+
+// Create an RS256 token.
+func SignToken(key *rsa.PrivateKey, keyID string, claims any) (string, error) {
+    header := tokenHeader{
+        Algorithm: "RS256",
+        KeyID: keyID,
+        Type: "JWT",
+    };
+
+    return signToken(key, header, claims);
+}
+
+// The header is passed separately so that tests can make bad headers.
+func signToken(key *rsa.PrivateKey, header any, claims any) (string, error) {
+    headerText, err := util.ToJSON(header);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to serialize token header: '%w'.", err);
+    }
+
+    claimsText, err := util.ToJSON(claims);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to serialize token claims: '%w'.", err);
+    }
+
+    payload := base64.RawURLEncoding.EncodeToString([]byte(headerText)) + "." + base64.RawURLEncoding.EncodeToString([]byte(claimsText));
+
+    digest := sha256.Sum256([]byte(payload));
+    signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:]);
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to sign token: '%w'.", err);
+    }
+
+    return payload + "." + base64.RawURLEncoding.EncodeToString(signature), nil;
+}
+
+func parseRSAKey(jwk JSONWebKey) (*rsa.PublicKey, error) {
+    n, err := base64.RawURLEncoding.DecodeString(jwk.N);
+    if (err != nil) {
+        return nil, fmt.Errorf("Modulus is not valid base64: '%w'.", err);
+    }
+
+    e, err := base64.RawURLEncoding.DecodeString(jwk.E);
+    if (err != nil) {
+        return nil, fmt.Errorf("Exponent is not valid base64: '%w'.", err);
+    }
+
+    exponent := new(big.Int).SetBytes(e);
+    if (!exponent.IsInt64() || (exponent.Int64() < 3) || (exponent.Int64() > (1 << 31))) {
+        return nil, fmt.Errorf("Exponent is out of range.");
+    }
+
+    return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil;
+}
+
+func decodeJSONPart(part string, target any) error {
+    text, err := base64.RawURLEncoding.DecodeString(part);
+    if (err != nil) {
+        return fmt.Errorf("Part is not valid base64: '%w'.", err);
+    }
+
+    return util.JSONFromString(string(text), target);
+}
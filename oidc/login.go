@@ -0,0 +1,184 @@
+package oidc
+
+import (
+    "crypto/sha256"
+    "encoding/base64"
+    "fmt"
+    "net/url"
+    "sync"
+    "time"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/util"
+)
+
+// How long a user has to finish logging in at the provider.
+const LOGIN_TIMEOUT = 10 * time.Minute;
+
+const RANDOM_VALUE_LENGTH = 32;
+
+// A login that was started (the user was sent to the provider), but not finished.
+type pendingLogin struct {
+    CourseID string
+    Nonce string
+    CodeVerifier string
+    Expiration time.Time
+}
+
+// The result of a successful login.
+type Login struct {
+    CourseID string
+    Email string
+    Name string
+}
+
+type tokenResponse struct {
+    IDToken string `json:"id_token"`
+}
+
+var (
+    pendingLoginsLock sync.Mutex;
+    // Keyed by state.
+    pendingLogins map[string]*pendingLogin = make(map[string]*pendingLogin);
+)
+
+// Start a login to the given course.
+// Returns the URL to send the user to (at the provider) and the state,
+// which should also be stored with the user (e.g., in a cookie) to tie the callback to them.
+func StartLogin(courseID string) (string, string, error) {
+    provider, err := GetProvider();
+    if (err != nil) {
+        return "", "", err;
+    }
+
+    state, err := util.RandHex(RANDOM_VALUE_LENGTH);
+    if (err != nil) {
+        return "", "", fmt.Errorf("Failed to generate state: '%w'.", err);
+    }
+
+    nonce, err := util.RandHex(RANDOM_VALUE_LENGTH);
+    if (err != nil) {
+        return "", "", fmt.Errorf("Failed to generate nonce: '%w'.", err);
+    }
+
+    codeVerifier, err := util.RandHex(RANDOM_VALUE_LENGTH);
+    if (err != nil) {
+        return "", "", fmt.Errorf("Failed to generate code verifier: '%w'.", err);
+    }
+
+    query := url.Values{};
+    query.Set("response_type", "code");
+    query.Set("client_id", config.OIDC_CLIENT_ID.Get());
+    query.Set("redirect_uri", config.OIDC_REDIRECT_URL.Get());
+    query.Set("scope", "openid email profile");
+    query.Set("state", state);
+    query.Set("nonce", nonce);
+    query.Set("code_challenge", getCodeChallenge(codeVerifier));
+    query.Set("code_challenge_method", "S256");
+
+    authURL, err := url.Parse(provider.AuthorizationEndpoint);
+    if (err != nil) {
+        return "", "", fmt.Errorf("OIDC authorization endpoint is not a valid URL: '%w'.", err);
+    }
+
+    // Keep any query params that are already on the endpoint.
+    for key, values := range authURL.Query() {
+        for _, value := range values {
+            query.Add(key, value);
+        }
+    }
+
+    authURL.RawQuery = query.Encode();
+
+    now := time.Now();
+
+    pendingLoginsLock.Lock();
+    defer pendingLoginsLock.Unlock();
+
+    // Forget abandoned logins.
+    for otherState, login := range pendingLogins {
+        if (!now.Before(login.Expiration)) {
+            delete(pendingLogins, otherState);
+        }
+    }
+
+    pendingLogins[state] = &pendingLogin{
+        CourseID: courseID,
+        Nonce: nonce,
+        CodeVerifier: codeVerifier,
+        Expiration: now.Add(LOGIN_TIMEOUT),
+    };
+
+    return authURL.String(), state, nil;
+}
+
+// Finish a login using the state and code that the provider sent back.
+// A state can only be used once.
+func FinishLogin(state string, code string) (*Login, error) {
+    login := takePendingLogin(state);
+    if (login == nil) {
+        return nil, fmt.Errorf("Unknown or expired login state.");
+    }
+
+    provider, err := GetProvider();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    form := map[string]string{
+        "grant_type": "authorization_code",
+        "code": code,
+        "redirect_uri": config.OIDC_REDIRECT_URL.Get(),
+        "client_id": config.OIDC_CLIENT_ID.Get(),
+        "client_secret": config.OIDC_CLIENT_SECRET.Get(),
+        "code_verifier": login.CodeVerifier,
+    };
+
+    body, err := common.Post(provider.TokenEndpoint, form);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to exchange OIDC authorization code: '%w'.", err);
+    }
+
+    var response tokenResponse;
+    err = util.JSONFromString(body, &response);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to parse OIDC token response: '%w'.", err);
+    }
+
+    if (response.IDToken == "") {
+        return nil, fmt.Errorf("OIDC token response does not have an ID token.");
+    }
+
+    claims, err := provider.VerifyIDToken(response.IDToken, config.OIDC_CLIENT_ID.Get(), login.Nonce, time.Now());
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to verify ID token: '%w'.", err);
+    }
+
+    result := &Login{
+        CourseID: login.CourseID,
+        Email: claims.Email,
+        Name: claims.Name,
+    };
+
+    return result, nil;
+}
+
+func takePendingLogin(state string) *pendingLogin {
+    pendingLoginsLock.Lock();
+    defer pendingLoginsLock.Unlock();
+
+    login := pendingLogins[state];
+    delete(pendingLogins, state);
+
+    if ((login == nil) || !time.Now().Before(login.Expiration)) {
+        return nil;
+    }
+
+    return login;
+}
+
+func getCodeChallenge(codeVerifier string) string {
+    hash := sha256.Sum256([]byte(codeVerifier));
+    return base64.RawURLEncoding.EncodeToString(hash[:]);
+}
@@ -0,0 +1,33 @@
+package oidc
+
+import (
+    "os"
+    "testing"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/log"
+    "github.com/edulinq/autograder/util"
+)
+
+// Use the common main for all tests in this package.
+func TestMain(suite *testing.M) {
+    // Run inside a func so defers will run before os.Exit().
+    code := func() int {
+        config.MustEnableUnitTestingMode();
+        log.SetLevelFatal();
+
+        defer CleanupTestingMain();
+
+        return suite.Run();
+    }();
+
+    os.Exit(code);
+}
+
+func CleanupTestingMain() {
+    // Remove any temp directories.
+    err := util.RemoveRecordedTempDirs();
+    if (err != nil) {
+        log.Error("Error when removing temp dirs.", err);
+    }
+}
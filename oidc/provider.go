@@ -0,0 +1,154 @@
+package oidc
+
+// Login through an external OpenID Connect provider (e.g., a university's SSO),
+// using the authorization code flow (with PKCE).
+// Only the standard library is used, so only RS256 ID tokens are supported
+// (which every provider is required to support).
+
+import (
+    "crypto/rsa"
+    "fmt"
+    "strings"
+    "sync"
+
+    "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/util"
+)
+
+const DISCOVERY_PATH = "/.well-known/openid-configuration";
+
+// The parts of a provider's discovery document that we use.
+type Provider struct {
+    Issuer string `json:"issuer"`
+    AuthorizationEndpoint string `json:"authorization_endpoint"`
+    TokenEndpoint string `json:"token_endpoint"`
+    JWKSURI string `json:"jwks_uri"`
+
+    keysLock sync.Mutex
+    keys map[string]*rsa.PublicKey
+}
+
+var (
+    providersLock sync.Mutex;
+    providers map[string]*Provider = make(map[string]*Provider);
+)
+
+// Create a provider without discovery (e.g., for an LTI platform whose endpoints are configured directly).
+// Only the issuer and key set are needed to verify tokens.
+func NewProvider(issuer string, jwksURI string) *Provider {
+    return &Provider{
+        Issuer: issuer,
+        JWKSURI: jwksURI,
+    };
+}
+
+func IsEnabled() bool {
+    return (config.OIDC_ISSUER.Get() != "");
+}
+
+// Get the configured provider, fetching its discovery document if it has not been fetched before.
+func GetProvider() (*Provider, error) {
+    issuer := strings.TrimSuffix(config.OIDC_ISSUER.Get(), "/");
+    if (issuer == "") {
+        return nil, fmt.Errorf("No OIDC issuer is configured ('%s').", config.OIDC_ISSUER.Key);
+    }
+
+    providersLock.Lock();
+    defer providersLock.Unlock();
+
+    provider := providers[issuer];
+    if (provider != nil) {
+        return provider, nil;
+    }
+
+    body, err := common.Get(issuer + DISCOVERY_PATH);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to fetch OIDC discovery document for '%s': '%w'.", issuer, err);
+    }
+
+    provider = &Provider{};
+    err = util.JSONFromString(body, provider);
+    if (err != nil) {
+        return nil, fmt.Errorf("Failed to parse OIDC discovery document for '%s': '%w'.", issuer, err);
+    }
+
+    // The spec requires that the issuer in the document exactly matches the one used to fetch it.
+    if (strings.TrimSuffix(provider.Issuer, "/") != issuer) {
+        return nil, fmt.Errorf("OIDC discovery document has a different issuer. Expected: '%s', Actual: '%s'.", issuer, provider.Issuer);
+    }
+
+    if ((provider.AuthorizationEndpoint == "") || (provider.TokenEndpoint == "") || (provider.JWKSURI == "")) {
+        return nil, fmt.Errorf("OIDC discovery document for '%s' is missing required endpoints.", issuer);
+    }
+
+    providers[issuer] = provider;
+
+    return provider, nil;
+}
+
+// Get the provider's signing key with the given ID.
+// An empty ID is only allowed if the provider has exactly one key.
+// Keys are re-fetched when an unknown key is asked for (since providers rotate their keys).
+func (this *Provider) getKey(keyID string) (*rsa.PublicKey, error) {
+    this.keysLock.Lock();
+    defer this.keysLock.Unlock();
+
+    key := this.findKey(keyID);
+    if (key != nil) {
+        return key, nil;
+    }
+
+    err := this.fetchKeys();
+    if (err != nil) {
+        return nil, err;
+    }
+
+    key = this.findKey(keyID);
+    if (key == nil) {
+        return nil, fmt.Errorf("Could not find OIDC signing key '%s'.", keyID);
+    }
+
+    return key, nil;
+}
+
+func (this *Provider) findKey(keyID string) *rsa.PublicKey {
+    if ((keyID == "") && (len(this.keys) == 1)) {
+        for _, key := range this.keys {
+            return key;
+        }
+    }
+
+    return this.keys[keyID];
+}
+
+func (this *Provider) fetchKeys() error {
+    body, err := common.Get(this.JWKSURI);
+    if (err != nil) {
+        return fmt.Errorf("Failed to fetch OIDC signing keys: '%w'.", err);
+    }
+
+    var keySet JSONWebKeySet;
+    err = util.JSONFromString(body, &keySet);
+    if (err != nil) {
+        return fmt.Errorf("Failed to parse OIDC signing keys: '%w'.", err);
+    }
+
+    keys := make(map[string]*rsa.PublicKey);
+    for _, jwk := range keySet.Keys {
+        if ((jwk.KeyType != "RSA") || ((jwk.Use != "") && (jwk.Use != "sig"))) {
+            continue;
+        }
+
+        key, err := parseRSAKey(jwk);
+        if (err != nil) {
+            return fmt.Errorf("Failed to parse OIDC signing key '%s': '%w'.", jwk.KeyID, err);
+        }
+
+        keys[jwk.KeyID] = key;
+    }
+
+    this.keys = keys;
+
+    return nil;
+}
@@ -0,0 +1,169 @@
+package oidc
+
+import (
+    "crypto/rand"
+    "crypto/rsa"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "sync"
+    "time"
+
+    "github.com/edulinq/autograder/config"
+    "github.com/edulinq/autograder/util"
+)
+
+const (
+    TEST_CLIENT_ID = "autograder-test"
+    TEST_CLIENT_SECRET = "autograder-test-secret"
+    TEST_KEY_ID = "test-key"
+)
+
+// A minimal OIDC provider for testing.
+// Its authorization endpoint immediately logs in as Email and redirects back.
+type MockProvider struct {
+    Server *httptest.Server
+    Key *rsa.PrivateKey
+
+    // The user that will be logged in.
+    Email string
+    EmailVerified bool
+
+    lock sync.Mutex
+    // Code to the values given to the authorization endpoint.
+    codes map[string]url.Values
+}
+
+// Start a mock provider and point the config at it.
+// Callers should close the provider when done: `defer provider.Close();`.
+func StartMockProvider(email string) *MockProvider {
+    key, err := rsa.GenerateKey(rand.Reader, 2048);
+    if (err != nil) {
+        panic(err);
+    }
+
+    provider := &MockProvider{
+        Key: key,
+        Email: email,
+        EmailVerified: true,
+        codes: make(map[string]url.Values),
+    };
+
+    mux := http.NewServeMux();
+    mux.HandleFunc(DISCOVERY_PATH, provider.handleDiscovery);
+    mux.HandleFunc("/keys", provider.handleKeys);
+    mux.HandleFunc("/authorize", provider.handleAuthorize);
+    mux.HandleFunc("/token", provider.handleToken);
+
+    provider.Server = httptest.NewServer(mux);
+
+    config.OIDC_ISSUER.Set(provider.Server.URL);
+    config.OIDC_CLIENT_ID.Set(TEST_CLIENT_ID);
+    config.OIDC_CLIENT_SECRET.Set(TEST_CLIENT_SECRET);
+
+    return provider;
+}
+
+func (this *MockProvider) Close() {
+    this.Server.Close();
+
+    config.OIDC_ISSUER.Set("");
+    config.OIDC_CLIENT_ID.Set("");
+    config.OIDC_CLIENT_SECRET.Set("");
+}
+
+// Get ID token claims that would pass verification.
+func (this *MockProvider) GetClaims(nonce string) map[string]any {
+    now := time.Now();
+
+    return map[string]any{
+        "iss": this.Server.URL,
+        "sub": "sub-" + this.Email,
+        "aud": TEST_CLIENT_ID,
+        "exp": now.Add(time.Hour).Unix(),
+        "iat": now.Unix(),
+        "nonce": nonce,
+        "email": this.Email,
+        "email_verified": this.EmailVerified,
+    };
+}
+
+// Sign an (RS256) ID token with the provider's key.
+// The header is given so that bad headers can be tested.
+func (this *MockProvider) SignIDToken(header map[string]any, claims map[string]any) string {
+    token, err := signToken(this.Key, header, claims);
+    if (err != nil) {
+        panic(err);
+    }
+
+    return token;
+}
+
+func (this *MockProvider) handleDiscovery(response http.ResponseWriter, request *http.Request) {
+    document := map[string]any{
+        "issuer": this.Server.URL,
+        "authorization_endpoint": this.Server.URL + "/authorize",
+        "token_endpoint": this.Server.URL + "/token",
+        "jwks_uri": this.Server.URL + "/keys",
+    };
+
+    response.Write([]byte(util.MustToJSON(document)));
+}
+
+func (this *MockProvider) handleKeys(response http.ResponseWriter, request *http.Request) {
+    keySet := JSONWebKeySet{
+        Keys: []JSONWebKey{NewRSAJSONWebKey(TEST_KEY_ID, &this.Key.PublicKey)},
+    };
+
+    response.Write([]byte(util.MustToJSON(keySet)));
+}
+
+func (this *MockProvider) handleAuthorize(response http.ResponseWriter, request *http.Request) {
+    query := request.URL.Query();
+
+    code := util.UUID();
+
+    this.lock.Lock();
+    this.codes[code] = query;
+    this.lock.Unlock();
+
+    target, err := url.Parse(query.Get("redirect_uri"));
+    if (err != nil) {
+        http.Error(response, "Bad redirect_uri.", http.StatusBadRequest);
+        return;
+    }
+
+    targetQuery := target.Query();
+    targetQuery.Set("code", code);
+    targetQuery.Set("state", query.Get("state"));
+    target.RawQuery = targetQuery.Encode();
+
+    http.Redirect(response, request, target.String(), http.StatusFound);
+}
+
+func (this *MockProvider) handleToken(response http.ResponseWriter, request *http.Request) {
+    this.lock.Lock();
+    authQuery := this.codes[request.PostFormValue("code")];
+    delete(this.codes, request.PostFormValue("code"));
+    this.lock.Unlock();
+
+    if ((authQuery == nil) ||
+            (request.PostFormValue("client_id") != TEST_CLIENT_ID) ||
+            (request.PostFormValue("client_secret") != TEST_CLIENT_SECRET) ||
+            (request.PostFormValue("redirect_uri") != authQuery.Get("redirect_uri")) ||
+            (getCodeChallenge(request.PostFormValue("code_verifier")) != authQuery.Get("code_challenge"))) {
+        http.Error(response, `{"error": "invalid_grant"}`, http.StatusBadRequest);
+        return;
+    }
+
+    header := map[string]any{"alg": "RS256", "kid": TEST_KEY_ID};
+    idToken := this.SignIDToken(header, this.GetClaims(authQuery.Get("nonce")));
+
+    result := map[string]any{
+        "access_token": util.UUID(),
+        "token_type": "Bearer",
+        "id_token": idToken,
+    };
+
+    response.Write([]byte(util.MustToJSON(result)));
+}
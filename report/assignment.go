@@ -42,7 +42,12 @@ type ScoringReportQuestionStats struct {
 const DEFAULT_VALUE float64 = -1.0;
 
 func GetAssignmentScoringReport(assignment *model.Assignment) (*AssignmentScoringReport, error) {
-    questionNames, scores, lastSubmissionTime, err := fetchScores(assignment);
+    return GetFilteredAssignmentScoringReport(assignment, nil);
+}
+
+// Get a report on only the students that match the filter (e.g., a single section).
+func GetFilteredAssignmentScoringReport(assignment *model.Assignment, filter *model.UserFilter) (*AssignmentScoringReport, error) {
+    questionNames, scores, lastSubmissionTime, err := fetchScores(assignment, filter);
     if (err != nil) {
         return nil, err;
     }
@@ -84,21 +89,33 @@ func GetAssignmentScoringReport(assignment *model.Assignment) (*AssignmentScorin
     return &report, nil;
 }
 
-func fetchScores(assignment *model.Assignment) ([]string, map[string][]float64, time.Time, error) {
+func fetchScores(assignment *model.Assignment, filter *model.UserFilter) ([]string, map[string][]float64, time.Time, error) {
     results, err := db.GetRecentSubmissions(assignment, model.RoleStudent);
     if (err != nil) {
         return nil, nil, time.Time{}, fmt.Errorf("Failed to get recent submission results: '%w'.", err);
     }
 
+    var users map[string]*model.User = nil;
+    if (!filter.IsEmpty()) {
+        users, err = db.GetUsers(assignment.GetCourse());
+        if (err != nil) {
+            return nil, nil, time.Time{}, fmt.Errorf("Failed to get users: '%w'.", err);
+        }
+    }
+
     questionNames := make([]string, 0);
     scores := make(map[string][]float64);
     lastSubmissionTime := time.Time{};
 
-    for _, result := range results {
+    for email, result := range results {
         if (result == nil) {
             continue;
         }
 
+        if (!filter.Matches(users[email])) {
+            continue;
+        }
+
         resultTime, err := result.GradingStartTime.Time();
         if (err != nil) {
             return nil, nil, time.Time{}, fmt.Errorf("Failed to get submission result time: '%w'.", err);
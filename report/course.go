@@ -10,10 +10,15 @@ type CourseScoringReport struct {
 }
 
 func GetCourseScoringReport(course *model.Course) (*CourseScoringReport, error) {
+    return GetFilteredCourseScoringReport(course, nil);
+}
+
+// Get a report on only the students that match the filter (e.g., a single section).
+func GetFilteredCourseScoringReport(course *model.Course, filter *model.UserFilter) (*CourseScoringReport, error) {
     assignmentReports := make([]*AssignmentScoringReport, 0);
 
     for _, assignment := range course.GetSortedAssignments() {
-        assignmentReport, err := GetAssignmentScoringReport(assignment);
+        assignmentReport, err := GetFilteredAssignmentScoringReport(assignment, filter);
         if (err != nil) {
             return nil, err;
         }
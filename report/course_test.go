@@ -6,6 +6,7 @@ import (
 
     "github.com/edulinq/autograder/common"
     "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
     "github.com/edulinq/autograder/util"
 )
 
@@ -47,6 +48,45 @@ func TestCourseReportHTML(test *testing.T) {
     }
 }
 
+func TestCourseReportFilter(test *testing.T) {
+    defer db.ResetForTesting();
+
+    course := db.MustGetTestCourse();
+    user, err := db.GetUser(course, "student@test.com");
+    if (err != nil) {
+        test.Fatalf("Failed to get user: '%v'.", err);
+    }
+
+    user.Sections = []string{"Lab 01"};
+
+    err = db.SaveUser(course, user);
+    if (err != nil) {
+        test.Fatalf("Failed to save user: '%v'.", err);
+    }
+
+    report, err := GetFilteredCourseScoringReport(course, &model.UserFilter{Section: "Lab 01"});
+    if (err != nil) {
+        test.Fatalf("Failed to get matching course report: '%v'.", err);
+    }
+
+    if (!reflect.DeepEqual(expected, report)) {
+        test.Fatalf("Matching report not as expected.\n--- Expected ---\n%s\n--- Actual ---\n%s\n",
+                util.MustToJSONIndent(expected), util.MustToJSONIndent(report));
+    }
+
+    report, err = GetFilteredCourseScoringReport(course, &model.UserFilter{Section: "Lab 02"});
+    if (err != nil) {
+        test.Fatalf("Failed to get non-matching course report: '%v'.", err);
+    }
+
+    for _, assignmentReport := range report.Assignments {
+        if (assignmentReport.NumberOfSubmissions != 0) {
+            test.Fatalf("Non-matching report has submissions for '%s': %d.",
+                    assignmentReport.AssignmentName, assignmentReport.NumberOfSubmissions);
+        }
+    }
+}
+
 var expected *CourseScoringReport = &CourseScoringReport{
     CourseName: "Course 101",
     Assignments: []*AssignmentScoringReport{
@@ -1,6 +1,7 @@
 package scoring
 
 import (
+    "errors"
     "fmt"
     "strings"
     "time"
@@ -64,13 +65,17 @@ func computeFinalScores(
     // Next, create the grades that will actually be uploaded and the comments that will be updated..
     finalScores, commentsToUpdate := filterFinalScores(assignment, users, scoringInfos, locks, existingComments);
 
+    // Failures in the upload/update do not stop the other from happening.
+    // Both are safe to redo, so running the scoring again will pick up anything that failed.
+    var errs error = nil;
+
     // Upload the grades.
     if (dryRun) {
         log.Info("Dry Run: Skipping upload of final grades.", assignment, log.NewAttr("grades", finalScores));
     } else {
         err = lms.UpdateAssignmentScores(assignment.GetCourse(), assignment.GetLMSID(), finalScores);
         if (err != nil) {
-            return fmt.Errorf("Failed to upload final scores: '%w'.", err);
+            errs = errors.Join(errs, fmt.Errorf("Failed to upload final scores: '%w'.", err));
         }
     }
 
@@ -80,11 +85,11 @@ func computeFinalScores(
     } else {
         err = lms.UpdateComments(assignment.GetCourse(), assignment.GetLMSID(), commentsToUpdate);
         if (err != nil) {
-            return fmt.Errorf("Failed to update final comments: '%w'.", err);
+            errs = errors.Join(errs, fmt.Errorf("Failed to update final comments: '%w'.", err));
         }
     }
 
-    return nil;
+    return errs;
 }
 
 func parseComments(lmsScores []*lmstypes.SubmissionScore) (map[string]bool, map[string]*model.ScoringInfo, error) {
@@ -1,6 +1,7 @@
 package scoring
 
 import (
+    "errors"
     "fmt"
 
     "github.com/edulinq/autograder/log"
@@ -12,6 +13,8 @@ func FullCourseScoringAndUpload(course *model.Course, dryRun bool) error {
 
     log.Debug("Beginning full scoring for course.", course, log.NewAttr("dry-run", dryRun));
 
+    var errs error = nil;
+
     for i, assignment := range assignments {
         if (assignment.GetLMSID() == "") {
             log.Warn("Assignment has no LMS id, skipping scoring.", course, assignment);
@@ -24,11 +27,13 @@ func FullCourseScoringAndUpload(course *model.Course, dryRun bool) error {
 
         err := FullAssignmentScoringAndUpload(assignment, dryRun);
         if (err != nil) {
-            return fmt.Errorf("Failed to grade assignment '%s' for course '%s': '%w'.", assignment.GetID(), course.GetID(), err);
+            // Keep going, a failed assignment can be resumed by running the scoring again.
+            log.Error("Failed to score assignment, continuing with the remaining assignments.", course, assignment, err);
+            errs = errors.Join(errs, fmt.Errorf("Failed to grade assignment '%s' for course '%s': '%w'.", assignment.GetID(), course.GetID(), err));
         }
     }
 
     log.Debug("Finished full scoring for course.", course, log.NewAttr("dry-run", dryRun));
 
-    return nil;
+    return errs;
 }
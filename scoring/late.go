@@ -7,6 +7,7 @@ import (
     "time"
 
     "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/db"
     "github.com/edulinq/autograder/lms"
     "github.com/edulinq/autograder/lms/lmstypes"
     "github.com/edulinq/autograder/log"
@@ -47,16 +48,17 @@ func ApplyLatePolicy(
         return nil;
     }
 
-    lmsAssignment, err := lms.FetchAssignment(assignment.GetCourse(), assignment.GetLMSID());
+    dueDate, maxPoints, err := getDueDateAndMaxPoints(assignment);
     if (err != nil) {
         return err;
     }
 
-    if (lmsAssignment.DueDate == nil) {
-        return fmt.Errorf("Assignment does not have a due date.");
+    extensions, err := db.GetAssignmentExtensions(assignment);
+    if (err != nil) {
+        return fmt.Errorf("Failed to get extensions: '%w'.", err);
     }
 
-    applyBaselinePolicy(assignment, policy, users, scores, *lmsAssignment.DueDate);
+    applyBaselinePolicy(assignment, policy, users, scores, extensions, dueDate);
 
     // Baseline policy is complete.
     if (policy.Type == model.BaselinePolicy) {
@@ -66,7 +68,7 @@ func ApplyLatePolicy(
     if ((policy.Type == model.ConstantPenalty) || (policy.Type == model.PercentagePenalty)) {
         penalty := policy.Penalty;
         if (policy.Type == model.PercentagePenalty) {
-            penalty = lmsAssignment.MaxPoints * policy.Penalty;
+            penalty = maxPoints * policy.Penalty;
         }
 
         applyConstantPolicy(policy, scores, penalty);
@@ -74,7 +76,7 @@ func ApplyLatePolicy(
     }
 
     if (policy.Type == model.LateDays) {
-        penalty := lmsAssignment.MaxPoints * policy.Penalty;
+        penalty := maxPoints * policy.Penalty;
         err = applyLateDaysPolicy(policy, assignment, users, scores, penalty, dryRun);
         if (err != nil) {
             return fmt.Errorf("Failed to apply late days policy: '%w'.", err);
@@ -86,8 +88,49 @@ func ApplyLatePolicy(
     return fmt.Errorf("Unknown late policy type: '%s'.", policy.Type);
 }
 
+// The assignment's own due date and max points are authoritative (see lmssync for keeping them in sync with the LMS).
+// The LMS is only consulted for values that the assignment does not have.
+func getDueDateAndMaxPoints(assignment *model.Assignment) (time.Time, float64, error) {
+    maxPoints := assignment.MaxPoints;
+
+    if (!assignment.DueDate.IsZero() && !util.IsZero(maxPoints)) {
+        dueDate, err := assignment.DueDate.Time();
+        if (err != nil) {
+            return time.Time{}, 0.0, fmt.Errorf("Failed to parse assignment due date: '%w'.", err);
+        }
+
+        return dueDate, maxPoints, nil;
+    }
+
+    lmsAssignment, err := lms.FetchAssignment(assignment.GetCourse(), assignment.GetLMSID());
+    if (err != nil) {
+        return time.Time{}, 0.0, err;
+    }
+
+    var dueDate time.Time;
+    if (!assignment.DueDate.IsZero()) {
+        dueDate, err = assignment.DueDate.Time();
+        if (err != nil) {
+            return time.Time{}, 0.0, fmt.Errorf("Failed to parse assignment due date: '%w'.", err);
+        }
+    } else if ((lmsAssignment != nil) && (lmsAssignment.DueDate != nil)) {
+        log.Warn("Assignment has no due date, using the LMS due date. Sync assignments with the LMS to store it.", assignment);
+        dueDate = *lmsAssignment.DueDate;
+    } else {
+        return time.Time{}, 0.0, fmt.Errorf("Assignment does not have a due date.");
+    }
+
+    if (util.IsZero(maxPoints) && (lmsAssignment != nil)) {
+        maxPoints = lmsAssignment.MaxPoints;
+    }
+
+    return dueDate, maxPoints, nil;
+}
+
 // Apply a common policy.
-func applyBaselinePolicy(assignment *model.Assignment, policy model.LateGradingPolicy, users map[string]*model.User, scores map[string]*model.ScoringInfo, dueDate time.Time) {
+// A user's number of days late accounts for any extension they have.
+func applyBaselinePolicy(assignment *model.Assignment, policy model.LateGradingPolicy, users map[string]*model.User,
+        scores map[string]*model.ScoringInfo, extensions map[string]*model.Extension, dueDate time.Time) {
     for email, score := range scores {
         scoreTime, err := score.SubmissionTime.Time();
         if (err != nil) {
@@ -96,7 +139,8 @@ func applyBaselinePolicy(assignment *model.Assignment, policy model.LateGradingP
             continue;
         }
 
-        score.NumDaysLate = computeLateDays(dueDate, scoreTime);
+        extension := extensions[email];
+        score.NumDaysLate = extension.GetPenalizedDaysLate(computeLateDays(extension.GetDueDate(dueDate), scoreTime));
 
         _, ok := users[email];
         if (!ok) {
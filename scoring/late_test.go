@@ -5,6 +5,8 @@ import (
     "testing"
 
     "github.com/edulinq/autograder/common"
+    "github.com/edulinq/autograder/db"
+    "github.com/edulinq/autograder/model"
     "github.com/edulinq/autograder/util"
 )
 
@@ -31,3 +33,73 @@ func TestLateDaysInfoJSONContainsAutograderKey(test *testing.T) {
                 common.AUTOGRADER_COMMENT_IDENTITY_KEY, content);
     }
 }
+
+func TestApplyBaselinePolicyExtensions(test *testing.T) {
+    dueDate := common.Timestamp("2024-01-10T00:00:00Z").MustTime();
+    policy := model.LateGradingPolicy{Type: model.ConstantPenalty, Penalty: 1, RejectAfterDays: 3};
+
+    // Every submission is two days late.
+    submissionTime := common.Timestamp("2024-01-11T12:00:00Z");
+
+    testCases := []struct{extension *model.Extension; expectedDaysLate int; expectedScore float64}{
+        {nil, 2, 8},
+        {&model.Extension{DueDate: "2024-01-11T00:00:00Z"}, 1, 9},
+        {&model.Extension{DueDate: "2024-01-12T00:00:00Z"}, 0, 10},
+        {&model.Extension{ExtraLateDays: 1}, 1, 9},
+        {&model.Extension{WaiveLatePenalty: true}, 0, 10},
+    };
+
+    for i, testCase := range testCases {
+        users := map[string]*model.User{"student@test.com": &model.User{Email: "student@test.com"}};
+        scores := map[string]*model.ScoringInfo{"student@test.com": &model.ScoringInfo{SubmissionTime: submissionTime, RawScore: 10, Score: 10}};
+
+        extensions := map[string]*model.Extension{};
+        if (testCase.extension != nil) {
+            extensions["student@test.com"] = testCase.extension;
+        }
+
+        applyBaselinePolicy(nil, policy, users, scores, extensions, dueDate);
+        applyConstantPolicy(policy, scores, policy.Penalty);
+
+        score := scores["student@test.com"];
+        if ((score.Reject) || (score.NumDaysLate != testCase.expectedDaysLate) || (score.Score != testCase.expectedScore)) {
+            test.Errorf("Case %d: Unexpected score. Expected days late: %d, Expected score: %f, Actual: '%+v'.",
+                    i, testCase.expectedDaysLate, testCase.expectedScore, score);
+        }
+    }
+}
+
+func TestGetDueDateAndMaxPoints(test *testing.T) {
+    // The test LMS has no assignment information, so only the local values can be used.
+    testCases := []struct{dueDate common.Timestamp; maxPoints float64; expectError bool}{
+        {"2024-01-10T00:00:00Z", 10, false},
+        {"2024-01-10T00:00:00Z", 0, false},
+        {"", 10, true},
+    };
+
+    for i, testCase := range testCases {
+        assignment := db.MustGetTestAssignment();
+        assignment.DueDate = testCase.dueDate;
+        assignment.MaxPoints = testCase.maxPoints;
+
+        dueDate, maxPoints, err := getDueDateAndMaxPoints(assignment);
+        if (testCase.expectError) {
+            if (err == nil) {
+                test.Errorf("Case %d: Did not get an expected error.", i);
+            }
+
+            continue;
+        }
+
+        if (err != nil) {
+            test.Errorf("Case %d: Got an unexpected error: '%v'.", i, err);
+            continue;
+        }
+
+        if (!dueDate.Equal(testCase.dueDate.MustTime()) || (maxPoints != testCase.maxPoints)) {
+            test.Errorf("Case %d: Unexpected values. Expected: ('%s', %f), Actual: ('%s', %f).",
+                    i, testCase.dueDate, testCase.maxPoints, dueDate, maxPoints);
+            continue;
+        }
+    }
+}
@@ -0,0 +1,93 @@
+package util
+
+import (
+    "bytes"
+    "strings"
+    "sync"
+)
+
+// Lines longer than this are split into several lines,
+// so a writer that never writes a newline cannot make the buffered line grow forever.
+const MAX_LINE_WRITER_LINE_LENGTH = 64 * 1024;
+
+// A writer that keeps everything written to it (like a strings.Builder),
+// and also calls a function with each complete line (without the newline).
+// Call Flush() once writing is done to get the final line if it did not end in a newline.
+// Lines longer than MAX_LINE_WRITER_LINE_LENGTH are passed on in pieces.
+type LineWriter struct {
+    lock sync.Mutex
+    text strings.Builder
+    partial bytes.Buffer
+    onLine func(string)
+}
+
+// A nil onLine is allowed (the writer will just keep the text).
+func NewLineWriter(onLine func(string)) *LineWriter {
+    return &LineWriter{
+        onLine: onLine,
+    };
+}
+
+func (this *LineWriter) Write(data []byte) (int, error) {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    this.text.Write(data);
+
+    if (this.onLine == nil) {
+        return len(data), nil;
+    }
+
+    // Only the new data is scanned for newlines.
+    remaining := data;
+    for {
+        index := bytes.IndexByte(remaining, '\n');
+        if (index < 0) {
+            this.appendPartial(remaining);
+            break;
+        }
+
+        this.appendPartial(remaining[0:index]);
+        this.emitPartial();
+
+        remaining = remaining[(index + 1):];
+    }
+
+    return len(data), nil;
+}
+
+// Add to the current line, passing on the line early if it gets too long.
+func (this *LineWriter) appendPartial(data []byte) {
+    for (len(data) > 0) {
+        if (this.partial.Len() >= MAX_LINE_WRITER_LINE_LENGTH) {
+            this.emitPartial();
+        }
+
+        size := min(len(data), (MAX_LINE_WRITER_LINE_LENGTH - this.partial.Len()));
+        this.partial.Write(data[0:size]);
+        data = data[size:];
+    }
+}
+
+func (this *LineWriter) emitPartial() {
+    this.onLine(this.partial.String());
+    this.partial.Reset();
+}
+
+func (this *LineWriter) Flush() {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    if ((this.onLine != nil) && (this.partial.Len() > 0)) {
+        this.emitPartial();
+    }
+
+    this.partial.Reset();
+}
+
+func (this *LineWriter) String() string {
+    this.lock.Lock();
+    defer this.lock.Unlock();
+
+    return this.text.String();
+}
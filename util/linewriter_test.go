@@ -0,0 +1,55 @@
+package util
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestLineWriter(test *testing.T) {
+    longLine := strings.Repeat("a", MAX_LINE_WRITER_LINE_LENGTH);
+
+    testCases := []struct{ writes []string; expected []string }{
+        {[]string{}, []string{}},
+        {[]string{"a\n"}, []string{"a"}},
+        {[]string{"a\nb\n"}, []string{"a", "b"}},
+        {[]string{"a", "b\n", "c"}, []string{"ab", "c"}},
+        {[]string{"a\n\nb"}, []string{"a", "", "b"}},
+        {[]string{"\n"}, []string{""}},
+
+        // Lines that are too long are split.
+        {[]string{longLine + "\n"}, []string{longLine}},
+        {[]string{longLine + "b\n"}, []string{longLine, "b"}},
+        {[]string{longLine[0:10], longLine[10:], "bc"}, []string{longLine, "bc"}},
+        {[]string{longLine + longLine + "b"}, []string{longLine, longLine, "b"}},
+    };
+
+    for i, testCase := range testCases {
+        lines := make([]string, 0);
+        writer := NewLineWriter(func(line string) {
+            lines = append(lines, line);
+        });
+
+        for _, data := range testCase.writes {
+            count, err := writer.Write([]byte(data));
+            if (err != nil) {
+                test.Fatalf("Case %d: Failed to write: '%v'.", i, err);
+            }
+
+            if (count != len(data)) {
+                test.Fatalf("Case %d: Wrong write count. Expected: %d, actual: %d.", i, len(data), count);
+            }
+        }
+
+        writer.Flush();
+
+        if (!reflect.DeepEqual(testCase.expected, lines)) {
+            test.Errorf("Case %d: Unexpected lines. Expected: %d lines, actual: %d lines.", i, len(testCase.expected), len(lines));
+            continue;
+        }
+
+        if (writer.String() != strings.Join(testCase.writes, "")) {
+            test.Errorf("Case %d: Text does not match what was written.", i);
+        }
+    }
+}
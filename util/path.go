@@ -244,6 +244,59 @@ func SearchParents(basepath string, name string) string {
     return "";
 }
 
+// Join a relative path onto a base dir,
+// and ensure that the result (after resolving any symbolic links) is still inside the base dir.
+// Neither the base dir nor the path need to exist.
+func JoinInsideDir(baseDir string, path string) (string, error) {
+    if (!filepath.IsLocal(path)) {
+        return "", fmt.Errorf("Path '%s' must be a relative path inside of '%s'.", path, baseDir);
+    }
+
+    fullPath := filepath.Join(baseDir, path);
+
+    realBaseDir, err := resolveExistingPath(ShouldAbs(baseDir));
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to resolve dir '%s': '%w'.", baseDir, err);
+    }
+
+    realPath, err := resolveExistingPath(ShouldAbs(fullPath));
+    if (err != nil) {
+        return "", fmt.Errorf("Failed to resolve path '%s': '%w'.", fullPath, err);
+    }
+
+    relPath, err := filepath.Rel(realBaseDir, realPath);
+    if ((err != nil) || !filepath.IsLocal(relPath)) {
+        return "", fmt.Errorf("Path '%s' resolves to '%s', which is outside of '%s'.", path, realPath, baseDir);
+    }
+
+    return fullPath, nil;
+}
+
+// Resolve the symbolic links in the longest existing prefix of an absolute path.
+func resolveExistingPath(path string) (string, error) {
+    current := path;
+    rest := "";
+
+    for {
+        resolved, err := filepath.EvalSymlinks(current);
+        if (err == nil) {
+            return filepath.Join(resolved, rest), nil;
+        }
+
+        if (!os.IsNotExist(err)) {
+            return "", err;
+        }
+
+        parent := filepath.Dir(current);
+        if (parent == current) {
+            return path, nil;
+        }
+
+        rest = filepath.Join(filepath.Base(current), rest);
+        current = parent;
+    }
+}
+
 // This method is not robust (in many ways) and should be generally avoided in non-testing code.
 func PathHasParent(child string, parent string) bool {
     child = ShouldAbs(child);
@@ -0,0 +1,52 @@
+package util
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestJoinInsideDir(test *testing.T) {
+    tempDir := test.TempDir();
+
+    baseDir := filepath.Join(tempDir, "base");
+    err := os.MkdirAll(filepath.Join(baseDir, "sub"), 0755);
+    if (err != nil) {
+        test.Fatalf("Failed to create base dir: '%v'.", err);
+    }
+
+    err = os.Symlink(tempDir, filepath.Join(baseDir, "outside-link"));
+    if (err != nil) {
+        test.Fatalf("Failed to create outside link: '%v'.", err);
+    }
+
+    err = os.Symlink(filepath.Join(baseDir, "sub"), filepath.Join(baseDir, "inside-link"));
+    if (err != nil) {
+        test.Fatalf("Failed to create inside link: '%v'.", err);
+    }
+
+    testCases := []struct{ path string; ok bool }{
+        {"a.txt", true},
+        {"sub/a.txt", true},
+        {"sub/../a.txt", true},
+        {"missing/dir/a.txt", true},
+        {"inside-link/a.txt", true},
+        {"outside-link/base/a.txt", true},
+
+        {"", false},
+        {"../a.txt", false},
+        {"sub/../../a.txt", false},
+        {"/etc/passwd", false},
+        {filepath.Join(baseDir, "a.txt"), false},
+        {"outside-link/a.txt", false},
+    };
+
+    for i, testCase := range testCases {
+        path, err := JoinInsideDir(baseDir, testCase.path);
+        if (testCase.ok && (err != nil)) {
+            test.Errorf("Case %d: Failed to join valid path '%s': '%v'.", i, testCase.path, err);
+        } else if (!testCase.ok && (err == nil)) {
+            test.Errorf("Case %d: Invalid path '%s' was joined: '%s'.", i, testCase.path, path);
+        }
+    }
+}